@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"log"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// GRPCLogger 返回 gRPC-go 内部日志桥接，可直接传给 grpclog.SetLoggerV2：
+//
+//	grpclog.SetLoggerV2(logger.GRPCLogger())
+//
+// 开启 smart_filter 时，连接断开、TLS握手等常见噪音降级为 DEBUG
+func GRPCLogger() *handler.GRPCLogger {
+	return handler.NewGRPCLogger(nil, 0, smartFilterEnabled())
+}
+
+// HTTPErrorLog 返回用于 http.Server.ErrorLog 的标准库 Logger：
+//
+//	srv := &http.Server{Addr: ":8443", ErrorLog: logger.HTTPErrorLog()}
+func HTTPErrorLog() *log.Logger {
+	return handler.NewHTTPErrorLog(nil, smartFilterEnabled())
+}
+
+// smartFilterEnabled 当前配置是否开启智能过滤
+func smartFilterEnabled() bool {
+	return GlobalConfig == nil || GlobalConfig.Logger.Features.SmartFilter
+}
@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/middleware"
+)
+
+var (
+	channelsMu sync.RWMutex
+	channels   = map[string]*slog.Logger{}
+	// channelSupervisors 各通道的输出端管理器，重新初始化或关闭时释放
+	channelSupervisors []*handler.Supervisor
+)
+
+// Channel 返回命名通道的日志器，如 logger.Channel("audit")；
+// 通道未配置或未启用时返回带 channel 属性的全局日志器
+func Channel(name string) *slog.Logger {
+	channelsMu.RLock()
+	l, ok := channels[name]
+	channelsMu.RUnlock()
+	if ok {
+		return l
+	}
+	return GetLogger().With(slog.String("channel", name))
+}
+
+// Channels 返回已启用的通道名
+func Channels() []string {
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setupChannels 根据 logger.channels 创建命名通道，
+// 启用 access 通道后 GinMiddleware 的访问日志只写入该通道
+func setupChannels(cfg *config.Config) error {
+	created := make(map[string]*slog.Logger, len(cfg.Logger.Channels))
+	var supervisors []*handler.Supervisor
+	for name, ch := range cfg.Logger.Channels {
+		if !ch.Enabled {
+			continue
+		}
+		l, supervisor, err := createChannelLogger(name, ch, cfg)
+		if err != nil {
+			for _, s := range supervisors {
+				_ = s.Close()
+			}
+			return err
+		}
+		created[name] = l
+		supervisors = append(supervisors, supervisor)
+	}
+
+	channelsMu.Lock()
+	channels = created
+	previous := channelSupervisors
+	channelSupervisors = supervisors
+	channelsMu.Unlock()
+	for _, s := range previous {
+		_ = s.Close()
+	}
+
+	middleware.SetAccessLogger(created[config.ChannelAccess])
+	middleware.SetAuditLogger(created[config.ChannelAudit])
+	return nil
+}
+
+// createChannelLogger 创建通道日志器，级别为空时沿用全局级别并随 SetLevel 变化
+func createChannelLogger(name string, ch config.ChannelConfig, cfg *config.Config) (*slog.Logger, *handler.Supervisor, error) {
+	var level slog.Leveler = logLevel
+	if ch.Level != "" {
+		level = parseLogLevel(ch.Level)
+	}
+	opts := &slog.HandlerOptions{
+		Level: level,
+	}
+
+	output := ch.Output
+	if ch.Format != "" {
+		output.Console.Format = ch.Format
+		output.File.Format = ch.Format
+	}
+
+	sinks, err := outputSinks(name+".", output, opts, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sinks) == 0 {
+		sinks = append(sinks, handler.NewHandlerSink(name+".discard", slog.NewTextHandler(io.Discard, opts), handler.SinkOptions{}))
+	}
+	if sinks, err = routeSinks(name+".", sinks, output.Routes); err != nil {
+		return nil, nil, err
+	}
+	sinks = handler.MonitorLatency(sinks, latencyConfig(output.Latency))
+	supervisor := handler.NewSupervisor(sinks...)
+	if err := supervisor.Start(context.Background()); err != nil {
+		return nil, nil, err
+	}
+	notReady, err := warmupSinks(supervisor, cfg.Logger.Warmup)
+	if err != nil {
+		_ = supervisor.Close()
+		return nil, nil, err
+	}
+	logNotReady(GetLogger(), notReady)
+	supervisor.Watch(sinkHealthInterval, logSinkHealth)
+
+	h := supervisor.Handler()
+	if cfg.Logger.Features.SLO.Enabled {
+		h = handler.NewSLOHandler(h, sloTracker)
+	}
+	h = handler.NewSamplingHandler(h, ch.SampleRate)
+	h = handler.NewRedactHandler(h, cfg.Logger.Features.Privacy.RedactKeys)
+	if h, err = scrubHandler(h, cfg.Logger.Features.Privacy); err != nil {
+		_ = supervisor.Close()
+		return nil, nil, err
+	}
+
+	return slog.New(h).With(slog.String("channel", name)), supervisor, nil
+}
+
+// closeChannelSinks 关闭所有通道的输出端
+func closeChannelSinks(ctx context.Context) {
+	channelsMu.Lock()
+	previous := channelSupervisors
+	channelSupervisors = nil
+	channelsMu.Unlock()
+	for _, s := range previous {
+		_ = s.CloseContext(ctx)
+	}
+}
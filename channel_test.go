@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+// TestChannel 测试命名通道写入独立输出，未配置的通道回退到全局日志器
+func TestChannel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Channels = config.ChannelsConfig{
+		"audit": {
+			Enabled: true,
+			Format:  "json",
+			Output: config.OutputConfig{
+				File: config.FileConfig{Enabled: true, Path: path},
+			},
+		},
+	}
+	if err := setupChannels(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer setupChannels(&config.Config{})
+
+	Channel("audit").Info("role granted")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"channel":"audit"`) || !strings.Contains(string(data), "role granted") {
+		t.Errorf("audit channel output = %s", data)
+	}
+	if Channel("security") == nil {
+		t.Error("unconfigured channel should fall back to the global logger")
+	}
+}
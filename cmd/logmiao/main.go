@@ -0,0 +1,145 @@
+// Command logmiao 日志工具，提供终端查看器、配置校验和查看器密码哈希生成：
+//
+//	logmiao tui -file logs/app.log -file worker=logs/worker.log
+//	logmiao tui -url http://logs.internal:8081 -user admin -password secret
+//	logmiao check configs/logger.yaml
+//	echo -n 'secret' | logmiao hash-password
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/viewer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fileFlags 可重复的 -file 参数，格式为 path 或 name=path
+type fileFlags []string
+
+func (f *fileFlags) String() string { return strings.Join(*f, ",") }
+
+func (f *fileFlags) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+func main() {
+	var err error
+	switch {
+	case len(os.Args) >= 2 && os.Args[1] == "tui":
+		err = runTUI(os.Args[2:])
+	case len(os.Args) >= 2 && os.Args[1] == "check":
+		err = runCheck(os.Args[2:])
+	case len(os.Args) >= 2 && os.Args[1] == "hash-password":
+		err = runHashPassword(os.Stdin)
+	default:
+		fmt.Fprintln(os.Stderr, "usage: logmiao tui [-file [name=]path]... [-url viewer-url] [-level warn]")
+		fmt.Fprintln(os.Stderr, "       logmiao check [config-file]...")
+		fmt.Fprintln(os.Stderr, "       logmiao hash-password < password.txt")
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logmiao:", err)
+		os.Exit(1)
+	}
+}
+
+// runCheck 校验配置文件，默认为 configs/logger.yaml；任一文件有问题时返回错误
+func runCheck(paths []string) error {
+	if len(paths) == 0 {
+		paths = []string{"configs/logger.yaml"}
+	}
+	failed := 0
+	for _, path := range paths {
+		if err := config.Check(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s:\n%v\n", path, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: ok\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d config files have problems", failed, len(paths))
+	}
+	return nil
+}
+
+// runHashPassword 从标准输入读取密码（首行），输出可填入 viewer.auth.password_hash 的 bcrypt 哈希
+func runHashPassword(r io.Reader) error {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return fmt.Errorf("empty password")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(hash))
+	return nil
+}
+
+// runTUI 解析参数，启动日志来源并进入终端查看器
+func runTUI(args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	var files fileFlags
+	fs.Var(&files, "file", "JSON log file to follow, as path or name=path (repeatable)")
+	url := fs.String("url", "", "remote logmiao viewer to poll, e.g. http://localhost:8081")
+	user := fs.String("user", "", "viewer basic auth username")
+	password := fs.String("password", "", "viewer basic auth password")
+	token := fs.String("token", "", "viewer bearer token (auth.tokens), used instead of -user/-password")
+	level := fs.String("level", "", "minimum level: debug, info, warn, error")
+	buffer := fs.Int("buffer", viewer.DefaultBufferSize, "records kept in memory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(files) == 0 && *url == "" {
+		files = append(files, "logs/app.log")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	store := viewer.NewStore(*buffer)
+	for _, f := range files {
+		name, path, ok := strings.Cut(f, "=")
+		if !ok {
+			name, path = "", f
+		}
+		go viewer.NewFileSource(name, path).Run(ctx, store)
+	}
+	if *url != "" {
+		remote := viewer.NewRemoteSource(*url, *user, *password)
+		remote.Token = *token
+		go remote.Run(ctx, store)
+	}
+
+	tui := viewer.NewTUI(store)
+	tui.Size = terminalSize
+	if *level != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(*level)); err != nil {
+			return err
+		}
+		tui.SetMinLevel(l)
+	}
+
+	restore, err := makeRaw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return tui.Run(ctx, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// Check 校验配置文件而不应用：文件存在且语法正确、include 片段可以解析、没有拼写错误等未知配置项、
+// 级别和格式取值有效。可用于启动探针、CI 或 logmiao check 命令，返回的错误包含发现的全部问题
+func Check(path string) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if _, err := mergeIncludes(v, path); err != nil {
+		return err
+	}
+	setDefaultsOn(v)
+
+	// include 只在加载时使用，不对应 Config 中的字段
+	var checked struct {
+		Config  `mapstructure:",squash"`
+		Include []string `mapstructure:"include"`
+	}
+	if err := v.Unmarshal(&checked, func(dc *mapstructure.DecoderConfig) {
+		dc.ErrorUnused = true
+	}); err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+	return errors.Join(validate(&checked.Config)...)
+}
+
+var (
+	validLevels         = []string{"debug", "info", "warn", "warning", "error"}
+	validConsoleFormats = []string{"color", "json", "text"}
+	validFileFormats    = []string{"json", "text"}
+	validWarmupModes    = []string{"off", "warn", "fail"}
+	validFilterActions  = []string{"drop", "keep", "downgrade"}
+	validQueuePolicies  = []string{"block", "drop_newest", "drop_oldest", "sample"}
+	validLatencyActions = []string{"async", "open", "log"}
+	validScrubPatterns  = []string{"credit_card", "jwt", "aws_key", "email", "cn_id"}
+)
+
+// validate 检查枚举取值与正则表达式，空值表示使用默认值
+func validate(cfg *Config) []error {
+	var errs []error
+	check := func(key, value string, valid []string) {
+		if value != "" && !slices.Contains(valid, value) {
+			errs = append(errs, fmt.Errorf("%s: 无效的取值 %q，可选 %s", key, value, strings.Join(valid, ", ")))
+		}
+	}
+
+	check("logger.level", cfg.Logger.Level, validLevels)
+	modules := make([]string, 0, len(cfg.Logger.Levels))
+	for module := range cfg.Logger.Levels {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		check("logger.levels."+module, cfg.Logger.Levels[module], validLevels)
+	}
+	check("logger.format", cfg.Logger.Format, validConsoleFormats)
+	check("logger.output.console.format", cfg.Logger.Output.Console.Format, validConsoleFormats)
+	check("logger.output.file.format", cfg.Logger.Output.File.Format, validFileFormats)
+	check("logger.warmup.mode", cfg.Logger.Warmup.Mode, validWarmupModes)
+	check("logger.output.async.policy", cfg.Logger.Output.Async.Queue.Policy, validQueuePolicies)
+	check("logger.viewer.push.backpressure.policy", cfg.Logger.Viewer.Push.Backpressure.Policy, validQueuePolicies)
+	check("logger.output.latency.action", cfg.Logger.Output.Latency.Action, validLatencyActions)
+	for i, f := range cfg.Logger.Filters {
+		if f.Action == "" {
+			errs = append(errs, fmt.Errorf("logger.filters[%d].action: 缺少取值，可选 %s", i, strings.Join(validFilterActions, ", ")))
+		}
+		check(fmt.Sprintf("logger.filters[%d].action", i), f.Action, validFilterActions)
+		check(fmt.Sprintf("logger.filters[%d].to", i), f.To, validLevels)
+	}
+	for i, name := range cfg.Logger.Features.Privacy.Scrub {
+		check(fmt.Sprintf("logger.features.privacy.scrub[%d]", i), name, validScrubPatterns)
+	}
+	for i, rule := range cfg.Logger.Features.Privacy.PhoneRules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("logger.features.privacy.phone_rules[%d].pattern: 无效的正则 %q: %w", i, rule.Pattern, err))
+		}
+	}
+	return errs
+}
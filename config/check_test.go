@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheck 测试配置校验：示例配置与 include 通过，语法错误、未知配置项和无效取值均报告
+func TestCheck(t *testing.T) {
+	if err := Check("../configs/logger.yaml"); err != nil {
+		t.Errorf("sample config should pass: %v", err)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	write("base.yaml", "logger:\n  format: json\n")
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string // 错误中应包含的内容，为空表示应通过
+	}{
+		{"include", "include: [base.yaml]\nlogger:\n  level: debug\n", nil},
+		{"syntax", "logger:\n  level: [debug\n", []string{"读取配置文件失败"}},
+		{"unknown key", "logger:\n  levle: debug\n  output:\n    file:\n      pth: x.log\n", []string{"levle", "pth"}},
+		{"invalid values", "logger:\n  level: verbose\n  levels:\n    db: loud\n  output:\n    file:\n      format: xml\n",
+			[]string{"logger.level", "logger.levels.db", "logger.output.file.format"}},
+		{"queue policy", "logger:\n  output:\n    async:\n      policy: drop_newset\n", []string{"logger.output.async.policy"}},
+		{"phone rule", "logger:\n  features:\n    privacy:\n      phone_rules:\n        - region: US\n          pattern: '^\\+1(\\d{3}'\n",
+			[]string{"logger.features.privacy.phone_rules[0].pattern"}},
+		{"missing include", "include: [missing.yaml]\n", []string{"missing.yaml"}},
+	}
+	for _, tt := range tests {
+		err := Check(write(strings.ReplaceAll(tt.name, " ", "_")+".yaml", tt.content))
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected error", tt.name)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error should mention %q: %v", tt.name, want, err)
+			}
+		}
+	}
+
+	if err := Check(filepath.Join(dir, "nope.yaml")); err == nil {
+		t.Error("missing file should be reported")
+	}
+}
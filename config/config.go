@@ -2,43 +2,196 @@ package config
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config 是日志系统的整体配置
 type Config struct {
-	Logger LoggerConfig `mapstructure:"logger"`
+	Logger   LoggerConfig `mapstructure:"logger"`
+	Source   string       `mapstructure:"-"` // 实际加载的配置来源（文件绝对路径或远程地址），为空表示使用默认配置
+	Includes []string     `mapstructure:"-"` // 通过 include 合并的配置片段（绝对路径，按合并顺序）
+}
+
+// SourceName 返回配置来源，未加载任何配置文件时为 defaults
+func (c *Config) SourceName() string {
+	if c.Source == "" {
+		return "defaults"
+	}
+	return c.Source
 }
 
 // LoggerConfig 日志配置
 type LoggerConfig struct {
-	Level      string           `mapstructure:"level"`      // 日志级别: debug, info, warn, error
-	Format     string           `mapstructure:"format"`     // 输出格式: color, json, text
-	Output     OutputConfig     `mapstructure:"output"`     // 输出配置
-	Features   FeaturesConfig   `mapstructure:"features"`   // 功能配置
-	Middleware MiddlewareConfig `mapstructure:"middleware"` // 中间件配置
-	Viewer     ViewerConfig     `mapstructure:"viewer"`     // Web查看器配置
+	Level            string            `mapstructure:"level"`             // 日志级别: debug, info, warn, error
+	Levels           map[string]string `mapstructure:"levels"`            // 按模块设置级别，如 database: debug；子模块 database.postgres 沿用 database
+	Transforms       []TransformConfig `mapstructure:"transforms"`        // 记录到达输出端之前的转换规则，按顺序执行
+	Filters          []FilterConfig    `mapstructure:"filters"`           // 按消息、属性和级别丢弃、保留或降级记录，第一条命中的规则生效
+	Format           string            `mapstructure:"format"`            // 输出格式: color, json, text
+	HotReload        bool              `mapstructure:"hot_reload"`        // 监听配置文件及 include 片段，修改后自动重新加载
+	ShutdownTimeout  time.Duration     `mapstructure:"shutdown_timeout"`  // Flush 与 Close 等待写出剩余记录的最长时间
+	MaxRecordSize    int               `mapstructure:"max_record_size"`   // 单条记录序列化后的大小上限（字节），超出时截断最大的属性并标记 truncated=true，0 表示不限制
+	StructuredBanner bool              `mapstructure:"structured_banner"` // PrintBanner 与 PrintHealthCheck 同时输出 type=banner/health 的结构化记录
+	Warmup           WarmupConfig      `mapstructure:"warmup"`            // 启动时验证输出端可用
+	Output           OutputConfig      `mapstructure:"output"`            // 输出配置
+	Features         FeaturesConfig    `mapstructure:"features"`          // 功能配置
+	Middleware       MiddlewareConfig  `mapstructure:"middleware"`        // 中间件配置
+	Viewer           ViewerConfig      `mapstructure:"viewer"`            // Web查看器配置
+	Resource         ResourceConfig    `mapstructure:"resource"`          // OpenTelemetry Resource 属性
+	Channels         ChannelsConfig    `mapstructure:"channels"`          // 独立日志通道
+	Transport        TransportConfig   `mapstructure:"transport"`         // 远程推送、Webhook共用的网络传输配置
+}
+
+// WarmupConfig 输出端预热：初始化时验证文件可写、远程端点可达且认证通过
+type WarmupConfig struct {
+	Mode    string        `mapstructure:"mode"`    // off（不验证）, warn（记录降级警告后继续）, fail（初始化失败）
+	Timeout time.Duration `mapstructure:"timeout"` // 预热与就绪检查的最长耗时
+}
+
+// TransformConfig 记录转换规则：
+// rename（from → to）、extract（pattern 的命名捕获组，来源为 from 指定的属性或消息）、derive（func(from) → to）
+type TransformConfig struct {
+	Type    string `mapstructure:"type"`    // rename, extract, derive
+	From    string `mapstructure:"from"`    // 源属性完整路径，如 http.status；extract 为空时从消息提取
+	To      string `mapstructure:"to"`      // rename 的新键名，derive 的目标属性
+	Pattern string `mapstructure:"pattern"` // extract 的正则，如 order (?P<order_id>\d+)
+	Func    string `mapstructure:"func"`    // derive 的函数：status_class, lower, upper
+}
+
+// FilterConfig 过滤规则，已设置的条件全部满足时执行 action：
+// drop（丢弃）、keep（保留并跳过后续规则）、downgrade（降低到 to 级别）
+type FilterConfig struct {
+	Message string `mapstructure:"message"` // 消息的正则
+	Attr    string `mapstructure:"attr"`    // 属性完整路径，如 http.path
+	Value   string `mapstructure:"value"`   // 属性值的正则，为空时只要求属性存在
+	Level   string `mapstructure:"level"`   // 级别区间，如 error+、debug..info
+	Action  string `mapstructure:"action"`  // drop, keep, downgrade
+	To      string `mapstructure:"to"`      // downgrade 的目标级别，默认 debug
 }
 
 // OutputConfig 输出配置
 type OutputConfig struct {
-	Console ConsoleConfig `mapstructure:"console"`
-	File    FileConfig    `mapstructure:"file"`
+	Console  ConsoleConfig  `mapstructure:"console"`
+	File     FileConfig     `mapstructure:"file"`
+	Envelope EnvelopeConfig `mapstructure:"envelope"` // JSON记录信封
+	Async    AsyncConfig    `mapstructure:"async"`    // 异步写出
+	Severity SeverityConfig `mapstructure:"severity"` // JSON输出的级别字段映射
+	Routes   []RouteConfig  `mapstructure:"routes"`   // 按级别路由到输出端，为空时所有记录写入所有输出端
+	Latency  LatencyConfig  `mapstructure:"latency"`  // 输出端写入耗时监控
+}
+
+// LatencyConfig 输出端写入耗时监控：单条记录写入超过 budget 的情况连续出现 strikes 次后，
+// 该输出端改为异步写出（async）、熔断丢弃（open）或只记录警告（log），避免一个缓慢的目标拖慢应用
+type LatencyConfig struct {
+	Budget       time.Duration `mapstructure:"budget"`        // 单条记录的写入耗时上限，0 表示不监控
+	Strikes      int           `mapstructure:"strikes"`       // 连续超时多少次后降级
+	Action       string        `mapstructure:"action"`        // async, open, log
+	QueueSize    int           `mapstructure:"queue_size"`    // async 的队列容量，满时丢弃新记录
+	OpenDuration time.Duration `mapstructure:"open_duration"` // open 的熔断时长，到期后放行一条试探
+}
+
+// RouteConfig 级别路由规则，如 level: error+, sinks: [file, push]；
+// 出现在规则中的输出端只接收匹配其规则的记录，未出现的输出端接收全部记录
+type RouteConfig struct {
+	Level string   `mapstructure:"level"` // error+（及以上）、info（仅该级别）、debug..warn（区间）
+	Sinks []string `mapstructure:"sinks"` // 输出端名称：console、console.<名称>、file、viewer/recent、push
+}
+
+// SeverityConfig JSON输出的级别字段映射，供按固定 severity 字符串分类的采集器使用
+type SeverityConfig struct {
+	Profile string            `mapstructure:"profile"` // 预设：stackdriver；为空时保持 slog 默认
+	Key     string            `mapstructure:"key"`     // 级别字段名，覆盖预设
+	Levels  map[string]string `mapstructure:"levels"`  // 级别映射，如 warn: WARNING、fatal: CRITICAL，覆盖预设中的同名级别
+}
+
+// ChannelsConfig 命名日志通道配置，如 app、audit、access、security；
+// access 通道启用后接收 GinMiddleware 的访问日志
+type ChannelsConfig map[string]ChannelConfig
+
+// 内置中间件使用的通道名
+const (
+	ChannelAccess = "access" // GinMiddleware 访问日志
+	ChannelAudit  = "audit"  // Audit 中间件审计日志
+)
+
+// ChannelConfig 日志通道配置，通道拥有独立的级别、格式、输出、轮转和采样
+type ChannelConfig struct {
+	Enabled    bool         `mapstructure:"enabled"`
+	Level      string       `mapstructure:"level"`       // 为空时沿用全局级别
+	Format     string       `mapstructure:"format"`      // 不为空时覆盖控制台和文件的格式
+	Output     OutputConfig `mapstructure:"output"`      // 通道输出，结构同 logger.output
+	SampleRate float64      `mapstructure:"sample_rate"` // Info 及以下记录的保留比例，1 表示全部保留
+}
+
+// AsyncConfig 异步写出配置，记录放入有界队列由后台协程写出
+type AsyncConfig struct {
+	Enabled            bool               `mapstructure:"enabled"`
+	Queue              BackpressureConfig `mapstructure:",squash"`
+	DropReportInterval time.Duration      `mapstructure:"drop_report_interval"` // 汇总输出丢弃数量的间隔，0表示不输出
+}
+
+// BackpressureConfig 队列容量及溢出策略
+type BackpressureConfig struct {
+	QueueSize  int    `mapstructure:"queue_size"`
+	Policy     string `mapstructure:"policy"`      // block, drop_newest, drop_oldest, sample
+	SampleRate int    `mapstructure:"sample_rate"` // sample 策略下每 N 条保留 1 条
+}
+
+// TransportConfig 远程日志推送和Webhook共用的HTTP传输配置，支持自定义CA、客户端证书（mTLS）和代理
+type TransportConfig struct {
+	Timeout            time.Duration `mapstructure:"timeout"`              // 单次请求超时
+	CAFile             string        `mapstructure:"ca_file"`              // 自定义CA证书（PEM），追加到系统信任链
+	CertFile           string        `mapstructure:"cert_file"`            // 客户端证书（PEM），与 key_file 一起启用 mTLS
+	KeyFile            string        `mapstructure:"key_file"`             // 客户端私钥（PEM）
+	ServerName         string        `mapstructure:"server_name"`          // 覆盖TLS校验使用的服务器名称
+	InsecureSkipVerify bool          `mapstructure:"insecure_skip_verify"` // 跳过证书校验，仅用于测试
+	Proxy              string        `mapstructure:"proxy"`                // 代理地址，支持 http://、https://、socks5://；为空时读取 HTTPS_PROXY 等环境变量
+}
+
+// EnvelopeConfig JSON记录信封配置，启用后每条JSON记录包装为
+// {"schema_version":1,"app":"...","payload":{...}}，便于下游解析器平滑升级
+type EnvelopeConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	App     string `mapstructure:"app"` // 应用名称
 }
 
 // ConsoleConfig 控制台输出配置
 type ConsoleConfig struct {
-	Enabled bool   `mapstructure:"enabled"`
-	Format  string `mapstructure:"format"` // color, json, text
+	Enabled     bool                         `mapstructure:"enabled"`
+	Format      string                       `mapstructure:"format"`       // color, json, text
+	Source      string                       `mapstructure:"source"`       // 调用位置：short, full, off
+	PrettyJSON  bool                         `mapstructure:"pretty_json"`  // color格式下将JSON字符串、map等属性值缩进并语法高亮
+	Width       int                          `mapstructure:"width"`        // color格式的折行宽度：0 自动检测终端宽度，-1 不折行，>0 固定宽度（适合捕获输出）
+	LevelTheme  string                       `mapstructure:"level_theme"`  // color格式的级别标签主题：default（[INFO]）、badge（背景色徽章 INF）
+	LevelLabels map[string]string            `mapstructure:"level_labels"` // 覆盖主题中的级别文字，如 info: INF、fatal: FTL
+	ValueColors map[string]map[string]string `mapstructure:"value_colors"` // color格式按属性取值着色，如 cache: {hit: green, miss: yellow, "*": magenta}
+	Writer      io.Writer                    `mapstructure:"-"`            // 主输出目标，为空时使用 os.Stderr，只能在代码中设置
+	Writers     []ConsoleWriter              `mapstructure:"-"`            // 同时写入的其他目标，各自使用独立格式，只能在代码中设置
+}
+
+// ConsoleWriter 控制台输出的附加目标，如测试用的内存缓冲区或终端录制器
+type ConsoleWriter struct {
+	Name   string // 输出端名称，显示为 console.<name>
+	Writer io.Writer
+	Format string // color, json, text，为空时与主输出相同
 }
 
 // FileConfig 文件输出配置
 type FileConfig struct {
-	Enabled  bool           `mapstructure:"enabled"`
-	Path     string         `mapstructure:"path"`
-	Format   string         `mapstructure:"format"` // json, text
-	Rotation RotationConfig `mapstructure:"rotation"`
+	Enabled       bool              `mapstructure:"enabled"`
+	Path          string            `mapstructure:"path"`
+	Format        string            `mapstructure:"format"` // json, text
+	Rotation      RotationConfig    `mapstructure:"rotation"`
+	Shared        bool              `mapstructure:"shared"`         // 多个进程写入同一文件：写入与轮转前取得文件锁，避免轮转时互相破坏
+	PositionFile  string            `mapstructure:"position_file"`  // 活动文件的路径、inode 与偏移量写入该 JSON 文件，供采集器对接；为空时不写
+	Source        string            `mapstructure:"source"`         // 调用位置：short, full, off
+	TamperEvident bool              `mapstructure:"tamper_evident"` // JSON记录追加哈希链，可用 handler.VerifyHashChain 校验，轮转后的文件用 VerifyHashChainFrom
+	Coerce        map[string]string `mapstructure:"coerce"`         // 属性完整路径 → string、int、float，统一类型以免下游映射冲突
 }
 
 // RotationConfig 日志轮转配置
@@ -51,45 +204,319 @@ type RotationConfig struct {
 
 // FeaturesConfig 功能配置
 type FeaturesConfig struct {
-	SmartFilter         bool          `mapstructure:"smart_filter"`         // 智能过滤
-	KeywordHighlight    bool          `mapstructure:"keyword_highlight"`    // 关键词高亮
-	AutoSampling        bool          `mapstructure:"auto_sampling"`        // 自动采样
-	PerformanceTracking bool          `mapstructure:"performance_tracking"` // 性能追踪
-	Privacy             PrivacyConfig `mapstructure:"privacy"`              // 隐私脱敏配置
+	SmartFilter         bool                `mapstructure:"smart_filter"`         // 智能过滤
+	SmartFilters        []string            `mapstructure:"smart_filters"`        // 智能过滤启用的具名过滤器：gin_debug、health_check、chromedp 或 handler.RegisterFilter 注册的名称
+	KeywordHighlight    bool                `mapstructure:"keyword_highlight"`    // 关键词高亮
+	AutoSampling        bool                `mapstructure:"auto_sampling"`        // 自动采样：按 sampling 配置的键限速，高频日志降频
+	Sampling            SamplingConfig      `mapstructure:"sampling"`             // auto_sampling 的采样键与阈值
+	PerformanceTracking bool                `mapstructure:"performance_tracking"` // 性能追踪，启用 logger.StartSpan 计时
+	SlowSpanThreshold   time.Duration       `mapstructure:"slow_span_threshold"`  // StartSpan 计时超过该值时以 WARN 输出
+	Privacy             PrivacyConfig       `mapstructure:"privacy"`              // 隐私脱敏配置
+	DebugTargeting      DebugTargeting      `mapstructure:"debug_targeting"`      // 定向调试
+	Baggage             BaggageConfig       `mapstructure:"baggage"`              // Baggage 传播
+	ErrorWatchdog       ErrorWatchdogConfig `mapstructure:"error_watchdog"`       // 错误率看门狗
+	ErrorStacks         bool                `mapstructure:"error_stacks"`         // Error 及以上级别的记录自动附加调用堆栈
+	NovelErrors         NovelErrorsConfig   `mapstructure:"novel_errors"`         // 新错误检测
+	SLO                 SLOConfig           `mapstructure:"slo"`                  // 基于访问日志的 SLO 统计
+	MsgTemplate         bool                `mapstructure:"msg_template"`         // 附加归一化的消息模板 msg_template，便于按模板聚合
+	Burst               BurstConfig         `mapstructure:"burst"`                // 日志风暴时合并相似记录为周期汇总
+}
+
+// EnabledNames 返回已开启的功能名称（与配置键相同），用于启动报告
+func (f FeaturesConfig) EnabledNames() []string {
+	flags := []struct {
+		name    string
+		enabled bool
+	}{
+		{"smart_filter", f.SmartFilter},
+		{"keyword_highlight", f.KeywordHighlight},
+		{"auto_sampling", f.AutoSampling},
+		{"performance_tracking", f.PerformanceTracking},
+		{"debug_targeting", f.DebugTargeting.Enabled},
+		{"baggage", f.Baggage.Enabled},
+		{"error_watchdog", f.ErrorWatchdog.Enabled},
+		{"error_stacks", f.ErrorStacks},
+		{"novel_errors", f.NovelErrors.Enabled},
+		{"slo", f.SLO.Enabled},
+		{"msg_template", f.MsgTemplate},
+		{"burst", f.Burst.Enabled},
+	}
+	var names []string
+	for _, flag := range flags {
+		if flag.enabled {
+			names = append(names, flag.name)
+		}
+	}
+	return names
+}
+
+// SamplingConfig 按键限速采样配置，每个键每个 tick 内前 first 条原样保留，之后每 thereafter 条保留 1 条
+type SamplingConfig struct {
+	Keys       []string      `mapstructure:"keys"`       // 组成采样键的属性，如 route、tenant；为空或记录中没有时按消息
+	Tick       time.Duration `mapstructure:"tick"`       // 计数周期
+	First      int           `mapstructure:"first"`      // 每个周期原样保留的条数
+	Thereafter int           `mapstructure:"thereafter"` // 超出后每 N 条保留 1 条
+}
+
+// BurstConfig 日志风暴汇总配置：每秒记录数超过阈值时，同一级别、同一消息模板的记录每个周期只放行一条，
+// 其余在周期结束时汇总为一条 "N similar warn records in last 30s"
+type BurstConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Threshold int           `mapstructure:"threshold"` // 每秒记录数阈值
+	Window    time.Duration `mapstructure:"window"`    // 汇总周期
+}
+
+// SLOConfig 基于访问日志状态码按路由统计可用性和错误预算
+type SLOConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Window          time.Duration `mapstructure:"window"`           // 滚动窗口，如 1h
+	Objective       float64       `mapstructure:"objective"`        // 可用性目标，如 0.999
+	SummaryInterval time.Duration `mapstructure:"summary_interval"` // 定期输出汇总记录的间隔，0 表示不输出
+}
+
+// NovelErrorsConfig 新错误检测配置，首次出现的错误模板输出提示记录并触发回调或Webhook
+type NovelErrorsConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Capacity int           `mapstructure:"capacity"` // 记住的错误模板数
+	Warmup   time.Duration `mapstructure:"warmup"`   // 启动后只学习不告警的时长
+	Webhook  string        `mapstructure:"webhook"`  // 新错误以JSON POST到该地址
+}
+
+// ErrorWatchdogConfig 错误率看门狗配置，窗口内错误数超过阈值时触发回调或Webhook
+type ErrorWatchdogConfig struct {
+	Enabled   bool          `mapstructure:"enabled"`
+	Window    time.Duration `mapstructure:"window"`    // 滑动窗口，如 1m
+	Threshold int           `mapstructure:"threshold"` // 窗口内错误数阈值
+	Cooldown  time.Duration `mapstructure:"cooldown"`  // 两次告警的最小间隔
+	Webhook   string        `mapstructure:"webhook"`   // 告警Webhook地址，可选
+}
+
+// BaggageConfig 将请求上下文中的 Baggage 条目复制为日志属性
+type BaggageConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Keys    []string `mapstructure:"keys"`   // 需要复制的条目，为空时复制全部
+	Prefix  string   `mapstructure:"prefix"` // 属性键前缀，如 "baggage."
+}
+
+// DebugTargeting 定向调试配置，命中的请求或记录不受全局级别限制
+type DebugTargeting struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Header  string            `mapstructure:"header"` // 携带调试令牌的请求头
+	Secret  string            `mapstructure:"secret"` // 令牌签名密钥，为空时不接受请求头
+	Rules   []DebugTargetRule `mapstructure:"rules"`  // 属性匹配规则，匹配 With 绑定的属性
+}
+
+// DebugTargetRule 定向调试属性规则，如 key=user_id, value=123
+type DebugTargetRule struct {
+	Key   string `mapstructure:"key"`
+	Value string `mapstructure:"value"`
 }
 
 // PrivacyConfig 隐私脱敏配置
 type PrivacyConfig struct {
-	EnableEmailMask     bool `mapstructure:"enable_email_mask"`     // 启用邮箱脱敏
-	EnablePhoneMask     bool `mapstructure:"enable_phone_mask"`     // 启用手机号脱敏
-	EnableInputSanitize bool `mapstructure:"enable_input_sanitize"` // 启用输入清理
+	EnableEmailMask     bool            `mapstructure:"enable_email_mask"`     // 启用邮箱脱敏
+	EnablePhoneMask     bool            `mapstructure:"enable_phone_mask"`     // 启用手机号脱敏
+	EnableInputSanitize bool            `mapstructure:"enable_input_sanitize"` // 启用输入清理
+	EnableSessionMask   bool            `mapstructure:"enable_session_mask"`   // 会话ID替换为哈希
+	PhoneRules          []PhoneMaskRule `mapstructure:"phone_rules"`           // 按地区自定义的手机号脱敏规则
+	RedactKeys          []string        `mapstructure:"redact_keys"`           // 值替换为 [REDACTED] 的属性键（含分组内与 map 中的键），为空时不按键脱敏
+	Scrub               []string        `mapstructure:"scrub"`                 // 扫描消息与字符串属性值的内置规则：credit_card、jwt、aws_key、email、cn_id
+	ScrubPatterns       []string        `mapstructure:"scrub_patterns"`        // 自定义扫描正则，匹配内容替换为 [REDACTED]
+}
+
+// PhoneMaskRule 手机号脱敏规则，Pattern 匹配后按 Mask 模板替换
+type PhoneMaskRule struct {
+	Region  string `mapstructure:"region"`  // 地区标识，如 CN、US，仅用于说明
+	Pattern string `mapstructure:"pattern"` // 正则表达式，可使用捕获组
+	Mask    string `mapstructure:"mask"`    // 替换模板，如 "$1****$2"
 }
 
 // MiddlewareConfig 中间件配置
 type MiddlewareConfig struct {
-	LogBody     bool `mapstructure:"log_body"`      // 记录请求体
-	LogHeaders  bool `mapstructure:"log_headers"`   // 记录请求头
-	MaxBodySize int  `mapstructure:"max_body_size"` // 最大请求体大小
+	LogBody          bool                 `mapstructure:"log_body"`           // 记录请求体
+	LogHeaders       bool                 `mapstructure:"log_headers"`        // 记录请求头
+	MaxBodySize      int                  `mapstructure:"max_body_size"`      // 最大请求体大小
+	Tenant           TenantConfig         `mapstructure:"tenant"`             // 租户提取
+	Session          SessionConfig        `mapstructure:"session"`            // 会话生命周期日志
+	Audit            AuditConfig          `mapstructure:"audit"`              // 审计日志
+	Incident         IncidentConfig       `mapstructure:"incident"`           // 5xx 现场转储
+	HAR              HARConfig            `mapstructure:"har"`                // 失败请求 HAR 导出
+	Sampling         AccessSamplingConfig `mapstructure:"sampling"`           // 成功请求的访问日志采样
+	HealthChecks     HealthChecksConfig   `mapstructure:"health_checks"`      // 健康检查请求的处理方式
+	RequestID        RequestIDConfig      `mapstructure:"request_id"`         // 请求ID生成与读取
+	CaptureGinOutput bool                 `mapstructure:"capture_gin_output"` // 将 gin.DefaultWriter/DefaultErrorWriter 重定向到日志系统
+	ContextAttrs     []ContextAttr        `mapstructure:"context_attrs"`      // 复制到访问日志的 gin.Context 值
+	ResponseHeaders  []HeaderAttr         `mapstructure:"response_headers"`   // 复制到访问日志的响应头，如 X-Cache、Age
+	PhaseTimings     bool                 `mapstructure:"phase_timings"`      // 访问日志输出各阶段耗时（phases），配合 logger.MarkHandlerStart()
+	ConnInfo         bool                 `mapstructure:"conn_info"`          // 访问日志输出 HTTP 协议版本、TLS 版本与加密套件、SNI
+}
+
+// ContextAttr 将 gin.Context 中的值（如鉴权中间件设置的 user_id）写入访问日志，
+// attr 为空时使用 key 作为属性名
+type ContextAttr struct {
+	Key  string `mapstructure:"key"`
+	Attr string `mapstructure:"attr"`
+}
+
+// HeaderAttr 复制到访问日志的响应头，attr 为空时使用小写并以下划线连接的头名称（X-Cache → x_cache）
+type HeaderAttr struct {
+	Header string `mapstructure:"header"`
+	Attr   string `mapstructure:"attr"`
+}
+
+// AccessSamplingConfig 访问日志采样配置，4xx/5xx 和慢请求始终记录
+type AccessSamplingConfig struct {
+	Rate          int           `mapstructure:"rate"`           // 2xx 请求每 rate 条保留 1 条，<=1 表示不采样
+	SlowThreshold time.Duration `mapstructure:"slow_threshold"` // 耗时达到该值的请求始终记录，0 表示不按耗时豁免
+}
+
+// HealthChecksConfig 健康检查等跳过路径的访问日志处理方式
+type HealthChecksConfig struct {
+	Mode     string        `mapstructure:"mode"`     // drop：不记录；summary：按路径计数并定期输出一条汇总
+	Interval time.Duration `mapstructure:"interval"` // summary 模式的汇总周期
+}
+
+// RequestIDConfig 请求ID中间件配置
+type RequestIDConfig struct {
+	Format  string   `mapstructure:"format"`  // default（req_<hex>）、uuidv7、ulid、snowflake
+	Prefix  string   `mapstructure:"prefix"`  // 生成的ID前缀，如 api-
+	NodeID  int64    `mapstructure:"node_id"` // snowflake 节点号（0-1023）
+	Headers []string `mapstructure:"headers"` // 按顺序读取的入站请求头，如 X-Request-ID、X-Correlation-ID、X-Amzn-Trace-Id
+
+	ResponseHeader string `mapstructure:"response_header"` // 写回请求ID的响应头
+	Response       string `mapstructure:"response"`        // always：总是写回；generated：只在新生成时写回；never：不写回
+	Trailer        bool   `mapstructure:"trailer"`         // 同时以 HTTP trailer 写回，流式响应的客户端在响应结束时也能读到
+}
+
+// HARConfig 失败请求 HAR 导出配置
+type HARConfig struct {
+	Dir         string   `mapstructure:"dir"`           // HAR 文件目录
+	Statuses    []string `mapstructure:"statuses"`      // 需要导出的状态码，如 4xx、5xx、429
+	Paths       []string `mapstructure:"paths"`         // 路径前缀过滤，为空时不过滤
+	MaxEntries  int      `mapstructure:"max_entries"`   // 单个文件最大条目数
+	MaxBodySize int      `mapstructure:"max_body_size"` // 请求体和响应体最大记录字节数
+}
+
+// IncidentConfig 5xx 现场转储配置
+type IncidentConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`       // 启用后为每个请求保留最近记录（含 Debug 级别）
+	Dir         string `mapstructure:"dir"`           // 转储目录
+	RingSize    int    `mapstructure:"ring_size"`     // 每个请求保留的最近记录数
+	MaxBodySize int    `mapstructure:"max_body_size"` // 请求体和响应体最大记录字节数
+}
+
+// AuditConfig 审计中间件配置
+type AuditConfig struct {
+	Methods []string `mapstructure:"methods"` // 需要审计的请求方法
+}
+
+// SessionConfig 会话生命周期日志配置
+type SessionConfig struct {
+	Cookie      string        `mapstructure:"cookie"`       // 会话Cookie名
+	Header      string        `mapstructure:"header"`       // Cookie不存在时读取的请求头
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"` // 空闲超时，如 30m
+}
+
+// TenantConfig 租户提取配置，按 header、jwt_claim、subdomain 的顺序查找
+type TenantConfig struct {
+	Header    string `mapstructure:"header"`    // 携带租户标识的请求头
+	JWTClaim  string `mapstructure:"jwt_claim"` // Bearer 令牌中的声明名
+	Subdomain bool   `mapstructure:"subdomain"` // 是否从子域名提取
 }
 
 // ViewerConfig Web日志查看器配置
 type ViewerConfig struct {
-	Enabled bool       `mapstructure:"enabled"`
-	Port    int        `mapstructure:"port"`
-	Auth    AuthConfig `mapstructure:"auth"`
+	Enabled    bool                 `mapstructure:"enabled"`
+	Port       int                  `mapstructure:"port"`
+	Auth       AuthConfig           `mapstructure:"auth"`
+	BufferSize int                  `mapstructure:"buffer_size"` // 内存中保留的日志条数
+	Recent     bool                 `mapstructure:"recent"`      // 未启用查看器时也在内存中保留最近的日志，供 logger.Recent 使用
+	Source     string               `mapstructure:"source"`      // 本进程在查看器中的来源名称，默认为主机名
+	Sources    []ViewerSourceConfig `mapstructure:"sources"`     // 额外采集的日志文件
+	History    []string             `mapstructure:"history"`     // 查询时间范围早于内存缓冲区时继续检索的本进程 JSON 日志文件，支持通配符与 .gz
+	Push       ViewerPushConfig     `mapstructure:"push"`        // 推送到远程查看器
+	Metrics    bool                 `mapstructure:"metrics"`     // 在查看器端口提供 Prometheus 的 /metrics
+	Admin      bool                 `mapstructure:"admin"`       // 在查看器端口提供 /api/levels，运行时添加自动到期的级别覆盖规则
+}
+
+// ViewerSourceConfig 查看器额外采集的日志文件（JSON格式）
+type ViewerSourceConfig struct {
+	Name string `mapstructure:"name"`
+	Path string `mapstructure:"path"`
+}
+
+// ViewerPushConfig 将本进程日志推送到中心查看器的配置
+type ViewerPushConfig struct {
+	Enabled        bool               `mapstructure:"enabled"`
+	URL            string             `mapstructure:"url"` // 中心查看器地址，如 http://logs.internal:8081
+	Username       string             `mapstructure:"username"`
+	Password       string             `mapstructure:"password"`
+	Token          string             `mapstructure:"token"`           // 中心查看器 auth.tokens 中的令牌，设置后代替用户名密码
+	SpillPath      string             `mapstructure:"spill_path"`      // 熔断或发送失败时的降级文件，为空时丢弃
+	CircuitBreaker BreakerConfig      `mapstructure:"circuit_breaker"` // 熔断配置
+	Backpressure   BackpressureConfig `mapstructure:"backpressure"`    // 发送队列溢出策略
+	Batch          BatchConfig        `mapstructure:"batch"`           // 组批阈值
+	Retry          RetryConfig        `mapstructure:"retry"`           // 发送失败重试策略
+	WriteTimeout   time.Duration      `mapstructure:"write_timeout"`   // 单次发送超时，超时后按重试策略重试；关闭时还受 Shutdown 的 ctx 限制
+	Coerce         map[string]string  `mapstructure:"coerce"`          // 同 output.file.coerce，作用于推送的记录
+}
+
+// BatchConfig 远程输出的组批配置，任一阈值达到即发送一批
+type BatchConfig struct {
+	MaxRecords  int           `mapstructure:"max_records"`  // 每批最多条数
+	MaxBytes    int           `mapstructure:"max_bytes"`    // 每批最多字节数，0表示不限制
+	MaxInterval time.Duration `mapstructure:"max_interval"` // 最长等待时间
+	Concurrency int           `mapstructure:"concurrency"`  // 同时进行的发送数
+}
+
+// RetryConfig 远程输出的重试策略，408/429/5xx及网络错误按指数退避加抖动重试
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // 最多尝试次数（含首次），1表示不重试
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"` // 首次重试前的等待
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`     // 单次等待上限
+	Multiplier     float64       `mapstructure:"multiplier"`      // 退避倍数
+	Jitter         float64       `mapstructure:"jitter"`          // 抖动比例（0~1）
 }
 
-// AuthConfig 认证配置
+// BreakerConfig 远程输出的熔断配置
+type BreakerConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold"` // 连续失败多少次后熔断
+	OpenDuration     time.Duration `mapstructure:"open_duration"`     // 熔断持续时间
+}
+
+// AuthConfig 查看器认证配置。浏览器通过登录页面获得会话 Cookie，脚本和推送端使用 Bearer 令牌或 Basic 认证
 type AuthConfig struct {
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	Username     string        `mapstructure:"username"`
+	PasswordHash string        `mapstructure:"password_hash"` // bcrypt 哈希，可用 logmiao hash-password 生成
+	Password     string        `mapstructure:"password"`      // 明文密码，仅为兼容旧配置保留，建议改用 password_hash
+	Tokens       []string      `mapstructure:"tokens"`        // API 的 Bearer 令牌
+	SessionTTL   time.Duration `mapstructure:"session_ttl"`   // 登录会话的有效期
+	MaxFailures  int           `mapstructure:"max_failures"`  // 同一客户端IP连续失败该次数后锁定
+	Lockout      time.Duration `mapstructure:"lockout"`       // 锁定时长
+	Disabled     bool          `mapstructure:"disabled"`      // 显式关闭认证，仅用于本机调试；未配置任何认证方式时查看器拒绝启动
 }
 
 // GlobalConfig 全局配置实例
 var GlobalConfig *Config
 
+// loadMu 串行化对全局 viper 实例的读写，viper 本身不是并发安全的
+var loadMu sync.Mutex
+
+// SetGlobalConfig 替换全局配置实例，与配置加载互斥
+func SetGlobalConfig(cfg *Config) {
+	loadMu.Lock()
+	GlobalConfig = cfg
+	loadMu.Unlock()
+}
+
 // LoadConfig 从指定的路径加载配置
 func LoadConfig(path string) (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	return loadConfig(path)
+}
+
+func loadConfig(path string) (*Config, error) {
 	viper.SetConfigName("logger")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(".")
@@ -111,111 +538,213 @@ func LoadConfig(path string) (*Config, error) {
 		}
 	}
 
+	var source string
+	var includes []string
+	if used := viper.ConfigFileUsed(); used != "" {
+		if _, err := os.Stat(used); err == nil {
+			source, _ = filepath.Abs(used)
+			if includes, err = mergeIncludes(viper.GetViper(), source); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("解析配置失败: %w", err)
 	}
+	config.Source, config.Includes = source, includes
 
 	GlobalConfig = &config
 	return &config, nil
 }
 
+// defaultConfig 返回只包含默认值的配置，与 setDefaultsOn 保持一致
+func defaultConfig() *Config {
+	v := viper.New()
+	setDefaultsOn(v)
+	var config Config
+	_ = v.Unmarshal(&config) // 默认值均为合法类型，不会解析失败
+	return &config
+}
+
 // setDefaults 设置默认配置值
 func setDefaults() {
+	setDefaultsOn(viper.GetViper())
+}
+
+// setDefaultsOn 在指定的 viper 实例上设置默认配置值
+func setDefaultsOn(v *viper.Viper) {
 	// 日志级别和格式
-	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.format", "color")
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.format", "color")
+	v.SetDefault("logger.hot_reload", false)
 
 	// 控制台输出
-	viper.SetDefault("logger.output.console.enabled", true)
-	viper.SetDefault("logger.output.console.format", "color")
+	v.SetDefault("logger.output.console.enabled", true)
+	v.SetDefault("logger.output.console.format", "color")
+	v.SetDefault("logger.output.console.source", "short")
+	v.SetDefault("logger.output.console.pretty_json", true)
+	v.SetDefault("logger.output.console.width", 0)
+	v.SetDefault("logger.output.console.level_theme", "default")
 
 	// 文件输出
-	viper.SetDefault("logger.output.file.enabled", true)
-	viper.SetDefault("logger.output.file.path", "logs/app.log")
-	viper.SetDefault("logger.output.file.format", "json")
-	viper.SetDefault("logger.output.file.rotation.max_size", 10)
-	viper.SetDefault("logger.output.file.rotation.max_backups", 5)
-	viper.SetDefault("logger.output.file.rotation.max_age", 30)
-	viper.SetDefault("logger.output.file.rotation.compress", true)
+	v.SetDefault("logger.output.file.enabled", true)
+	v.SetDefault("logger.output.file.path", "logs/app.log")
+	v.SetDefault("logger.output.file.format", "json")
+	v.SetDefault("logger.output.file.source", "full")
+	v.SetDefault("logger.output.file.rotation.max_size", 10)
+	v.SetDefault("logger.output.file.rotation.max_backups", 5)
+	v.SetDefault("logger.output.file.rotation.max_age", 30)
+	v.SetDefault("logger.output.file.rotation.compress", true)
+	v.SetDefault("logger.output.file.shared", false)
+
+	// JSON记录信封
+	v.SetDefault("logger.output.envelope.enabled", false)
+
+	// 访问日志通道
+	v.SetDefault("logger.channels.access.enabled", false)
+	v.SetDefault("logger.channels.access.output.console.enabled", false)
+	v.SetDefault("logger.channels.access.output.file.enabled", true)
+	v.SetDefault("logger.channels.access.output.file.path", "logs/access.log")
+	v.SetDefault("logger.channels.access.output.file.format", "json")
+	v.SetDefault("logger.channels.access.output.file.rotation.max_size", 100)
+	v.SetDefault("logger.channels.access.output.file.rotation.max_backups", 7)
+	v.SetDefault("logger.channels.access.output.file.rotation.max_age", 14)
+	v.SetDefault("logger.channels.access.output.file.rotation.compress", true)
+	v.SetDefault("logger.channels.access.sample_rate", 1.0)
+
+	// 异步写出
+	v.SetDefault("logger.output.async.enabled", false)
+	v.SetDefault("logger.output.async.queue_size", 4096)
+	v.SetDefault("logger.output.async.policy", "block")
+	v.SetDefault("logger.output.async.sample_rate", 10)
+	v.SetDefault("logger.output.async.drop_report_interval", "1m")
+	v.SetDefault("logger.shutdown_timeout", "5s")
+	v.SetDefault("logger.max_record_size", 1<<20)
+	v.SetDefault("logger.structured_banner", false)
+	v.SetDefault("logger.output.latency.budget", "0")
+	v.SetDefault("logger.output.latency.strikes", 3)
+	v.SetDefault("logger.output.latency.action", "async")
+	v.SetDefault("logger.output.latency.queue_size", 1024)
+	v.SetDefault("logger.output.latency.open_duration", "30s")
+
+	v.SetDefault("logger.warmup.mode", "off")
+	v.SetDefault("logger.warmup.timeout", "5s")
 
 	// 功能配置
-	viper.SetDefault("logger.features.smart_filter", true)
-	viper.SetDefault("logger.features.keyword_highlight", true)
-	viper.SetDefault("logger.features.auto_sampling", false)
-	viper.SetDefault("logger.features.performance_tracking", true)
+	v.SetDefault("logger.features.smart_filter", true)
+	v.SetDefault("logger.features.smart_filters", []string{"gin_debug", "health_check"})
+	v.SetDefault("logger.features.keyword_highlight", true)
+	v.SetDefault("logger.features.auto_sampling", false)
+	v.SetDefault("logger.features.performance_tracking", true)
+	v.SetDefault("logger.features.slow_span_threshold", "500ms")
 
 	// 隐私脱敏配置 - 默认全部关闭
-	viper.SetDefault("logger.features.privacy.enable_email_mask", false)
-	viper.SetDefault("logger.features.privacy.enable_phone_mask", false)
-	viper.SetDefault("logger.features.privacy.enable_input_sanitize", false)
+	v.SetDefault("logger.features.privacy.enable_email_mask", false)
+	v.SetDefault("logger.features.privacy.enable_phone_mask", false)
+	v.SetDefault("logger.features.privacy.enable_input_sanitize", false)
+	v.SetDefault("logger.features.privacy.enable_session_mask", true)
+	v.SetDefault("logger.features.privacy.redact_keys", []string{})
+	v.SetDefault("logger.features.privacy.scrub", []string{})
+	v.SetDefault("logger.features.privacy.scrub_patterns", []string{})
+
+	// 定向调试配置
+	v.SetDefault("logger.features.debug_targeting.enabled", false)
+	v.SetDefault("logger.features.debug_targeting.header", "X-Debug-Log")
+
+	// Baggage 传播配置
+	v.SetDefault("logger.features.baggage.enabled", false)
+
+	// 错误率看门狗配置
+	v.SetDefault("logger.features.error_watchdog.enabled", false)
+	v.SetDefault("logger.features.error_watchdog.window", "1m")
+	v.SetDefault("logger.features.error_watchdog.threshold", 50)
+	v.SetDefault("logger.features.error_watchdog.cooldown", "5m")
+	v.SetDefault("logger.features.error_stacks", false)
+	v.SetDefault("logger.features.msg_template", false)
+	v.SetDefault("logger.features.sampling.tick", "1s")
+	v.SetDefault("logger.features.sampling.first", 100)
+	v.SetDefault("logger.features.sampling.thereafter", 100)
+	v.SetDefault("logger.features.burst.enabled", false)
+	v.SetDefault("logger.features.burst.threshold", 1000)
+	v.SetDefault("logger.features.burst.window", "30s")
+	v.SetDefault("logger.features.novel_errors.enabled", false)
+	v.SetDefault("logger.features.novel_errors.capacity", 10000)
+	v.SetDefault("logger.features.novel_errors.warmup", "1m")
+	v.SetDefault("logger.features.slo.enabled", false)
+	v.SetDefault("logger.features.slo.window", "1h")
+	v.SetDefault("logger.features.slo.objective", 0.999)
+	v.SetDefault("logger.features.slo.summary_interval", "5m")
 
 	// 中间件配置
-	viper.SetDefault("logger.middleware.log_body", true)
-	viper.SetDefault("logger.middleware.log_headers", false)
-	viper.SetDefault("logger.middleware.max_body_size", 2048)
+	v.SetDefault("logger.middleware.log_body", true)
+	v.SetDefault("logger.middleware.log_headers", false)
+	v.SetDefault("logger.middleware.max_body_size", 2048)
+	v.SetDefault("logger.middleware.capture_gin_output", false)
+	v.SetDefault("logger.middleware.phase_timings", false)
+	v.SetDefault("logger.middleware.conn_info", false)
+	v.SetDefault("logger.middleware.context_attrs", []map[string]any{{"key": "cache_status", "attr": "cache"}})
+	v.SetDefault("logger.middleware.sampling.rate", 1)
+	v.SetDefault("logger.middleware.health_checks.mode", "drop")
+	v.SetDefault("logger.middleware.request_id.format", "default")
+	v.SetDefault("logger.middleware.request_id.headers", []string{"X-Request-ID"})
+	v.SetDefault("logger.middleware.request_id.response_header", "X-Request-ID")
+	v.SetDefault("logger.middleware.request_id.response", "always")
+	v.SetDefault("logger.middleware.health_checks.interval", "1m")
+	v.SetDefault("logger.middleware.sampling.slow_threshold", "1s")
+	v.SetDefault("logger.middleware.tenant.header", "X-Tenant-ID")
+	v.SetDefault("logger.middleware.session.cookie", "session_id")
+	v.SetDefault("logger.middleware.session.header", "X-Session-ID")
+	v.SetDefault("logger.middleware.session.idle_timeout", "30m")
+	v.SetDefault("logger.middleware.audit.methods", []string{"POST", "PUT", "PATCH", "DELETE"})
+	v.SetDefault("logger.middleware.incident.enabled", false)
+	v.SetDefault("logger.middleware.incident.dir", "logs/incidents")
+	v.SetDefault("logger.middleware.incident.ring_size", 100)
+	v.SetDefault("logger.middleware.incident.max_body_size", 65536)
+	v.SetDefault("logger.middleware.har.dir", "logs/har")
+	v.SetDefault("logger.middleware.har.statuses", []string{"5xx"})
+	v.SetDefault("logger.middleware.har.max_entries", 100)
+	v.SetDefault("logger.middleware.har.max_body_size", 65536)
 
 	// Web查看器配置
-	viper.SetDefault("logger.viewer.enabled", false)
-	viper.SetDefault("logger.viewer.port", 8081)
-	viper.SetDefault("logger.viewer.auth.username", "admin")
-	viper.SetDefault("logger.viewer.auth.password", "secret")
+	v.SetDefault("logger.viewer.enabled", false)
+	v.SetDefault("logger.viewer.port", 8081)
+	v.SetDefault("logger.viewer.auth.session_ttl", "12h")
+	v.SetDefault("logger.viewer.auth.max_failures", 5)
+	v.SetDefault("logger.viewer.auth.lockout", "15m")
+	v.SetDefault("logger.viewer.buffer_size", 5000)
+	v.SetDefault("logger.viewer.recent", false)
+	v.SetDefault("logger.viewer.metrics", false)
+	v.SetDefault("logger.viewer.admin", false)
+	v.SetDefault("logger.viewer.push.enabled", false)
+	v.SetDefault("logger.viewer.push.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("logger.viewer.push.circuit_breaker.open_duration", "30s")
+	v.SetDefault("logger.viewer.push.backpressure.queue_size", 1024)
+	v.SetDefault("logger.viewer.push.backpressure.policy", "drop_newest")
+	v.SetDefault("logger.viewer.push.batch.max_records", 100)
+	v.SetDefault("logger.viewer.push.batch.max_bytes", 1<<20)
+	v.SetDefault("logger.viewer.push.batch.max_interval", "1s")
+	v.SetDefault("logger.viewer.push.batch.concurrency", 1)
+	v.SetDefault("logger.viewer.push.write_timeout", "5s")
+	v.SetDefault("logger.viewer.push.retry.max_attempts", 3)
+	v.SetDefault("logger.viewer.push.retry.initial_backoff", "200ms")
+	v.SetDefault("logger.viewer.push.retry.max_backoff", "10s")
+	v.SetDefault("logger.viewer.push.retry.multiplier", 2.0)
+	v.SetDefault("logger.viewer.push.retry.jitter", 0.2)
+
+	// 网络传输默认配置
+	v.SetDefault("logger.transport.timeout", "5s")
 }
 
 // LoadConfigWithDefaults 加载配置，如果文件不存在则使用默认配置
 func LoadConfigWithDefaults(path string) *Config {
-	config, err := LoadConfig(path)
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	config, err := loadConfig(path)
 	if err != nil {
 		fmt.Printf("使用默认配置: %v\n", err)
-		// 返回默认配置
-		setDefaults()
-		config = &Config{
-			Logger: LoggerConfig{
-				Level:  viper.GetString("logger.level"),
-				Format: viper.GetString("logger.format"),
-				Output: OutputConfig{
-					Console: ConsoleConfig{
-						Enabled: viper.GetBool("logger.output.console.enabled"),
-						Format:  viper.GetString("logger.output.console.format"),
-					},
-					File: FileConfig{
-						Enabled: viper.GetBool("logger.output.file.enabled"),
-						Path:    viper.GetString("logger.output.file.path"),
-						Format:  viper.GetString("logger.output.file.format"),
-						Rotation: RotationConfig{
-							MaxSize:    viper.GetInt("logger.output.file.rotation.max_size"),
-							MaxBackups: viper.GetInt("logger.output.file.rotation.max_backups"),
-							MaxAge:     viper.GetInt("logger.output.file.rotation.max_age"),
-							Compress:   viper.GetBool("logger.output.file.rotation.compress"),
-						},
-					},
-				},
-				Features: FeaturesConfig{
-					SmartFilter:         viper.GetBool("logger.features.smart_filter"),
-					KeywordHighlight:    viper.GetBool("logger.features.keyword_highlight"),
-					AutoSampling:        viper.GetBool("logger.features.auto_sampling"),
-					PerformanceTracking: viper.GetBool("logger.features.performance_tracking"),
-					Privacy: PrivacyConfig{
-						EnableEmailMask:     viper.GetBool("logger.features.privacy.enable_email_mask"),
-						EnablePhoneMask:     viper.GetBool("logger.features.privacy.enable_phone_mask"),
-						EnableInputSanitize: viper.GetBool("logger.features.privacy.enable_input_sanitize"),
-					},
-				},
-				Middleware: MiddlewareConfig{
-					LogBody:     viper.GetBool("logger.middleware.log_body"),
-					LogHeaders:  viper.GetBool("logger.middleware.log_headers"),
-					MaxBodySize: viper.GetInt("logger.middleware.max_body_size"),
-				},
-				Viewer: ViewerConfig{
-					Enabled: viper.GetBool("logger.viewer.enabled"),
-					Port:    viper.GetInt("logger.viewer.port"),
-					Auth: AuthConfig{
-						Username: viper.GetString("logger.viewer.auth.username"),
-						Password: viper.GetString("logger.viewer.auth.password"),
-					},
-				},
-			},
-		}
+		config = defaultConfig()
 		GlobalConfig = config
 	}
 	return config
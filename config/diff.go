@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Change 两份配置之间的一处差异，Path 为配置文件中的键路径，如 logger.level
+type Change struct {
+	Path string `json:"path"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// String 返回 path: old -> new 形式的描述
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// sensitiveKeys 键名包含这些词的配置只报告“已变更”，不输出取值
+var sensitiveKeys = []string{"password", "secret", "token"}
+
+// Diff 比较两份配置，按字段顺序返回所有变化的叶子键；old 为nil时视为全部为零值
+func Diff(old, new *Config) []Change {
+	if old == nil {
+		old = &Config{}
+	}
+	if new == nil {
+		new = &Config{}
+	}
+	var changes []Change
+	diffValue("", reflect.ValueOf(*old), reflect.ValueOf(*new), &changes)
+	return changes
+}
+
+// diffValue 递归比较结构体字段，非结构体字段按值整体比较
+func diffValue(path string, a, b reflect.Value, changes *[]Change) {
+	if a.Kind() == reflect.Struct && a.Type().NumField() > 0 && a.Type().PkgPath() == reflect.TypeOf(Config{}).PkgPath() {
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+			if name == "-" {
+				continue // 只能在代码中设置的字段（如输出目标）不参与比较
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			diffValue(name, a.Field(i), b.Field(i), changes)
+		}
+		return
+	}
+
+	if equalValue(a, b) {
+		return
+	}
+	change := Change{Path: path, Old: formatValue(a), New: formatValue(b)}
+	for _, key := range sensitiveKeys {
+		if strings.Contains(strings.ToLower(path), key) {
+			change.Old, change.New = "***", "***"
+		}
+	}
+	*changes = append(*changes, change)
+}
+
+// equalValue 按值比较配置字段，nil 与空的切片、映射视为相同（未配置与配置为 [] 没有区别）
+func equalValue(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// formatValue 将配置值格式化为便于阅读的字符串
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+// TestDiff 测试按键路径报告变化并隐藏敏感取值
+func TestDiff(t *testing.T) {
+	old := &Config{}
+	old.Logger.Level = "info"
+	old.Logger.Viewer.Auth.Password = "a"
+
+	updated := *old
+	updated.Logger.Level = "debug"
+	updated.Logger.Output.File.Enabled = true
+	updated.Logger.Viewer.Auth.Password = "b"
+
+	changes := Diff(old, &updated)
+	got := make(map[string]Change, len(changes))
+	for _, c := range changes {
+		got[c.Path] = c
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %v", changes)
+	}
+	if c := got["logger.level"]; c.Old != `"info"` || c.New != `"debug"` {
+		t.Errorf("logger.level change = %+v", c)
+	}
+	if c := got["logger.output.file.enabled"]; c.Old != "false" || c.New != "true" {
+		t.Errorf("file toggle change = %+v", c)
+	}
+	if c := got["logger.viewer.auth.password"]; c.New != "***" {
+		t.Errorf("password should be redacted, got %+v", c)
+	}
+	if Diff(old, old) != nil {
+		t.Error("identical configs should have no changes")
+	}
+
+	empty := *old
+	empty.Logger.Transforms = []TransformConfig{}
+	empty.Logger.Levels = map[string]string{}
+	if changes := Diff(old, &empty); changes != nil {
+		t.Errorf("nil and empty collections should be equal, got %v", changes)
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// includeKey 配置文件中引用共享片段的顶层键
+const includeKey = "include"
+
+// mergeIncludes 将配置文件 include 引用的片段合并到 v 中：片段按声明顺序依次合并，
+// 当前文件的内容最后合并，覆盖片段中的同名项。片段可以继续 include，路径相对于引用它的文件，
+// 支持 conf.d/*.yaml 形式的通配符。返回参与合并的片段绝对路径
+func mergeIncludes(v *viper.Viper, path string) ([]string, error) {
+	if len(v.GetStringSlice(includeKey)) == 0 {
+		return nil, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := viper.New()
+	var files []string
+	if err := mergeIncludeFile(merged, abs, map[string]bool{}, &files); err != nil {
+		return nil, err
+	}
+	settings := merged.AllSettings()
+	delete(settings, includeKey)
+	if err := v.MergeConfigMap(settings); err != nil {
+		return nil, fmt.Errorf("合并配置片段失败: %w", err)
+	}
+	// 最后一项是当前文件自身
+	return files[:len(files)-1], nil
+}
+
+// mergeIncludeFile 先合并 path 引用的片段，再合并 path 自身；stack 用于检测循环引用
+func mergeIncludeFile(target *viper.Viper, path string, stack map[string]bool, files *[]string) error {
+	if stack[path] {
+		return fmt.Errorf("配置文件循环引用: %s", path)
+	}
+	stack[path] = true
+	defer delete(stack, path)
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("读取配置片段失败: %w", err)
+	}
+
+	for _, pattern := range v.GetStringSlice(includeKey) {
+		includes, err := resolveInclude(filepath.Dir(path), pattern)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, include := range includes {
+			if err := mergeIncludeFile(target, include, stack, files); err != nil {
+				return err
+			}
+		}
+	}
+
+	settings := v.AllSettings()
+	delete(settings, includeKey)
+	if err := target.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("合并配置片段失败: %w", err)
+	}
+	*files = append(*files, path)
+	return nil
+}
+
+// resolveInclude 将 include 项解析为绝对路径；通配符没有匹配项时忽略，普通路径不存在时由读取报错
+func resolveInclude(dir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("无效的 include 路径 %q: %w", pattern, err)
+	}
+	return matches, nil
+}
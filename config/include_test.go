@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigInclude 测试 include 片段的合并顺序、相对路径、通配符与循环引用检测
+func TestLoadConfigInclude(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	write("shared/base.yaml", `
+include: ["rotation.yaml"]
+logger:
+  level: warn
+  viewer:
+    enabled: true
+    port: 9090
+`)
+	write("shared/rotation.yaml", `
+logger:
+  output:
+    file:
+      rotation:
+        max_size: 100
+        max_backups: 20
+`)
+	write("conf.d/10-feature.yaml", "logger:\n  features:\n    slo:\n      enabled: true\n")
+	service := write("logger.yaml", `
+include:
+  - shared/base.yaml
+  - conf.d/*.yaml
+logger:
+  level: debug
+  output:
+    file:
+      rotation:
+        max_backups: 3
+`)
+
+	cfg, err := LoadConfig(service)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logger.Level != "debug" {
+		t.Errorf("service file should override included level, got %q", cfg.Logger.Level)
+	}
+	if !cfg.Logger.Viewer.Enabled || cfg.Logger.Viewer.Port != 9090 {
+		t.Errorf("viewer block should come from the shared fragment, got %+v", cfg.Logger.Viewer)
+	}
+	if r := cfg.Logger.Output.File.Rotation; r.MaxSize != 100 || r.MaxBackups != 3 || r.MaxAge != 30 {
+		t.Errorf("rotation = %+v, want nested include merged with override and defaults", r)
+	}
+	if !cfg.Logger.Features.SLO.Enabled {
+		t.Error("glob include should be merged")
+	}
+	if len(cfg.Includes) != 3 || !strings.HasSuffix(cfg.Includes[0], "rotation.yaml") {
+		t.Errorf("Includes = %v", cfg.Includes)
+	}
+
+	write("loop.yaml", "include: [logger-loop.yaml]\n")
+	loop := write("logger-loop.yaml", "include: [loop.yaml]\n")
+	if _, err := LoadConfig(loop); err == nil || !strings.Contains(err.Error(), "循环引用") {
+		t.Errorf("expected include cycle error, got %v", err)
+	}
+	if _, err := LoadConfig(write("missing.yaml", "include: [nope.yaml]\n")); err == nil {
+		t.Error("missing include should fail")
+	}
+}
+
+// TestLoadConfigWithDefaultsFallback 测试配置文件格式错误时回退的配置包含全部默认值
+func TestLoadConfigWithDefaultsFallback(t *testing.T) {
+	prev := GlobalConfig
+	t.Cleanup(func() { SetGlobalConfig(prev) })
+
+	path := filepath.Join(t.TempDir(), "logger.yaml")
+	if err := os.WriteFile(path, []byte("logger: [level\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want, err := parseConfig(nil, "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadConfigWithDefaults(path); !reflect.DeepEqual(got, want) {
+		t.Errorf("fallback config should match the defaults:\ngot  %+v\nwant %+v", got.Logger, want.Logger)
+	}
+}
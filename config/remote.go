@@ -0,0 +1,271 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultRemoteInterval 远程配置默认轮询间隔
+const DefaultRemoteInterval = 30 * time.Second
+
+// RemoteSource 远程配置来源，返回配置内容和格式（yaml/json）
+type RemoteSource interface {
+	Fetch(ctx context.Context) (data []byte, format string, err error)
+}
+
+// HTTPSource 通过 HTTP(S) 地址获取配置文件
+type HTTPSource struct {
+	URL    string
+	Format string      // yaml 或 json，为空时根据扩展名和Content-Type推断
+	Header http.Header // 额外请求头，如认证信息
+	Client *http.Client
+}
+
+// Fetch 获取配置内容
+func (s *HTTPSource) Fetch(ctx context.Context) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for key, values := range s.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := httpClient(s.Client).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("获取远程配置失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("获取远程配置失败: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("读取远程配置失败: %w", err)
+	}
+
+	format := s.Format
+	if format == "" {
+		format = detectFormat(s.URL, resp.Header.Get("Content-Type"))
+	}
+	return data, format, nil
+}
+
+// ConsulSource 从 Consul KV 获取配置
+type ConsulSource struct {
+	Address string // 如 http://127.0.0.1:8500
+	Key     string // 如 config/my-service/logger.yaml
+	Token   string
+	Format  string
+	Client  *http.Client
+}
+
+// Fetch 获取配置内容
+func (s *ConsulSource) Fetch(ctx context.Context) ([]byte, string, error) {
+	src := &HTTPSource{
+		URL:    strings.TrimRight(s.Address, "/") + "/v1/kv/" + strings.TrimLeft(s.Key, "/") + "?raw",
+		Format: s.Format,
+		Client: s.Client,
+	}
+	if s.Token != "" {
+		src.Header = http.Header{"X-Consul-Token": []string{s.Token}}
+	}
+	if src.Format == "" {
+		src.Format = detectFormat(s.Key, "")
+	}
+	return src.Fetch(ctx)
+}
+
+// EtcdSource 通过 etcd v3 的 HTTP 网关获取配置
+type EtcdSource struct {
+	Endpoint string // 如 http://127.0.0.1:2379
+	Key      string
+	Format   string
+	Client   *http.Client
+}
+
+// Fetch 获取配置内容
+func (s *EtcdSource) Fetch(ctx context.Context) ([]byte, string, error) {
+	payload, _ := json.Marshal(map[string]string{
+		"key": base64.StdEncoding.EncodeToString([]byte(s.Key)),
+	})
+
+	url := strings.TrimRight(s.Endpoint, "/") + "/v3/kv/range"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient(s.Client).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("获取etcd配置失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("获取etcd配置失败: HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Kvs []struct {
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, "", fmt.Errorf("解析etcd响应失败: %w", err)
+	}
+	if len(result.Kvs) == 0 {
+		return nil, "", fmt.Errorf("etcd中不存在配置: %s", s.Key)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.Kvs[0].Value)
+	if err != nil {
+		return nil, "", fmt.Errorf("解码etcd配置失败: %w", err)
+	}
+
+	format := s.Format
+	if format == "" {
+		format = detectFormat(s.Key, "")
+	}
+	return data, format, nil
+}
+
+// IsRemotePath 判断配置路径是否是远程地址
+func IsRemotePath(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// LoadRemoteConfig 从远程来源加载配置，不修改 GlobalConfig
+func LoadRemoteConfig(ctx context.Context, src RemoteSource) (*Config, error) {
+	data, format, err := src.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseConfig(data, format)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Source = SourceName(src)
+	return cfg, nil
+}
+
+// SourceName 返回远程来源的描述，用于启动报告；HTTP 地址去掉查询参数，避免记录其中的令牌
+func SourceName(src RemoteSource) string {
+	switch s := src.(type) {
+	case *HTTPSource:
+		if u, err := url.Parse(s.URL); err == nil {
+			u.RawQuery, u.User = "", nil
+			return u.String()
+		}
+		return "http"
+	case *ConsulSource:
+		return "consul:" + s.Key
+	case *EtcdSource:
+		return "etcd:" + s.Key
+	case fmt.Stringer:
+		return s.String()
+	}
+	return fmt.Sprintf("%T", src)
+}
+
+// WatchRemote 定期轮询远程配置，内容变化时调用 onChange，直到 ctx 结束
+func WatchRemote(ctx context.Context, src RemoteSource, interval time.Duration, onChange func(*Config)) {
+	if interval <= 0 {
+		interval = DefaultRemoteInterval
+	}
+
+	var lastSum [sha256.Size]byte
+	if data, _, err := src.Fetch(ctx); err == nil {
+		lastSum = sha256.Sum256(data)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		data, format, err := src.Fetch(ctx)
+		if err != nil {
+			continue // 远程暂时不可用时保持当前配置
+		}
+
+		sum := sha256.Sum256(data)
+		if sum == lastSum {
+			continue
+		}
+
+		cfg, err := parseConfig(data, format)
+		if err != nil {
+			continue // 配置内容无效时保持当前配置
+		}
+		cfg.Source = SourceName(src)
+		lastSum = sum
+		onChange(cfg)
+	}
+}
+
+// parseConfig 解析配置内容，未设置的项使用默认值；不修改 GlobalConfig，由应用配置的一方设置
+func parseConfig(data []byte, format string) (*Config, error) {
+	if format == "" {
+		format = "yaml"
+	}
+
+	v := viper.New()
+	v.SetConfigType(format)
+	setDefaultsOn(v)
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("读取配置失败: %w", err)
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+
+	return &config, nil
+}
+
+// detectFormat 根据路径扩展名和Content-Type推断配置格式
+func detectFormat(path, contentType string) string {
+	if strings.Contains(contentType, "json") {
+		return "json"
+	}
+	base := path
+	if idx := strings.Index(base, "?"); idx != -1 {
+		base = base[:idx]
+	}
+	if strings.HasSuffix(base, ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// httpClient 返回可用的HTTP客户端
+func httpClient(c *http.Client) *http.Client {
+	if c != nil {
+		return c
+	}
+	return &http.Client{Timeout: 10 * time.Second}
+}
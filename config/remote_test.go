@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestLoadRemoteConfig 测试从HTTP和etcd网关加载配置
+func TestLoadRemoteConfig(t *testing.T) {
+	yaml := "logger:\n  level: debug\n  output:\n    file:\n      enabled: false\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/logger.yaml":
+			_, _ = w.Write([]byte(yaml))
+		case "/v3/kv/range":
+			encoded := base64.StdEncoding.EncodeToString([]byte(yaml))
+			_, _ = w.Write([]byte(`{"kvs":[{"value":"` + encoded + `"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sources := map[string]RemoteSource{
+		"http": &HTTPSource{URL: server.URL + "/logger.yaml"},
+		"etcd": &EtcdSource{Endpoint: server.URL, Key: "/services/api/logger.yaml"},
+	}
+
+	prev := GlobalConfig
+	t.Cleanup(func() { GlobalConfig = prev })
+	GlobalConfig = nil
+
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := LoadRemoteConfig(context.Background(), src)
+			if err != nil {
+				t.Fatalf("LoadRemoteConfig failed: %v", err)
+			}
+			if cfg.Logger.Level != "debug" {
+				t.Errorf("expected level debug, got %s", cfg.Logger.Level)
+			}
+			if cfg.Source != SourceName(src) || cfg.Source == "" {
+				t.Errorf("expected source %q, got %q", SourceName(src), cfg.Source)
+			}
+			if cfg.Logger.Output.File.Enabled {
+				t.Error("file output should be disabled by remote config")
+			}
+			// 未设置的项应使用默认值
+			if !cfg.Logger.Output.Console.Enabled {
+				t.Error("console output should fall back to default")
+			}
+			if GlobalConfig != nil {
+				t.Error("loading a remote config should not replace GlobalConfig")
+			}
+		})
+	}
+
+	if _, err := LoadRemoteConfig(context.Background(), &HTTPSource{URL: server.URL + "/missing"}); err == nil {
+		t.Error("expected error for missing remote config")
+	}
+}
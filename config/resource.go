@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// OpenTelemetry Resource 语义约定的属性键
+const (
+	ResourceServiceName    = "service.name"
+	ResourceServiceVersion = "service.version"
+	ResourceEnvironment    = "deployment.environment"
+)
+
+// ResourceConfig OpenTelemetry Resource 属性配置，所有机器可读的输出都会附带这些属性
+type ResourceConfig struct {
+	ServiceName    string `mapstructure:"service_name"`
+	ServiceVersion string `mapstructure:"service_version"`
+	Environment    string `mapstructure:"environment"`
+	Attributes     string `mapstructure:"attributes"` // 其他 Resource 属性，格式同 OTEL_RESOURCE_ATTRIBUTES: "k1=v1,k2=v2"
+}
+
+// Resolve 返回合并后的 Resource 属性。优先级：配置文件 > OTEL_SERVICE_NAME > OTEL_RESOURCE_ATTRIBUTES
+func (r ResourceConfig) Resolve() map[string]string {
+	attrs := make(map[string]string)
+
+	// 兼容 OpenTelemetry SDK 的标准环境变量
+	parseResourcePairs(attrs, os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		attrs[ResourceServiceName] = name
+	}
+
+	parseResourcePairs(attrs, r.Attributes)
+	if r.ServiceName != "" {
+		attrs[ResourceServiceName] = r.ServiceName
+	}
+	if r.ServiceVersion != "" {
+		attrs[ResourceServiceVersion] = r.ServiceVersion
+	}
+	if r.Environment != "" {
+		attrs[ResourceEnvironment] = r.Environment
+	}
+	return attrs
+}
+
+// parseResourcePairs 解析 "k1=v1,k2=v2" 格式的属性列表
+func parseResourcePairs(attrs map[string]string, s string) {
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(key) != "" {
+			attrs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// SortedKeys 返回排序后的属性键，保证输出顺序稳定
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package config
+
+import "testing"
+
+// TestResourceResolve 测试 Resource 属性的来源优先级：配置文件 > OTEL_SERVICE_NAME > OTEL_RESOURCE_ATTRIBUTES
+func TestResourceResolve(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.name=from-attrs, host.name = web-1 ,deployment.environment=staging,broken")
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+
+	attrs := ResourceConfig{}.Resolve()
+	want := map[string]string{
+		ResourceServiceName: "from-env",
+		ResourceEnvironment: "staging",
+		"host.name":         "web-1",
+	}
+	if len(attrs) != len(want) {
+		t.Errorf("unexpected attrs from env: %v", attrs)
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("%s = %q, want %q", key, attrs[key], value)
+		}
+	}
+
+	attrs = ResourceConfig{
+		ServiceName: "api",
+		Environment: "prod",
+		Attributes:  "host.name=web-2,team=payments",
+	}.Resolve()
+	want = map[string]string{
+		ResourceServiceName: "api",
+		ResourceEnvironment: "prod",
+		"host.name":         "web-2",
+		"team":              "payments",
+	}
+	for key, value := range want {
+		if attrs[key] != value {
+			t.Errorf("config should override env: %s = %q, want %q", key, attrs[key], value)
+		}
+	}
+	if _, ok := attrs[ResourceServiceVersion]; ok {
+		t.Errorf("unset service.version should not be added: %v", attrs)
+	}
+}
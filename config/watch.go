@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce 文件事件的合并间隔，编辑器保存时通常连续产生多个事件
+const watchDebounce = 100 * time.Millisecond
+
+// WatchFile 监听 cfg 加载自的配置文件及其 include 片段，内容变化时重新加载并调用 onChange；
+// 新配置读取或解析失败时调用 onError 并保持当前配置。监听所在目录而非文件本身，
+// 以兼容编辑器的原子保存和 Kubernetes ConfigMap 的符号链接替换。阻塞直到 ctx 结束
+func WatchFile(ctx context.Context, cfg *Config, onChange func(*Config), onError func(error)) error {
+	if cfg.Source == "" {
+		return errors.New("配置未从文件加载，无法监听")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	files := watchedFiles(cfg)
+	if err := watchDirs(watcher, files); err != nil {
+		return err
+	}
+	lastSum := filesSum(files)
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			onError(err)
+		case <-debounce.C:
+			sum := filesSum(files)
+			if sum == lastSum {
+				continue
+			}
+			newCfg, err := LoadConfig(cfg.Source)
+			if err != nil {
+				onError(err)
+				continue
+			}
+			// include 列表可能随之变化，重新确定监听范围
+			files = watchedFiles(newCfg)
+			if err := watchDirs(watcher, files); err != nil {
+				onError(err)
+			}
+			lastSum = filesSum(files)
+			onChange(newCfg)
+		}
+	}
+}
+
+// watchedFiles 返回配置文件及其 include 片段
+func watchedFiles(cfg *Config) []string {
+	return append([]string{cfg.Source}, cfg.Includes...)
+}
+
+// watchDirs 监听文件所在的目录，重复添加同一目录无副作用
+func watchDirs(watcher *fsnotify.Watcher, files []string) error {
+	for _, file := range files {
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filesSum 计算文件内容的摘要，用于忽略内容未变化的事件；读取失败的文件按空内容计算
+func filesSum(files []string) [sha256.Size]byte {
+	h := sha256.New()
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		h.Write([]byte(file))
+		h.Write(data)
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatchFile 测试配置文件及 include 片段修改后重新加载，无效内容时报告错误并保持监听
+func TestWatchFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	path := filepath.Join(dir, "logger.yaml")
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(base, "logger:\n  format: json\n")
+	write(path, "include: [base.yaml]\nlogger:\n  level: info\n")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan *Config, 4)
+	errs := make(chan error, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchFile(ctx, cfg, func(c *Config) { changes <- c }, func(err error) { errs <- err })
+	}()
+	time.Sleep(50 * time.Millisecond) // 等待监听建立
+
+	write(path, "include: [base.yaml]\nlogger:\n  level: debug\n")
+	select {
+	case c := <-changes:
+		if c.Logger.Level != "debug" || c.Logger.Format != "json" {
+			t.Errorf("reloaded config = %s/%s", c.Logger.Level, c.Logger.Format)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("change to config file was not reloaded")
+	}
+
+	write(base, "logger:\n  format: text\n")
+	select {
+	case c := <-changes:
+		if c.Logger.Format != "text" {
+			t.Errorf("include change should be reloaded, format = %s", c.Logger.Format)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("change to included fragment was not reloaded")
+	}
+
+	write(path, "logger: [broken\n")
+	select {
+	case <-errs:
+	case c := <-changes:
+		t.Fatalf("invalid config should not be applied, got %+v", c.Logger)
+	case <-time.After(2 * time.Second):
+		t.Fatal("invalid config should be reported")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+package demo
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// UserID 随机用户ID，如 user_a1B2c3D4
+func UserID() string {
+	return "user_" + RandomString(8)
+}
+
+// Action 随机的用户操作
+func Action() string {
+	return pick("login", "logout", "view", "edit", "delete", "create", "update")
+}
+
+// Resource 随机的资源类型
+func Resource() string {
+	return pick("user", "post", "comment", "file", "setting", "profile")
+}
+
+// IP 随机的IPv4地址
+func IP() string {
+	return fmt.Sprintf("%d.%d.%d.%d", rand.IntN(256), rand.IntN(256), rand.IntN(256), rand.IntN(256))
+}
+
+// UserAgent 随机的浏览器 User-Agent
+func UserAgent() string {
+	return pick(
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/91.0.4472.124",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) Safari/537.36",
+		"Mozilla/5.0 (X11; Linux x86_64) Firefox/89.0",
+	)
+}
+
+// SessionID 随机会话ID
+func SessionID() string {
+	return "sess_" + RandomString(16)
+}
+
+// Route 随机的 API 路由及请求方法
+func Route() (method, path string) {
+	routes := [][2]string{
+		{"GET", "/api/v1/users"},
+		{"GET", "/api/v1/users/:id"},
+		{"POST", "/api/v1/users"},
+		{"PUT", "/api/v1/users/:id"},
+		{"DELETE", "/api/v1/users/:id"},
+		{"GET", "/api/v1/orders"},
+		{"POST", "/api/v1/orders"},
+		{"GET", "/health"},
+	}
+	r := routes[rand.IntN(len(routes))]
+	return r[0], r[1]
+}
+
+// Status 随机的响应状态码，大部分为 2xx，少量 4xx 与 5xx
+func Status() int {
+	switch n := rand.IntN(100); {
+	case n < 85:
+		return pickInt(200, 200, 200, 201, 204)
+	case n < 97:
+		return pickInt(400, 401, 403, 404, 429)
+	default:
+		return pickInt(500, 502, 503)
+	}
+}
+
+// Latency 随机的请求耗时，呈长尾分布：多数在 50ms 内，少数超过 1s
+func Latency() time.Duration {
+	ms := rand.ExpFloat64() * 20
+	if rand.IntN(100) == 0 {
+		ms += 1000
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// UserData 随机的嵌套用户数据，用于演示 slog.Any 输出大型 map
+func UserData() map[string]any {
+	return map[string]any{
+		"id":       UserID(),
+		"username": RandomString(10),
+		"email":    RandomString(8) + "@example.com",
+		"profile": map[string]any{
+			"first_name":  RandomString(6),
+			"last_name":   RandomString(8),
+			"age":         rand.IntN(80) + 18,
+			"country":     RandomString(5),
+			"preferences": []string{RandomString(4), RandomString(6), RandomString(5)},
+		},
+		"statistics": map[string]any{
+			"login_count":    rand.IntN(1000),
+			"posts_created":  rand.IntN(50),
+			"last_login":     time.Now().Add(-time.Duration(rand.IntN(24*7)) * time.Hour),
+			"account_status": "active",
+		},
+		"metadata": map[string]any{
+			"created_at": time.Now().Add(-time.Duration(rand.IntN(365*2)) * 24 * time.Hour),
+			"updated_at": time.Now().Add(-time.Duration(rand.IntN(24)) * time.Hour),
+			"version":    rand.IntN(10) + 1,
+		},
+	}
+}
+
+// RandomString 由字母和数字组成的随机字符串
+func RandomString(length int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	result := make([]byte, length)
+	for i := range result {
+		result[i] = letters[rand.IntN(len(letters))]
+	}
+	return string(result)
+}
+
+func pick(values ...string) string {
+	return values[rand.IntN(len(values))]
+}
+
+func pickInt(values ...int) int {
+	return values[rand.IntN(len(values))]
+}
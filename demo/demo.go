@@ -0,0 +1,86 @@
+// Package demo 生成接近真实业务的日志，用于在本地评估 logmiao 的输出效果与吞吐：
+// 场景覆盖各级别、结构化与大型属性、并发写入、热循环批量写出、HTTP 访问日志、错误风暴、
+// 敏感值脱敏、计时段与模块级别，配合不同的配置文件即可观察各处理器的组合效果。
+//
+//	logger.Init("configs/logger.yaml")
+//	defer logger.Close()
+//	for _, r := range demo.Run() { // 不指定场景时运行 All()
+//		fmt.Println(r)
+//	}
+package demo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	logger "github.com/shuakami/logmiao"
+)
+
+// Scenario 演示场景，Run 返回写出的记录数（含被级别或过滤规则丢弃的记录）
+type Scenario struct {
+	Name string
+	Run  func(ctx context.Context, l *slog.Logger) int
+}
+
+// Result 场景的运行结果
+type Result struct {
+	Name      string        `json:"name"`
+	Records   int           `json:"records"`
+	Duration  time.Duration `json:"duration"`
+	PerSecond float64       `json:"per_second"`
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%-12s %8d records %12s %12.0f/s", r.Name, r.Records, r.Duration.Round(time.Microsecond), r.PerSecond)
+}
+
+// Run 使用全局日志器按顺序运行场景，未指定时运行 All()，结束后刷新输出端
+func Run(scenarios ...Scenario) []Result {
+	results := RunWith(logger.GetLogger(), scenarios...)
+	_ = logger.Flush()
+	return results
+}
+
+// RunWith 使用指定的日志器按顺序运行场景，未指定时运行 All()。
+// 日志器绑定到 ctx，使用 logger.Event 与 logger.StartSpan 的场景同样写入该日志器
+func RunWith(l *slog.Logger, scenarios ...Scenario) []Result {
+	if len(scenarios) == 0 {
+		scenarios = All()
+	}
+	ctx := logger.WithContext(context.Background(), l)
+	results := make([]Result, 0, len(scenarios))
+	for _, s := range scenarios {
+		start := time.Now()
+		n := s.Run(ctx, l)
+		elapsed := time.Since(start)
+		r := Result{Name: s.Name, Records: n, Duration: elapsed}
+		if elapsed > 0 {
+			r.PerSecond = float64(n) / elapsed.Seconds()
+		}
+		results = append(results, r)
+		l.Info("Demo scenario finished",
+			slog.String("scenario", r.Name),
+			slog.Int("records", r.Records),
+			slog.Duration("duration", r.Duration),
+			slog.Float64("records_per_second", r.PerSecond),
+		)
+	}
+	return results
+}
+
+// All 返回全部场景，规模适合在几秒内运行完毕
+func All() []Scenario {
+	return []Scenario{
+		Levels(),
+		Structured(1000),
+		Concurrent(100, 100),
+		Bulk(10, 500),
+		Requests(1000),
+		Errors(500),
+		Privacy(),
+		Spans(50),
+		Modules(),
+	}
+}
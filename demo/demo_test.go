@@ -0,0 +1,61 @@
+package demo
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuffer 并发写入安全的缓冲区
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestRunWith 测试所有场景都能运行，并写入指定的日志器
+func TestRunWith(t *testing.T) {
+	var buf lockedBuffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	scenarios := []Scenario{
+		Levels(), Structured(10), Concurrent(4, 5), Bulk(2, 5), Requests(10),
+		Errors(10), Privacy(), Spans(3), Modules(),
+	}
+	results := RunWith(l, scenarios...)
+
+	if len(results) != len(scenarios) {
+		t.Fatalf("got %d results, want %d", len(results), len(scenarios))
+	}
+	want := map[string]int{"structured": 10, "concurrent": 20, "bulk": 12, "requests": 10, "spans": 6}
+	total := 0
+	for _, r := range results {
+		if n, ok := want[r.Name]; ok && r.Records != n {
+			t.Errorf("%s: %d records, want %d", r.Name, r.Records, n)
+		}
+		total += r.Records
+	}
+
+	out := buf.String()
+	if got := strings.Count(out, "Demo scenario finished"); got != len(scenarios) {
+		t.Errorf("%d scenario summaries, want %d", got, len(scenarios))
+	}
+	if got := strings.Count(out, "\n"); got != total+len(scenarios) {
+		t.Errorf("%d lines written, want %d", got, total+len(scenarios))
+	}
+	if !strings.Contains(out, "order.created") {
+		t.Error("events should be written to the bound logger")
+	}
+}
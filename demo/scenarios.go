@@ -0,0 +1,233 @@
+package demo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	logger "github.com/shuakami/logmiao"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// Levels 各级别的记录、分组属性与自定义错误类型
+func Levels() Scenario {
+	return Scenario{Name: "levels", Run: func(ctx context.Context, l *slog.Logger) int {
+		l.Debug("这是调试信息", slog.String("module", "main"), slog.String("function", "main"))
+		l.Info("用户登录成功",
+			slog.String("user_id", "12345"),
+			slog.String("ip", "192.168.1.100"),
+			slog.Duration("login_time", 150*time.Millisecond),
+		)
+		l.Warn("缓存未命中", slog.String("cache_key", "user:12345"), slog.String("fallback", "database"))
+		l.Error("用户处理失败",
+			logger.Error(&userError{UserID: "invalid-user-id", Reason: "用户ID格式无效"}),
+			slog.String("user_id", "invalid-user-id"),
+			slog.String("operation", "process"),
+		)
+		l.Info("API调用统计",
+			slog.Group("stats",
+				slog.Int("total_requests", 1234),
+				slog.Int("successful", 1200),
+				slog.Int("failed", 34),
+				slog.Float64("success_rate", 97.24),
+			),
+			slog.Group("performance",
+				slog.Duration("avg_response_time", 45*time.Millisecond),
+				slog.Duration("max_response_time", 500*time.Millisecond),
+			),
+		)
+		return 5
+	}}
+}
+
+// userError 自定义错误类型
+type userError struct {
+	UserID string
+	Reason string
+}
+
+func (e *userError) Error() string {
+	return "用户处理错误: " + e.Reason + " (UserID: " + e.UserID + ")"
+}
+
+// Structured n 条带嵌套分组的用户操作记录
+func Structured(n int) Scenario {
+	return Scenario{Name: "structured", Run: func(ctx context.Context, l *slog.Logger) int {
+		for i := 0; i < n; i++ {
+			l.Info("用户操作记录",
+				slog.String("user_id", UserID()),
+				slog.String("action", Action()),
+				slog.String("resource", Resource()),
+				slog.Int("attempt", i),
+				slog.Duration("processing_time", time.Duration(rand.IntN(500))*time.Millisecond),
+				slog.Group("metadata",
+					slog.String("ip", IP()),
+					slog.String("user_agent", UserAgent()),
+					slog.String("session_id", SessionID()),
+				),
+				slog.Group("performance",
+					slog.Int("cpu_usage", rand.IntN(100)),
+					slog.Int("memory_usage", rand.IntN(1024)),
+					slog.Int("io_operations", rand.IntN(50)),
+				),
+			)
+		}
+		return n
+	}}
+}
+
+// Concurrent workers 个goroutine各写 perWorker 条随机级别的记录
+func Concurrent(workers, perWorker int) Scenario {
+	return Scenario{Name: "concurrent", Run: func(ctx context.Context, l *slog.Logger) int {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for j := 0; j < perWorker; j++ {
+					attrs := []slog.Attr{slog.Int("worker", worker), slog.Int("message", j)}
+					switch rand.IntN(4) {
+					case 0:
+						l.LogAttrs(ctx, slog.LevelDebug, "调试消息", attrs...)
+					case 1:
+						l.LogAttrs(ctx, slog.LevelInfo, "信息消息", append(attrs,
+							slog.Duration("random_duration", time.Duration(rand.IntN(1000))*time.Millisecond))...)
+					case 2:
+						l.LogAttrs(ctx, slog.LevelWarn, "警告消息", append(attrs, slog.String("reason", "模拟警告"))...)
+					default:
+						l.LogAttrs(ctx, slog.LevelError, "错误消息", append(attrs, slog.String("error", "模拟错误"))...)
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		return workers * perWorker
+	}}
+}
+
+// Bulk 热循环中通过批量缓冲写出 batches 批、每批 perBatch 条带大型 map 属性的记录
+func Bulk(batches, perBatch int) Scenario {
+	return Scenario{Name: "bulk", Run: func(ctx context.Context, l *slog.Logger) int {
+		buf := handler.NewLocalBuffer(handler.LocalConfig{})
+		defer buf.Close()
+		local := slog.New(handler.NewLocalHandler(l.Handler(), buf))
+		for batch := 0; batch < batches; batch++ {
+			start := time.Now()
+			for i := 0; i < perBatch; i++ {
+				local.Info("批量用户数据处理",
+					slog.Int("batch", batch),
+					slog.Int("record", i),
+					slog.Any("user_data", UserData()),
+					slog.String("processing_status", "completed"),
+				)
+			}
+			local.Info("批次处理完成",
+				slog.Int("batch", batch),
+				slog.Int("logs_in_batch", perBatch),
+				slog.Duration("batch_duration", time.Since(start)),
+			)
+		}
+		return batches * (perBatch + 1)
+	}}
+}
+
+// Requests n 条 HTTP 访问日志：5xx 为 Error，4xx 为 Warn，其余为 Info，包含健康检查与长尾耗时
+func Requests(n int) Scenario {
+	return Scenario{Name: "requests", Run: func(ctx context.Context, l *slog.Logger) int {
+		for i := 0; i < n; i++ {
+			method, path := Route()
+			status := Status()
+			level := slog.LevelInfo
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+			l.LogAttrs(ctx, level, "HTTP Request",
+				slog.String("request_id", RandomString(12)),
+				slog.Group("http",
+					slog.String("method", method),
+					slog.String("path", path),
+					slog.Int("status", status),
+					slog.Duration("latency", Latency()),
+					slog.String("client_ip", IP()),
+					slog.String("user_agent", UserAgent()),
+				),
+			)
+		}
+		return n
+	}}
+}
+
+// errUpstream 下游服务错误，演示按错误去重与新错误检测
+var errUpstream = errors.New("upstream unavailable")
+
+// Errors n 条错误风暴：同一错误大量重复、少量不同的新错误，以及已取消的请求
+func Errors(n int) Scenario {
+	return Scenario{Name: "errors", Run: func(ctx context.Context, l *slog.Logger) int {
+		for i := 0; i < n; i++ {
+			switch rand.IntN(20) {
+			case 0:
+				l.Error("查询失败", logger.Error(fmt.Errorf("query orders: %w", context.Canceled)))
+			case 1:
+				l.Error("支付回调处理失败", logger.Error(fmt.Errorf("order %d: invalid signature", rand.IntN(100))))
+			default:
+				l.Error("调用库存服务失败",
+					logger.Error(fmt.Errorf("inventory: %w", errUpstream)),
+					slog.Int("attempt", i%3+1),
+				)
+			}
+		}
+		return n
+	}}
+}
+
+// Privacy 需要脱敏的值：logger.Secret 包装的值、redact_keys 中的键，以及出现在消息和任意属性中的邮箱、卡号与令牌
+func Privacy() Scenario {
+	return Scenario{Name: "privacy", Run: func(ctx context.Context, l *slog.Logger) int {
+		l.Info("用户注册", slog.String("user_id", UserID()), slog.String("password", RandomString(12)))
+		l.Info("连接数据库", slog.Any("dsn", logger.Secret("postgres://app:"+RandomString(10)+"@db:5432/app")))
+		l.Info("请求外部接口", slog.Any("headers", map[string]string{"Authorization": "Bearer " + RandomString(24)}))
+		l.Warn("发送通知失败 alice@example.com", slog.String("detail", "card 4111 1111 1111 1111 declined"))
+		l.Info("签发令牌", slog.String("jwt", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjM0In0."+RandomString(22)))
+		return 5
+	}}
+}
+
+// Spans n 个计时段与链式事件，少量计时段超过慢阈值
+func Spans(n int) Scenario {
+	return Scenario{Name: "spans", Run: func(ctx context.Context, l *slog.Logger) int {
+		for i := 0; i < n; i++ {
+			done := logger.StartSpan(ctx, "db.query", slog.String("table", Resource()))
+			if rand.IntN(10) == 0 {
+				time.Sleep(5 * time.Millisecond)
+			}
+			done()
+			logger.Event(ctx, "order.created").
+				Str("order_id", RandomString(10)).
+				Int("items", rand.IntN(5)+1).
+				Float("amount", float64(rand.IntN(100000))/100).
+				Send()
+		}
+		return 2 * n
+	}}
+}
+
+// Modules 按模块输出的记录，配合 logger.levels 观察模块级别
+func Modules() Scenario {
+	return Scenario{Name: "modules", Run: func(ctx context.Context, l *slog.Logger) int {
+		modules := []string{"database", "database.postgres", "cache", "http"}
+		for _, name := range modules {
+			m := l.With(fields.Module(name))
+			m.Debug("模块调试信息", slog.String("detail", RandomString(8)))
+			m.Info("模块运行正常", slog.Int("connections", rand.IntN(100)))
+		}
+		return 2 * len(modules)
+	}}
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// loggerCtxKey 上下文中存放日志器的键
+type loggerCtxKey struct{}
+
+// WithContext 将日志器绑定到上下文，供 FromContext 和 Event 使用
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, l)
+}
+
+// FromContext 获取上下文绑定的日志器，未绑定时返回全局日志器；
+// 上下文中的请求级属性（如租户标识）会附加到返回的日志器
+func FromContext(ctx context.Context) *slog.Logger {
+	l := GetLogger()
+	if ctx != nil {
+		if bound, ok := ctx.Value(loggerCtxKey{}).(*slog.Logger); ok && bound != nil {
+			l = bound
+		}
+		if attrs := handler.AttrsFromContext(ctx); len(attrs) > 0 {
+			l = slog.New(l.Handler().WithAttrs(attrs))
+		}
+	}
+	return l
+}
+
+// EventBuilder 链式日志事件构建器，级别未启用时为nil，所有方法均为空操作
+type EventBuilder struct {
+	ctx    context.Context
+	logger *slog.Logger
+	level  slog.Level
+	msg    string
+	attrs  []slog.Attr
+}
+
+var eventPool = sync.Pool{
+	New: func() interface{} { return &EventBuilder{} },
+}
+
+// Event 创建 Info 级别的日志事件
+//
+//	logger.Event(ctx, "user.login").Str("user_id", id).Dur("latency", d).Err(err).Send()
+func Event(ctx context.Context, msg string) *EventBuilder {
+	return newEvent(ctx, slog.LevelInfo, msg)
+}
+
+// DebugEvent 创建 Debug 级别的日志事件
+func DebugEvent(ctx context.Context, msg string) *EventBuilder {
+	return newEvent(ctx, slog.LevelDebug, msg)
+}
+
+// WarnEvent 创建 Warn 级别的日志事件
+func WarnEvent(ctx context.Context, msg string) *EventBuilder {
+	return newEvent(ctx, slog.LevelWarn, msg)
+}
+
+// ErrorEvent 创建 Error 级别的日志事件
+func ErrorEvent(ctx context.Context, msg string) *EventBuilder {
+	return newEvent(ctx, slog.LevelError, msg)
+}
+
+// newEvent 级别未启用时返回nil，避免构建属性的开销
+func newEvent(ctx context.Context, level slog.Level, msg string) *EventBuilder {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	l := FromContext(ctx)
+	if !l.Enabled(ctx, level) {
+		return nil
+	}
+
+	e := eventPool.Get().(*EventBuilder)
+	e.ctx = ctx
+	e.logger = l
+	e.level = level
+	e.msg = msg
+	return e
+}
+
+// Str 添加字符串属性
+func (e *EventBuilder) Str(key, value string) *EventBuilder {
+	return e.Attr(slog.String(key, value))
+}
+
+// Int 添加整数属性
+func (e *EventBuilder) Int(key string, value int) *EventBuilder {
+	return e.Attr(slog.Int(key, value))
+}
+
+// Int64 添加int64属性
+func (e *EventBuilder) Int64(key string, value int64) *EventBuilder {
+	return e.Attr(slog.Int64(key, value))
+}
+
+// Float 添加浮点数属性
+func (e *EventBuilder) Float(key string, value float64) *EventBuilder {
+	return e.Attr(slog.Float64(key, value))
+}
+
+// Bool 添加布尔属性
+func (e *EventBuilder) Bool(key string, value bool) *EventBuilder {
+	return e.Attr(slog.Bool(key, value))
+}
+
+// Dur 添加时长属性
+func (e *EventBuilder) Dur(key string, value time.Duration) *EventBuilder {
+	return e.Attr(slog.Duration(key, value))
+}
+
+// Time 添加时间属性
+func (e *EventBuilder) Time(key string, value time.Time) *EventBuilder {
+	return e.Attr(slog.Time(key, value))
+}
+
+// Any 添加任意类型属性
+func (e *EventBuilder) Any(key string, value interface{}) *EventBuilder {
+	return e.Attr(slog.Any(key, value))
+}
+
+// Err 添加错误属性，err 为nil时忽略
+func (e *EventBuilder) Err(err error) *EventBuilder {
+	if err == nil {
+		return e
+	}
+	return e.Attr(Error(err))
+}
+
+// Attr 添加任意 slog 属性
+func (e *EventBuilder) Attr(attr slog.Attr) *EventBuilder {
+	if e == nil {
+		return e
+	}
+	if e.attrs == nil {
+		e.attrs = make([]slog.Attr, 0, 8)
+	}
+	e.attrs = append(e.attrs, attr)
+	return e
+}
+
+// Send 输出日志事件，调用后构建器不可再使用
+func (e *EventBuilder) Send() {
+	if e == nil {
+		return
+	}
+	// 使用调用者的位置作为记录来源
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), e.level, e.msg, pcs[0])
+	r.AddAttrs(e.attrs...)
+	_ = e.logger.Handler().Handle(e.ctx, r)
+
+	// 归还到对象池，保留属性切片的容量
+	e.ctx = nil
+	e.logger = nil
+	e.attrs = e.attrs[:0]
+	eventPool.Put(e)
+}
@@ -0,0 +1,171 @@
+// Package fields 提供标准化的日志属性构造函数，
+// 键名与彩色处理器、智能过滤器和Gin中间件特殊处理的字段保持一致
+package fields
+
+import (
+	"log/slog"
+	"time"
+)
+
+// 标准属性键名
+const (
+	KeyMethod       = "method"
+	KeyStatus       = "status"
+	KeyPath         = "path"
+	KeyRoute        = "route"
+	KeyURL          = "url"
+	KeyLatency      = "latency"
+	KeyTTFB         = "ttfb"
+	KeyDuration     = "duration"
+	KeyClientIP     = "client_ip"
+	KeyIP           = "ip"
+	KeyUserAgent    = "user_agent"
+	KeyRequestID    = "request_id"
+	KeyUserID       = "user_id"
+	KeySessionID    = "session_id"
+	KeyTenantID     = "tenant_id"
+	KeyCache        = "cache"
+	KeyError        = "error"
+	KeyStack        = "stack"
+	KeyTrace        = "trace"
+	KeyBytes        = "bytes"
+	KeyRequestSize  = "request_size"
+	KeyResponseSize = "response_size"
+	KeyType         = "type"
+	KeySampled      = "sampled"
+	KeySampleRate   = "sample_rate"
+	KeyModule       = "module"
+)
+
+// Method HTTP请求方法
+func Method(method string) slog.Attr {
+	return slog.String(KeyMethod, method)
+}
+
+// HTTPStatus HTTP状态码，彩色输出时按状态码分级着色
+func HTTPStatus(code int) slog.Attr {
+	return slog.Int(KeyStatus, code)
+}
+
+// Path 请求路径
+func Path(path string) slog.Attr {
+	return slog.String(KeyPath, path)
+}
+
+// Route 路由模板，如 /users/:id
+func Route(route string) slog.Attr {
+	return slog.String(KeyRoute, route)
+}
+
+// URL 完整URL
+func URL(url string) slog.Attr {
+	return slog.String(KeyURL, url)
+}
+
+// Latency 请求耗时
+func Latency(d time.Duration) slog.Attr {
+	return slog.Duration(KeyLatency, d)
+}
+
+// TTFB 首字节耗时（从收到请求到写出响应头或第一个字节）
+func TTFB(d time.Duration) slog.Attr {
+	return slog.Duration(KeyTTFB, d)
+}
+
+// Duration 操作耗时
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration(KeyDuration, d)
+}
+
+// ClientIP 客户端IP
+func ClientIP(ip string) slog.Attr {
+	return slog.String(KeyClientIP, ip)
+}
+
+// IP 通用IP地址
+func IP(ip string) slog.Attr {
+	return slog.String(KeyIP, ip)
+}
+
+// UserAgent 客户端User-Agent
+func UserAgent(ua string) slog.Attr {
+	return slog.String(KeyUserAgent, ua)
+}
+
+// RequestID 请求ID
+func RequestID(id string) slog.Attr {
+	return slog.String(KeyRequestID, id)
+}
+
+// UserID 用户ID
+func UserID(id string) slog.Attr {
+	return slog.String(KeyUserID, id)
+}
+
+// SessionID 会话ID
+func SessionID(id string) slog.Attr {
+	return slog.String(KeySessionID, id)
+}
+
+// TenantID 租户标识
+func TenantID(id string) slog.Attr {
+	return slog.String(KeyTenantID, id)
+}
+
+// Cache 缓存状态，如 HIT、MISS
+func Cache(status string) slog.Attr {
+	return slog.String(KeyCache, status)
+}
+
+// Err 错误信息，err 为nil时值为空字符串
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String(KeyError, "")
+	}
+	return slog.String(KeyError, err.Error())
+}
+
+// Stack 堆栈信息，彩色输出时按行展开
+func Stack(stack string) slog.Attr {
+	return slog.String(KeyStack, stack)
+}
+
+// Trace 追踪信息
+func Trace(trace string) slog.Attr {
+	return slog.String(KeyTrace, trace)
+}
+
+// Bytes 字节数
+func Bytes(n int64) slog.Attr {
+	return slog.Int64(KeyBytes, n)
+}
+
+// RequestSize 请求体大小（字节）
+func RequestSize(n int64) slog.Attr {
+	return slog.Int64(KeyRequestSize, n)
+}
+
+// ResponseSize 响应体大小（字节）
+func ResponseSize(n int64) slog.Attr {
+	return slog.Int64(KeyResponseSize, n)
+}
+
+// Type 记录类型，如 http_request、panic
+func Type(t string) slog.Attr {
+	return slog.String(KeyType, t)
+}
+
+// Module 日志所属模块，按 logger.levels 中该模块的级别过滤
+func Module(name string) slog.Attr {
+	return slog.String(KeyModule, name)
+}
+
+// Sampled 标记经过采样保留的记录
+func Sampled() slog.Attr {
+	return slog.Bool(KeySampled, true)
+}
+
+// SampleRate 采样率，即每条保留记录代表的原始记录数
+func SampleRate(n int) slog.Attr {
+	return slog.Int(KeySampleRate, n)
+}
@@ -0,0 +1,66 @@
+package fields
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestFieldKeys 测试各构造函数使用的键名和值
+func TestFieldKeys(t *testing.T) {
+	tests := []struct {
+		attr slog.Attr
+		key  string
+		want any
+	}{
+		{Method("GET"), KeyMethod, "GET"},
+		{HTTPStatus(404), KeyStatus, int64(404)},
+		{Path("/users/7"), KeyPath, "/users/7"},
+		{Route("/users/:id"), KeyRoute, "/users/:id"},
+		{Latency(150 * time.Millisecond), KeyLatency, 150 * time.Millisecond},
+		{TTFB(20 * time.Millisecond), KeyTTFB, 20 * time.Millisecond},
+		{ClientIP("10.0.0.1"), KeyClientIP, "10.0.0.1"},
+		{RequestID("req-1"), KeyRequestID, "req-1"},
+		{SessionID("sess_ab12"), KeySessionID, "sess_ab12"},
+		{Err(errors.New("boom")), KeyError, "boom"},
+		{Err(nil), KeyError, ""},
+		{Bytes(2048), KeyBytes, int64(2048)},
+		{ResponseSize(512), KeyResponseSize, int64(512)},
+		{Type("http_request"), KeyType, "http_request"},
+		{Sampled(), KeySampled, true},
+		{SampleRate(10), KeySampleRate, int64(10)},
+	}
+	for _, tt := range tests {
+		if tt.attr.Key != tt.key {
+			t.Errorf("key = %q, want %q", tt.attr.Key, tt.key)
+		}
+		if got := tt.attr.Value.Any(); got != tt.want {
+			t.Errorf("%s = %v (%T), want %v (%T)", tt.key, got, got, tt.want, tt.want)
+		}
+	}
+}
+
+// TestFieldsJSONOutput 测试属性经 JSON 处理器输出的键和值
+func TestFieldsJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).LogAttrs(context.Background(), slog.LevelInfo, "HTTP Request",
+		Type("http_request"), Method("POST"), Path("/orders"), HTTPStatus(201), UserID("u-1"), RequestSize(64))
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type": "http_request", "method": "POST", "path": "/orders",
+		"status": float64(201), "user_id": "u-1", "request_size": float64(64),
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v in %s", key, got[key], value, buf.String())
+		}
+	}
+}
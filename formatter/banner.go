@@ -9,8 +9,19 @@ import (
 	"github.com/shuakami/logmiao/config"
 )
 
+// Release 横幅中显示的发布信息
+type Release struct {
+	Version string
+	Commit  string
+}
+
 // PrintBanner 打印美观的应用启动横幅
 func PrintBanner(appName, version string, cfg *config.Config) {
+	PrintBannerWithRelease(appName, version, cfg, Release{})
+}
+
+// PrintBannerWithRelease 打印带发布版本和提交号的启动横幅
+func PrintBannerWithRelease(appName, version string, cfg *config.Config, release Release) {
 	// 定义调色板
 	titleColor := color.New(color.FgHiCyan, color.Bold)
 	labelColor := color.New(color.FgWhite)
@@ -33,6 +44,37 @@ func PrintBanner(appName, version string, cfg *config.Config) {
 	labelColor.Printf("    └─ CPUs:        ")
 	valueColor.Printf("%d\n\n", runtime.NumCPU())
 
+	// 发布信息组
+	if release.Version != "" || release.Commit != "" {
+		treeColor.Println("  ● Release")
+		if release.Version != "" {
+			prefix := "    ├─ "
+			if release.Commit == "" {
+				prefix = "    └─ "
+			}
+			labelColor.Printf("%sVersion:     ", prefix)
+			valueColor.Println(release.Version)
+		}
+		if release.Commit != "" {
+			labelColor.Printf("    └─ Commit:      ")
+			valueColor.Println(release.Commit)
+		}
+		fmt.Println()
+	}
+
+	// 运行环境组：部署环境和实际加载的配置来源
+	if cfg != nil {
+		env := cfg.Logger.Resource.Resolve()[config.ResourceEnvironment]
+		if env == "" {
+			env = "-"
+		}
+		treeColor.Println("  ● Environment")
+		labelColor.Printf("    ├─ Env:         ")
+		valueColor.Println(env)
+		labelColor.Printf("    └─ Config:      ")
+		valueColor.Printf("%s\n\n", cfg.SourceName())
+	}
+
 	// 日志配置信息组
 	if cfg != nil {
 		treeColor.Println("  ● Logger Config")
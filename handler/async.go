@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// asyncItem 队列中的一条待处理记录
+type asyncItem struct {
+	ctx     context.Context
+	record  slog.Record
+	handler slog.Handler
+}
+
+// asyncState 派生处理器共享的队列和后台协程
+type asyncState struct {
+	queue  *Queue[asyncItem]
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	mu     sync.RWMutex // 入队持读锁、关闭持写锁，关闭后不会再有记录进入已停止消费的队列
+	closed atomic.Bool
+
+	processed atomic.Int64 // 后台协程已写出的记录数，供 Flush 判断队列是否写完
+}
+
+// AsyncHandler 异步处理器，记录放入有界队列由后台协程写出，队列满时按溢出策略处理
+type AsyncHandler struct {
+	handler slog.Handler
+	state   *asyncState
+}
+
+// NewAsyncHandler 创建异步处理器，溢出策略无效时返回错误
+func NewAsyncHandler(handler slog.Handler, cfg QueueConfig) (*AsyncHandler, error) {
+	queue, err := NewQueue[asyncItem](cfg)
+	if err != nil {
+		return nil, err
+	}
+	state := &asyncState{
+		queue: queue,
+		done:  make(chan struct{}),
+	}
+	state.wg.Add(1)
+	go state.run()
+
+	return &AsyncHandler{handler: handler, state: state}, nil
+}
+
+// run 后台消费队列
+func (s *asyncState) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case item := <-s.queue.C():
+			_ = item.handler.Handle(item.ctx, item.record)
+			s.processed.Add(1)
+		case <-s.done:
+			// 退出前写出队列中剩余的记录
+			for {
+				select {
+				case item := <-s.queue.C():
+					_ = item.handler.Handle(item.ctx, item.record)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.state.mu.RLock()
+	// 关闭后同步写出，避免丢失关闭过程中产生的日志
+	if h.state.closed.Load() {
+		h.state.mu.RUnlock()
+		return h.handler.Handle(ctx, r)
+	}
+	// 记录会在调用返回后被处理，需要克隆。block 策略下 Push 可能等待，
+	// 此时 Close 等待读锁释放，后台协程仍在消费，不会死锁
+	h.state.queue.Push(asyncItem{
+		ctx:     context.WithoutCancel(ctx),
+		record:  r.Clone(),
+		handler: h.handler,
+	})
+	h.state.mu.RUnlock()
+	return nil
+}
+
+// HandleSync 绕过队列直接写出，用于丢弃汇总等队列已满时也必须送达的内部记录
+func (h *AsyncHandler) HandleSync(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+// Flush 等待调用时已入队的记录写出，ctx 结束时返回 ctx 的错误。
+// 被 drop_oldest 挤出的记录不再等待
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	target := h.state.queue.Stats().Enqueued
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for !h.state.closed.Load() && h.state.processed.Load()+h.state.queue.Stats().DroppedOldest < target {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Stats 返回队列统计
+func (h *AsyncHandler) Stats() QueueStats {
+	return h.state.queue.Stats()
+}
+
+// Close 停止后台协程并写出剩余记录
+func (h *AsyncHandler) Close() error {
+	h.state.once.Do(func() {
+		h.state.mu.Lock()
+		h.state.closed.Store(true)
+		h.state.mu.Unlock()
+		close(h.state.done)
+	})
+	h.state.wg.Wait()
+	return nil
+}
+
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// WalkAttrs 深度优先遍历属性，展开 slog.Group 并解析 LogValuer，
+// fn 收到以 "." 连接的完整键名（如 http.path）和叶子值；prefix 为 WithGroup 累积的组名，fn 返回 false 时停止遍历
+func WalkAttrs(prefix string, attrs []slog.Attr, fn func(key string, v slog.Value) bool) bool {
+	for _, a := range attrs {
+		if !walkAttr(prefix, a, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// WalkRecordAttrs 遍历记录的属性，规则同 WalkAttrs
+func WalkRecordAttrs(prefix string, r slog.Record, fn func(key string, v slog.Value) bool) {
+	r.Attrs(func(a slog.Attr) bool {
+		return walkAttr(prefix, a, fn)
+	})
+}
+
+func walkAttr(prefix string, a slog.Attr, fn func(key string, v slog.Value) bool) bool {
+	v := a.Value.Resolve()
+	key := joinKey(prefix, a.Key)
+	if v.Kind() == slog.KindGroup {
+		// 键为空的组内联到上一层
+		return WalkAttrs(key, v.Group(), fn)
+	}
+	return fn(key, v)
+}
+
+// joinKey 以 "." 连接组名和键名
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + "." + key
+}
+
+// KeyMatches 检查完整键名是否匹配规则键：完全相同，或以 "."+key 结尾（如 http.path 匹配 path）
+func KeyMatches(path, key string) bool {
+	return path == key || strings.HasSuffix(path, "."+key)
+}
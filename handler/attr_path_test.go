@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWalkRecordAttrs 测试展开分组后的完整键名
+func TestWalkRecordAttrs(t *testing.T) {
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	r.AddAttrs(
+		slog.String("method", "GET"),
+		slog.Group("http", slog.String("path", "/health"), slog.Group("client", slog.String("ip", "10.0.0.1"))),
+		slog.Group("", slog.Int("inline", 1)),
+	)
+
+	var keys []string
+	WalkRecordAttrs("req", r, func(key string, v slog.Value) bool {
+		keys = append(keys, key+"="+v.String())
+		return true
+	})
+	want := "req.method=GET,req.http.path=/health,req.http.client.ip=10.0.0.1,req.inline=1"
+	if got := strings.Join(keys, ","); got != want {
+		t.Errorf("keys = %s, want %s", got, want)
+	}
+}
+
+// TestFiltersMatchGroupedAttrs 测试健康检查过滤和定向调试规则对分组属性同样生效
+func TestFiltersMatchGroupedAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	filtered := slog.New(NewSmartFilterHandler(slog.NewTextHandler(&buf, nil), FilterConfig{IgnoreHealthCheck: true}))
+	filtered.Info("request", slog.Group("http", slog.String("path", "/health")))
+	filtered.WithGroup("http").Info("request", slog.String("url", "http://svc/ping"))
+	if buf.Len() != 0 {
+		t.Errorf("grouped health check paths should be filtered: %s", buf.String())
+	}
+
+	buf.Reset()
+	target := slog.New(NewDebugTargetHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.LevelInfo, []DebugRule{{Key: "auth.user_id", Value: "42"}}))
+	target.With(slog.Group("auth", slog.String("user_id", "42"))).Debug("nested")
+	target.WithGroup("auth").With(slog.String("user_id", "42")).Debug("with group")
+	target.With(slog.Group("auth", slog.String("user_id", "7"))).Debug("other user")
+	out := buf.String()
+	if !strings.Contains(out, "nested") || !strings.Contains(out, "with group") || strings.Contains(out, "other user") {
+		t.Errorf("debug rules should match grouped attrs by their full key: %s", out)
+	}
+}
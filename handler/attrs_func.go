@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// AttrsFuncHandler 在处理每条记录时调用 fn 获取属性并追加到记录，用于可在运行时变化的公共属性
+type AttrsFuncHandler struct {
+	handler slog.Handler
+	fn      func() []slog.Attr
+}
+
+// NewAttrsFuncHandler 创建动态属性处理器
+func NewAttrsFuncHandler(handler slog.Handler, fn func() []slog.Attr) *AttrsFuncHandler {
+	return &AttrsFuncHandler{handler: handler, fn: fn}
+}
+
+func (h *AttrsFuncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *AttrsFuncHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := h.fn(); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *AttrsFuncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AttrsFuncHandler{handler: h.handler.WithAttrs(attrs), fn: h.fn}
+}
+
+func (h *AttrsFuncHandler) WithGroup(name string) slog.Handler {
+	return &AttrsFuncHandler{handler: h.handler.WithGroup(name), fn: h.fn}
+}
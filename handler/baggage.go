@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// baggageKey 上下文中存放 Baggage 的键
+type baggageKey struct{}
+
+// BaggageExtractor 从上下文提取 Baggage 条目。使用 OpenTelemetry 时可替换为基于
+// baggage.FromContext(ctx) 的实现
+type BaggageExtractor func(ctx context.Context) map[string]string
+
+// ContextWithBaggage 将 Baggage 条目绑定到上下文
+func ContextWithBaggage(ctx context.Context, members map[string]string) context.Context {
+	return context.WithValue(ctx, baggageKey{}, members)
+}
+
+// BaggageFromContext 获取上下文中的 Baggage 条目
+func BaggageFromContext(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	members, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return members
+}
+
+// ParseBaggageHeader 解析 W3C baggage 请求头，如 "tenant=acme,experiment=b;ttl=60"
+func ParseBaggageHeader(header string) map[string]string {
+	if header == "" {
+		return nil
+	}
+
+	members := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		// 忽略 ";" 之后的属性
+		if idx := strings.Index(member, ";"); idx != -1 {
+			member = member[:idx]
+		}
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if decoded, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		members[key] = value
+	}
+	return members
+}
+
+// BaggageHandler 将请求上下文中选定的 Baggage 条目复制为日志属性。Baggage 属性始终位于顶层：
+// WithGroup 之后不再把分组交给内层处理器，而是在写出时将记录的属性嵌套到分组中
+type BaggageHandler struct {
+	handler slog.Handler
+	keys    []string
+	prefix  string
+	extract BaggageExtractor
+	groups  []string
+	bound   [][]slog.Attr // 各分组内 WithAttrs 绑定的属性，与 groups 一一对应
+}
+
+// NewBaggageHandler 创建 Baggage 处理器，keys 为空时复制全部条目，prefix 为属性键前缀
+func NewBaggageHandler(handler slog.Handler, keys []string, prefix string, extract BaggageExtractor) *BaggageHandler {
+	if extract == nil {
+		extract = BaggageFromContext
+	}
+	return &BaggageHandler{
+		handler: handler,
+		keys:    keys,
+		prefix:  prefix,
+		extract: extract,
+	}
+}
+
+func (h *BaggageHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *BaggageHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.groups) > 0 {
+		attrs := make([]slog.Attr, 0, r.NumAttrs())
+		r.Attrs(func(a slog.Attr) bool {
+			attrs = append(attrs, a)
+			return true
+		})
+		// 由内向外嵌套，空分组与 slog 的行为一致不输出
+		for i := len(h.groups) - 1; i >= 0; i-- {
+			inner := append(append([]slog.Attr{}, h.bound[i]...), attrs...)
+			attrs = nil
+			if len(inner) > 0 {
+				attrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(inner...)}}
+			}
+		}
+		nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		nr.AddAttrs(attrs...)
+		r = nr
+	}
+
+	members := h.extract(ctx)
+	if len(members) == 0 {
+		return h.handler.Handle(ctx, r)
+	}
+
+	if len(h.keys) == 0 {
+		for key, value := range members {
+			r.AddAttrs(slog.String(h.prefix+key, value))
+		}
+	} else {
+		for _, key := range h.keys {
+			if value, ok := members[key]; ok {
+				r.AddAttrs(slog.String(h.prefix+key, value))
+			}
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *BaggageHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	if len(h.groups) == 0 {
+		c.handler = h.handler.WithAttrs(attrs)
+	} else {
+		last := len(h.bound) - 1
+		c.bound = append([][]slog.Attr{}, h.bound...)
+		c.bound[last] = append(append([]slog.Attr{}, h.bound[last]...), attrs...)
+	}
+	return &c
+}
+
+func (h *BaggageHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(append([]string{}, h.groups...), name)
+	c.bound = append(append([][]slog.Attr{}, h.bound...), nil)
+	return &c
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestBaggageHandler 测试 Baggage 条目复制为日志属性
+func TestBaggageHandler(t *testing.T) {
+	members := ParseBaggageHeader("tenant=acme, experiment=b%20test;ttl=60, ignored=x")
+	if members["tenant"] != "acme" || members["experiment"] != "b test" {
+		t.Fatalf("unexpected baggage members: %v", members)
+	}
+
+	var buf bytes.Buffer
+	h := NewBaggageHandler(slog.NewTextHandler(&buf, nil), []string{"tenant", "experiment"}, "baggage.", nil)
+	slog.New(h).InfoContext(ContextWithBaggage(context.Background(), members), "order created")
+
+	out := buf.String()
+	if !strings.Contains(out, "baggage.tenant=acme") || !strings.Contains(out, `baggage.experiment="b test"`) {
+		t.Errorf("baggage attrs missing: %s", out)
+	}
+	if strings.Contains(out, "ignored") {
+		t.Errorf("unselected baggage entry should not be copied: %s", out)
+	}
+}
+
+// TestBaggageHandlerGroup 测试 WithGroup 之后 Baggage 属性仍位于顶层，记录与绑定的属性位于分组内
+func TestBaggageHandlerGroup(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewBaggageHandler(slog.NewJSONHandler(&buf, nil), []string{"tenant"}, "", nil)
+	ctx := ContextWithBaggage(context.Background(), map[string]string{"tenant": "acme"})
+	slog.New(h).With("service", "api").WithGroup("req").With("id", 7).InfoContext(ctx, "order created", "status", 200)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := got["req"].(map[string]any)
+	if got["tenant"] != "acme" || got["service"] != "api" || req["id"] != float64(7) || req["status"] != float64(200) {
+		t.Errorf("unexpected record: %s", buf.String())
+	}
+	if _, ok := req["tenant"]; ok {
+		t.Errorf("baggage should not be nested in the group: %s", buf.String())
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchConfig 批量发送配置，任一阈值达到即发送一批
+type BatchConfig struct {
+	MaxRecords  int           // 每批最多条数，默认100
+	MaxBytes    int           // 每批最多字节数，<=0 表示不限制
+	MaxInterval time.Duration // 最长等待时间，默认1秒
+	Concurrency int           // 同时进行的发送数，默认1（保持顺序）
+}
+
+// BatchStats 批量发送统计
+type BatchStats struct {
+	Batches      int64   `json:"batches"`
+	Records      int64   `json:"records"`
+	Bytes        int64   `json:"bytes"`
+	AvgBatchSize float64 `json:"avg_batch_size"`
+	InFlight     int64   `json:"in_flight"`
+	LastFlushMs  float64 `json:"last_flush_ms"` // 最近一次发送耗时（毫秒）
+	AvgFlushMs   float64 `json:"avg_flush_ms"`
+	MaxFlushMs   float64 `json:"max_flush_ms"`
+}
+
+// Batcher 从通道读取条目，按条数/字节数/时间阈值组批后交给 flush 发送，
+// 供所有HTTP/流式远程输出共用
+type Batcher[T any] struct {
+	src   <-chan T
+	cfg   BatchConfig
+	size  func(T) int
+	flush func([]T)
+
+	batches  chan []T
+	flushReq chan chan struct{}
+	done     chan struct{}
+	loopWG   sync.WaitGroup
+	flushWG  sync.WaitGroup
+	once     sync.Once
+
+	batchCount   atomic.Int64
+	records      atomic.Int64
+	bytes        atomic.Int64
+	inFlight     atomic.Int64
+	pending      atomic.Int64 // 已组成但尚未发送完成的批次
+	lastLatency  atomic.Int64
+	totalLatency atomic.Int64
+	maxLatency   atomic.Int64
+}
+
+// NewBatcher 创建并启动批量发送器，size 在设置 MaxBytes 时用于估算条目大小
+func NewBatcher[T any](src <-chan T, cfg BatchConfig, size func(T) int, flush func([]T)) *Batcher[T] {
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 100
+	}
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = time.Second
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if size == nil {
+		cfg.MaxBytes = 0
+	}
+
+	b := &Batcher[T]{
+		src:      src,
+		cfg:      cfg,
+		size:     size,
+		flush:    flush,
+		batches:  make(chan []T),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i := 0; i < cfg.Concurrency; i++ {
+		b.flushWG.Add(1)
+		go b.worker()
+	}
+	b.loopWG.Add(1)
+	go b.loop()
+	return b
+}
+
+// Close 发送通道中剩余的条目并等待进行中的发送完成
+func (b *Batcher[T]) Close() {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	b.loopWG.Wait()
+	b.flushWG.Wait()
+}
+
+// Flush 立即发送通道中已有的条目和未满的批次，并等待发送完成；ctx 结束时返回 ctx 的错误
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case b.flushReq <- ack:
+	case <-b.done:
+		return nil // 已关闭，Close 会写出剩余的条目
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for b.pending.Load() > 0 {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Stats 返回批量发送统计
+func (b *Batcher[T]) Stats() BatchStats {
+	stats := BatchStats{
+		Batches:     b.batchCount.Load(),
+		Records:     b.records.Load(),
+		Bytes:       b.bytes.Load(),
+		InFlight:    b.inFlight.Load(),
+		LastFlushMs: millis(b.lastLatency.Load()),
+		MaxFlushMs:  millis(b.maxLatency.Load()),
+	}
+	if stats.Batches > 0 {
+		stats.AvgBatchSize = float64(stats.Records) / float64(stats.Batches)
+		stats.AvgFlushMs = millis(b.totalLatency.Load() / stats.Batches)
+	}
+	return stats
+}
+
+// loop 组批，阈值达到或定时器触发时交给发送协程
+func (b *Batcher[T]) loop() {
+	defer b.loopWG.Done()
+	defer close(b.batches)
+
+	ticker := time.NewTicker(b.cfg.MaxInterval)
+	defer ticker.Stop()
+
+	var batch []T
+	batchBytes := 0
+	emit := func() {
+		if len(batch) == 0 {
+			return
+		}
+		b.pending.Add(1)
+		b.batches <- batch
+		batch = nil
+		batchBytes = 0
+	}
+	add := func(item T) {
+		if b.cfg.MaxBytes > 0 {
+			n := b.size(item)
+			// 加入后超过字节上限时先发送已有的条目
+			if len(batch) > 0 && batchBytes+n > b.cfg.MaxBytes {
+				emit()
+			}
+			batchBytes += n
+			b.bytes.Add(int64(n))
+		}
+		batch = append(batch, item)
+		if len(batch) >= b.cfg.MaxRecords || (b.cfg.MaxBytes > 0 && batchBytes >= b.cfg.MaxBytes) {
+			emit()
+		}
+	}
+
+	// drain 取出通道中已有的条目并发送未满的批次
+	drain := func() {
+		for {
+			select {
+			case item := <-b.src:
+				add(item)
+			default:
+				emit()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case item := <-b.src:
+			add(item)
+		case <-ticker.C:
+			emit()
+		case ack := <-b.flushReq:
+			drain()
+			close(ack)
+		case <-b.done:
+			drain()
+			return
+		}
+	}
+}
+
+// worker 执行发送并记录延迟
+func (b *Batcher[T]) worker() {
+	defer b.flushWG.Done()
+	for batch := range b.batches {
+		b.inFlight.Add(1)
+		start := time.Now()
+		b.flush(batch)
+		latency := int64(time.Since(start))
+		b.inFlight.Add(-1)
+		b.pending.Add(-1)
+
+		b.batchCount.Add(1)
+		b.records.Add(int64(len(batch)))
+		b.lastLatency.Store(latency)
+		b.totalLatency.Add(latency)
+		for {
+			max := b.maxLatency.Load()
+			if latency <= max || b.maxLatency.CompareAndSwap(max, latency) {
+				break
+			}
+		}
+	}
+}
+
+// millis 纳秒转换为毫秒
+func millis(ns int64) float64 {
+	return float64(ns) / float64(time.Millisecond)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestBatcherThresholds 测试按条数、字节数阈值组批以及关闭时发送剩余条目
+func TestBatcherThresholds(t *testing.T) {
+	src := make(chan string, 16)
+	var mu sync.Mutex
+	var batches [][]string
+	b := NewBatcher(src, BatchConfig{MaxRecords: 3, MaxBytes: 10, MaxInterval: time.Hour},
+		func(s string) int { return len(s) },
+		func(batch []string) {
+			mu.Lock()
+			batches = append(batches, batch)
+			mu.Unlock()
+		})
+
+	for _, s := range []string{"a", "b", "c", "dddddd", "eeeee", "f"} {
+		src <- s
+	}
+	b.Close()
+
+	// a,b,c 按条数成批；dddddd 与 eeeee 超过10字节被拆开；f 在关闭时发送
+	want := [][]string{{"a", "b", "c"}, {"dddddd"}, {"eeeee", "f"}}
+	if len(batches) != len(want) {
+		t.Fatalf("batches = %v, want %v", batches, want)
+	}
+	for i := range want {
+		if len(batches[i]) != len(want[i]) || batches[i][0] != want[i][0] {
+			t.Errorf("batch %d = %v, want %v", i, batches[i], want[i])
+		}
+	}
+
+	stats := b.Stats()
+	if stats.Batches != 3 || stats.Records != 6 || stats.AvgBatchSize != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestBatcherFlush 测试 Flush 立即发送未满的批次并等待发送完成
+func TestBatcherFlush(t *testing.T) {
+	src := make(chan string, 16)
+	var mu sync.Mutex
+	var sent []string
+	b := NewBatcher(src, BatchConfig{MaxRecords: 100, MaxInterval: time.Hour}, nil,
+		func(batch []string) {
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			sent = append(sent, batch...)
+			mu.Unlock()
+		})
+	defer b.Close()
+
+	src <- "a"
+	src <- "b"
+	if err := b.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	got := len(sent)
+	mu.Unlock()
+	if got != 2 {
+		t.Errorf("expected 2 entries sent after Flush, got %d", got)
+	}
+}
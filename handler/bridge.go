@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// bridgeNoiseRegex 第三方库内部常见的连接噪音，开启过滤时降级为 DEBUG
+var bridgeNoiseRegex = regexp.MustCompile(`TLS handshake error|first record does not look like a TLS handshake|` +
+	`connection reset by peer|use of closed network connection|broken pipe|` +
+	`transport: loopyWriter exited|HandleStreams failed to read frame|transport is closing`)
+
+// bridgeLog 以指定级别写出一条来自第三方库的日志，skip 为相对调用方的栈深度
+func bridgeLog(logger *slog.Logger, level slog.Level, msg, component string, filterNoise bool, skip int) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return
+	}
+	if filterNoise && level > slog.LevelDebug && bridgeNoiseRegex.MatchString(msg) {
+		level = slog.LevelDebug
+	}
+
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:])
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(slog.String("component", component))
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+// GRPCLogger 将 gRPC-go 内部日志（grpclog.LoggerV2）转发到 slog
+//
+//	grpclog.SetLoggerV2(handler.NewGRPCLogger(nil, 0, true))
+type GRPCLogger struct {
+	logger      *slog.Logger // 为空时使用 slog.Default()
+	verbosity   int
+	filterNoise bool
+}
+
+// NewGRPCLogger 创建 gRPC 日志桥接，verbosity 对应 GRPC_GO_LOG_VERBOSITY_LEVEL
+func NewGRPCLogger(logger *slog.Logger, verbosity int, filterNoise bool) *GRPCLogger {
+	return &GRPCLogger{logger: logger, verbosity: verbosity, filterNoise: filterNoise}
+}
+
+func (g *GRPCLogger) log(level slog.Level, msg string) {
+	bridgeLog(g.logger, level, msg, "grpc", g.filterNoise, 2)
+}
+
+func (g *GRPCLogger) Info(args ...any) {
+	g.log(slog.LevelInfo, fmt.Sprint(args...))
+}
+
+func (g *GRPCLogger) Infoln(args ...any) {
+	g.log(slog.LevelInfo, fmt.Sprintln(args...))
+}
+
+func (g *GRPCLogger) Infof(format string, args ...any) {
+	g.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (g *GRPCLogger) Warning(args ...any) {
+	g.log(slog.LevelWarn, fmt.Sprint(args...))
+}
+
+func (g *GRPCLogger) Warningln(args ...any) {
+	g.log(slog.LevelWarn, fmt.Sprintln(args...))
+}
+
+func (g *GRPCLogger) Warningf(format string, args ...any) {
+	g.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (g *GRPCLogger) Error(args ...any) {
+	g.log(slog.LevelError, fmt.Sprint(args...))
+}
+
+func (g *GRPCLogger) Errorln(args ...any) {
+	g.log(slog.LevelError, fmt.Sprintln(args...))
+}
+
+func (g *GRPCLogger) Errorf(format string, args ...any) {
+	g.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Fatal 系列按 grpclog 约定在记录后退出进程
+func (g *GRPCLogger) Fatal(args ...any) {
+	g.log(slog.LevelError, fmt.Sprint(args...))
+	os.Exit(1)
+}
+
+func (g *GRPCLogger) Fatalln(args ...any) {
+	g.log(slog.LevelError, fmt.Sprintln(args...))
+	os.Exit(1)
+}
+
+func (g *GRPCLogger) Fatalf(format string, args ...any) {
+	g.log(slog.LevelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// V 报告是否输出指定详细级别的日志
+func (g *GRPCLogger) V(l int) bool {
+	return l <= g.verbosity
+}
+
+// httpErrorWriter 解析 net/http 内部日志并转发到 slog
+type httpErrorWriter struct {
+	logger      *slog.Logger
+	filterNoise bool
+}
+
+func (w *httpErrorWriter) Write(p []byte) (int, error) {
+	msg := string(p)
+	level := slog.LevelWarn
+	if strings.Contains(msg, "panic serving") {
+		level = slog.LevelError
+	}
+	// log.Logger.Output -> Write，跳过 log 包的两层
+	bridgeLog(w.logger, level, msg, "net/http", w.filterNoise, 3)
+	return len(p), nil
+}
+
+// NewHTTPErrorLog 创建可用作 http.Server.ErrorLog 的标准库 Logger
+//
+//	srv := &http.Server{ErrorLog: handler.NewHTTPErrorLog(nil, true)}
+func NewHTTPErrorLog(logger *slog.Logger, filterNoise bool) *log.Logger {
+	return log.New(&httpErrorWriter{logger: logger, filterNoise: filterNoise}, "", 0)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestBridgeLevelMapping 测试 gRPC 与 net/http 日志的级别映射和噪音降级
+func TestBridgeLevelMapping(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	grpcLogger := NewGRPCLogger(logger, 0, true)
+	grpcLogger.Warningf("addrConn failed: %s", "dial tcp")
+	grpcLogger.Info("transport: loopyWriter exited with error: connection reset by peer")
+
+	errorLog := NewHTTPErrorLog(logger, true)
+	errorLog.Printf("http: TLS handshake error from 10.0.0.1:5000: EOF")
+	errorLog.Printf("http: panic serving 10.0.0.1:5000: boom")
+
+	got := buf.String()
+	for _, want := range []string{
+		`level=WARN msg="addrConn failed: dial tcp" component=grpc`,
+		`level=ERROR msg="http: panic serving 10.0.0.1:5000: boom" component=net/http`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "loopyWriter") || strings.Contains(got, "TLS handshake") {
+		t.Errorf("noise should be demoted below INFO:\n%s", got)
+	}
+	if grpcLogger.V(1) || !grpcLogger.V(0) {
+		t.Error("V should respect verbosity")
+	}
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// TruncatedKey 超出大小上限而被截断的记录附加的标记属性
+const TruncatedKey = "truncated"
+
+// minTruncatedValue 截断后每个属性至少保留的字节数，使截断的值仍可辨认
+const minTruncatedValue = 64
+
+// RecordBudget 单条记录序列化后的大小上限，超出时截断其中最大的属性，避免 slog.Any(userData)
+// 之类的值产生数 MB 的日志行导致下游解析失败
+type RecordBudget struct {
+	max       int
+	truncated atomic.Int64
+}
+
+// NewRecordBudget 创建记录大小上限，max 为字节数
+func NewRecordBudget(max int) *RecordBudget {
+	return &RecordBudget{max: max}
+}
+
+// Truncated 返回被截断的记录数
+func (b *RecordBudget) Truncated() int64 {
+	return b.truncated.Load()
+}
+
+// RecordBudgetHandler 估算记录序列化后的大小（含 WithAttrs 绑定的属性），超出上限时从最大的属性开始截断为字符串，
+// 仍然超出时截断消息，并附加 truncated=true。估算不解析 LogValuer、不序列化任意值，
+// 只有估算超出上限时才序列化计算准确大小
+type RecordBudgetHandler struct {
+	handler slog.Handler
+	budget  *RecordBudget
+	bound   int // WithAttrs 绑定属性的估算大小
+}
+
+// NewRecordBudgetHandler 创建记录大小限制处理器，budget 为nil或上限<=0时直接返回原处理器
+func NewRecordBudgetHandler(handler slog.Handler, budget *RecordBudget) slog.Handler {
+	if budget == nil || budget.max <= 0 {
+		return handler
+	}
+	return &RecordBudgetHandler{handler: handler, budget: budget}
+}
+
+func (h *RecordBudgetHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RecordBudgetHandler) Handle(ctx context.Context, r slog.Record) error {
+	total := h.bound + len(r.Message) + recordOverhead
+	r.Attrs(func(a slog.Attr) bool {
+		total += estimateSize(a, h.budget.max)
+		return total <= h.budget.max
+	})
+	if total <= h.budget.max {
+		return h.handler.Handle(ctx, r)
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	sizes := make([]int, 0, r.NumAttrs())
+	total = h.bound + len(r.Message) + recordOverhead
+	r.Attrs(func(a slog.Attr) bool {
+		size := attrSize(a)
+		attrs = append(attrs, a)
+		sizes = append(sizes, size)
+		total += size
+		return true
+	})
+	if total <= h.budget.max {
+		return h.handler.Handle(ctx, r)
+	}
+	h.budget.truncated.Add(1)
+
+	// 从最大的属性开始截断，直到总大小回到上限以内
+	excess := total - h.budget.max + len(TruncatedKey) + 8
+	order := make([]int, len(attrs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return sizes[order[i]] > sizes[order[j]] })
+	for _, i := range order {
+		if excess <= 0 {
+			break
+		}
+		// 转义与截断说明使实际缩减量小于截去的字节数，逐步收紧直到够用或达到最小保留长度
+		s := attrString(attrs[i].Value)
+		size := sizes[i]
+		for keep := len(s) - excess; keep < len(s); {
+			keep = max(keep, minTruncatedValue)
+			attrs[i] = slog.String(attrs[i].Key, truncateUTF8(s, keep))
+			newSize := attrSize(attrs[i])
+			excess -= size - newSize
+			size = newSize
+			if excess <= 0 || keep == minTruncatedValue {
+				break
+			}
+			keep -= excess
+		}
+	}
+
+	msg := r.Message
+	if excess > 0 {
+		msg = truncateUTF8(msg, max(len(msg)-excess, minTruncatedValue))
+	}
+	nr := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	nr.AddAttrs(attrs...)
+	nr.AddAttrs(slog.Bool(TruncatedKey, true))
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *RecordBudgetHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	bound := h.bound
+	for _, a := range attrs {
+		bound += estimateSize(a, h.budget.max)
+	}
+	return &RecordBudgetHandler{handler: h.handler.WithAttrs(attrs), budget: h.budget, bound: bound}
+}
+
+func (h *RecordBudgetHandler) WithGroup(name string) slog.Handler {
+	return &RecordBudgetHandler{handler: h.handler.WithGroup(name), budget: h.budget, bound: h.bound + len(name) + 5}
+}
+
+// recordOverhead time、level、msg 等固定字段的估算大小
+const recordOverhead = 96
+
+// attrSize 估算属性以 JSON 输出时的字节数，"key":value,
+func attrSize(a slog.Attr) int {
+	v := a.Value.Resolve()
+	size := len(a.Key) + 4
+	switch v.Kind() {
+	case slog.KindString:
+		return size + jsonStringLen(v.String())
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		return size + 20
+	case slog.KindBool:
+		return size + 5
+	case slog.KindTime:
+		return size + 35
+	case slog.KindGroup:
+		size += 2
+		for _, ga := range v.Group() {
+			size += attrSize(ga)
+		}
+		return size
+	default:
+		return size + jsonStringLen(attrString(v))
+	}
+}
+
+// maxEstimateDepth estimateSize 遍历任意值的最大嵌套深度
+const maxEstimateDepth = 32
+
+// marshalerEstimate 实现 json.Marshaler 或 encoding.TextMarshaler 的值的估算大小，其输出只能通过调用得知
+const marshalerEstimate = 32
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// estimateSize 不解析 LogValuer、不序列化地估算属性以 JSON 输出时的字节数，超过 limit 后提前返回
+func estimateSize(a slog.Attr, limit int) int {
+	v := a.Value
+	size := len(a.Key) + 4
+	switch v.Kind() {
+	case slog.KindString:
+		return size + jsonStringLen(v.String())
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		return size + 20
+	case slog.KindBool:
+		return size + 5
+	case slog.KindTime:
+		return size + 35
+	case slog.KindGroup:
+		size += 2
+		for _, ga := range v.Group() {
+			if size > limit {
+				break
+			}
+			size += estimateSize(ga, limit)
+		}
+		return size
+	default:
+		if err, ok := v.Any().(error); ok {
+			return size + jsonStringLen(err.Error())
+		}
+		return size + anySize(reflect.ValueOf(v.Any()), limit, 0)
+	}
+}
+
+// anySize 按反射遍历估算任意值序列化为 JSON 的字节数，超过 limit 或嵌套过深时提前返回
+func anySize(rv reflect.Value, limit, depth int) int {
+	if !rv.IsValid() {
+		return 4
+	}
+	if depth > maxEstimateDepth {
+		return 0
+	}
+	if t := rv.Type(); t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return marshalerEstimate
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return 4
+		}
+		return anySize(rv.Elem(), limit, depth+1)
+	case reflect.String:
+		return jsonStringLen(rv.String())
+	case reflect.Bool:
+		return 5
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return 20
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return 4
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return (rv.Len()+2)/3*4 + 2 // base64
+		}
+		size := 2
+		for i := 0; i < rv.Len() && size <= limit; i++ {
+			size += anySize(rv.Index(i), limit, depth+1) + 1
+		}
+		return size
+	case reflect.Map:
+		if rv.IsNil() {
+			return 4
+		}
+		size := 2
+		iter := rv.MapRange()
+		for iter.Next() && size <= limit {
+			size += anySize(iter.Key(), limit, depth+1) + anySize(iter.Value(), limit, depth+1) + 4
+		}
+		return size
+	case reflect.Struct:
+		size := 2
+		t := rv.Type()
+		for i := 0; i < t.NumField() && size <= limit; i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" {
+				continue
+			}
+			size += len(f.Name) + 4 + anySize(rv.Field(i), limit, depth+1)
+		}
+		return size
+	default:
+		return 4
+	}
+}
+
+// jsonStringLen 字符串按 JSON 转义后的字节数
+func jsonStringLen(s string) int {
+	n := len(s)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			n++
+		case c < 0x20:
+			n += 5
+		}
+	}
+	return n
+}
+
+// attrString 属性值的文本形式：字符串原样返回，分组与任意值按 JSON 序列化
+func attrString(v slog.Value) string {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindGroup:
+		m := make(map[string]any, len(v.Group()))
+		for _, ga := range v.Group() {
+			m[ga.Key] = attrString(ga.Value)
+		}
+		if data, err := json.Marshal(m); err == nil {
+			return string(data)
+		}
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+		if data, err := json.Marshal(v.Any()); err == nil {
+			return string(data)
+		}
+		return fmt.Sprintf("%+v", v.Any())
+	}
+	return v.String()
+}
+
+// truncateUTF8 截断到不超过 n 字节的完整字符，并注明原始长度
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "...(truncated " + strconv.Itoa(len(s)) + " bytes)"
+}
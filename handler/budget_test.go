@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestRecordBudgetHandler 测试超出上限的记录只截断最大的属性并标记 truncated，未超出的记录原样输出
+func TestRecordBudgetHandler(t *testing.T) {
+	var buf bytes.Buffer
+	budget := NewRecordBudget(1024)
+	logger := slog.New(NewRecordBudgetHandler(slog.NewJSONHandler(&buf, nil), budget)).With("service", "api")
+
+	userData := map[string]any{"blob": strings.Repeat("x", 4096)}
+	logger.Info("payload", "user_data", userData, "user_id", 42, "note", strings.Repeat("y", 200))
+	logger.Info("small", "user_id", 7)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if len(lines[0]) > 1024 {
+		t.Errorf("truncated record is %d bytes, want <= 1024", len(lines[0]))
+	}
+	var first map[string]any
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	if first["truncated"] != true || first["user_id"] != float64(42) || first["service"] != "api" {
+		t.Errorf("unexpected truncated record: %s", lines[0])
+	}
+	if s, _ := first["user_data"].(string); !strings.Contains(s, "truncated 4") {
+		t.Errorf("largest attr should be truncated: %s", lines[0])
+	}
+	if first["note"] != strings.Repeat("y", 200) {
+		t.Errorf("smaller attrs should be kept when the largest one suffices: %s", lines[0])
+	}
+	if bytes.Contains(lines[1], []byte("truncated")) {
+		t.Errorf("small record should be untouched: %s", lines[1])
+	}
+	if got := budget.Truncated(); got != 1 {
+		t.Errorf("Truncated() = %d, want 1", got)
+	}
+}
+
+// countingValuer 记录 LogValue 的调用次数
+type countingValuer struct {
+	calls *int
+	Items []string
+}
+
+func (v countingValuer) LogValue() slog.Value {
+	*v.calls++
+	return slog.AnyValue(v.Items)
+}
+
+// TestRecordBudgetEstimate 测试未超出上限的记录不解析 LogValuer，按估算超出上限的切片和字节值仍被截断
+func TestRecordBudgetEstimate(t *testing.T) {
+	calls := 0
+	small := countingValuer{calls: &calls, Items: []string{"a", "b"}}
+	if size := estimateSize(slog.Any("v", small), 1024); size <= 0 || size > 64 {
+		t.Errorf("estimateSize(small valuer) = %d", size)
+	}
+	if calls != 0 {
+		t.Errorf("estimate should not resolve LogValuers, got %d calls", calls)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(NewRecordBudgetHandler(slog.NewJSONHandler(&buf, nil), NewRecordBudget(512)))
+	logger.Info("small", "v", small)
+	if calls != 1 {
+		t.Errorf("small record should resolve the LogValuer once in the output handler, got %d calls", calls)
+	}
+
+	items := make([]string, 200)
+	for i := range items {
+		items[i] = "item"
+	}
+	logger.Info("big", "items", items, "blob", bytes.Repeat([]byte{1}, 1024))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 || len(lines[1]) > 512 || !bytes.Contains(lines[1], []byte(`"truncated":true`)) {
+		t.Errorf("oversized record should be truncated: %s", lines[len(lines)-1])
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBurstWindow 默认的突发汇总周期
+const DefaultBurstWindow = 30 * time.Second
+
+// BurstConfig 日志风暴汇总配置
+type BurstConfig struct {
+	Threshold int           // 每秒记录数超过该值时进入风暴模式，<=0 表示不启用
+	Window    time.Duration // 汇总周期，默认30秒
+}
+
+// BurstHandler 在日志风暴期间按消息模板合并相似记录：每个周期内同一级别、同一模板的记录只放行第一条，
+// 其余计数并在周期结束时输出一条汇总；不同的消息照常放行，速率回落到阈值以下后恢复逐条输出
+type BurstHandler struct {
+	handler slog.Handler
+	state   *burstState
+}
+
+// burstState WithAttrs/WithGroup 派生的处理器共享的风暴状态
+type burstState struct {
+	mu       sync.Mutex
+	cfg      BurstConfig
+	emit     slog.Handler // 汇总记录的输出处理器
+	secStart time.Time
+	secCount int
+	storming bool
+	start    time.Time
+	entries  map[string]*burstEntry
+	timer    *time.Timer // 当前周期结束时输出汇总
+	closed   bool
+}
+
+// burstEntry 一个周期内同类记录的计数
+type burstEntry struct {
+	level      slog.Level
+	sample     string
+	template   string
+	suppressed int
+}
+
+// NewBurstHandler 创建风暴汇总处理器，Threshold<=0 时直接返回原处理器
+func NewBurstHandler(handler slog.Handler, cfg BurstConfig) slog.Handler {
+	if cfg.Threshold <= 0 {
+		return handler
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultBurstWindow
+	}
+	return &BurstHandler{handler: handler, state: &burstState{cfg: cfg, emit: handler}}
+}
+
+func (h *BurstHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *BurstHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.state.suppress(r) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// Flush 立即输出当前周期内被合并记录的汇总并开始新的周期
+func (h *BurstHandler) Flush() {
+	h.state.flush()
+}
+
+// Close 停止汇总定时器并输出尚未输出的汇总，之后的记录逐条放行
+func (h *BurstHandler) Close() error {
+	h.state.mu.Lock()
+	h.state.closed = true
+	h.state.mu.Unlock()
+	h.state.flush()
+	return nil
+}
+
+func (h *BurstHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BurstHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+func (h *BurstHandler) WithGroup(name string) slog.Handler {
+	return &BurstHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
+
+// suppress 统计速率并判断记录是否应合并到汇总中
+func (s *burstState) suppress(r slog.Record) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	// 按秒统计速率：紧邻的上一秒超过阈值时保持风暴模式，否则退出
+	now := time.Now()
+	if now.Sub(s.secStart) >= time.Second {
+		s.storming = s.secCount > s.cfg.Threshold && now.Sub(s.secStart) < 2*time.Second
+		s.secStart = now
+		s.secCount = 0
+	}
+	s.secCount++
+	if s.secCount > s.cfg.Threshold {
+		s.storming = true
+	}
+	if !s.storming {
+		return false
+	}
+
+	template := MessageTemplate(r.Message)
+	key := r.Level.String() + "|" + template
+	if s.entries == nil {
+		s.start = now
+		s.entries = make(map[string]*burstEntry)
+		s.timer = time.AfterFunc(s.cfg.Window, s.flush)
+	}
+	entry, ok := s.entries[key]
+	if !ok {
+		// 周期内第一次出现的消息照常放行，作为汇总的样本
+		s.entries[key] = &burstEntry{level: r.Level, sample: r.Message, template: template}
+		return false
+	}
+	entry.suppressed++
+	return true
+}
+
+// flush 输出当前周期内被合并记录的汇总并开始新的周期
+func (s *burstState) flush() {
+	s.mu.Lock()
+	entries, start := s.entries, s.start
+	s.entries = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mu.Unlock()
+
+	summaries := make([]*burstEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.suppressed > 0 {
+			summaries = append(summaries, e)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].suppressed > summaries[j].suppressed })
+
+	for _, e := range summaries {
+		msg := fmt.Sprintf("%d similar %s records in last %s", e.suppressed, strings.ToLower(e.level.String()), s.cfg.Window)
+		r := slog.NewRecord(time.Now(), e.level, msg, 0)
+		r.AddAttrs(
+			slog.String("type", "burst_summary"),
+			slog.Int("suppressed", e.suppressed),
+			slog.Time("since", start),
+			slog.Duration("window", s.cfg.Window),
+			slog.String("sample", e.sample),
+			slog.String(MsgTemplateKey, e.template),
+		)
+		ctx := context.Background()
+		if s.emit.Enabled(ctx, e.level) {
+			_ = s.emit.Handle(ctx, r)
+		}
+	}
+}
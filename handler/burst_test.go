@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// capturingHandler 并发安全地收集记录的测试处理器
+type capturingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *capturingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *capturingHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *capturingHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	msgs := make([]string, len(h.records))
+	for i, r := range h.records {
+		msgs[i] = r.Message
+	}
+	return msgs
+}
+
+// TestBurstHandler 测试风暴期间相似记录合并为汇总，不同的消息照常放行
+func TestBurstHandler(t *testing.T) {
+	capture := &capturingHandler{}
+	logger := slog.New(NewBurstHandler(capture, BurstConfig{Threshold: 10, Window: 50 * time.Millisecond}))
+
+	for i := 0; i < 100; i++ {
+		logger.Warn(fmt.Sprintf("upstream timeout after %dms", i))
+	}
+	logger.Error("database unavailable")
+
+	msgs := capture.messages()
+	if len(msgs) != 12 { // 阈值内的 10 条、风暴中第一条样本、不同的错误
+		t.Fatalf("expected 12 records before the summary, got %d: %v", len(msgs), msgs)
+	}
+	if msgs[11] != "database unavailable" {
+		t.Errorf("distinct messages should pass through during a storm, got %q", msgs[11])
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	msgs = capture.messages()
+	if len(msgs) != 13 || msgs[12] != "89 similar warn records in last 50ms" {
+		t.Fatalf("expected one summary record, got %v", msgs[12:])
+	}
+	capture.mu.Lock()
+	summary := capture.records[12]
+	capture.mu.Unlock()
+	summary.Attrs(func(a slog.Attr) bool {
+		if a.Key == "sample" && a.Value.String() != "upstream timeout after 10ms" {
+			t.Errorf("summary sample = %q", a.Value.String())
+		}
+		return true
+	})
+}
+
+// TestBurstHandlerClose 测试关闭时停止定时器并立即输出尚未输出的汇总
+func TestBurstHandlerClose(t *testing.T) {
+	capture := &capturingHandler{}
+	h := NewBurstHandler(capture, BurstConfig{Threshold: 5, Window: time.Hour}).(*BurstHandler)
+	logger := slog.New(h)
+
+	for i := 0; i < 20; i++ {
+		logger.Warn("queue full")
+	}
+	h.Flush()
+	msgs := capture.messages()
+	if len(msgs) != 7 || msgs[6] != "14 similar warn records in last 1h0m0s" {
+		t.Fatalf("expected flush to emit the summary, got %v", msgs)
+	}
+
+	logger.Warn("queue full")
+	logger.Warn("queue full")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	msgs = capture.messages()
+	if len(msgs) != 9 || msgs[8] != "1 similar warn records in last 1h0m0s" {
+		t.Fatalf("expected close to emit the pending summary, got %v", msgs[7:])
+	}
+	h.state.mu.Lock()
+	timer := h.state.timer
+	h.state.mu.Unlock()
+	if timer != nil {
+		t.Error("timer should be stopped after close")
+	}
+
+	logger.Warn("queue full")
+	if n := len(capture.messages()); n != 10 {
+		t.Errorf("records after close should pass through, got %d records", n)
+	}
+}
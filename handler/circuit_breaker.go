@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常放行
+	BreakerOpen     = "open"      // 熔断中，直接走降级路径
+	BreakerHalfOpen = "half_open" // 试探性放行一次
+)
+
+// BreakerConfig 熔断器配置
+type BreakerConfig struct {
+	FailureThreshold int           // 连续失败多少次后熔断
+	OpenDuration     time.Duration // 熔断持续时间，之后进入半开状态试探
+}
+
+// BreakerStats 熔断器统计
+type BreakerStats struct {
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Opens               int64     `json:"opens"`    // 累计熔断次数
+	Rejected            int64     `json:"rejected"` // 熔断期间被拒绝（走降级路径）的次数
+	LastFailure         time.Time `json:"last_failure,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// CircuitBreaker 远程输出的熔断器，连续失败后在一段时间内直接拒绝请求，避免每条记录都等待连接超时
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	state    string
+	failures int
+	openedAt time.Time
+	stats    BreakerStats
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = 30 * time.Second
+	}
+	return &CircuitBreaker{cfg: cfg, state: BreakerClosed}
+}
+
+// Allow 判断当前是否允许请求通过
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) >= b.cfg.OpenDuration {
+			// 熔断期结束，放行一次试探请求
+			b.state = BreakerHalfOpen
+			return true
+		}
+		b.stats.Rejected++
+		return false
+	case BreakerHalfOpen:
+		// 试探请求尚未返回结果时拒绝其他请求
+		b.stats.Rejected++
+		return false
+	default:
+		return true
+	}
+}
+
+// Success 记录一次成功，恢复到正常状态
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = BreakerClosed
+}
+
+// Failure 记录一次失败，达到阈值或试探失败时熔断
+func (b *CircuitBreaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.stats.LastFailure = time.Now()
+	if err != nil {
+		b.stats.LastError = err.Error()
+	}
+
+	if b.state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+		b.stats.Opens++
+	}
+}
+
+// Stats 返回熔断器统计
+func (b *CircuitBreaker) Stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.State = b.state
+	stats.ConsecutiveFailures = b.failures
+	return stats
+}
+
+// CircuitBreakerHandler 为输出处理器加上熔断保护，熔断或失败时将记录转交降级处理器
+type CircuitBreakerHandler struct {
+	handler  slog.Handler
+	fallback slog.Handler // 可为nil，表示直接丢弃
+	breaker  *CircuitBreaker
+}
+
+// NewCircuitBreakerHandler 创建熔断处理器
+func NewCircuitBreakerHandler(handler, fallback slog.Handler, breaker *CircuitBreaker) *CircuitBreakerHandler {
+	return &CircuitBreakerHandler{handler: handler, fallback: fallback, breaker: breaker}
+}
+
+// Breaker 返回使用的熔断器
+func (h *CircuitBreakerHandler) Breaker() *CircuitBreaker {
+	return h.breaker
+}
+
+func (h *CircuitBreakerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *CircuitBreakerHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.breaker.Allow() {
+		return h.handleFallback(ctx, r)
+	}
+
+	if err := h.handler.Handle(ctx, r.Clone()); err != nil {
+		h.breaker.Failure(err)
+		return h.handleFallback(ctx, r)
+	}
+	h.breaker.Success()
+	return nil
+}
+
+// handleFallback 交给降级处理器
+func (h *CircuitBreakerHandler) handleFallback(ctx context.Context, r slog.Record) error {
+	if h.fallback == nil {
+		return nil
+	}
+	return h.fallback.Handle(ctx, r)
+}
+
+func (h *CircuitBreakerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fallback slog.Handler
+	if h.fallback != nil {
+		fallback = h.fallback.WithAttrs(attrs)
+	}
+	return &CircuitBreakerHandler{handler: h.handler.WithAttrs(attrs), fallback: fallback, breaker: h.breaker}
+}
+
+func (h *CircuitBreakerHandler) WithGroup(name string) slog.Handler {
+	var fallback slog.Handler
+	if h.fallback != nil {
+		fallback = h.fallback.WithGroup(name)
+	}
+	return &CircuitBreakerHandler{handler: h.handler.WithGroup(name), fallback: fallback, breaker: h.breaker}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingHandler 总是返回错误的处理器
+type failingHandler struct {
+	calls int
+}
+
+func (h *failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *failingHandler) Handle(context.Context, slog.Record) error {
+	h.calls++
+	return errors.New("connection refused")
+}
+func (h *failingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *failingHandler) WithGroup(string) slog.Handler      { return h }
+
+// TestCircuitBreakerHandler 测试连续失败后熔断并走降级路径
+func TestCircuitBreakerHandler(t *testing.T) {
+	remote := &failingHandler{}
+	var fallback bytes.Buffer
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour})
+	logger := slog.New(NewCircuitBreakerHandler(remote, slog.NewTextHandler(&fallback, nil), breaker))
+
+	for i := 0; i < 5; i++ {
+		logger.Info("record")
+	}
+
+	if remote.calls != 2 {
+		t.Errorf("remote should only be called until the circuit opens, got %d calls", remote.calls)
+	}
+	if got := strings.Count(fallback.String(), "record"); got != 5 {
+		t.Errorf("all records should reach the fallback, got %d", got)
+	}
+
+	stats := breaker.Stats()
+	if stats.State != BreakerOpen || stats.Opens != 1 || stats.Rejected != 3 {
+		t.Errorf("unexpected breaker stats: %+v", stats)
+	}
+}
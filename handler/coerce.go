@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// CoerceType 属性值的目标类型
+type CoerceType string
+
+const (
+	CoerceString CoerceType = "string"
+	CoerceInt    CoerceType = "int"
+	CoerceFloat  CoerceType = "float"
+)
+
+// CoerceRawSuffix 转换失败时原值改写到的键名后缀，如 status 转换失败时写入 status_raw（字符串）
+const CoerceRawSuffix = "_raw"
+
+// Coercer 将指定属性转换为固定类型，避免 Elasticsearch 等下游因同一字段类型不一致而拒收记录；
+// 转换失败时原值以字符串写入 <key>_raw 并计数
+type Coercer struct {
+	types    map[string]CoerceType // 完整路径 → 目标类型
+	nested   bool                  // 是否有分组内的路径
+	failures atomic.Int64
+}
+
+// NewCoercer 创建类型转换器，types 为完整路径（如 http.status）到 string、int、float 的映射
+func NewCoercer(types map[string]string) (*Coercer, error) {
+	c := &Coercer{types: make(map[string]CoerceType, len(types))}
+	for path, name := range types {
+		t := CoerceType(strings.ToLower(name))
+		switch t {
+		case CoerceString, CoerceInt, CoerceFloat:
+		default:
+			return nil, fmt.Errorf("coerce %s: unknown type %q", path, name)
+		}
+		c.types[path] = t
+		c.nested = c.nested || strings.Contains(path, ".")
+	}
+	return c, nil
+}
+
+// Failures 返回转换失败的次数
+func (c *Coercer) Failures() int64 {
+	return c.failures.Load()
+}
+
+// matches 检查属性是否需要转换，或是可能包含需转换属性的分组
+func (c *Coercer) matches(prefix string, a slog.Attr) bool {
+	if _, ok := c.types[joinKey(prefix, a.Key)]; ok {
+		return true
+	}
+	return c.nested && a.Value.Kind() == slog.KindGroup
+}
+
+// coerceAttrs 返回转换后的属性，不修改 attrs
+func (c *Coercer) coerceAttrs(prefix string, attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		key := joinKey(prefix, a.Key)
+		if t, ok := c.types[key]; ok {
+			v := a.Value.Resolve()
+			if coerced, ok := coerceValue(v, t); ok {
+				out = append(out, slog.Attr{Key: a.Key, Value: coerced})
+			} else {
+				c.failures.Add(1)
+				out = append(out, slog.String(a.Key+CoerceRawSuffix, v.String()))
+			}
+			continue
+		}
+		if c.nested && a.Value.Kind() == slog.KindGroup {
+			a.Value = slog.GroupValue(c.coerceAttrs(key, a.Value.Group())...)
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// coerceValue 将值转换为目标类型；浮点数只有为整数时才能转换为 int
+func coerceValue(v slog.Value, t CoerceType) (slog.Value, bool) {
+	switch t {
+	case CoerceString:
+		if v.Kind() == slog.KindGroup {
+			return slog.Value{}, false
+		}
+		return slog.StringValue(v.String()), true
+
+	case CoerceInt:
+		switch v.Kind() {
+		case slog.KindInt64:
+			return v, true
+		case slog.KindUint64:
+			if v.Uint64() <= math.MaxInt64 {
+				return slog.Int64Value(int64(v.Uint64())), true
+			}
+		case slog.KindDuration:
+			return slog.Int64Value(int64(v.Duration())), true
+		case slog.KindFloat64:
+			return floatToInt(v.Float64())
+		case slog.KindString:
+			s := strings.TrimSpace(v.String())
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return slog.Int64Value(n), true
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return floatToInt(f)
+			}
+		}
+
+	case CoerceFloat:
+		switch v.Kind() {
+		case slog.KindFloat64:
+			return v, true
+		case slog.KindInt64:
+			return slog.Float64Value(float64(v.Int64())), true
+		case slog.KindUint64:
+			return slog.Float64Value(float64(v.Uint64())), true
+		case slog.KindDuration:
+			return slog.Float64Value(float64(v.Duration())), true
+		case slog.KindString:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+				return slog.Float64Value(f), true
+			}
+		}
+	}
+	return slog.Value{}, false
+}
+
+// floatToInt 只转换整数值的浮点数，避免静默截断
+func floatToInt(f float64) (slog.Value, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+		return slog.Value{}, false
+	}
+	return slog.Int64Value(int64(f)), true
+}
+
+// CoerceHandler 在写出前按 Coercer 转换属性类型，WithAttrs 绑定的属性同样转换
+type CoerceHandler struct {
+	handler slog.Handler
+	coercer *Coercer
+	group   string // WithGroup 累积的组名
+}
+
+// NewCoerceHandler 创建类型转换处理器，coercer 为nil或没有规则时直接返回原处理器
+func NewCoerceHandler(handler slog.Handler, coercer *Coercer) slog.Handler {
+	if coercer == nil || len(coercer.types) == 0 {
+		return handler
+	}
+	return &CoerceHandler{handler: handler, coercer: coercer}
+}
+
+func (h *CoerceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *CoerceHandler) Handle(ctx context.Context, r slog.Record) error {
+	needed := false
+	r.Attrs(func(a slog.Attr) bool {
+		needed = h.coercer.matches(h.group, a)
+		return !needed
+	})
+	if !needed {
+		return h.handler.Handle(ctx, r)
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(h.coercer.coerceAttrs(h.group, attrs)...)
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *CoerceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CoerceHandler{handler: h.handler.WithAttrs(h.coercer.coerceAttrs(h.group, attrs)), coercer: h.coercer, group: h.group}
+}
+
+func (h *CoerceHandler) WithGroup(name string) slog.Handler {
+	return &CoerceHandler{handler: h.handler.WithGroup(name), coercer: h.coercer, group: joinKey(h.group, name)}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestCoerceHandler 测试按路径统一类型（含分组与 WithAttrs 绑定的属性），失败时写入 _raw 并计数
+func TestCoerceHandler(t *testing.T) {
+	var buf bytes.Buffer
+	coercer, err := NewCoercer(map[string]string{
+		"status":          "int",
+		"user_id":         "string",
+		"http.latency_ms": "float",
+		"retries":         "int",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(NewCoerceHandler(slog.NewJSONHandler(&buf, nil), coercer)).With("user_id", 42)
+	logger.Info("ok", "status", "200", slog.Group("http", slog.String("latency_ms", "12.5")), "other", "7")
+	logger.Info("bad", "status", "abc", "retries", 1.5)
+	logger.WithGroup("http").Info("grouped", "latency_ms", 3)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	records := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal(line, &records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := records[0]
+	if first["status"] != float64(200) || first["user_id"] != "42" || first["other"] != "7" {
+		t.Errorf("attrs not coerced: %s", lines[0])
+	}
+	if http, _ := first["http"].(map[string]any); http["latency_ms"] != 12.5 {
+		t.Errorf("grouped attr not coerced: %s", lines[0])
+	}
+
+	second := records[1]
+	if second["status"] != nil || second["status_raw"] != "abc" || second["retries_raw"] != "1.5" {
+		t.Errorf("failed coercion should move value to _raw: %s", lines[1])
+	}
+	if got := coercer.Failures(); got != 2 {
+		t.Errorf("Failures() = %d, want 2", got)
+	}
+
+	if http, _ := records[2]["http"].(map[string]any); http["latency_ms"] != float64(3) {
+		t.Errorf("paths under WithGroup should be coerced: %s", lines[2])
+	}
+
+	if _, err := NewCoercer(map[string]string{"a": "bool"}); err == nil {
+		t.Error("unknown type should be rejected")
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -139,15 +140,32 @@ func colorize(msg string, enableHighlight bool) string {
 	return msg
 }
 
+// colorBufPool 复用 ColorHandler 格式化记录的缓冲区
+var colorBufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// putColorBuf 归还缓冲区，过大的缓冲区直接丢弃以免长期占用内存
+func putColorBuf(buf *bytes.Buffer) {
+	if buf.Cap() <= 64<<10 {
+		colorBufPool.Put(buf)
+	}
+}
+
 // ColorHandler 彩色日志处理器，提供美观的控制台输出
 type ColorHandler struct {
 	w               io.Writer
 	opts            *slog.HandlerOptions
-	levelColors     map[slog.Level]*color.Color
-	mu              sync.Mutex
-	lastLogTime     time.Time
+	levelTheme      LevelTheme
+	valueColors     ValueColors
+	mu              *sync.Mutex // 派生的处理器共享锁和上次输出时间
+	lastLogTime     *time.Time
 	enableHighlight bool
 	compactMode     bool
+	prettyJSON      bool
+	width           int         // 折行宽度：0 自动检测终端宽度，<0 不折行
+	hyperlinks      bool        // 源码位置和URL输出为 OSC 8 超链接
+	attrs           []slog.Attr // WithAttrs 附加的属性，已按分组嵌套
+	groups          []string
+	clock           func() time.Time // 判断与上一条日志间隔的时钟，Renderer 中固定
 }
 
 // NewColorHandler 创建新的彩色处理器
@@ -159,14 +177,15 @@ func NewColorHandler(w io.Writer, opts *slog.HandlerOptions) *ColorHandler {
 	return &ColorHandler{
 		w:               w,
 		opts:            opts,
+		mu:              &sync.Mutex{},
+		lastLogTime:     &time.Time{},
+		clock:           time.Now,
+		hyperlinks:      hyperlinksSupported() && DetectWidth(w) > 0,
 		enableHighlight: true,
 		compactMode:     false,
-		levelColors: map[slog.Level]*color.Color{
-			slog.LevelDebug: color.New(color.FgHiWhite),
-			slog.LevelInfo:  color.New(color.FgGreen),
-			slog.LevelWarn:  color.New(color.FgYellow),
-			slog.LevelError: color.New(color.FgRed),
-		},
+		prettyJSON:      true,
+		levelTheme:      DefaultLevelTheme(),
+		valueColors:     DefaultValueColors(),
 	}
 }
 
@@ -178,6 +197,51 @@ func NewColorHandlerWithOptions(w io.Writer, opts *slog.HandlerOptions, enableHi
 	return handler
 }
 
+// SetWidth 设置折行宽度：0 自动检测终端宽度（非终端时不折行），<0 不折行，>0 使用固定宽度
+func (h *ColorHandler) SetWidth(width int) {
+	h.width = width
+}
+
+// lineWidth 返回当前的折行宽度，0 表示不折行
+func (h *ColorHandler) lineWidth() int {
+	switch {
+	case h.width > 0:
+		return h.width
+	case h.width == 0:
+		return DetectWidth(h.w)
+	default:
+		return 0
+	}
+}
+
+// SetHyperlinks 设置是否将源码位置和URL输出为可点击的 OSC 8 超链接，默认在支持的终端中开启
+func (h *ColorHandler) SetHyperlinks(enabled bool) {
+	h.hyperlinks = enabled
+}
+
+// link 开启超链接且输出带颜色时包装为超链接，否则返回原文本
+func (h *ColorHandler) link(target, text string) string {
+	if !h.hyperlinks || color.NoColor {
+		return text
+	}
+	return hyperlink(target, text)
+}
+
+// SetValueColors 设置按属性取值着色的规则，如 cache 的 HIT/MISS
+func (h *ColorHandler) SetValueColors(rules ValueColors) {
+	h.valueColors = rules
+}
+
+// SetLevelTheme 设置级别标签的文字与样式，如 BadgeLevelTheme() 的三字母背景色徽章
+func (h *ColorHandler) SetLevelTheme(theme LevelTheme) {
+	h.levelTheme = theme
+}
+
+// SetPrettyJSON 设置是否将JSON字符串、map等属性值缩进并语法高亮输出
+func (h *ColorHandler) SetPrettyJSON(enabled bool) {
+	h.prettyJSON = enabled
+}
+
 func (h *ColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	minLevel := slog.LevelInfo
 	if h.opts != nil && h.opts.Level != nil {
@@ -187,56 +251,81 @@ func (h *ColorHandler) Enabled(ctx context.Context, level slog.Level) bool {
 }
 
 func (h *ColorHandler) Handle(ctx context.Context, r slog.Record) error {
+	buf := colorBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer putColorBuf(buf)
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	now := time.Now()
+	now := h.clock()
 	// 如果距离上一条日志超过200毫秒，就加一个空行作为视觉分割
-	if !h.compactMode && !h.lastLogTime.IsZero() && now.Sub(h.lastLogTime) > 200*time.Millisecond {
-		fmt.Fprintln(h.w)
-	}
-	h.lastLogTime = now
-
-	// 获取级别颜色
-	levelColor := h.levelColors[r.Level]
-	if levelColor == nil {
-		levelColor = color.New(color.FgWhite)
+	if !h.compactMode && !h.lastLogTime.IsZero() && now.Sub(*h.lastLogTime) > 200*time.Millisecond {
+		fmt.Fprintln(buf)
 	}
+	*h.lastLogTime = now
 
 	// 输出日志级别和时间
+	timeFormat := "2006-01-02 15:04:05.000"
 	if h.compactMode {
-		levelColor.Fprintf(h.w, "[%s]", r.Level)
-		fmt.Fprintf(h.w, " %s", r.Time.Format("15:04:05.000"))
-	} else {
-		levelColor.Fprintf(h.w, "[%s]", r.Level)
-		fmt.Fprintf(h.w, " %s", r.Time.Format("2006-01-02 15:04:05.000"))
+		timeFormat = "15:04:05.000"
+	}
+	label, labelWidth := h.levelTheme.render(r.Level)
+	fmt.Fprint(buf, label)
+	fmt.Fprintf(buf, " %s", r.Time.Format(timeFormat))
+
+	// 对消息进行关键字高亮，超出终端宽度时折行并与首行消息对齐
+	width := h.lineWidth()
+	prefix := labelWidth + 1 + len(timeFormat) + 1
+	lines := wrapText(r.Message, prefix, prefix, width)
+	for i, line := range lines {
+		if i > 0 {
+			fmt.Fprint(buf, "\n"+strings.Repeat(" ", prefix-1))
+		}
+		fmt.Fprintf(buf, " %s", colorize(line, h.enableHighlight))
 	}
 
-	// 对消息进行关键字高亮
-	colorizedMessage := colorize(r.Message, h.enableHighlight)
-	fmt.Fprintf(h.w, " %s", colorizedMessage)
+	// 调用位置
+	if h.opts.AddSource {
+		if src := recordSource(r); src != nil {
+			a := slog.Any(slog.SourceKey, src)
+			if h.opts.ReplaceAttr != nil {
+				a = h.opts.ReplaceAttr(nil, a)
+			}
+			if a.Key != "" {
+				location := a.Value.String()
+				if s, ok := a.Value.Any().(*slog.Source); ok {
+					location = s.File + ":" + strconv.Itoa(s.Line)
+				}
+				color.New(color.FgHiBlack).Fprintf(buf, " (%s)", h.link(fileURL(src.File), location))
+			}
+		}
+	}
 
-	// 处理结构化属性
-	attrs := make([]slog.Attr, 0)
+	// 处理结构化属性：先输出 WithAttrs 附加的属性，记录自身的属性放入当前分组
+	recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
 	r.Attrs(func(a slog.Attr) bool {
-		attrs = append(attrs, a)
+		recordAttrs = append(recordAttrs, a)
 		return true
 	})
+	attrs := append(append([]slog.Attr{}, h.attrs...), nestAttrs(h.groups, recordAttrs)...)
 
 	if len(attrs) > 0 {
-		fmt.Fprintln(h.w) // 换行
+		fmt.Fprintln(buf) // 换行
 		for _, attr := range attrs {
-			h.handleAttr(attr, 1)
+			h.handleAttr(buf, attr, 1, width)
 		}
 	} else {
-		fmt.Fprintln(h.w) // 结束当前日志行
+		fmt.Fprintln(buf) // 结束当前日志行
 	}
 
-	return nil
+	// 整条记录一次写出，多个写入方共用同一输出时行不会交错
+	_, err := h.w.Write(buf.Bytes())
+	return err
 }
 
 // handleAttr 处理结构化属性
-func (h *ColorHandler) handleAttr(a slog.Attr, indent int) {
+func (h *ColorHandler) handleAttr(buf *bytes.Buffer, a slog.Attr, indent, width int) {
 	keyColor := color.New(color.FgCyan)
 	defaultValColor := color.New(color.FgWhite)
 
@@ -245,56 +334,56 @@ func (h *ColorHandler) handleAttr(a slog.Attr, indent int) {
 	// 1. 处理特殊的错误和堆栈信息
 	if a.Key == "error" || a.Key == "stack" || a.Key == "trace" {
 		errorColor := color.New(color.FgHiRed)
-		errorColor.Fprintf(h.w, "%s%s:\n", indentStr, a.Key)
+		errorColor.Fprintf(buf, "%s%s:\n", indentStr, a.Key)
 		valStr := a.Value.String()
 		for _, line := range splitLines(valStr) {
 			if line != "" {
-				errorColor.Fprintf(h.w, "%s    %s\n", indentStr, line)
+				errorColor.Fprintf(buf, "%s    %s\n", indentStr, line)
 			}
 		}
 		return
 	}
 
 	// 2. 处理特殊字段的彩色输出
-	keyColor.Fprintf(h.w, "%s%s: ", indentStr, a.Key)
+	keyColor.Fprintf(buf, "%s%s: ", indentStr, a.Key)
 
 	valStr := a.Value.String()
+	if c, ok := h.valueColors.match(a.Key, valStr); ok && a.Value.Kind() != slog.KindGroup {
+		c.Fprintln(buf, valStr)
+		return
+	}
 	handled := true
 
 	switch a.Key {
 	case "method":
-		color.New(color.FgHiBlue, color.Bold).Fprintln(h.w, valStr)
+		color.New(color.FgHiBlue, color.Bold).Fprintln(buf, valStr)
 	case "status", "status_code":
 		if status, err := strconv.Atoi(valStr); err == nil {
 			switch {
 			case status >= 500:
-				color.New(color.FgRed, color.Bold).Fprintln(h.w, valStr)
+				color.New(color.FgRed, color.Bold).Fprintln(buf, valStr)
 			case status >= 400:
-				color.New(color.FgYellow, color.Bold).Fprintln(h.w, valStr)
+				color.New(color.FgYellow, color.Bold).Fprintln(buf, valStr)
 			case status >= 200:
-				color.New(color.FgGreen, color.Bold).Fprintln(h.w, valStr)
+				color.New(color.FgGreen, color.Bold).Fprintln(buf, valStr)
 			default:
-				defaultValColor.Fprintln(h.w, valStr)
+				defaultValColor.Fprintln(buf, valStr)
 			}
 		} else {
-			defaultValColor.Fprintln(h.w, valStr)
+			defaultValColor.Fprintln(buf, valStr)
 		}
-	case "duration", "latency":
-		color.New(color.FgMagenta).Fprintln(h.w, valStr)
+	case "duration", "latency", "ttfb":
+		color.New(color.FgMagenta).Fprintln(buf, valStr)
 	case "url", "path":
-		color.New(color.FgCyan, color.Underline).Fprintln(h.w, valStr)
-	case "ip", "client_ip":
-		color.New(color.FgYellow).Fprintln(h.w, valStr)
-	case "cache", "cache_status":
-		if valStr == "HIT" {
-			color.New(color.FgGreen).Fprintln(h.w, valStr)
-		} else if valStr == "MISS" {
-			color.New(color.FgYellow).Fprintln(h.w, valStr)
-		} else {
-			color.New(color.FgMagenta).Fprintln(h.w, valStr)
+		text := valStr
+		if isWebURL(valStr) {
+			text = h.link(valStr, valStr)
 		}
+		color.New(color.FgCyan, color.Underline).Fprintln(buf, text)
+	case "ip", "client_ip":
+		color.New(color.FgYellow).Fprintln(buf, valStr)
 	case "user_id", "session_id":
-		color.New(color.FgCyan, color.Bold).Fprintln(h.w, valStr)
+		color.New(color.FgCyan, color.Bold).Fprintln(buf, valStr)
 	default:
 		handled = false
 	}
@@ -302,25 +391,78 @@ func (h *ColorHandler) handleAttr(a slog.Attr, indent int) {
 	// 3. 处理普通字段和分组
 	if !handled {
 		if a.Value.Kind() == slog.KindGroup {
-			fmt.Fprintln(h.w) // 换行
+			fmt.Fprintln(buf) // 换行
 			attrs := a.Value.Group()
 			for _, ga := range attrs {
-				h.handleAttr(ga, indent+1)
+				h.handleAttr(buf, ga, indent+1, width)
 			}
+		} else if pretty, ok := h.prettyValue(a.Value); ok {
+			// JSON值缩进到属性下方展示
+			fmt.Fprintln(buf)
+			fmt.Fprint(buf, highlightJSON(pretty, indentStr+"    "))
 		} else {
-			// 应用关键字高亮到值
-			colorizedValue := colorize(valStr, h.enableHighlight)
-			fmt.Fprintln(h.w, colorizedValue)
+			// 应用关键字高亮到值，超出终端宽度时折行，续行比键多缩进一级
+			used := len(indentStr) + textWidth(a.Key) + 2
+			if isWebURL(valStr) {
+				fmt.Fprintln(buf, h.link(valStr, valStr))
+				return
+			}
+			for i, line := range wrapText(valStr, used, len(indentStr)+4, width) {
+				if i > 0 {
+					fmt.Fprint(buf, indentStr+"    ")
+				}
+				fmt.Fprintln(buf, colorize(line, h.enableHighlight))
+			}
 		}
 	}
 }
 
+// prettyValue 开启pretty_json时返回缩进后的JSON
+func (h *ColorHandler) prettyValue(v slog.Value) ([]byte, bool) {
+	if !h.prettyJSON {
+		return nil, false
+	}
+	return prettyJSON(v.Resolve())
+}
+
 func (h *ColorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return h
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	h2.attrs = append(h2.attrs, nestAttrs(h.groups, attrs)...)
+	return h2
 }
 
 func (h *ColorHandler) WithGroup(name string) slog.Handler {
-	return h
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	return h2
+}
+
+// clone 复制处理器，派生的处理器共享输出锁
+func (h *ColorHandler) clone() *ColorHandler {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return &ColorHandler{
+		w:               h.w,
+		opts:            h.opts,
+		levelTheme:      h.levelTheme,
+		valueColors:     h.valueColors,
+		mu:              h.mu,
+		lastLogTime:     h.lastLogTime,
+		enableHighlight: h.enableHighlight,
+		compactMode:     h.compactMode,
+		prettyJSON:      h.prettyJSON,
+		width:           h.width,
+		hyperlinks:      h.hyperlinks,
+		attrs:           append([]slog.Attr{}, h.attrs...),
+		groups:          append([]string{}, h.groups...),
+		clock:           h.clock,
+	}
 }
 
 // splitLines 分割多行字符串
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// TestColorHandlerWithAttrs 测试派生处理器输出公共属性与分组
+func TestColorHandlerWithAttrs(t *testing.T) {
+	color.NoColor = true
+
+	var buf bytes.Buffer
+	base := slog.New(NewColorHandler(&buf, nil))
+	base.With("component", "db").WithGroup("query").Info("slow query", "table", "users")
+
+	got := buf.String()
+	for _, want := range []string{
+		"    component: db\n",
+		"    query: \n        table: users\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+// TestColorHandlerWrap 测试固定宽度下长消息与属性值的折行缩进
+func TestColorHandlerWrap(t *testing.T) {
+	color.NoColor = true
+
+	var buf bytes.Buffer
+	h := NewColorHandler(&buf, nil)
+	h.SetWidth(60)
+	slog.New(h).Info("connection pool exhausted while waiting for an idle connection",
+		"detail", "the upstream database did not accept new connections within the configured timeout")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		if textWidth(line) > 60 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+	if len(lines) < 4 || !strings.HasPrefix(lines[1], strings.Repeat(" ", 30)) || !strings.HasPrefix(lines[len(lines)-1], "        ") {
+		t.Errorf("unexpected wrapping:\n%s", buf.String())
+	}
+}
+
+// TestColorHandlerHyperlinks 测试源码位置与URL属性的 OSC 8 超链接
+func TestColorHandlerHyperlinks(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	var buf bytes.Buffer
+	h := NewColorHandler(&buf, SourceOptions(nil, SourceShort))
+	h.SetHyperlinks(true)
+	slog.New(h).Info("fetched", "url", "https://example.com/a?b=1", "callback", "http://hooks.local/x")
+
+	got := buf.String()
+	for _, want := range []string{
+		"\x1b]8;;file:///",
+		"color_handler_test.go\x1b\\handler/color_handler_test.go:",
+		"\x1b]8;;https://example.com/a?b=1\x1b\\https://example.com/a?b=1\x1b]8;;\x1b\\",
+		"\x1b]8;;http://hooks.local/x\x1b\\",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%q", want, got)
+		}
+	}
+
+	buf.Reset()
+	h.SetHyperlinks(false)
+	slog.New(h).Info("fetched", "url", "https://example.com")
+	if strings.Contains(buf.String(), "\x1b]8;;") {
+		t.Errorf("hyperlinks disabled should print plain text: %q", buf.String())
+	}
+}
+
+// TestColorHandlerLevelTheme 测试徽章主题与自定义级别文字
+func TestColorHandlerLevelTheme(t *testing.T) {
+	theme := BadgeLevelTheme().WithLabels(map[slog.Level]string{slog.LevelWarn: "WARN"})
+	got := Renderer{Width: 60, LevelTheme: &theme}.Render(func(l *slog.Logger) {
+		l.Info("ready")
+		l.Warn("disk usage is above the configured threshold on the data volume")
+	})
+
+	lines := strings.Split(got, "\n")
+	if !strings.HasPrefix(lines[0], " INF  2024-01-01") {
+		t.Errorf("expected INF badge, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], " WARN  2024-01-01") {
+		t.Errorf("expected custom WARN label, got %q", lines[1])
+	}
+	// 折行与消息首行对齐：徽章宽度6 + 空格 + 时间23 + 空格
+	if len(lines) < 3 || !strings.HasPrefix(lines[2], strings.Repeat(" ", 31)) {
+		t.Errorf("continuation should align with the message: %q", got)
+	}
+}
+
+// writeCounter 记录 Write 调用次数
+type writeCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+// TestColorHandlerSingleWrite 测试每条记录（含属性与分组）只调用一次 Write，避免多个写入方交错
+func TestColorHandlerSingleWrite(t *testing.T) {
+	color.NoColor = true
+
+	var w writeCounter
+	logger := slog.New(NewColorHandler(&w, nil))
+	logger.Info("request", "method", "GET", slog.Group("http", slog.Int("status", 200)))
+	logger.Warn("slow", "duration", "1s")
+
+	if w.writes != 2 {
+		t.Errorf("Write called %d times for 2 records:\n%s", w.writes, w.String())
+	}
+	if !strings.Contains(w.String(), "        status: 200\n") {
+		t.Errorf("unexpected output:\n%s", w.String())
+	}
+}
+
+// TestColorHandlerValueColors 测试按属性取值着色：默认的 cache 规则、不区分大小写的配置规则与通配规则，
+// 以及 With/WithGroup 派生的日志器沿用着色规则
+func TestColorHandlerValueColors(t *testing.T) {
+	prev := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = prev }()
+
+	rules, err := ParseValueColors(map[string]map[string]string{"x_cache": {"stale": "red+bold", "*": "hi_blue"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	h := NewColorHandler(&buf, nil)
+	h.SetValueColors(rules)
+	slog.New(h).Info("served", "cache", "HIT", "x_cache", "STALE", "route", "/items")
+	slog.New(h).Info("served", "x_cache", "revalidated")
+	slog.New(h).With("route", "/items").WithGroup("upstream").Info("served", "x_cache", "MISS")
+
+	got := buf.String()
+	for _, want := range []string{"\x1b[32mHIT\x1b[0m", "\x1b[31;1mSTALE", "\x1b[94mrevalidated\x1b[0m", "\x1b[94mMISS\x1b[0m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%q", want, got)
+		}
+	}
+
+	if _, err := ParseValueColors(map[string]map[string]string{"cache": {"hit": "bright"}}); err == nil {
+		t.Error("unknown color names should be rejected")
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ctxAttrsKey 上下文中存放请求级属性的键
+type ctxAttrsKey struct{}
+
+// ContextWithAttrs 将请求级属性追加到上下文，已有同名键时以新值为准
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	existing := AttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	for _, a := range existing {
+		if !hasAttrKey(attrs, a.Key) {
+			merged = append(merged, a)
+		}
+	}
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, ctxAttrsKey{}, merged)
+}
+
+// AttrsFromContext 获取上下文中的请求级属性
+func AttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(ctxAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// hasAttrKey 检查属性列表中是否存在指定键
+func hasAttrKey(attrs []slog.Attr, key string) bool {
+	for _, a := range attrs {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// debugTargetKey 上下文中标记定向调试的键
+type debugTargetKey struct{}
+
+// WithDebugTarget 标记上下文为定向调试，该上下文产生的日志不受全局级别限制
+func WithDebugTarget(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, debugTargetKey{}, true)
+}
+
+// IsDebugTargeted 检查上下文是否被标记为定向调试
+func IsDebugTargeted(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	targeted, _ := ctx.Value(debugTargetKey{}).(bool)
+	return targeted
+}
+
+// DebugRule 定向调试规则，属性 Key 的值等于 Value 时命中；
+// 分组内的属性按 "." 连接的完整键名匹配，Key 也可只写末段，如 user_id 匹配 auth.user_id
+type DebugRule struct {
+	Key   string
+	Value string
+}
+
+// DebugTargetHandler 定向调试处理器，定向调试的上下文或 With 绑定的属性命中规则时，Debug 记录不受全局级别限制。
+// 低于全局级别的调用只在这两种情况下启用，调用处传入的属性不参与匹配，避免为每条 Debug 调用构建记录
+type DebugTargetHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+	rules   []DebugRule
+	matched bool   // WithAttrs 绑定的属性已命中规则
+	group   string // WithGroup 累积的组名
+}
+
+// NewDebugTargetHandler 创建定向调试处理器，level 为全局日志级别
+func NewDebugTargetHandler(handler slog.Handler, level slog.Leveler, rules []DebugRule) *DebugTargetHandler {
+	return &DebugTargetHandler{
+		handler: handler,
+		level:   level,
+		rules:   rules,
+	}
+}
+
+func (h *DebugTargetHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.level.Level() {
+		return h.handler.Enabled(ctx, level)
+	}
+	// 低于全局级别时，只有定向调试的上下文或已命中规则的日志器才需要构建记录
+	return level >= slog.LevelDebug && (h.matched || IsDebugTargeted(ctx))
+}
+
+func (h *DebugTargetHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() {
+		if !h.matched && !IsDebugTargeted(ctx) && !h.recordMatches(r) {
+			return nil
+		}
+		// 标记上下文，让后续的分发和过滤跳过级别检查
+		ctx = WithDebugTarget(ctx)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// recordMatches 检查记录属性是否命中任意规则
+func (h *DebugTargetHandler) recordMatches(r slog.Record) bool {
+	if len(h.rules) == 0 {
+		return false
+	}
+	matched := false
+	WalkRecordAttrs(h.group, r, func(key string, v slog.Value) bool {
+		matched = h.valueMatches(key, v)
+		return !matched
+	})
+	return matched
+}
+
+// valueMatches 检查单个叶子属性是否命中任意规则
+func (h *DebugTargetHandler) valueMatches(key string, v slog.Value) bool {
+	for _, rule := range h.rules {
+		if KeyMatches(key, rule.Key) && v.String() == rule.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *DebugTargetHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	matched := h.matched
+	if !matched && len(h.rules) > 0 {
+		WalkAttrs(h.group, attrs, func(key string, v slog.Value) bool {
+			matched = h.valueMatches(key, v)
+			return !matched
+		})
+	}
+	return &DebugTargetHandler{
+		handler: h.handler.WithAttrs(attrs),
+		level:   h.level,
+		rules:   h.rules,
+		matched: matched,
+		group:   h.group,
+	}
+}
+
+func (h *DebugTargetHandler) WithGroup(name string) slog.Handler {
+	return &DebugTargetHandler{
+		handler: h.handler.WithGroup(name),
+		level:   h.level,
+		rules:   h.rules,
+		matched: h.matched,
+		group:   joinKey(h.group, name),
+	}
+}
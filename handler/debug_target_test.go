@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestDebugTargetHandler 测试定向调试规则和上下文标记
+func TestDebugTargetHandler(t *testing.T) {
+	var buf bytes.Buffer
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := slog.New(NewDebugTargetHandler(inner, slog.LevelInfo, []DebugRule{{Key: "user_id", Value: "123"}}))
+
+	logger.Debug("ignored debug", slog.String("user_id", "456"))
+	logger.Debug("call-site attr", slog.String("user_id", "123"))
+	logger.With(slog.String("user_id", "123")).Debug("matched by bound attr")
+	logger.With(slog.String("user_id", "456")).Debug("unmatched bound attr")
+	logger.DebugContext(WithDebugTarget(context.Background()), "matched by context")
+	logger.Info("normal info")
+
+	out := buf.String()
+	for _, msg := range []string{"ignored debug", "call-site attr", "unmatched bound attr"} {
+		if strings.Contains(out, msg) {
+			t.Errorf("%q should be dropped, got:\n%s", msg, out)
+		}
+	}
+	for _, msg := range []string{"matched by bound attr", "matched by context", "normal info"} {
+		if !strings.Contains(out, msg) {
+			t.Errorf("expected %q in output, got:\n%s", msg, out)
+		}
+	}
+}
+
+// TestDebugTargetEnabled 测试规则存在时未定向、未命中的 Debug 调用不启用
+func TestDebugTargetEnabled(t *testing.T) {
+	h := NewDebugTargetHandler(slog.NewTextHandler(io.Discard, nil), slog.LevelInfo, []DebugRule{{Key: "user_id", Value: "123"}})
+	ctx := context.Background()
+	if h.Enabled(ctx, slog.LevelDebug) {
+		t.Error("debug should be disabled without a targeted context or matched attrs")
+	}
+	if !h.Enabled(WithDebugTarget(ctx), slog.LevelDebug) {
+		t.Error("debug should be enabled for a targeted context")
+	}
+	if !h.WithAttrs([]slog.Attr{slog.Int("user_id", 123)}).Enabled(ctx, slog.LevelDebug) {
+		t.Error("debug should be enabled after WithAttrs matched a rule")
+	}
+}
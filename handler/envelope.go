@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// SchemaVersion 日志记录信封的结构版本，字段命名发生不兼容变化时递增
+const SchemaVersion = 1
+
+// EnvelopeWriter 将每条JSON记录包装为 {"schema_version":1,"app":"...","payload":{...}}
+type EnvelopeWriter struct {
+	w      io.Writer
+	prefix []byte
+	pool   sync.Pool
+}
+
+// NewEnvelopeWriter 创建信封写入器，需配合 slog.JSONHandler 使用（每条记录一次 Write）
+func NewEnvelopeWriter(w io.Writer, app string) *EnvelopeWriter {
+	appJSON, _ := json.Marshal(app)
+	prefix := `{"schema_version":` + strconv.Itoa(SchemaVersion) + `,"app":` + string(appJSON) + `,"payload":`
+
+	return &EnvelopeWriter{
+		w:      w,
+		prefix: []byte(prefix),
+		pool: sync.Pool{
+			New: func() interface{} { return new(bytes.Buffer) },
+		},
+	}
+}
+
+func (e *EnvelopeWriter) Write(p []byte) (int, error) {
+	buf := e.pool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		e.pool.Put(buf)
+	}()
+
+	buf.Write(e.prefix)
+	buf.Write(bytes.TrimRight(p, "\n"))
+	buf.WriteString("}\n")
+
+	// 一次性写出，避免多写入方时行内容交错
+	if _, err := e.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
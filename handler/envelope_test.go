@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestEnvelopeWriter 测试每条JSON记录被包装为带版本和应用名的信封，原记录完整位于 payload 中
+func TestEnvelopeWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(NewEnvelopeWriter(&buf, `order "svc"`), nil))
+	logger.Info("order created", "order_id", 42, slog.Group("user", slog.String("id", "u-1")))
+	logger.Warn("stock low")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per record, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var env struct {
+		SchemaVersion int            `json:"schema_version"`
+		App           string         `json:"app"`
+		Payload       map[string]any `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &env); err != nil {
+		t.Fatalf("envelope is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if env.SchemaVersion != SchemaVersion || env.App != `order "svc"` {
+		t.Errorf("unexpected envelope header: %s", lines[0])
+	}
+	user, _ := env.Payload["user"].(map[string]any)
+	if env.Payload["msg"] != "order created" || env.Payload["order_id"] != float64(42) || user["id"] != "u-1" {
+		t.Errorf("payload should hold the original record: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[0], `{"schema_version":1,"app":`) {
+		t.Errorf("envelope fields should come first: %s", lines[0])
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultStackDepth 自动附加堆栈的默认最大帧数
+const DefaultStackDepth = 32
+
+// ErrorStackHandler 为 Error 及以上级别的记录自动附加精简的调用堆栈，
+// 已带 stack 属性（或 error 分组中带 stack）的记录保持不变；必须在调用方的goroutine中执行，
+// 因此需位于异步处理器之外
+type ErrorStackHandler struct {
+	handler slog.Handler
+	depth   int
+}
+
+// NewErrorStackHandler 创建错误堆栈处理器，depth 为最大帧数
+func NewErrorStackHandler(handler slog.Handler, depth int) *ErrorStackHandler {
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+	return &ErrorStackHandler{handler: handler, depth: depth}
+}
+
+func (h *ErrorStackHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *ErrorStackHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError && r.PC != 0 && !hasStackAttr(r) {
+		if stack := callerStack(r.PC, h.depth); stack != "" {
+			r = r.Clone()
+			r.AddAttrs(slog.String("stack", stack))
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *ErrorStackHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ErrorStackHandler{handler: h.handler.WithAttrs(attrs), depth: h.depth}
+}
+
+func (h *ErrorStackHandler) WithGroup(name string) slog.Handler {
+	return &ErrorStackHandler{handler: h.handler.WithGroup(name), depth: h.depth}
+}
+
+// hasStackAttr 检查记录是否已带堆栈
+func hasStackAttr(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "stack" {
+			found = true
+		} else if a.Key == "error" && a.Value.Kind() == slog.KindGroup {
+			for _, ga := range a.Value.Group() {
+				if ga.Key == "stack" {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// callerStack 从记录调用位置开始收集堆栈，跳过日志库自身和运行时的帧
+func callerStack(pc uintptr, depth int) string {
+	pcs := make([]uintptr, depth+32)
+	n := runtime.Callers(2, pcs)
+	iter := runtime.CallersFrames(pcs[:n])
+
+	var frames []runtime.Frame
+	start := -1
+	for {
+		f, more := iter.Next()
+		frames = append(frames, f)
+		// 调用位置在最后一个 slog 帧之后
+		if strings.HasPrefix(f.Function, "log/slog.") {
+			start = len(frames)
+		}
+		if !more {
+			break
+		}
+	}
+	// 未经过 slog.Logger（如直接调用 Handle）时按记录的 PC 定位
+	if start < 0 {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			for i, f := range frames {
+				if f.Function == fn.Name() {
+					start = i
+					break
+				}
+			}
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	count := 0
+	for _, f := range frames[start:] {
+		if count >= depth {
+			break
+		}
+		if strings.HasPrefix(f.Function, "runtime.") {
+			continue
+		}
+		sb.WriteString(f.Function)
+		sb.WriteString("\n    ")
+		sb.WriteString(f.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(f.Line))
+		sb.WriteByte('\n')
+		count++
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestErrorStackHandler 测试 Error 记录自动附加从调用位置开始的堆栈
+func TestErrorStackHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewErrorStackHandler(slog.NewJSONHandler(&buf, nil), 0))
+
+	logger.Error("query failed")
+	out := buf.String()
+	if !strings.Contains(out, `"stack":"github.com/shuakami/logmiao/handler.TestErrorStackHandler`) {
+		t.Errorf("stack should start at the logging call: %s", out)
+	}
+	if strings.Contains(out, "log/slog.") {
+		t.Errorf("stack should not contain slog frames: %s", out)
+	}
+
+	buf.Reset()
+	logger.Info("fine")
+	logger.Error("explicit", "stack", "custom")
+	if strings.Count(buf.String(), `"stack"`) != 1 || !strings.Contains(buf.String(), `"stack":"custom"`) {
+		t.Errorf("info records and records with a stack should be left unchanged: %s", buf.String())
+	}
+}
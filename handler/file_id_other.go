@@ -0,0 +1,10 @@
+//go:build !unix
+
+package handler
+
+import "os"
+
+// fileID 非 Unix 平台的 FileInfo 不包含 inode，返回0
+func fileID(info os.FileInfo) (inode, device uint64) {
+	return 0, 0
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package handler
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID 返回文件的 inode 与设备号
+func fileID(info os.FileInfo) (inode, device uint64) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino), uint64(st.Dev)
+	}
+	return 0, 0
+}
@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package handler
+
+import "os"
+
+// lockFile 不支持文件锁的平台上不加锁，仅保证进程内的写入顺序
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package handler
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile 取得文件的排他建议锁，阻塞直到成功
+func lockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package handler
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile 取得文件的排他锁，阻塞直到成功
+func lockFile(f *os.File) error {
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, new(windows.Overlapped))
+}
+
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// 过滤规则的动作
+const (
+	FilterDrop      = "drop"      // 丢弃记录
+	FilterKeep      = "keep"      // 保留记录，不再匹配后续规则，用于在宽泛的 drop 规则前豁免部分记录
+	FilterDowngrade = "downgrade" // 将记录降低到 To 级别，降低后低于输出级别的记录不再输出
+)
+
+// FilterRule 可配置的过滤规则，所有已设置的条件都满足时执行动作。规则按声明顺序匹配，第一条命中的规则生效
+type FilterRule struct {
+	Message string // 消息的正则，为空时不检查消息
+	Attr    string // 属性的完整路径（如 http.path），也匹配以 "."+Attr 结尾的路径；为空时不检查属性
+	Value   string // 属性值的正则，为空时只要求属性存在
+	Level   string // 只匹配该级别区间的记录，格式同输出路由（error+、info、debug..warn），为空时不限制
+	Action  string // drop, keep, downgrade
+	To      string // downgrade 的目标级别，默认 debug
+}
+
+// filterRule 编译后的过滤规则
+type filterRule struct {
+	message *regexp.Regexp
+	attr    string
+	value   *regexp.Regexp
+	levels  *LevelRange
+	action  string
+	to      slog.Level
+}
+
+// compileFilterRule 校验并编译单条规则
+func compileFilterRule(rule FilterRule) (filterRule, error) {
+	compiled := filterRule{attr: rule.Attr, action: rule.Action, to: slog.LevelDebug}
+	switch rule.Action {
+	case FilterDrop, FilterKeep:
+	case FilterDowngrade:
+		if rule.To != "" {
+			if err := compiled.to.UnmarshalText([]byte(rule.To)); err != nil {
+				return compiled, fmt.Errorf("to: %w", err)
+			}
+		}
+	default:
+		return compiled, fmt.Errorf("unknown action %q, want drop, keep or downgrade", rule.Action)
+	}
+	if rule.Message == "" && rule.Attr == "" && rule.Level == "" {
+		return compiled, fmt.Errorf("at least one of message, attr or level is required")
+	}
+	if rule.Value != "" && rule.Attr == "" {
+		return compiled, fmt.Errorf("value requires attr")
+	}
+
+	var err error
+	if rule.Message != "" {
+		if compiled.message, err = regexp.Compile(rule.Message); err != nil {
+			return compiled, fmt.Errorf("message: %w", err)
+		}
+	}
+	if rule.Value != "" {
+		if compiled.value, err = regexp.Compile(rule.Value); err != nil {
+			return compiled, fmt.Errorf("value: %w", err)
+		}
+	}
+	if rule.Level != "" {
+		levels, err := ParseLevelRange(rule.Level)
+		if err != nil {
+			return compiled, fmt.Errorf("level: %w", err)
+		}
+		compiled.levels = &levels
+	}
+	return compiled, nil
+}
+
+// RuleFilterHandler 按配置的规则丢弃、保留或降级记录，规则在创建时编译。
+// 属性条件同时检查记录自身的属性和 WithAttrs 绑定的属性
+type RuleFilterHandler struct {
+	handler slog.Handler
+	rules   []filterRule
+	bound   []slog.Attr // WithAttrs 绑定的属性，已按分组嵌套
+	groups  []string
+}
+
+// NewRuleFilterHandler 创建规则过滤处理器，没有规则时直接返回原处理器
+func NewRuleFilterHandler(handler slog.Handler, rules []FilterRule) (slog.Handler, error) {
+	if len(rules) == 0 {
+		return handler, nil
+	}
+	compiled := make([]filterRule, 0, len(rules))
+	for i, rule := range rules {
+		c, err := compileFilterRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("filters[%d]: %w", i, err)
+		}
+		compiled = append(compiled, c)
+	}
+	return &RuleFilterHandler{handler: handler, rules: compiled}, nil
+}
+
+func (h *RuleFilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RuleFilterHandler) Handle(ctx context.Context, r slog.Record) error {
+	for i := range h.rules {
+		rule := &h.rules[i]
+		if !h.matches(rule, r) {
+			continue
+		}
+		switch rule.action {
+		case FilterDrop:
+			return nil
+		case FilterDowngrade:
+			if rule.to < r.Level {
+				if !h.handler.Enabled(ctx, rule.to) {
+					return nil
+				}
+				r.Level = rule.to
+			}
+		}
+		return h.handler.Handle(ctx, r)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// matches 检查记录是否满足规则的全部条件
+func (h *RuleFilterHandler) matches(rule *filterRule, r slog.Record) bool {
+	if rule.levels != nil && !rule.levels.Contains(r.Level) {
+		return false
+	}
+	if rule.message != nil && !rule.message.MatchString(r.Message) {
+		return false
+	}
+	if rule.attr == "" {
+		return true
+	}
+
+	found := false
+	check := func(key string, v slog.Value) bool {
+		if KeyMatches(key, rule.attr) && (rule.value == nil || rule.value.MatchString(v.String())) {
+			found = true
+			return false
+		}
+		return true
+	}
+	if WalkAttrs("", h.bound, check) {
+		WalkRecordAttrs(strings.Join(h.groups, "."), r, check)
+	}
+	return found
+}
+
+func (h *RuleFilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RuleFilterHandler{
+		handler: h.handler.WithAttrs(attrs),
+		rules:   h.rules,
+		bound:   append(append([]slog.Attr{}, h.bound...), nestAttrs(h.groups, attrs)...),
+		groups:  h.groups,
+	}
+}
+
+func (h *RuleFilterHandler) WithGroup(name string) slog.Handler {
+	return &RuleFilterHandler{
+		handler: h.handler.WithGroup(name),
+		rules:   h.rules,
+		bound:   h.bound,
+		groups:  append(append([]string{}, h.groups...), name),
+	}
+}
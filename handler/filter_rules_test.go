@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestRuleFilterHandler 测试按消息、属性（含分组与 WithAttrs 绑定的属性）和级别丢弃、保留与降级
+func TestRuleFilterHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewRuleFilterHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}), []FilterRule{
+		{Attr: "http.path", Value: "^/healthz/important$", Action: FilterKeep},
+		{Attr: "http.path", Value: "^/healthz", Action: FilterDrop},
+		{Message: "^cache miss", Level: "info", Action: FilterDowngrade},
+		{Message: "^slow query", Action: FilterDowngrade, To: "warn"},
+		{Attr: "noisy", Action: FilterDrop},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(h)
+	logger.Info("probe", slog.Group("http", slog.String("path", "/healthz")))
+	logger.WithGroup("http").Info("probe", "path", "/healthz/important")
+	logger.Info("cache miss for key")
+	logger.Error("cache miss for key")
+	logger.Error("slow query took 3s")
+	logger.With("noisy", true).Info("bound attr matches")
+	logger.Info("kept")
+
+	var got []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var m map[string]any
+		if err := json.Unmarshal(line, &m); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 records, got %d: %s", len(got), buf.String())
+	}
+	if http, _ := got[0]["http"].(map[string]any); http["path"] != "/healthz/important" {
+		t.Errorf("keep should win over the later drop rule: %v", got[0])
+	}
+	if got[1]["msg"] != "cache miss for key" || got[1]["level"] != "ERROR" {
+		t.Errorf("level range should limit downgrade to info records: %v", got[1])
+	}
+	if got[2]["level"] != "WARN" {
+		t.Errorf("slow query should be downgraded to warn: %v", got[2])
+	}
+	if got[3]["msg"] != "kept" {
+		t.Errorf("unmatched record should pass through: %v", got[3])
+	}
+
+	for _, rule := range []FilterRule{
+		{Message: "x", Action: "mute"},
+		{Action: FilterDrop},
+		{Message: "(", Action: FilterDrop},
+		{Value: "x", Action: FilterDrop},
+		{Level: "loud", Action: FilterDrop},
+		{Message: "x", Action: FilterDowngrade, To: "quiet"},
+	} {
+		if _, err := NewRuleFilterHandler(slog.NewJSONHandler(&buf, nil), []FilterRule{rule}); err == nil {
+			t.Errorf("rule %+v should be rejected", rule)
+		}
+	}
+}
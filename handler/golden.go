@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ansiRegex 匹配 SGR 颜色序列和 OSC 8 超链接序列
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b\]8;[^\x1b]*\x1b\\`)
+
+// StripANSI 去除字符串中的颜色和超链接转义序列
+func StripANSI(s string) string {
+	return ansiRegex.ReplaceAllString(s, "")
+}
+
+// Renderer 将 ColorHandler 的输出渲染到内存，用于控制台格式的快照测试。
+// 时间固定、宽度固定、不检测终端，输出在不同环境下保持一致
+type Renderer struct {
+	Options    *slog.HandlerOptions
+	Time       time.Time // 记录时间，零值时使用 2024-01-01 00:00:00 UTC
+	Width      int       // 折行宽度，<=0 不折行
+	Color      bool      // 保留颜色序列；为 false 时输出纯文本
+	Hyperlinks bool      // 输出 OSC 8 超链接（仅 Color 为 true 时生效）
+	Compact    bool
+	Highlight  bool
+	PrettyJSON bool
+	LevelTheme *LevelTheme // 级别标签主题，为空时使用默认主题
+}
+
+// renderMu 渲染期间会临时修改 color.NoColor，串行执行
+var renderMu sync.Mutex
+
+// Render 调用 fn 写日志并返回 ColorHandler 的输出。
+// Color 为 true 时渲染期间强制开启颜色，不应与其他控制台输出并发使用
+func (r Renderer) Render(fn func(*slog.Logger)) string {
+	renderMu.Lock()
+	defer renderMu.Unlock()
+
+	noColor := color.NoColor
+	color.NoColor = !r.Color
+	defer func() { color.NoColor = noColor }()
+
+	at := r.Time
+	if at.IsZero() {
+		at = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	width := r.Width
+	if width <= 0 {
+		width = -1
+	}
+
+	var buf bytes.Buffer
+	h := NewColorHandlerWithOptions(&buf, r.Options, r.Highlight, r.Compact)
+	h.SetWidth(width)
+	h.SetHyperlinks(r.Hyperlinks)
+	h.SetPrettyJSON(r.PrettyJSON)
+	if r.LevelTheme != nil {
+		h.SetLevelTheme(*r.LevelTheme)
+	}
+	h.clock = func() time.Time { return at }
+	fn(slog.New(&fixedTimeHandler{Handler: h, at: at}))
+
+	if !r.Color {
+		return StripANSI(buf.String())
+	}
+	return buf.String()
+}
+
+// fixedTimeHandler 将记录时间替换为固定值
+type fixedTimeHandler struct {
+	slog.Handler
+	at time.Time
+}
+
+func (h *fixedTimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = h.at
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *fixedTimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fixedTimeHandler{Handler: h.Handler.WithAttrs(attrs), at: h.at}
+}
+
+func (h *fixedTimeHandler) WithGroup(name string) slog.Handler {
+	return &fixedTimeHandler{Handler: h.Handler.WithGroup(name), at: h.at}
+}
+
+// GoldenUpdateEnv 设置为 1 时 AssertGolden 用实际输出覆盖快照文件
+const GoldenUpdateEnv = "LOGMIAO_UPDATE_GOLDEN"
+
+// TB testing.TB 中 AssertGolden 用到的方法，避免非测试代码依赖 testing 包
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// AssertGolden 比较输出与快照文件（通常位于 testdata/ 下），
+// 设置 LOGMIAO_UPDATE_GOLDEN=1 时写入实际输出
+func AssertGolden(t TB, path, got string) {
+	t.Helper()
+	if os.Getenv(GoldenUpdateEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with %s=1 to create it): %v", GoldenUpdateEnv, err)
+	}
+	if string(want) != got {
+		t.Errorf("output differs from %s (run with %s=1 to update)\n--- want\n%s\n--- got\n%s", path, GoldenUpdateEnv, want, got)
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestColorHandlerGolden 控制台格式的快照测试，修改格式后以 LOGMIAO_UPDATE_GOLDEN=1 更新快照
+func TestColorHandlerGolden(t *testing.T) {
+	render := func(color bool) string {
+		return Renderer{Width: 80, Color: color, Highlight: true, PrettyJSON: true}.Render(func(l *slog.Logger) {
+			l.Info("server started successfully", "port", 8080, "mode", "release")
+			l.With("component", "db").Warn("slow query", "elapsed", "1.2s", "rows", map[string]int{"users": 3})
+			l.Error("request failed", "error", errors.New("connection refused"))
+		})
+	}
+
+	AssertGolden(t, filepath.Join("testdata", "color_handler.golden"), render(false))
+
+	colored := render(true)
+	if !strings.Contains(colored, "\x1b[") {
+		t.Error("Color renderer should keep escape sequences")
+	}
+	if StripANSI(colored) != render(false) {
+		t.Error("stripping the colored output should match the plain output")
+	}
+}
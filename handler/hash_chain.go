@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// HashChainWriter 防篡改写入器，每条JSON记录追加 prev_hash 和 hash 字段，
+// hash = sha256(prev_hash + 去掉这两个字段的原始记录)，任意一行被修改或删除都会使后续校验失败
+type HashChainWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	prev string
+}
+
+// NewHashChainWriter 创建防篡改写入器，prev 为链上一条记录的哈希，新文件传空字符串
+func NewHashChainWriter(w io.Writer, prev string) *HashChainWriter {
+	return &HashChainWriter{w: w, prev: prev}
+}
+
+func (h *HashChainWriter) Write(p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\n")
+	if len(line) < 2 || line[len(line)-1] != '}' {
+		return h.w.Write(p)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hash := chainHash(h.prev, line)
+	var buf bytes.Buffer
+	buf.Grow(len(line) + 160)
+	buf.Write(line[:len(line)-1])
+	if len(line) > 2 {
+		buf.WriteByte(',')
+	}
+	fmt.Fprintf(&buf, `"prev_hash":%q,"hash":%q}`+"\n", h.prev, hash)
+
+	if _, err := h.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	h.prev = hash
+	return len(p), nil
+}
+
+// chainHash 计算链上一条记录的哈希
+func chainHash(prev string, line []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(prev))
+	sum.Write(line)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// ErrChainBroken 哈希链校验失败
+var ErrChainBroken = errors.New("hash chain broken")
+
+// VerifyHashChain 校验从空哈希开始的哈希链（新文件），返回第一条不一致记录的行号。
+// 第一条记录的 prev_hash 同样被校验，删除开头的记录也会被发现
+func VerifyHashChain(r io.Reader) error {
+	return VerifyHashChainFrom(r, "")
+}
+
+// VerifyHashChainFrom 校验续接在 prev 之后的哈希链，用于轮转后的文件或重启后续写的文件，
+// prev 为上一个文件最后一条记录的哈希（见 LastChainHash）
+func VerifyHashChainFrom(r io.Reader, prev string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		original, linkPrev, hash, ok := splitChainFields(line)
+		if !ok || chainHash(linkPrev, original) != hash {
+			return fmt.Errorf("%w at line %d", ErrChainBroken, lineNo)
+		}
+		if linkPrev != prev {
+			return fmt.Errorf("%w at line %d: prev_hash %q, want %q", ErrChainBroken, lineNo, linkPrev, prev)
+		}
+		prev = hash
+	}
+	return scanner.Err()
+}
+
+// LastChainHash 读取已有文件最后一条记录的哈希，用于重启后续接哈希链
+func LastChainHash(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	last := ""
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if _, _, hash, ok := splitChainFields(scanner.Bytes()); ok {
+			last = hash
+		}
+	}
+	return last
+}
+
+// splitChainFields 拆出原始记录和链字段
+func splitChainFields(line []byte) (original []byte, prev, hash string, ok bool) {
+	idx := bytes.LastIndex(line, []byte(`"prev_hash":`))
+	if idx < 0 {
+		return nil, "", "", false
+	}
+	var fields struct {
+		Prev string `json:"prev_hash"`
+		Hash string `json:"hash"`
+	}
+	if err := json.Unmarshal(append([]byte{'{'}, line[idx:]...), &fields); err != nil {
+		return nil, "", "", false
+	}
+	body := bytes.TrimSuffix(line[:idx], []byte(","))
+	original = make([]byte, 0, len(body)+1)
+	original = append(append(original, body...), '}')
+	return original, fields.Prev, fields.Hash, true
+}
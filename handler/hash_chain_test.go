@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestHashChainWriter 测试哈希链写出、校验和篡改检测
+func TestHashChainWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(NewHashChainWriter(&buf, ""), nil))
+	logger.Info("role granted", "user", "alice")
+	logger.Info("role revoked", "user", "bob")
+	logger.Info("password reset", "user", "carol")
+
+	if err := VerifyHashChain(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("untouched chain should verify: %v", err)
+	}
+
+	tampered := strings.Replace(buf.String(), `"user":"bob"`, `"user":"mallory"`, 1)
+	if err := VerifyHashChain(strings.NewReader(tampered)); !errors.Is(err, ErrChainBroken) {
+		t.Errorf("modified record should break the chain, got %v", err)
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	removed := lines[0] + lines[2]
+	if err := VerifyHashChain(strings.NewReader(removed)); !errors.Is(err, ErrChainBroken) {
+		t.Errorf("deleted record should break the chain, got %v", err)
+	}
+}
+
+// TestHashChainGenesis 测试删除开头的记录会被发现，续接的文件按上一个文件的最后哈希校验
+func TestHashChainGenesis(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(NewHashChainWriter(&buf, ""), nil))
+	logger.Info("role granted", "user", "alice")
+	logger.Info("role revoked", "user", "bob")
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	if err := VerifyHashChain(strings.NewReader(lines[1])); !errors.Is(err, ErrChainBroken) {
+		t.Errorf("deleting the first record should break the chain, got %v", err)
+	}
+
+	_, _, first, _ := splitChainFields([]byte(strings.TrimSpace(lines[0])))
+	if err := VerifyHashChainFrom(strings.NewReader(lines[1]), first); err != nil {
+		t.Errorf("continued chain should verify against the previous hash: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// maxPrettyJSONSize 超过该大小的JSON值不展开，避免刷屏
+const maxPrettyJSONSize = 8 * 1024
+
+var (
+	jsonKeyColor     = color.New(color.FgCyan)
+	jsonStringColor  = color.New(color.FgGreen)
+	jsonNumberColor  = color.New(color.FgHiWhite, color.Bold)
+	jsonLiteralColor = color.New(color.FgYellow)
+	jsonPunctColor   = color.New(color.FgHiBlack)
+)
+
+// prettyJSON 属性值为JSON对象/数组字符串或map、slice、结构体时返回缩进后的JSON
+func prettyJSON(v slog.Value) ([]byte, bool) {
+	var raw []byte
+	switch v.Kind() {
+	case slog.KindString:
+		s := strings.TrimSpace(v.String())
+		if len(s) < 2 || !((s[0] == '{' && s[len(s)-1] == '}') || (s[0] == '[' && s[len(s)-1] == ']')) {
+			return nil, false
+		}
+		raw = []byte(s)
+	case slog.KindAny:
+		if _, ok := v.Any().(error); ok {
+			return nil, false
+		}
+		data, err := json.Marshal(v.Any())
+		if err != nil || len(data) < 2 || (data[0] != '{' && data[0] != '[') {
+			return nil, false
+		}
+		raw = data
+	default:
+		return nil, false
+	}
+	if len(raw) > maxPrettyJSONSize {
+		return nil, false
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// highlightJSON 为缩进后的JSON添加语法高亮，每行加上 indent 前缀
+func highlightJSON(data []byte, indent string) string {
+	var sb strings.Builder
+	for _, line := range strings.Split(string(data), "\n") {
+		sb.WriteString(indent)
+		sb.WriteString(highlightJSONLine(line))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// highlightJSONLine 高亮一行缩进JSON：键、字符串、数字、字面量和标点分别着色
+func highlightJSONLine(line string) string {
+	var sb strings.Builder
+	i := 0
+	for i < len(line) {
+		c := line[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				end = len(line) - 1
+			}
+			token := line[i : end+1]
+			if strings.HasPrefix(strings.TrimLeft(line[end+1:], " "), ":") {
+				sb.WriteString(jsonKeyColor.Sprint(token))
+			} else {
+				sb.WriteString(jsonStringColor.Sprint(token))
+			}
+			i = end + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i
+			for end < len(line) && strings.IndexByte("-+.eE0123456789", line[end]) >= 0 {
+				end++
+			}
+			sb.WriteString(jsonNumberColor.Sprint(line[i:end]))
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i
+			for end < len(line) && line[end] >= 'a' && line[end] <= 'z' {
+				end++
+			}
+			sb.WriteString(jsonLiteralColor.Sprint(line[i:end]))
+			i = end
+		case strings.IndexByte("{}[],:", c) >= 0:
+			sb.WriteString(jsonPunctColor.Sprint(string(c)))
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
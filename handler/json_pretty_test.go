@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// TestColorHandlerPrettyJSON 测试JSON字符串与map属性值的缩进输出
+func TestColorHandlerPrettyJSON(t *testing.T) {
+	color.NoColor = true
+
+	var buf bytes.Buffer
+	logger := slog.New(NewColorHandler(&buf, nil))
+	logger.Info("request",
+		slog.String("request_body", `{"name":"miao","tags":["a","b"]}`),
+		slog.Any("user_data", map[string]any{"id": 1, "active": true}),
+		slog.String("note", "{not json}"),
+	)
+	got := buf.String()
+
+	for _, want := range []string{
+		"request_body: \n        {\n",
+		`          "name": "miao",`,
+		`            "a",`,
+		`          "active": true,`,
+		"note: {not json}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	buf.Reset()
+	h := NewColorHandler(&buf, nil)
+	h.SetPrettyJSON(false)
+	slog.New(h).Info("request", slog.String("request_body", `{"name":"miao"}`))
+	if !strings.Contains(buf.String(), `request_body: {"name":"miao"}`) {
+		t.Errorf("pretty_json disabled should keep the raw value: %s", buf.String())
+	}
+}
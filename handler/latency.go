@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 输出端写入持续超时后的处理方式
+const (
+	LatencyAsync = "async" // 改为后台队列写出，队列满时丢弃新记录
+	LatencyOpen  = "open"  // 熔断，期间直接丢弃，到期后放行一条试探
+	LatencyLog   = "log"   // 只记录
+)
+
+// LatencyConfig 输出端写入耗时监控配置
+type LatencyConfig struct {
+	Budget       time.Duration // 单条记录的写入耗时上限，<=0 表示不监控
+	Strikes      int           // 连续超时多少次后执行 Action，默认3
+	Action       string        // async, open, log，默认 async
+	QueueSize    int           // async 的队列容量，默认1024
+	OpenDuration time.Duration // open 的熔断时长，默认30s
+	OnSlow       func(LatencyEvent)
+}
+
+// LatencyEvent 输出端连续超时的通知，OnSlow 在独立的goroutine中调用，可以安全地写日志
+type LatencyEvent struct {
+	Sink    string
+	Elapsed time.Duration // 最近一次写入的耗时
+	Budget  time.Duration
+	Action  string
+}
+
+// SinkLatencyStats 输出端写入耗时统计
+type SinkLatencyStats struct {
+	Name    string        `json:"name"`
+	Mode    string        `json:"mode"`    // sync, async，open 动作下为熔断器状态
+	Slow    int64         `json:"slow"`    // 超过上限的写入次数
+	Max     time.Duration `json:"max"`     // 观察到的最长写入耗时
+	Dropped int64         `json:"dropped"` // 熔断期间或异步队列满时丢弃的记录数
+}
+
+// MonitorLatency 为每个输出端加上写入耗时监控，使单个缓慢的输出端（如挂载的网络磁盘、阻塞的终端）
+// 不会拖慢应用：连续 Strikes 次超过 Budget 后按 Action 降级。Budget<=0 时原样返回
+func MonitorLatency(sinks []Sink, cfg LatencyConfig) []Sink {
+	if cfg.Budget <= 0 {
+		return sinks
+	}
+	if cfg.Strikes <= 0 {
+		cfg.Strikes = 3
+	}
+	if cfg.Action == "" {
+		cfg.Action = LatencyAsync
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	monitored := make([]Sink, len(sinks))
+	for i, sink := range sinks {
+		s := &latencySink{Sink: sink, cfg: cfg}
+		if cfg.Action == LatencyOpen {
+			s.breaker = NewCircuitBreaker(BreakerConfig{FailureThreshold: cfg.Strikes, OpenDuration: cfg.OpenDuration})
+		}
+		monitored[i] = s
+	}
+	return monitored
+}
+
+// latencySink 监控写入耗时的输出端
+type latencySink struct {
+	Sink
+	cfg     LatencyConfig
+	breaker *CircuitBreaker // 仅 open 动作
+
+	strikes atomic.Int64 // 连续超时次数
+	slow    atomic.Int64
+	max     atomic.Int64
+	dropped atomic.Int64
+
+	mu     sync.Mutex
+	async  atomic.Pointer[AsyncHandler] // 切换为异步写出后不为nil
+	closed bool
+}
+
+// Handler 返回带耗时监控的底层处理器，Supervisor 据此分发记录
+func (s *latencySink) Handler() slog.Handler {
+	if hs, ok := s.Sink.(interface{ Handler() slog.Handler }); ok {
+		return &latencyHandler{handler: hs.Handler(), sink: s}
+	}
+	return &latencyHandler{handler: &sinkWriteHandler{sink: s.Sink}, sink: s}
+}
+
+func (s *latencySink) Write(ctx context.Context, r slog.Record) error {
+	return s.handle(ctx, r, &sinkWriteHandler{sink: s.Sink})
+}
+
+// handle 按当前模式写出记录：异步模式入队，熔断期间丢弃，否则同步写出并计时
+func (s *latencySink) handle(ctx context.Context, r slog.Record, h slog.Handler) error {
+	if async := s.async.Load(); async != nil {
+		return (&AsyncHandler{handler: h, state: async.state}).Handle(ctx, r)
+	}
+	if s.breaker != nil && !s.breaker.Allow() {
+		s.dropped.Add(1)
+		return nil
+	}
+	start := time.Now()
+	err := h.Handle(ctx, r)
+	s.observe(time.Since(start))
+	return err
+}
+
+// observe 统计一次写入耗时，连续超时达到 Strikes 次时执行降级动作
+func (s *latencySink) observe(elapsed time.Duration) {
+	for {
+		cur := s.max.Load()
+		if int64(elapsed) <= cur || s.max.CompareAndSwap(cur, int64(elapsed)) {
+			break
+		}
+	}
+	if elapsed <= s.cfg.Budget {
+		if s.strikes.Load() != 0 {
+			s.strikes.Store(0)
+		}
+		if s.breaker != nil {
+			s.breaker.Success()
+		}
+		return
+	}
+	s.slow.Add(1)
+	n := s.strikes.Add(1)
+
+	switch s.cfg.Action {
+	case LatencyOpen:
+		opens := s.breaker.Stats().Opens
+		s.breaker.Failure(fmt.Errorf("write took %s, budget %s", elapsed, s.cfg.Budget))
+		if s.breaker.Stats().Opens > opens {
+			s.notify(elapsed)
+		}
+	case LatencyAsync:
+		if n >= int64(s.cfg.Strikes) && s.startAsync() {
+			s.notify(elapsed)
+		}
+	default:
+		// 只在刚达到阈值时通知一次，恢复正常后重新计数
+		if n == int64(s.cfg.Strikes) {
+			s.notify(elapsed)
+		}
+	}
+}
+
+// startAsync 切换为异步写出，已切换或输出端已关闭时返回 false
+func (s *latencySink) startAsync() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.async.Load() != nil {
+		return false
+	}
+	async, err := NewAsyncHandler(nil, QueueConfig{Size: s.cfg.QueueSize, Policy: PolicyDropNewest})
+	if err != nil {
+		return false
+	}
+	s.async.Store(async)
+	return true
+}
+
+func (s *latencySink) notify(elapsed time.Duration) {
+	if s.cfg.OnSlow != nil {
+		go s.cfg.OnSlow(LatencyEvent{Sink: s.Name(), Elapsed: elapsed, Budget: s.cfg.Budget, Action: s.cfg.Action})
+	}
+}
+
+// LatencyStats 返回写入耗时统计
+func (s *latencySink) LatencyStats() SinkLatencyStats {
+	stats := SinkLatencyStats{
+		Name:    s.Name(),
+		Mode:    "sync",
+		Slow:    s.slow.Load(),
+		Max:     time.Duration(s.max.Load()),
+		Dropped: s.dropped.Load(),
+	}
+	if async := s.async.Load(); async != nil {
+		stats.Mode = LatencyAsync
+		stats.Dropped += async.Stats().Dropped
+	}
+	if s.breaker != nil && s.breaker.Stats().State != BreakerClosed {
+		stats.Mode = s.breaker.Stats().State
+	}
+	return stats
+}
+
+// Healthy 熔断期间报告不健康，使 Supervisor 的健康检查记录降级与恢复
+func (s *latencySink) Healthy() error {
+	if err := s.Sink.Healthy(); err != nil {
+		return err
+	}
+	if s.breaker != nil {
+		if stats := s.breaker.Stats(); stats.State != BreakerClosed {
+			return fmt.Errorf("circuit %s after slow writes: %s", stats.State, stats.LastError)
+		}
+	}
+	return nil
+}
+
+func (s *latencySink) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext 先等待异步队列写完，再刷新底层输出端
+func (s *latencySink) FlushContext(ctx context.Context) error {
+	if async := s.async.Load(); async != nil {
+		if err := async.Flush(ctx); err != nil {
+			return err
+		}
+	}
+	return flushSink(ctx, s.Sink)
+}
+
+func (s *latencySink) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext 写出异步队列中剩余的记录后关闭底层输出端
+func (s *latencySink) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	if async := s.async.Load(); async != nil {
+		_ = async.Close()
+	}
+	return closeSink(ctx, s.Sink)
+}
+
+func (s *latencySink) Probe(ctx context.Context) error {
+	if p, ok := s.Sink.(Prober); ok {
+		return p.Probe(ctx)
+	}
+	return nil
+}
+
+// Latency 返回监控了写入耗时的输出端的统计
+func (s *Supervisor) Latency() []SinkLatencyStats {
+	var stats []SinkLatencyStats
+	for _, sink := range s.sinks {
+		if ls, ok := sink.(interface{ LatencyStats() SinkLatencyStats }); ok {
+			stats = append(stats, ls.LatencyStats())
+		}
+	}
+	return stats
+}
+
+// latencyHandler 经由 latencySink 计时写出的处理器
+type latencyHandler struct {
+	handler slog.Handler
+	sink    *latencySink
+}
+
+func (h *latencyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *latencyHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.sink.handle(ctx, r, h.handler)
+}
+
+func (h *latencyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &latencyHandler{handler: h.handler.WithAttrs(attrs), sink: h.sink}
+}
+
+func (h *latencyHandler) WithGroup(name string) slog.Handler {
+	return &latencyHandler{handler: h.handler.WithGroup(name), sink: h.sink}
+}
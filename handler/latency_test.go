@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowHandler 写入前等待 delay 的处理器
+type slowHandler struct {
+	capturingHandler
+	delay atomic.Int64
+}
+
+func (h *slowHandler) Handle(ctx context.Context, r slog.Record) error {
+	time.Sleep(time.Duration(h.delay.Load()))
+	return h.capturingHandler.Handle(ctx, r)
+}
+
+// TestMonitorLatencyAsync 测试连续超时后输出端切换为异步写出，关闭时写完剩余记录
+func TestMonitorLatencyAsync(t *testing.T) {
+	slow := &slowHandler{}
+	slow.delay.Store(int64(20 * time.Millisecond))
+	events := make(chan LatencyEvent, 1)
+	sinks := MonitorLatency([]Sink{NewHandlerSink("file", slow, SinkOptions{})}, LatencyConfig{
+		Budget:  5 * time.Millisecond,
+		Strikes: 2,
+		OnSlow:  func(e LatencyEvent) { events <- e },
+	})
+	supervisor := NewSupervisor(sinks...)
+	logger := slog.New(supervisor.Handler())
+
+	logger.Info("one")
+	logger.Info("two")
+	select {
+	case e := <-events:
+		if e.Sink != "file" || e.Action != LatencyAsync {
+			t.Errorf("event = %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no slow sink event")
+	}
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		logger.Info("queued")
+	}
+	if d := time.Since(start); d > 20*time.Millisecond {
+		t.Errorf("writes after switching to async took %s", d)
+	}
+	stats := supervisor.Latency()
+	if len(stats) != 1 || stats[0].Mode != LatencyAsync || stats[0].Slow != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if err := supervisor.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(slow.messages()); got != 7 {
+		t.Errorf("written %d records, want 7", got)
+	}
+}
+
+// TestMonitorLatencyOpen 测试连续超时后熔断丢弃，并通过健康检查报告
+func TestMonitorLatencyOpen(t *testing.T) {
+	slow := &slowHandler{}
+	slow.delay.Store(int64(10 * time.Millisecond))
+	sinks := MonitorLatency([]Sink{NewHandlerSink("push", slow, SinkOptions{})}, LatencyConfig{
+		Budget:       time.Millisecond,
+		Strikes:      2,
+		Action:       LatencyOpen,
+		OpenDuration: time.Hour,
+	})
+	supervisor := NewSupervisor(sinks...)
+	logger := slog.New(supervisor.Handler())
+
+	for i := 0; i < 5; i++ {
+		logger.Info("record")
+	}
+	if got := len(slow.messages()); got != 2 {
+		t.Errorf("written %d records, want 2", got)
+	}
+	stats := supervisor.Latency()[0]
+	if stats.Mode != BreakerOpen || stats.Dropped != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if health := supervisor.Health(); health[0].Healthy {
+		t.Errorf("open sink reported healthy: %+v", health)
+	}
+}
+
+// TestMonitorLatencyRecovers 测试偶发的超时在恢复正常后重新计数，不触发降级
+func TestMonitorLatencyRecovers(t *testing.T) {
+	slow := &slowHandler{}
+	var fired atomic.Bool
+	sinks := MonitorLatency([]Sink{NewHandlerSink("file", slow, SinkOptions{})}, LatencyConfig{
+		Budget:  5 * time.Millisecond,
+		Strikes: 2,
+		Action:  LatencyLog,
+		OnSlow:  func(LatencyEvent) { fired.Store(true) },
+	})
+	logger := slog.New(NewSupervisor(sinks...).Handler())
+	for i := 0; i < 3; i++ {
+		slow.delay.Store(int64(10 * time.Millisecond))
+		logger.Info("slow")
+		slow.delay.Store(0)
+		logger.Info("fast")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if fired.Load() {
+		t.Error("isolated slow writes should not trigger the action")
+	}
+}
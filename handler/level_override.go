@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LevelOverride 运行时级别覆盖：属性 Key 的值等于 Value 的记录在 Expires 之前按 Level 输出，不受全局级别限制；
+// Key 的匹配规则同 DebugRule
+type LevelOverride struct {
+	Key     string     `json:"key"`
+	Value   string     `json:"value"`
+	Level   slog.Level `json:"level"`
+	Expires time.Time  `json:"expires"`
+}
+
+// LevelOverrides 运行时级别覆盖规则集合，到期的规则自动失效。规则写时复制，Handle 无锁读取
+type LevelOverrides struct {
+	mu    sync.Mutex
+	rules atomic.Pointer[[]LevelOverride]
+	now   func() time.Time
+}
+
+// NewLevelOverrides 创建空的级别覆盖规则集合
+func NewLevelOverrides() *LevelOverrides {
+	return &LevelOverrides{now: time.Now}
+}
+
+// Set 添加规则，Key 与 Value 相同的规则被替换
+func (o *LevelOverrides) Set(rule LevelOverride) {
+	o.update(func(rules []LevelOverride) []LevelOverride {
+		rules = slices.DeleteFunc(rules, func(r LevelOverride) bool { return r.Key == rule.Key && r.Value == rule.Value })
+		return append(rules, rule)
+	})
+}
+
+// Remove 删除 Key 与 Value 相同的规则，返回是否存在
+func (o *LevelOverrides) Remove(key, value string) bool {
+	removed := false
+	o.update(func(rules []LevelOverride) []LevelOverride {
+		n := len(rules)
+		rules = slices.DeleteFunc(rules, func(r LevelOverride) bool { return r.Key == key && r.Value == value })
+		removed = len(rules) < n
+		return rules
+	})
+	return removed
+}
+
+// List 返回尚未到期的规则
+func (o *LevelOverrides) List() []LevelOverride {
+	return slices.Clone(o.active())
+}
+
+// update 在锁内修改规则，同时清理已到期的规则
+func (o *LevelOverrides) update(fn func([]LevelOverride) []LevelOverride) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	rules := slices.Clone(o.active())
+	rules = fn(rules)
+	o.rules.Store(&rules)
+}
+
+// active 返回尚未到期的规则，不可修改
+func (o *LevelOverrides) active() []LevelOverride {
+	p := o.rules.Load()
+	if p == nil || len(*p) == 0 {
+		return nil
+	}
+	now := o.now()
+	rules := *p
+	for _, r := range rules {
+		if !now.Before(r.Expires) {
+			return slices.DeleteFunc(slices.Clone(rules), func(r LevelOverride) bool { return !now.Before(r.Expires) })
+		}
+	}
+	return rules
+}
+
+// LevelOverrideHandler 按运行时规则放行低于全局级别的记录：命中规则且级别不低于规则级别的记录
+// 以定向调试的方式输出，后续的级别检查（含模块级别）不再拦截
+type LevelOverrideHandler struct {
+	handler   slog.Handler
+	level     slog.Leveler
+	overrides *LevelOverrides
+	bound     []slog.Attr // WithAttrs 绑定的属性，已按分组嵌套
+	group     string      // WithGroup 累积的组名
+}
+
+// NewLevelOverrideHandler 创建运行时级别覆盖处理器，level 为全局日志级别
+func NewLevelOverrideHandler(handler slog.Handler, level slog.Leveler, overrides *LevelOverrides) *LevelOverrideHandler {
+	return &LevelOverrideHandler{handler: handler, level: level, overrides: overrides}
+}
+
+func (h *LevelOverrideHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= h.level.Level() {
+		return h.handler.Enabled(ctx, level)
+	}
+	for _, rule := range h.overrides.active() {
+		if level >= rule.Level {
+			return true
+		}
+	}
+	return IsDebugTargeted(ctx)
+}
+
+func (h *LevelOverrideHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() && !IsDebugTargeted(ctx) {
+		if !h.matches(r) {
+			return nil
+		}
+		// 标记上下文，让后续的分发和过滤跳过级别检查
+		ctx = WithDebugTarget(ctx)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// matches 检查记录（含绑定的属性）是否命中级别不高于记录级别的规则
+func (h *LevelOverrideHandler) matches(r slog.Record) bool {
+	var rules []LevelOverride
+	for _, rule := range h.overrides.active() {
+		if r.Level >= rule.Level {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return false
+	}
+	matched := false
+	check := func(key string, v slog.Value) bool {
+		for _, rule := range rules {
+			if KeyMatches(key, rule.Key) && v.String() == rule.Value {
+				matched = true
+				return false
+			}
+		}
+		return true
+	}
+	if WalkAttrs("", h.bound, check) {
+		WalkRecordAttrs(h.group, r, check)
+	}
+	return matched
+}
+
+func (h *LevelOverrideHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var groups []string
+	if h.group != "" {
+		groups = []string{h.group}
+	}
+	return &LevelOverrideHandler{
+		handler:   h.handler.WithAttrs(attrs),
+		level:     h.level,
+		overrides: h.overrides,
+		bound:     append(append([]slog.Attr{}, h.bound...), nestAttrs(groups, attrs)...),
+		group:     h.group,
+	}
+}
+
+func (h *LevelOverrideHandler) WithGroup(name string) slog.Handler {
+	return &LevelOverrideHandler{
+		handler:   h.handler.WithGroup(name),
+		level:     h.level,
+		overrides: h.overrides,
+		bound:     h.bound,
+		group:     joinKey(h.group, name),
+	}
+}
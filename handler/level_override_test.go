@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLevelOverrideHandler 测试命中规则的低级别记录（含绑定与分组属性）在到期前输出，到期后恢复全局级别
+func TestLevelOverrideHandler(t *testing.T) {
+	now := time.Now()
+	overrides := NewLevelOverrides()
+	overrides.now = func() time.Time { return now }
+	overrides.Set(LevelOverride{Key: "module", Value: "payments", Level: slog.LevelDebug, Expires: now.Add(10 * time.Minute)})
+	overrides.Set(LevelOverride{Key: "tenant", Value: "acme", Level: slog.LevelInfo, Expires: now.Add(time.Minute)})
+
+	var buf bytes.Buffer
+	inner := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := slog.New(NewLevelOverrideHandler(inner, slog.LevelWarn, overrides))
+
+	logger.With("module", "payments").Debug("bound match")
+	logger.WithGroup("req").Info("grouped match", "tenant", "acme")
+	logger.Debug("below override level", "tenant", "acme")
+	logger.Info("no match", "module", "orders")
+
+	out := buf.String()
+	for msg, want := range map[string]bool{"bound match": true, "grouped match": true, "below override level": false, "no match": false} {
+		if strings.Contains(out, msg) != want {
+			t.Errorf("%q logged = %v, want %v:\n%s", msg, !want, want, out)
+		}
+	}
+
+	now = now.Add(5 * time.Minute)
+	if got := overrides.List(); len(got) != 1 || got[0].Key != "module" {
+		t.Errorf("expired overrides should be dropped, got %+v", got)
+	}
+	if !overrides.Remove("module", "payments") || overrides.Remove("module", "payments") {
+		t.Error("Remove should report whether the override existed")
+	}
+	buf.Reset()
+	logger.Debug("after removal", "module", "payments")
+	if buf.Len() != 0 {
+		t.Errorf("records should follow the global level once overrides are gone: %s", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// LevelTheme 控制台级别标签的文字与样式
+type LevelTheme struct {
+	Labels map[slog.Level]string       // 级别显示文字，未配置的级别使用 slog 的名称
+	Colors map[slog.Level]*color.Color // 级别颜色，未配置的级别使用 Fallback
+	// Fallback 未配置颜色的级别使用的颜色
+	Fallback *color.Color
+	// Badge 为 true 时标签显示为两侧留空的背景色徽章（如 " INF "），否则显示为 [INFO]
+	Badge bool
+}
+
+// 内置的级别主题名称
+const (
+	LevelThemeDefault = "default" // [INFO] 前景色
+	LevelThemeBadge   = "badge"   // 与启动横幅一致的背景色徽章，三字母标签
+)
+
+// DefaultLevelTheme 默认主题：方括号包围的完整级别名，前景色区分级别
+func DefaultLevelTheme() LevelTheme {
+	return LevelTheme{
+		Colors: map[slog.Level]*color.Color{
+			slog.LevelDebug: color.New(color.FgHiWhite),
+			slog.LevelInfo:  color.New(color.FgGreen),
+			slog.LevelWarn:  color.New(color.FgYellow),
+			slog.LevelError: color.New(color.FgRed),
+		},
+		Fallback: color.New(color.FgWhite),
+	}
+}
+
+// BadgeLevelTheme 徽章主题：三字母标签加背景色，配色与启动横幅的级别显示一致
+func BadgeLevelTheme() LevelTheme {
+	return LevelTheme{
+		Labels: map[slog.Level]string{
+			slog.LevelDebug: "DBG",
+			slog.LevelInfo:  "INF",
+			slog.LevelWarn:  "WRN",
+			slog.LevelError: "ERR",
+			LevelFatal:      "FTL",
+		},
+		Colors: map[slog.Level]*color.Color{
+			slog.LevelDebug: color.New(color.FgWhite, color.BgMagenta, color.Bold),
+			slog.LevelInfo:  color.New(color.FgWhite, color.BgGreen, color.Bold),
+			slog.LevelWarn:  color.New(color.FgBlack, color.BgYellow, color.Bold),
+			slog.LevelError: color.New(color.FgWhite, color.BgRed, color.Bold),
+			LevelFatal:      color.New(color.FgWhite, color.BgHiRed, color.Bold),
+		},
+		Fallback: color.New(color.FgWhite, color.BgBlack, color.Bold),
+		Badge:    true,
+	}
+}
+
+// LevelThemeByName 按名称返回内置主题
+func LevelThemeByName(name string) (LevelTheme, bool) {
+	switch strings.ToLower(name) {
+	case "", LevelThemeDefault:
+		return DefaultLevelTheme(), true
+	case LevelThemeBadge:
+		return BadgeLevelTheme(), true
+	default:
+		return LevelTheme{}, false
+	}
+}
+
+// WithLabels 返回覆盖部分级别文字的主题副本
+func (t LevelTheme) WithLabels(labels map[slog.Level]string) LevelTheme {
+	if len(labels) == 0 {
+		return t
+	}
+	merged := make(map[slog.Level]string, len(t.Labels)+len(labels))
+	for level, label := range t.Labels {
+		merged[level] = label
+	}
+	for level, label := range labels {
+		merged[level] = label
+	}
+	t.Labels = merged
+	return t
+}
+
+// label 返回级别的显示文字
+func (t LevelTheme) label(level slog.Level) string {
+	if label, ok := t.Labels[level]; ok {
+		return label
+	}
+	return level.String()
+}
+
+// render 返回带样式的级别标签及其显示宽度
+func (t LevelTheme) render(level slog.Level) (string, int) {
+	c := t.Colors[level]
+	if c == nil {
+		c = t.Fallback
+	}
+	if c == nil {
+		c = color.New(color.FgWhite)
+	}
+	text := "[" + t.label(level) + "]"
+	if t.Badge {
+		text = " " + t.label(level) + " "
+	}
+	return c.Sprint(text), textWidth(text)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LocalConfig 批量缓冲的刷新条件
+type LocalConfig struct {
+	MaxRecords  int           // 缓冲的记录数达到该值时刷新，默认256
+	MaxInterval time.Duration // 第一条记录进入缓冲区后最长等待时间，默认100ms
+}
+
+const (
+	defaultLocalRecords  = 256
+	defaultLocalInterval = 100 * time.Millisecond
+)
+
+// localEntry 缓冲的记录及其写出时使用的处理器（含 WithAttrs/WithGroup）与上下文
+type localEntry struct {
+	handler slog.Handler
+	ctx     context.Context
+	r       slog.Record
+}
+
+// LocalBuffer 由单个goroutine独占的记录缓冲区。热循环中的记录先追加到缓冲区，
+// 达到条数、等待超时或 Flush/Close 时按原顺序一次写出，避免与其他goroutine逐条争用输出端的锁。
+// Error 及以上级别的记录会立即连同之前缓冲的记录一起写出
+type LocalBuffer struct {
+	cfg LocalConfig
+
+	flushMu sync.Mutex // 串行化写出，保证跨批次的顺序
+	mu      sync.Mutex
+	entries []localEntry
+	timer   *time.Timer
+	closed  bool
+}
+
+// NewLocalBuffer 创建批量缓冲区
+func NewLocalBuffer(cfg LocalConfig) *LocalBuffer {
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = defaultLocalRecords
+	}
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = defaultLocalInterval
+	}
+	b := &LocalBuffer{cfg: cfg, entries: make([]localEntry, 0, cfg.MaxRecords)}
+	b.timer = time.AfterFunc(time.Hour, func() { _ = b.Flush() })
+	b.timer.Stop()
+	return b
+}
+
+func (b *LocalBuffer) add(h slog.Handler, ctx context.Context, r slog.Record) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return h.Handle(ctx, r)
+	}
+	b.entries = append(b.entries, localEntry{handler: h, ctx: ctx, r: r.Clone()})
+	n := len(b.entries)
+	if n == 1 {
+		b.timer.Reset(b.cfg.MaxInterval)
+	}
+	b.mu.Unlock()
+
+	if n >= b.cfg.MaxRecords || r.Level >= slog.LevelError {
+		return b.Flush()
+	}
+	return nil
+}
+
+// Flush 按顺序写出缓冲的记录，返回各记录写出时的错误
+func (b *LocalBuffer) Flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.entries
+	b.entries = make([]localEntry, 0, b.cfg.MaxRecords)
+	b.timer.Stop()
+	b.mu.Unlock()
+
+	var errs []error
+	for _, e := range batch {
+		if err := e.handler.Handle(e.ctx, e.r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 写出剩余的记录，之后的记录不再缓冲而直接写出
+func (b *LocalBuffer) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return b.Flush()
+}
+
+// LocalHandler 将记录追加到 LocalBuffer 的处理器，级别判断仍由内层处理器决定，未启用的级别不进入缓冲区
+type LocalHandler struct {
+	handler slog.Handler
+	buf     *LocalBuffer
+}
+
+// NewLocalHandler 创建批量缓冲处理器
+func NewLocalHandler(handler slog.Handler, buf *LocalBuffer) slog.Handler {
+	return &LocalHandler{handler: handler, buf: buf}
+}
+
+func (h *LocalHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *LocalHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.buf.add(h.handler, ctx, r)
+}
+
+func (h *LocalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LocalHandler{handler: h.handler.WithAttrs(attrs), buf: h.buf}
+}
+
+func (h *LocalHandler) WithGroup(name string) slog.Handler {
+	return &LocalHandler{handler: h.handler.WithGroup(name), buf: h.buf}
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLocalBuffer 测试按条数、Error 级别与超时刷新，写出时保持顺序和绑定的属性
+func TestLocalBuffer(t *testing.T) {
+	capture := &capturingHandler{}
+	buf := NewLocalBuffer(LocalConfig{MaxRecords: 3, MaxInterval: 50 * time.Millisecond})
+	logger := slog.New(NewLocalHandler(capture, buf))
+
+	logger.Info("a")
+	logger.Info("b")
+	if n := len(capture.messages()); n != 0 {
+		t.Fatalf("records should stay buffered, got %d", n)
+	}
+	logger.Info("c")
+	if got := strings.Join(capture.messages(), ","); got != "a,b,c" {
+		t.Fatalf("size flush = %s", got)
+	}
+
+	logger.Info("d")
+	logger.Error("e")
+	if got := strings.Join(capture.messages(), ","); got != "a,b,c,d,e" {
+		t.Fatalf("error should flush immediately, got %s", got)
+	}
+
+	logger.Info("f")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(capture.messages()) < 6 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := strings.Join(capture.messages(), ","); got != "a,b,c,d,e,f" {
+		t.Fatalf("interval flush = %s", got)
+	}
+
+	if err := buf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("g")
+	if got := capture.messages(); got[len(got)-1] != "g" {
+		t.Errorf("records after Close should pass through, got %v", got)
+	}
+}
+
+// TestLocalHandlerAttrs 测试 WithAttrs/WithGroup 绑定的属性在延迟写出时保留
+func TestLocalHandlerAttrs(t *testing.T) {
+	var out bytes.Buffer
+	buf := NewLocalBuffer(LocalConfig{})
+	logger := slog.New(NewLocalHandler(slog.NewTextHandler(&out, nil), buf)).With("worker", 7).WithGroup("job")
+
+	logger.Info("step", "n", 1)
+	logger.Debug("hidden")
+	if out.Len() != 0 {
+		t.Fatalf("record should be buffered: %s", out.String())
+	}
+	if err := buf.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "msg=step worker=7 job.n=1") || strings.Contains(out.String(), "hidden") {
+		t.Errorf("output = %s", out.String())
+	}
+}
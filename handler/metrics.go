@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// RecordMetrics 按级别统计写出的记录数与输出端返回的错误数，计数单调递增，可直接作为 Prometheus counter 导出
+type RecordMetrics struct {
+	levels [4]atomic.Int64 // debug, info, warn, error
+	errors atomic.Int64
+}
+
+// metricLevels 统计的级别名称，与 RecordMetrics.levels 的下标对应
+var metricLevels = [4]string{"debug", "info", "warn", "error"}
+
+// NewRecordMetrics 创建记录计数器
+func NewRecordMetrics() *RecordMetrics {
+	return &RecordMetrics{}
+}
+
+// Records 返回各级别的记录数，键为 debug、info、warn、error；自定义级别计入不高于它的最近级别
+func (m *RecordMetrics) Records() map[string]int64 {
+	records := make(map[string]int64, len(metricLevels))
+	for i, name := range metricLevels {
+		records[name] = m.levels[i].Load()
+	}
+	return records
+}
+
+// Errors 返回输出端处理记录失败的次数
+func (m *RecordMetrics) Errors() int64 {
+	return m.errors.Load()
+}
+
+func (m *RecordMetrics) record(level slog.Level) {
+	i := 0
+	switch {
+	case level >= slog.LevelError:
+		i = 3
+	case level >= slog.LevelWarn:
+		i = 2
+	case level >= slog.LevelInfo:
+		i = 1
+	}
+	m.levels[i].Add(1)
+}
+
+// MetricsHandler 统计经过的记录和内层处理器返回的错误，置于输出端之外时计数的是实际写出的记录
+type MetricsHandler struct {
+	handler slog.Handler
+	metrics *RecordMetrics
+}
+
+// NewMetricsHandler 创建记录计数处理器
+func NewMetricsHandler(handler slog.Handler, metrics *RecordMetrics) slog.Handler {
+	return &MetricsHandler{handler: handler, metrics: metrics}
+}
+
+func (h *MetricsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *MetricsHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.metrics.record(r.Level)
+	err := h.handler.Handle(ctx, r)
+	if err != nil {
+		h.metrics.errors.Add(1)
+	}
+	return err
+}
+
+func (h *MetricsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &MetricsHandler{handler: h.handler.WithAttrs(attrs), metrics: h.metrics}
+}
+
+func (h *MetricsHandler) WithGroup(name string) slog.Handler {
+	return &MetricsHandler{handler: h.handler.WithGroup(name), metrics: h.metrics}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+// levelFailingHandler 对 Error 及以上级别的记录返回错误
+type levelFailingHandler struct{}
+
+func (h levelFailingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h levelFailingHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		return errors.New("sink unavailable")
+	}
+	return nil
+}
+func (h levelFailingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h levelFailingHandler) WithGroup(string) slog.Handler      { return h }
+
+// TestMetricsHandler 测试按级别计数（自定义级别归入最近的较低级别）以及错误计数
+func TestMetricsHandler(t *testing.T) {
+	metrics := NewRecordMetrics()
+	logger := slog.New(NewMetricsHandler(levelFailingHandler{}, metrics)).With("svc", "api").WithGroup("req")
+
+	logger.Info("a")
+	logger.Info("b")
+	logger.Warn("c")
+	logger.Error("d")
+	logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+	want := map[string]int64{"debug": 0, "info": 2, "warn": 1, "error": 2}
+	got := metrics.Records()
+	for level, n := range want {
+		if got[level] != n {
+			t.Errorf("records[%s] = %d, want %d", level, got[level], n)
+		}
+	}
+	if metrics.Errors() != 2 {
+		t.Errorf("errors = %d, want 2", metrics.Errors())
+	}
+}
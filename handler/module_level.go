@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// ModuleKey 标识日志所属模块的属性名
+const ModuleKey = "module"
+
+// ModuleLevels 按模块设置的日志级别。模块名按 "." 分层，未单独配置的子模块沿用最近的上级模块，
+// 如 database 的级别同样作用于 database.postgres；都未配置时使用 Base
+type ModuleLevels struct {
+	Base   slog.Leveler
+	Levels map[string]slog.Level
+}
+
+// Level 返回 Base 与各模块级别中最低的级别，实现 slog.Leveler，作为各输出处理器的过滤下限，
+// 具体模块的级别由 ModuleLevelHandler 判断
+func (m *ModuleLevels) Level() slog.Level {
+	level := m.Base.Level()
+	for _, l := range m.Levels {
+		level = min(level, l)
+	}
+	return level
+}
+
+// For 返回模块生效的级别
+func (m *ModuleLevels) For(module string) slog.Level {
+	for module != "" {
+		if level, ok := m.Levels[module]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			break
+		}
+		module = module[:i]
+	}
+	return m.Base.Level()
+}
+
+// ModuleLevelHandler 按记录所属模块的级别过滤：模块来自 WithAttrs 绑定的 module 属性
+// （如 logger.Module 派生的日志器），或记录自身携带的 module 属性。定向调试的记录不受限制
+type ModuleLevelHandler struct {
+	handler slog.Handler
+	levels  *ModuleLevels
+	module  string // WithAttrs 绑定的模块名
+	bound   bool   // 已通过 WithAttrs 确定模块
+	grouped bool   // WithGroup 之后的属性位于分组内，不再视为模块
+}
+
+// NewModuleLevelHandler 创建按模块过滤级别的处理器，未配置任何模块时直接返回原处理器
+func NewModuleLevelHandler(handler slog.Handler, levels *ModuleLevels) slog.Handler {
+	if levels == nil || len(levels.Levels) == 0 {
+		return handler
+	}
+	return &ModuleLevelHandler{handler: handler, levels: levels}
+}
+
+func (h *ModuleLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	threshold := h.levels.Level() // 模块要等到记录属性中才能确定
+	if h.bound {
+		threshold = h.levels.For(h.module)
+	}
+	if level < threshold && !IsDebugTargeted(ctx) {
+		return false
+	}
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *ModuleLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	module := h.module
+	if !h.bound && !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == ModuleKey {
+				module = a.Value.String()
+				return false
+			}
+			return true
+		})
+	}
+	if r.Level < h.levels.For(module) && !IsDebugTargeted(ctx) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *ModuleLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithAttrs(attrs)
+	for _, a := range attrs {
+		if a.Key == ModuleKey && !h.grouped {
+			clone.module, clone.bound = a.Value.String(), true
+		}
+	}
+	return &clone
+}
+
+func (h *ModuleLevelHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithGroup(name)
+	clone.grouped = true
+	return &clone
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// TestModuleLevelHandler 测试按模块级别过滤：WithAttrs 绑定的模块、记录属性中的模块、上级模块继承与定向调试
+func TestModuleLevelHandler(t *testing.T) {
+	base := new(slog.LevelVar)
+	levels := &ModuleLevels{Base: base, Levels: map[string]slog.Level{
+		"database": slog.LevelDebug,
+		"http":     slog.LevelWarn,
+	}}
+	if got := levels.Level(); got != slog.LevelDebug {
+		t.Errorf("floor level = %v, want DEBUG", got)
+	}
+	if got := levels.For("database.postgres"); got != slog.LevelDebug {
+		t.Errorf("submodule should inherit its parent level, got %v", got)
+	}
+
+	capture := &capturingHandler{}
+	logger := slog.New(NewModuleLevelHandler(capture, levels))
+	ctx := context.Background()
+
+	db := logger.With(ModuleKey, "database.postgres")
+	if !db.Enabled(ctx, slog.LevelDebug) {
+		t.Error("database logger should be enabled at debug")
+	}
+	if logger.With(ModuleKey, "http").Enabled(ctx, slog.LevelInfo) {
+		t.Error("http logger should not be enabled below warn")
+	}
+
+	db.Debug("query")
+	logger.Debug("plain debug")
+	logger.Info("plain info")
+	logger.Info("http info", ModuleKey, "http")
+	logger.Warn("http warn", ModuleKey, "http")
+	logger.WithGroup("req").Debug("grouped module", ModuleKey, "database")
+	logger.Debug("targeted", ModuleKey, "http")
+	logger.DebugContext(WithDebugTarget(ctx), "targeted debug", ModuleKey, "http")
+
+	want := []string{"query", "plain info", "http warn", "targeted debug"}
+	if got := capture.messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+
+	base.Set(slog.LevelDebug)
+	logger.Debug("after base change")
+	if msgs := capture.messages(); msgs[len(msgs)-1] != "after base change" {
+		t.Error("modules without a level should follow the base LevelVar")
+	}
+}
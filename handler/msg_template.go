@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// MsgTemplateKey 消息模板属性名
+const MsgTemplateKey = "msg_template"
+
+// MessageTemplate 返回归一化的消息模板，数字、ID等变量部分替换为占位符，
+// 规则与 ErrorTemplate 相同，使同类消息可按模板聚合
+func MessageTemplate(msg string) string {
+	return ErrorTemplate(msg)
+}
+
+// MsgTemplateHandler 为每条记录附加 msg_template 属性
+type MsgTemplateHandler struct {
+	handler slog.Handler
+}
+
+// NewMsgTemplateHandler 创建消息模板处理器
+func NewMsgTemplateHandler(handler slog.Handler) *MsgTemplateHandler {
+	return &MsgTemplateHandler{handler: handler}
+}
+
+func (h *MsgTemplateHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *MsgTemplateHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Message != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String(MsgTemplateKey, MessageTemplate(r.Message)))
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *MsgTemplateHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &MsgTemplateHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *MsgTemplateHandler) WithGroup(name string) slog.Handler {
+	return &MsgTemplateHandler{handler: h.handler.WithGroup(name)}
+}
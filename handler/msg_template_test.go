@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestMsgTemplateHandler 测试不同变量的同类消息得到相同模板
+func TestMsgTemplateHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewMsgTemplateHandler(slog.NewJSONHandler(&buf, nil)))
+
+	templates := make(map[string]bool)
+	for _, msg := range []string{"user 42 logged in after 3 attempts", "user 7 logged in after 12 attempts"} {
+		buf.Reset()
+		logger.Info(msg)
+		var out map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		templates[out[MsgTemplateKey].(string)] = true
+		if out["msg"] != msg {
+			t.Errorf("msg should be kept as is, got %v", out["msg"])
+		}
+	}
+	if len(templates) != 1 || !templates["user <n> logged in after <n> attempts"] {
+		t.Errorf("unexpected templates: %v", templates)
+	}
+}
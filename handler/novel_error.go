@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NovelErrorConfig 新错误检测配置
+type NovelErrorConfig struct {
+	Capacity int                // 记住的错误模板数，超出后淘汰最久未出现的模板
+	Warmup   time.Duration      // 启动后该时长内只学习不告警，避免重启后把已知错误当成新错误
+	Notify   []func(NovelError) // 由配置产生的回调（如 Webhook），每次 Configure 时整体替换
+}
+
+// NovelError 首次出现的错误
+type NovelError struct {
+	Time     time.Time `json:"time"`
+	Template string    `json:"template"`
+	Message  string    `json:"message"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// NovelErrorDetector 记录近期出现过的错误模板，出现从未见过的模板时调用注册的回调
+type NovelErrorDetector struct {
+	mu        sync.Mutex
+	cfg       NovelErrorConfig
+	seen      map[string]*list.Element
+	order     *list.List // 最近出现的模板在前
+	started   time.Time
+	callbacks []func(NovelError)
+}
+
+// NewNovelErrorDetector 创建新错误检测器
+func NewNovelErrorDetector(cfg NovelErrorConfig) *NovelErrorDetector {
+	d := &NovelErrorDetector{
+		seen:  make(map[string]*list.Element),
+		order: list.New(),
+	}
+	d.Configure(cfg)
+	return d
+}
+
+// Configure 更新检测配置并重新开始预热，已学习的模板和 OnNovelError 注册的回调保持不变
+func (d *NovelErrorDetector) Configure(cfg NovelErrorConfig) {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = 10000
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg = cfg
+	d.started = time.Now()
+	for d.order.Len() > cfg.Capacity {
+		d.evictOldest()
+	}
+}
+
+// OnNovelError 注册新错误回调，回调在独立的goroutine中执行
+func (d *NovelErrorDetector) OnNovelError(fn func(NovelError)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.callbacks = append(d.callbacks, fn)
+}
+
+// Observe 检查一条记录，Error 及以上级别且模板首次出现时返回 true
+func (d *NovelErrorDetector) Observe(r slog.Record) (NovelError, bool) {
+	if r.Level < slog.LevelError {
+		return NovelError{}, false
+	}
+
+	errText := ""
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "error" {
+			errText = a.Value.String()
+			return false
+		}
+		return true
+	})
+	template := ErrorTemplate(r.Message)
+	if errText != "" {
+		template += ": " + ErrorTemplate(errText)
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	if el, ok := d.seen[template]; ok {
+		d.order.MoveToFront(el)
+		d.mu.Unlock()
+		return NovelError{}, false
+	}
+	d.seen[template] = d.order.PushFront(template)
+	if d.order.Len() > d.cfg.Capacity {
+		d.evictOldest()
+	}
+	if now.Sub(d.started) < d.cfg.Warmup {
+		d.mu.Unlock()
+		return NovelError{}, false
+	}
+	callbacks := append(append([]func(NovelError){}, d.cfg.Notify...), d.callbacks...)
+	d.mu.Unlock()
+
+	novel := NovelError{Time: now, Template: template, Message: r.Message, Error: errText}
+	for _, fn := range callbacks {
+		go fn(novel)
+	}
+	return novel, true
+}
+
+// evictOldest 淘汰最久未出现的模板，调用方需持有锁
+func (d *NovelErrorDetector) evictOldest() {
+	if el := d.order.Back(); el != nil {
+		d.order.Remove(el)
+		delete(d.seen, el.Value.(string))
+	}
+}
+
+var (
+	uuidPattern   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	hexPattern    = regexp.MustCompile(`\b(0x)?[0-9a-fA-F]*[0-9][0-9a-fA-F]*\b`)
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+	quotePattern  = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+)
+
+// ErrorTemplate 将错误消息中的变量部分（引号内容、UUID、十六进制串、数字）替换为占位符，
+// 使同一类错误归为同一模板
+func ErrorTemplate(msg string) string {
+	msg = quotePattern.ReplaceAllString(msg, `"*"`)
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexPattern.ReplaceAllStringFunc(msg, func(s string) string {
+		if len(s) < 8 {
+			return s
+		}
+		return "<hex>"
+	})
+	msg = numberPattern.ReplaceAllString(msg, "<n>")
+	return strings.TrimSpace(msg)
+}
+
+// WebhookNovelError 返回将新错误以JSON POST到指定地址的回调
+func WebhookNovelError(url string) func(NovelError) {
+	return WebhookNovelErrorWithClient(url, &http.Client{Timeout: DefaultTransportTimeout})
+}
+
+// WebhookNovelErrorWithClient 使用指定的HTTP客户端发送新错误通知
+func WebhookNovelErrorWithClient(url string, client *http.Client) func(NovelError) {
+	return func(novel NovelError) {
+		postJSON(client, url, novel)
+	}
+}
+
+// NovelErrorHandler 转发记录，首次出现的错误额外输出一条 Novel error observed 记录
+type NovelErrorHandler struct {
+	handler  slog.Handler
+	detector *NovelErrorDetector
+}
+
+// NewNovelErrorHandler 创建新错误检测处理器
+func NewNovelErrorHandler(handler slog.Handler, detector *NovelErrorDetector) *NovelErrorHandler {
+	return &NovelErrorHandler{handler: handler, detector: detector}
+}
+
+func (h *NovelErrorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *NovelErrorHandler) Handle(ctx context.Context, r slog.Record) error {
+	novel, ok := h.detector.Observe(r)
+	if err := h.handler.Handle(ctx, r); err != nil || !ok {
+		return err
+	}
+
+	notice := slog.NewRecord(novel.Time, slog.LevelError, "Novel error observed", r.PC)
+	notice.AddAttrs(
+		slog.String("type", "novel_error"),
+		slog.String("template", novel.Template),
+	)
+	return h.handler.Handle(ctx, notice)
+}
+
+func (h *NovelErrorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &NovelErrorHandler{handler: h.handler.WithAttrs(attrs), detector: h.detector}
+}
+
+func (h *NovelErrorHandler) WithGroup(name string) slog.Handler {
+	return &NovelErrorHandler{handler: h.handler.WithGroup(name), detector: h.detector}
+}
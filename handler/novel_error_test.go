@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestErrorTemplate 测试变量部分被替换为占位符
+func TestErrorTemplate(t *testing.T) {
+	a := ErrorTemplate(`order 1042 failed: user "alice" not found (trace 3f2a9c81e0b4)`)
+	b := ErrorTemplate(`order 77 failed: user "bob" not found (trace 9b1d0e6f2c3a)`)
+	if a != b {
+		t.Errorf("templates should match:\n%s\n%s", a, b)
+	}
+}
+
+// TestNovelErrorHandler 测试只有首次出现的错误模板会额外输出提示记录
+func TestNovelErrorHandler(t *testing.T) {
+	var buf bytes.Buffer
+	detector := NewNovelErrorDetector(NovelErrorConfig{})
+	logger := slog.New(NewNovelErrorHandler(slog.NewTextHandler(&buf, nil), detector))
+
+	logger.Error("payment failed", "error", errors.New("card 4242 declined"))
+	logger.Error("payment failed", "error", errors.New("card 1881 declined"))
+	logger.Error("payment failed", "error", errors.New("gateway timeout"))
+	logger.Warn("retrying")
+
+	if got := strings.Count(buf.String(), "Novel error observed"); got != 2 {
+		t.Errorf("expected 2 novel error notices, got %d:\n%s", got, buf.String())
+	}
+}
+
+// TestNovelErrorConfigureNotify 测试 Configure 替换配置产生的回调，OnNovelError 注册的回调保留
+func TestNovelErrorConfigureNotify(t *testing.T) {
+	seen := make(chan string, 4)
+	detector := NewNovelErrorDetector(NovelErrorConfig{Notify: []func(NovelError){
+		func(NovelError) { seen <- "old" },
+	}})
+	detector.OnNovelError(func(NovelError) { seen <- "user" })
+	detector.Configure(NovelErrorConfig{Notify: []func(NovelError){
+		func(NovelError) { seen <- "new" },
+	}})
+
+	if _, ok := detector.Observe(slog.NewRecord(time.Now(), slog.LevelError, "disk full", 0)); !ok {
+		t.Fatal("expected a novel error")
+	}
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case name := <-seen:
+			got[name] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 callbacks, got %v", got)
+		}
+	}
+	if !got["new"] || !got["user"] {
+		t.Errorf("expected the new and user callbacks, got %v", got)
+	}
+	select {
+	case name := <-seen:
+		t.Errorf("replaced callback %q should not fire", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPositionInterval 位置文件的默认更新间隔
+const DefaultPositionInterval = time.Second
+
+// FilePosition 活动日志文件的位置信息，供 Promtail、Vector 等采集器对接，无需猜测 lumberjack 的轮转方式：
+// inode 变化表示文件已被轮转，旧文件（inode 为 PreviousInode）已改名为备份，Offset 之前都是完整的记录
+type FilePosition struct {
+	Path          string    `json:"path"`
+	Inode         uint64    `json:"inode,omitempty"`          // Windows 等平台为0
+	Device        uint64    `json:"device,omitempty"`         // Windows 等平台为0
+	Offset        int64     `json:"offset"`                   // 已写出的字节数
+	PreviousInode uint64    `json:"previous_inode,omitempty"` // 最近一次轮转前的 inode
+	Rotations     int64     `json:"rotations"`                // 本进程观察到的轮转次数
+	Updated       time.Time `json:"updated"`
+	PID           int       `json:"pid"`
+}
+
+// PositionWriter 包装日志文件写入器，有写入时按间隔将活动文件的路径、inode 与偏移量写入旁路的 JSON 文件。
+// 位置文件先写入临时文件再改名，采集器不会读到写了一半的内容
+type PositionWriter struct {
+	w        io.WriteCloser
+	path     string
+	sidecar  string
+	dirty    atomic.Bool
+	mu       sync.Mutex
+	position FilePosition
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewPositionWriter 创建位置跟踪写入器，path 为日志文件，sidecar 为位置文件，interval<=0 时使用 DefaultPositionInterval
+func NewPositionWriter(w io.WriteCloser, path, sidecar string, interval time.Duration) *PositionWriter {
+	if interval <= 0 {
+		interval = DefaultPositionInterval
+	}
+	p := &PositionWriter{
+		w:        w,
+		path:     path,
+		sidecar:  sidecar,
+		position: FilePosition{Path: path, PID: os.Getpid()},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	p.dirty.Store(true)
+	go p.run(interval)
+	return p
+}
+
+func (p *PositionWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.dirty.Store(true)
+	return n, err
+}
+
+// Close 写出最终位置并关闭底层写入器
+func (p *PositionWriter) Close() error {
+	close(p.stop)
+	<-p.done
+	err := p.w.Close()
+	p.dirty.Store(true)
+	if uerr := p.Update(); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// Position 返回最近一次更新的位置
+func (p *PositionWriter) Position() FilePosition {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position
+}
+
+// Update 有新的写入时重新读取文件状态并写出位置文件
+func (p *PositionWriter) Update() error {
+	if !p.dirty.Swap(false) {
+		return nil
+	}
+	info, err := os.Stat(p.path)
+	if os.IsNotExist(err) {
+		return nil // 尚未写入任何记录
+	}
+	if err != nil {
+		p.dirty.Store(true)
+		return err
+	}
+
+	p.mu.Lock()
+	pos := p.position
+	inode, device := fileID(info)
+	if pos.Inode != 0 && inode != pos.Inode {
+		pos.PreviousInode = pos.Inode
+		pos.Rotations++
+	}
+	pos.Inode, pos.Device = inode, device
+	pos.Offset = info.Size()
+	pos.Updated = time.Now()
+	p.position = pos
+	p.mu.Unlock()
+
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(p.sidecar, data)
+}
+
+func (p *PositionWriter) run(interval time.Duration) {
+	defer close(p.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = p.Update()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// writeFileAtomic 写入同目录的临时文件后改名替换目标文件
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// TestPositionWriter 测试位置文件记录偏移量，轮转后 inode 变化并记录旧文件的 inode
+func TestPositionWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	sidecar := filepath.Join(dir, "app.log.position")
+	rotator := &lumberjack.Logger{Filename: path}
+	w := NewPositionWriter(rotator, path, sidecar, 0)
+
+	read := func() FilePosition {
+		t.Helper()
+		if err := w.Update(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(sidecar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pos FilePosition
+		if err := json.Unmarshal(data, &pos); err != nil {
+			t.Fatal(err)
+		}
+		return pos
+	}
+
+	_, _ = w.Write([]byte("first line\n"))
+	first := read()
+	if first.Path != path || first.Offset != 11 || first.PID != os.Getpid() {
+		t.Errorf("unexpected position %+v", first)
+	}
+
+	if err := rotator.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("new\n"))
+	second := read()
+	if second.Offset != 4 {
+		t.Errorf("offset should restart after rotation: %+v", second)
+	}
+	if runtime.GOOS != "windows" && (second.Inode == first.Inode || second.PreviousInode != first.Inode || second.Rotations != 1) {
+		t.Errorf("rotation should change the inode: before %+v, after %+v", first, second)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
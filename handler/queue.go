@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// OverflowPolicy 队列已满时的处理策略
+type OverflowPolicy string
+
+const (
+	PolicyBlock      OverflowPolicy = "block"       // 阻塞调用方直到有空位（适合审计日志）
+	PolicyDropNewest OverflowPolicy = "drop_newest" // 丢弃新记录
+	PolicyDropOldest OverflowPolicy = "drop_oldest" // 丢弃队列中最旧的记录
+	PolicySample     OverflowPolicy = "sample"      // 按 1/SampleRate 采样保留新记录（挤出最旧的记录），其余丢弃
+)
+
+// QueueConfig 有界队列配置
+type QueueConfig struct {
+	Size       int            // 队列容量
+	Policy     OverflowPolicy // 队列已满时的策略
+	SampleRate int            // sample 策略下每 N 条保留 1 条
+}
+
+// QueueStats 队列统计，各策略分别计数
+type QueueStats struct {
+	Policy        string `json:"policy"`
+	Len           int    `json:"len"`
+	Cap           int    `json:"cap"`
+	Enqueued      int64  `json:"enqueued"`
+	Blocked       int64  `json:"blocked"`        // 因队列已满而阻塞的次数
+	DroppedNewest int64  `json:"dropped_newest"` // 丢弃的新记录
+	DroppedOldest int64  `json:"dropped_oldest"` // 被挤出的旧记录
+	SampledOut    int64  `json:"sampled_out"`    // 采样丢弃的记录
+	Dropped       int64  `json:"dropped"`        // 以上三项丢弃之和
+}
+
+// Queue 带溢出策略的有界队列，供异步处理器和远程输出共用
+type Queue[T any] struct {
+	ch  chan T
+	cfg QueueConfig
+
+	enqueued      atomic.Int64
+	blocked       atomic.Int64
+	droppedNewest atomic.Int64
+	droppedOldest atomic.Int64
+	sampledOut    atomic.Int64
+	sampleSeq     atomic.Int64
+}
+
+// Validate 检查溢出策略，为空时视为 block
+func (c QueueConfig) Validate() error {
+	switch c.Policy {
+	case "", PolicyBlock, PolicyDropNewest, PolicyDropOldest, PolicySample:
+		return nil
+	}
+	return fmt.Errorf("unknown overflow policy %q, want block, drop_newest, drop_oldest or sample", c.Policy)
+}
+
+// NewQueue 创建有界队列，溢出策略为空时使用 block，无法识别时返回错误
+func NewQueue[T any](cfg QueueConfig) (*Queue[T], error) {
+	if cfg.Size <= 0 {
+		cfg.Size = 1024
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = PolicyBlock
+	}
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = 10
+	}
+	return &Queue[T]{
+		ch:  make(chan T, cfg.Size),
+		cfg: cfg,
+	}, nil
+}
+
+// C 返回用于消费的通道
+func (q *Queue[T]) C() <-chan T {
+	return q.ch
+}
+
+// Push 放入一条记录，队列已满时按策略处理，返回记录是否入队
+func (q *Queue[T]) Push(item T) bool {
+	select {
+	case q.ch <- item:
+		q.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	switch q.cfg.Policy {
+	case PolicyBlock:
+		q.blocked.Add(1)
+		q.ch <- item
+		q.enqueued.Add(1)
+		return true
+
+	case PolicyDropOldest:
+		return q.pushDropOldest(item)
+
+	case PolicySample:
+		// 采样保留的记录同样不阻塞调用方，挤出队列中最旧的记录
+		if q.sampleSeq.Add(1)%int64(q.cfg.SampleRate) == 0 {
+			return q.pushDropOldest(item)
+		}
+		q.sampledOut.Add(1)
+		return false
+
+	default: // PolicyDropNewest
+		q.droppedNewest.Add(1)
+		return false
+	}
+}
+
+// pushDropOldest 丢弃队列中最旧的记录直到放入 item
+func (q *Queue[T]) pushDropOldest(item T) bool {
+	for {
+		select {
+		case <-q.ch:
+			q.droppedOldest.Add(1)
+		default:
+		}
+		select {
+		case q.ch <- item:
+			q.enqueued.Add(1)
+			return true
+		default:
+		}
+	}
+}
+
+// Dropped 返回因溢出而丢弃的记录总数
+func (q *Queue[T]) Dropped() int64 {
+	return q.droppedNewest.Load() + q.droppedOldest.Load() + q.sampledOut.Load()
+}
+
+// Stats 返回队列统计
+func (q *Queue[T]) Stats() QueueStats {
+	return QueueStats{
+		Policy:        string(q.cfg.Policy),
+		Len:           len(q.ch),
+		Cap:           cap(q.ch),
+		Enqueued:      q.enqueued.Load(),
+		Blocked:       q.blocked.Load(),
+		DroppedNewest: q.droppedNewest.Load(),
+		DroppedOldest: q.droppedOldest.Load(),
+		SampledOut:    q.sampledOut.Load(),
+		Dropped:       q.Dropped(),
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestQueueOverflowPolicies 测试各溢出策略的丢弃行为与计数
+func TestQueueOverflowPolicies(t *testing.T) {
+	newest, _ := NewQueue[int](QueueConfig{Size: 2, Policy: PolicyDropNewest})
+	for i := 0; i < 5; i++ {
+		newest.Push(i)
+	}
+	if s := newest.Stats(); s.Enqueued != 2 || s.DroppedNewest != 3 {
+		t.Errorf("drop_newest stats = %+v", s)
+	}
+	if first := <-newest.C(); first != 0 {
+		t.Errorf("drop_newest should keep the oldest records, got %d", first)
+	}
+
+	oldest, _ := NewQueue[int](QueueConfig{Size: 2, Policy: PolicyDropOldest})
+	for i := 0; i < 5; i++ {
+		oldest.Push(i)
+	}
+	if s := oldest.Stats(); s.DroppedOldest != 3 || s.Len != 2 {
+		t.Errorf("drop_oldest stats = %+v", s)
+	}
+	if first := <-oldest.C(); first != 3 {
+		t.Errorf("drop_oldest should keep the newest records, got %d", first)
+	}
+
+	sample, _ := NewQueue[int](QueueConfig{Size: 1, Policy: PolicySample, SampleRate: 100})
+	sample.Push(0)
+	for i := 0; i < 50; i++ {
+		sample.Push(i)
+	}
+	if s := sample.Stats(); s.SampledOut != 50 || sample.Dropped() != 50 {
+		t.Errorf("sample stats = %+v", s)
+	}
+
+	// 采样保留的记录不阻塞调用方，而是挤出最旧的记录
+	kept, _ := NewQueue[int](QueueConfig{Size: 1, Policy: PolicySample, SampleRate: 2})
+	for i := 0; i < 5; i++ {
+		kept.Push(i)
+	}
+	if s := kept.Stats(); s.SampledOut != 2 || s.DroppedOldest != 2 || s.Blocked != 0 {
+		t.Errorf("sample stats = %+v", s)
+	}
+	if first := <-kept.C(); first != 4 {
+		t.Errorf("sample should keep the newest sampled record, got %d", first)
+	}
+}
+
+// TestQueueUnknownPolicy 测试无法识别的溢出策略被拒绝，而不是静默按 drop_newest 处理
+func TestQueueUnknownPolicy(t *testing.T) {
+	if _, err := NewQueue[int](QueueConfig{Policy: "drop_newset"}); err == nil {
+		t.Error("unknown policy should be rejected")
+	}
+	if _, err := NewAsyncHandler(slog.NewTextHandler(io.Discard, nil), QueueConfig{Policy: "fifo"}); err == nil {
+		t.Error("NewAsyncHandler should reject unknown policies")
+	}
+}
+
+// TestAsyncHandlerCloseRace 测试与 Close 并发的写入不会丢失，block 策略下也不会永久阻塞
+func TestAsyncHandlerCloseRace(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		var buf lockedWriter
+		async, _ := NewAsyncHandler(slog.NewTextHandler(&buf, nil), QueueConfig{Size: 1, Policy: PolicyBlock})
+		logger := slog.New(async)
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					logger.Info("record")
+				}
+			}()
+		}
+		time.Sleep(time.Millisecond)
+		_ = async.Close()
+		wg.Wait()
+		if got := strings.Count(buf.String(), "record"); got != 200 {
+			t.Fatalf("got %d records, want 200", got)
+		}
+	}
+}
+
+// lockedWriter 并发写入安全的缓冲区
+type lockedWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *lockedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// TestAsyncHandlerClose 测试关闭时写出队列中剩余的记录
+func TestAsyncHandlerClose(t *testing.T) {
+	var buf bytes.Buffer
+	async, _ := NewAsyncHandler(slog.NewTextHandler(&buf, nil), QueueConfig{Size: 16})
+	logger := slog.New(async)
+	for i := 0; i < 10; i++ {
+		logger.Info("queued")
+	}
+	if err := async.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), "queued"); got != 10 {
+		t.Errorf("expected 10 records after Close, got %d", got)
+	}
+	logger.Info("after close")
+	if !strings.Contains(buf.String(), "after close") {
+		t.Error("records after Close should be handled synchronously")
+	}
+}
+
+// slowWriter 每次写入前等待，模拟较慢的输出端
+type slowWriter struct {
+	buf   bytes.Buffer
+	delay time.Duration
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return w.buf.Write(p)
+}
+
+// TestAsyncHandlerFlush 测试 Flush 等待已入队的记录写出，超时时返回 ctx 的错误
+func TestAsyncHandlerFlush(t *testing.T) {
+	w := &slowWriter{delay: 5 * time.Millisecond}
+	async, _ := NewAsyncHandler(slog.NewTextHandler(w, nil), QueueConfig{Size: 16})
+	defer async.Close()
+	logger := slog.New(async)
+	for i := 0; i < 10; i++ {
+		logger.Info("queued")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	if err := async.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	cancel()
+
+	if err := async.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(w.buf.String(), "queued"); got != 10 {
+		t.Errorf("expected 10 records after Flush, got %d", got)
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// RecordRing 请求级记录环形缓冲，保留最近的记录（包含低于全局级别的 Debug 记录）
+type RecordRing struct {
+	mu      sync.Mutex
+	records []slog.Record
+	next    int
+	full    bool
+}
+
+// NewRecordRing 创建容量为 size 的记录环
+func NewRecordRing(size int) *RecordRing {
+	if size <= 0 {
+		size = 100
+	}
+	return &RecordRing{records: make([]slog.Record, size)}
+}
+
+// Add 追加一条记录，已满时覆盖最旧的记录
+func (r *RecordRing) Add(rec slog.Record) {
+	r.mu.Lock()
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+}
+
+// Records 按时间顺序返回缓冲中的记录
+func (r *RecordRing) Records() []slog.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]slog.Record(nil), r.records[:r.next]...)
+	}
+	out := make([]slog.Record, 0, len(r.records))
+	out = append(out, r.records[r.next:]...)
+	return append(out, r.records[:r.next]...)
+}
+
+// recordRingKey 上下文中存放记录环的键
+type recordRingKey struct{}
+
+// WithRecordRing 将记录环绑定到上下文，该上下文产生的所有记录都会写入记录环
+func WithRecordRing(ctx context.Context, ring *RecordRing) context.Context {
+	return context.WithValue(ctx, recordRingKey{}, ring)
+}
+
+// RecordRingFromContext 获取上下文绑定的记录环
+func RecordRingFromContext(ctx context.Context) *RecordRing {
+	if ctx == nil {
+		return nil
+	}
+	ring, _ := ctx.Value(recordRingKey{}).(*RecordRing)
+	return ring
+}
+
+// RingHandler 将带记录环的上下文产生的记录写入记录环，再按原级别交给下游处理器
+type RingHandler struct {
+	handler slog.Handler
+	attrs   []slog.Attr
+}
+
+// NewRingHandler 创建记录环处理器，需作为最外层处理器以便接收低于全局级别的记录
+func NewRingHandler(handler slog.Handler) *RingHandler {
+	return &RingHandler{handler: handler}
+}
+
+func (h *RingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return RecordRingFromContext(ctx) != nil || h.handler.Enabled(ctx, level)
+}
+
+func (h *RingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ring := RecordRingFromContext(ctx); ring != nil {
+		captured := r.Clone()
+		captured.AddAttrs(h.attrs...)
+		ring.Add(captured)
+		if !h.handler.Enabled(ctx, r.Level) {
+			return nil
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *RingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RingHandler{
+		handler: h.handler.WithAttrs(attrs),
+		attrs:   append(append([]slog.Attr(nil), h.attrs...), attrs...),
+	}
+}
+
+func (h *RingHandler) WithGroup(name string) slog.Handler {
+	return &RingHandler{handler: h.handler.WithGroup(name), attrs: h.attrs}
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/shuakami/logmiao/privacy"
+)
+
+// DefaultRedactKeys 默认脱敏的属性键
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"api_key", "authorization", "cookie", "card_number", "cvv",
+}
+
+// RedactHandler 按属性键脱敏：键名（不区分大小写，- 视同 _）与配置的键相同，或完整路径以 "."+键 结尾时，
+// 值替换为 [REDACTED]。同样作用于分组内的属性、WithAttrs 绑定的属性，以及 slog.Any 传入的 map 中的键，
+// 不依赖调用方记得使用 MaskEmail 等函数
+type RedactHandler struct {
+	handler slog.Handler
+	keys    []string
+	prefix  string // WithGroup 累积的组名
+}
+
+// NewRedactHandler 创建按键脱敏处理器，keys 为空时直接返回原处理器
+func NewRedactHandler(handler slog.Handler, keys []string) slog.Handler {
+	if len(keys) == 0 {
+		return handler
+	}
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		normalized[i] = normalizeRedactKey(key)
+	}
+	return &RedactHandler{handler: handler, keys: normalized}
+}
+
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	changed := false
+	r.Attrs(func(a slog.Attr) bool {
+		a, ok := h.redactAttr(h.prefix, a)
+		changed = changed || ok
+		attrs = append(attrs, a)
+		return true
+	})
+	if !changed {
+		return h.handler.Handle(ctx, r)
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i], _ = h.redactAttr(h.prefix, a)
+	}
+	return &RedactHandler{handler: h.handler.WithAttrs(redacted), keys: h.keys, prefix: h.prefix}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{handler: h.handler.WithGroup(name), keys: h.keys, prefix: joinKey(h.prefix, name)}
+}
+
+// sensitive 检查完整键名是否需要脱敏
+func (h *RedactHandler) sensitive(path string) bool {
+	path = normalizeRedactKey(path)
+	for _, key := range h.keys {
+		if KeyMatches(path, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAttr 脱敏属性，返回是否有改动
+func (h *RedactHandler) redactAttr(prefix string, a slog.Attr) (slog.Attr, bool) {
+	path := joinKey(prefix, a.Key)
+	if a.Key != "" && h.sensitive(path) {
+		return slog.String(a.Key, privacy.Redacted), true
+	}
+
+	v := a.Value
+	if v.Kind() == slog.KindLogValuer {
+		v = v.Resolve()
+	}
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		redacted := make([]slog.Attr, len(group))
+		changed := false
+		for i, ga := range group {
+			var ok bool
+			redacted[i], ok = h.redactAttr(path, ga)
+			changed = changed || ok
+		}
+		if changed {
+			return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}, true
+		}
+	case slog.KindAny:
+		if value, ok := h.redactAny(path, reflect.ValueOf(v.Any())); ok {
+			return slog.Any(a.Key, value), true
+		}
+	}
+	return a, false
+}
+
+// redactAny 脱敏以字符串为键的 map（含嵌套的 map 与切片），有改动时返回脱敏后的副本
+func (h *RedactHandler) redactAny(path string, rv reflect.Value) (any, bool) {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		out := make(map[string]any, rv.Len())
+		changed := false
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			keyPath := joinKey(path, key)
+			switch {
+			case h.sensitive(keyPath):
+				out[key] = privacy.Redacted
+				changed = true
+			default:
+				if value, ok := h.redactAny(keyPath, iter.Value()); ok {
+					out[key] = value
+					changed = true
+				} else if iter.Value().CanInterface() {
+					out[key] = iter.Value().Interface()
+				}
+			}
+		}
+		if changed {
+			return out, true
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false // []byte
+		}
+		out := make([]any, rv.Len())
+		changed := false
+		for i := range out {
+			if value, ok := h.redactAny(path, rv.Index(i)); ok {
+				out[i] = value
+				changed = true
+			} else if rv.Index(i).CanInterface() {
+				out[i] = rv.Index(i).Interface()
+			}
+		}
+		if changed {
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+// normalizeRedactKey 统一键名的大小写与分隔符，使 Authorization、X-Api-Key 与配置的 authorization、x_api_key 匹配
+func normalizeRedactKey(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "-", "_")
+}
+
+// Redactor 在日志记录之外复用按键脱敏与内容扫描规则，用于现场转储、HAR 文件等直接写入磁盘的请求体与响应体
+type Redactor struct {
+	keys     *RedactHandler // 仅使用其键匹配逻辑
+	scrubber *Scrubber
+}
+
+// NewRedactor 创建脱敏器，keys 与 features.privacy.redact_keys 含义相同，scrubber 可为nil
+func NewRedactor(keys []string, scrubber *Scrubber) *Redactor {
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		normalized[i] = normalizeRedactKey(key)
+	}
+	return &Redactor{keys: &RedactHandler{keys: normalized}, scrubber: scrubber}
+}
+
+// jsonField JSON 中的字符串、数字或布尔字段，用于无法完整解析（如被截断）的 JSON
+var jsonField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"|-?[0-9][0-9.eE+-]*|true|false)`)
+
+// Body 脱敏请求体或响应体：JSON 与表单中键名匹配的值替换为 [REDACTED]，
+// 无法完整解析的 JSON（如超出大小上限被截断）按字段逐个匹配，最后对全文做内容扫描
+func (r *Redactor) Body(body []byte, contentType string) string {
+	if r == nil || len(body) == 0 {
+		return string(body)
+	}
+	text := string(body)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		text = r.Query(text)
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(bytes.TrimSpace(body)):
+		text = r.json(body)
+	}
+	if r.scrubber != nil {
+		text = r.scrubber.Scrub(text)
+	}
+	return text
+}
+
+// json 按键脱敏 JSON，没有改动时保留原文的格式
+func (r *Redactor) json(body []byte) string {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		redacted, changed := r.keys.redactAny("", reflect.ValueOf(v))
+		if !changed {
+			return string(body)
+		}
+		if data, err := json.Marshal(redacted); err == nil {
+			return string(data)
+		}
+	}
+	return jsonField.ReplaceAllStringFunc(string(body), func(m string) string {
+		sub := jsonField.FindStringSubmatch(m)
+		if !r.keys.sensitive(sub[1]) {
+			return m
+		}
+		return `"` + sub[1] + `"` + sub[2] + `"` + privacy.Redacted + `"`
+	})
+}
+
+// Query 脱敏 URL 查询串或表单，键名匹配的值替换为 [REDACTED]，无法解析时原样返回
+func (r *Redactor) Query(query string) string {
+	if r == nil || query == "" {
+		return query
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+	changed := false
+	for key, vs := range values {
+		if r.keys.sensitive(key) {
+			for i := range vs {
+				vs[i] = privacy.Redacted
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return query
+	}
+	return values.Encode()
+}
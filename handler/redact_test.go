@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestRedactHandler 测试顶层、分组内、WithAttrs 绑定的属性以及 map 中的敏感键被脱敏
+func TestRedactHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil), []string{"password", "authorization", "card_number", "x_api_key"}))
+
+	logger.With("Password", "bound-secret").WithGroup("req").Info("request",
+		slog.Group("headers", slog.String("Authorization", "Bearer abc"), slog.String("Accept", "*/*")),
+		slog.Any("body", map[string]any{
+			"card_number": "4111111111111111",
+			"items":       []any{map[string]any{"x-api-key": "k1", "sku": "A"}},
+		}),
+		slog.String("user", "alice"),
+	)
+
+	out := buf.String()
+	for _, secret := range []string{"bound-secret", "Bearer abc", "4111111111111111", "k1"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%q should be redacted: %s", secret, out)
+		}
+	}
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := m["req"].(map[string]any)
+	headers, _ := req["headers"].(map[string]any)
+	if m["Password"] != "[REDACTED]" || headers["Authorization"] != "[REDACTED]" || headers["Accept"] != "*/*" || req["user"] != "alice" {
+		t.Errorf("unexpected output: %s", out)
+	}
+	body, _ := req["body"].(map[string]any)
+	items, _ := body["items"].([]any)
+	if item, _ := items[0].(map[string]any); item["sku"] != "A" || item["x-api-key"] != "[REDACTED]" {
+		t.Errorf("nested map values should be redacted by key: %s", out)
+	}
+}
+
+// TestRedactorBody 测试请求体按 JSON、截断的 JSON 与表单脱敏，并做内容扫描
+func TestRedactorBody(t *testing.T) {
+	scrubber, _ := NewScrubber([]string{ScrubEmail}, nil)
+	r := NewRedactor(DefaultRedactKeys, scrubber)
+	cases := []struct {
+		body, contentType string
+		leaked            []string
+		kept              []string
+	}{
+		{`{"user":"bob","password":"hunter2","card":{"cvv":123}}`, "application/json", []string{"hunter2", "123"}, []string{`"user":"bob"`}},
+		{`{"user":"bob", "Token": "abc\"def", "note":"ok`, "application/json", []string{"abc"}, []string{`"user":"bob"`, `"Token": "[REDACTED]"`}},
+		{`user=bob&password=hunter2`, "application/x-www-form-urlencoded", []string{"hunter2"}, []string{"user=bob"}},
+		{`contact alice@example.com`, "text/plain", []string{"alice@example.com"}, []string{"contact"}},
+	}
+	for _, c := range cases {
+		got := r.Body([]byte(c.body), c.contentType)
+		for _, s := range c.leaked {
+			if strings.Contains(got, s) {
+				t.Errorf("Body(%q) = %q, leaks %q", c.body, got, s)
+			}
+		}
+		for _, s := range c.kept {
+			if !strings.Contains(got, s) {
+				t.Errorf("Body(%q) = %q, want %q", c.body, got, s)
+			}
+		}
+	}
+	if got := r.Query("page=2&api_key=k1"); strings.Contains(got, "k1") || !strings.Contains(got, "page=2") {
+		t.Errorf("Query = %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryConfig 远程输出的重试策略，退避时间按 Multiplier 指数增长并加入随机抖动
+type RetryConfig struct {
+	MaxAttempts    int           // 最多尝试次数（含首次），<=1 表示不重试
+	InitialBackoff time.Duration // 首次重试前的等待，默认200ms
+	MaxBackoff     time.Duration // 单次等待上限，默认10s
+	Multiplier     float64       // 退避倍数，默认2
+	Jitter         float64       // 抖动比例（0~1），默认0.2，即等待时间在 ±20% 内随机
+}
+
+// StatusError 远程服务返回的非成功HTTP状态
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Retryable 判断错误是否值得重试：408、429、5xx 及网络错误可重试，其余4xx不重试
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		code := status.StatusCode
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+	}
+	return true
+}
+
+// Retry 按策略执行 fn，遇到不可重试的错误、次数用尽或 ctx 结束时返回最后一次的错误
+func Retry(ctx context.Context, cfg RetryConfig, fn func() error) error {
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Second
+	}
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = 2
+	}
+	if cfg.Jitter < 0 || cfg.Jitter > 1 {
+		cfg.Jitter = 0.2
+	}
+
+	backoff := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= cfg.MaxAttempts || !Retryable(err) {
+			return err
+		}
+
+		wait := backoff
+		if cfg.Jitter > 0 {
+			wait += time.Duration((rand.Float64()*2 - 1) * cfg.Jitter * float64(backoff))
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		backoff = time.Duration(float64(backoff) * cfg.Multiplier)
+		if backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetryClassification 测试可重试状态的重试与不可重试状态的立即返回
+func TestRetryClassification(t *testing.T) {
+	cfg := RetryConfig{MaxAttempts: 4, InitialBackoff: time.Millisecond, Jitter: 0.5}
+
+	calls := 0
+	err := Retry(context.Background(), cfg, func() error {
+		calls++
+		if calls < 3 {
+			return &StatusError{StatusCode: 503}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("503 should be retried until success, calls=%d err=%v", calls, err)
+	}
+
+	calls = 0
+	err = Retry(context.Background(), cfg, func() error {
+		calls++
+		return &StatusError{StatusCode: 400}
+	})
+	var status *StatusError
+	if !errors.As(err, &status) || calls != 1 {
+		t.Errorf("400 should not be retried, calls=%d err=%v", calls, err)
+	}
+
+	calls = 0
+	_ = Retry(context.Background(), cfg, func() error {
+		calls++
+		return &StatusError{StatusCode: 429}
+	})
+	if calls != 4 {
+		t.Errorf("429 should use all attempts, calls=%d", calls)
+	}
+}
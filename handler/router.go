@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// LevelRange 闭区间 [Min, Max] 内的日志级别
+type LevelRange struct {
+	Min slog.Level
+	Max slog.Level
+}
+
+// Contains 检查级别是否在区间内
+func (r LevelRange) Contains(level slog.Level) bool {
+	return level >= r.Min && level <= r.Max
+}
+
+// levelBounds 标准级别的分界，用于确定单个级别名覆盖的区间
+var levelBounds = []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelError, LevelFatal}
+
+// ParseLevelRange 解析级别区间：error+ 表示 Error 及以上；info 表示 Info 到 Warn 之前；
+// debug..warn 表示 Debug 到 Warn（含）所在的区间。debug 包含更低的级别，fatal 包含更高的级别
+func ParseLevelRange(spec string) (LevelRange, error) {
+	spec = strings.TrimSpace(spec)
+	if low, ok := strings.CutSuffix(spec, "+"); ok {
+		r, err := levelBand(low)
+		if err != nil {
+			return LevelRange{}, err
+		}
+		return LevelRange{Min: r.Min, Max: math.MaxInt}, nil
+	}
+	if low, high, ok := strings.Cut(spec, ".."); ok {
+		lr, err := levelBand(low)
+		if err != nil {
+			return LevelRange{}, err
+		}
+		hr, err := levelBand(high)
+		if err != nil {
+			return LevelRange{}, err
+		}
+		if lr.Min > hr.Max {
+			return LevelRange{}, fmt.Errorf("level range %q: %s is above %s", spec, low, high)
+		}
+		return LevelRange{Min: lr.Min, Max: hr.Max}, nil
+	}
+	return levelBand(spec)
+}
+
+// levelBand 返回单个级别名覆盖的区间：从该级别到下一个标准级别之前
+func levelBand(name string) (LevelRange, error) {
+	var level slog.Level
+	if strings.EqualFold(name, "fatal") {
+		level = LevelFatal
+	} else if err := level.UnmarshalText([]byte(name)); err != nil {
+		return LevelRange{}, fmt.Errorf("level range: %w", err)
+	}
+
+	r := LevelRange{Min: level, Max: math.MaxInt}
+	if level <= slog.LevelDebug {
+		r.Min = math.MinInt
+	}
+	for _, bound := range levelBounds {
+		if bound > level {
+			r.Max = bound - 1
+			break
+		}
+	}
+	return r, nil
+}
+
+// Route 级别路由规则：匹配 Levels 的记录写入 Sinks 中的输出端
+type Route struct {
+	Levels LevelRange
+	Sinks  []string
+}
+
+// RouteSinks 按路由规则为输出端附加级别过滤。出现在规则中的输出端只接收匹配其规则的记录，
+// 未出现在任何规则中的输出端仍接收全部记录；规则引用不存在的输出端时返回错误
+func RouteSinks(sinks []Sink, routes []Route) ([]Sink, error) {
+	if len(routes) == 0 {
+		return sinks, nil
+	}
+
+	ranges := make(map[string][]LevelRange)
+	for _, route := range routes {
+		for _, name := range route.Sinks {
+			ranges[name] = append(ranges[name], route.Levels)
+		}
+	}
+
+	routed := make([]Sink, len(sinks))
+	for i, sink := range sinks {
+		r, ok := ranges[sink.Name()]
+		if !ok {
+			routed[i] = sink
+			continue
+		}
+		delete(ranges, sink.Name())
+		routed[i] = &routedSink{Sink: sink, ranges: r}
+	}
+	for name := range ranges {
+		return nil, fmt.Errorf("route references unknown sink %q", name)
+	}
+	return routed, nil
+}
+
+// routedSink 只接收匹配级别区间的记录的输出端
+type routedSink struct {
+	Sink
+	ranges []LevelRange
+}
+
+func (s *routedSink) Write(ctx context.Context, r slog.Record) error {
+	if !matchLevel(s.ranges, r.Level) {
+		return nil
+	}
+	return s.Sink.Write(ctx, r)
+}
+
+func (s *routedSink) CloseContext(ctx context.Context) error {
+	return closeSink(ctx, s.Sink)
+}
+
+func (s *routedSink) Probe(ctx context.Context) error {
+	if p, ok := s.Sink.(Prober); ok {
+		return p.Probe(ctx)
+	}
+	return nil
+}
+
+// Handler 返回带级别路由的底层处理器，Supervisor 据此分发记录
+func (s *routedSink) Handler() slog.Handler {
+	var h slog.Handler
+	if hs, ok := s.Sink.(interface{ Handler() slog.Handler }); ok {
+		h = hs.Handler()
+	} else {
+		h = &sinkWriteHandler{sink: s.Sink}
+	}
+	return &levelRouteHandler{handler: h, ranges: s.ranges}
+}
+
+// matchLevel 检查级别是否落在任一区间内
+func matchLevel(ranges []LevelRange, level slog.Level) bool {
+	for _, r := range ranges {
+		if r.Contains(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// levelRouteHandler 只处理匹配级别区间的记录
+type levelRouteHandler struct {
+	handler slog.Handler
+	ranges  []LevelRange
+}
+
+func (h *levelRouteHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return matchLevel(h.ranges, level) && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelRouteHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !matchLevel(h.ranges, r.Level) {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *levelRouteHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelRouteHandler{handler: h.handler.WithAttrs(attrs), ranges: h.ranges}
+}
+
+func (h *levelRouteHandler) WithGroup(name string) slog.Handler {
+	return &levelRouteHandler{handler: h.handler.WithGroup(name), ranges: h.ranges}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestParseLevelRange 测试级别区间的各种写法
+func TestParseLevelRange(t *testing.T) {
+	cases := []struct {
+		spec string
+		want LevelRange
+	}{
+		{"error+", LevelRange{slog.LevelError, math.MaxInt}},
+		{"info", LevelRange{slog.LevelInfo, slog.LevelWarn - 1}},
+		{"debug", LevelRange{math.MinInt, slog.LevelInfo - 1}},
+		{"fatal", LevelRange{LevelFatal, math.MaxInt}},
+		{"info..warn", LevelRange{slog.LevelInfo, slog.LevelError - 1}},
+		{"WARN", LevelRange{slog.LevelWarn, slog.LevelError - 1}},
+	}
+	for _, c := range cases {
+		got, err := ParseLevelRange(c.spec)
+		if err != nil || got != c.want {
+			t.Errorf("ParseLevelRange(%q) = %+v, %v; want %+v", c.spec, got, err, c.want)
+		}
+	}
+	for _, bad := range []string{"verbose", "error..info", ""} {
+		if _, err := ParseLevelRange(bad); err == nil {
+			t.Errorf("ParseLevelRange(%q) should fail", bad)
+		}
+	}
+}
+
+// TestRouteSinks 测试按级别路由：规则中的输出端只接收匹配的记录，其余输出端接收全部
+func TestRouteSinks(t *testing.T) {
+	var console, file, push bytes.Buffer
+	debug := &slog.HandlerOptions{Level: slog.LevelDebug}
+	sinks := []Sink{
+		NewHandlerSink("console", slog.NewTextHandler(&console, debug), SinkOptions{}),
+		NewHandlerSink("file", slog.NewTextHandler(&file, debug), SinkOptions{}),
+		NewHandlerSink("push", slog.NewTextHandler(&push, debug), SinkOptions{}),
+	}
+	errorUp, _ := ParseLevelRange("error+")
+	info, _ := ParseLevelRange("info")
+	routed, err := RouteSinks(sinks, []Route{
+		{Levels: errorUp, Sinks: []string{"file", "push"}},
+		{Levels: info, Sinks: []string{"file"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(NewSupervisor(routed...).Handler())
+	logger.Debug("debug-msg")
+	logger.Info("info-msg")
+	logger.Warn("warn-msg")
+	logger.Error("error-msg")
+
+	expect := func(name string, buf *bytes.Buffer, want ...string) {
+		t.Helper()
+		var got []string
+		for _, msg := range []string{"debug-msg", "info-msg", "warn-msg", "error-msg"} {
+			if strings.Contains(buf.String(), msg) {
+				got = append(got, msg)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s received %v, want %v", name, got, want)
+		}
+	}
+	expect("console", &console, "debug-msg", "info-msg", "warn-msg", "error-msg")
+	expect("file", &file, "info-msg", "error-msg")
+	expect("push", &push, "error-msg")
+
+	if _, err := RouteSinks(sinks, []Route{{Levels: info, Sinks: []string{"webhook"}}}); err == nil {
+		t.Error("routes to unknown sinks should be rejected")
+	}
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SamplingHandler 按比例保留低于 Warn 的记录，Warn 及以上级别始终保留
+type SamplingHandler struct {
+	handler slog.Handler
+	rate    float64
+}
+
+// NewSamplingHandler 创建采样处理器，rate 为保留比例，不在 (0,1) 区间时不采样
+func NewSamplingHandler(handler slog.Handler, rate float64) slog.Handler {
+	if rate <= 0 || rate >= 1 {
+		return handler
+	}
+	return &SamplingHandler{handler: handler, rate: rate}
+}
+
+// Enabled 检查是否启用指定级别
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle 按比例丢弃低级别记录，保留的记录附加 sampled=true 和 sample_rate（每条代表的记录数）
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn {
+		return h.handler.Handle(ctx, r)
+	}
+	if rand.Float64() >= h.rate {
+		return nil
+	}
+	return h.handler.Handle(ctx, MarkSampled(r, 1/h.rate))
+}
+
+// MarkSampled 为采样保留的记录附加 sampled=true 和 sample_rate，
+// 记录已带有 sample_rate（如访问日志采样）时两者相乘，供下游按比例还原数量
+func MarkSampled(r slog.Record, rate float64) slog.Record {
+	marked := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "sample_rate":
+			if prev := sampleRateOf(a.Value); prev > 0 {
+				rate *= prev
+			}
+		case "sampled":
+		default:
+			marked.AddAttrs(a)
+		}
+		return true
+	})
+	marked.AddAttrs(slog.Bool("sampled", true), slog.Float64("sample_rate", rate))
+	return marked
+}
+
+// sampleRateOf 读取数值类型的 sample_rate，无法识别时返回 0
+func sampleRateOf(v slog.Value) float64 {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return float64(v.Int64())
+	case slog.KindUint64:
+		return float64(v.Uint64())
+	case slog.KindFloat64:
+		return v.Float64()
+	}
+	return 0
+}
+
+// WithAttrs 返回带有额外属性的新处理器
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{handler: h.handler.WithAttrs(attrs), rate: h.rate}
+}
+
+// WithGroup 返回带有组名的新处理器
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{handler: h.handler.WithGroup(name), rate: h.rate}
+}
+
+// KeyedSamplingConfig 按键限速采样配置
+type KeyedSamplingConfig struct {
+	Keys       []string      // 组成采样键的属性完整路径，如 route、tenant；记录没有这些属性或为空时按消息
+	Tick       time.Duration // 计数周期，默认1秒
+	First      int           // 每个键每个周期内原样保留的条数，默认100
+	Thereafter int           // 超出 First 后每 N 条保留 1 条，默认100
+}
+
+// KeyedSamplingHandler 按键限速采样：每个键每个周期内前 First 条原样保留，之后每 Thereafter 条保留 1 条
+// 并附加采样标记。键由 Keys 指定的属性（含 WithAttrs 绑定的属性）组成，使一个嘈杂的租户或接口被降频时
+// 其他键仍完整保留。Warn 及以上级别和定向调试的记录始终保留
+type KeyedSamplingHandler struct {
+	handler slog.Handler
+	state   *keyedSamplingState
+	group   string            // WithGroup 累积的组名
+	bound   map[string]string // WithAttrs 绑定的采样键属性
+}
+
+// keyedSamplingState 派生处理器共享的计数
+type keyedSamplingState struct {
+	cfg      KeyedSamplingConfig
+	keys     map[string]bool
+	mu       sync.Mutex
+	counters map[string]*sampleCounter
+	sweep    time.Time // 下次清理过期计数的时间
+}
+
+// sampleCounter 一个键在当前周期内的计数
+type sampleCounter struct {
+	start time.Time
+	n     int
+}
+
+// NewKeyedSamplingHandler 创建按键限速采样处理器
+func NewKeyedSamplingHandler(handler slog.Handler, cfg KeyedSamplingConfig) slog.Handler {
+	if cfg.Tick <= 0 {
+		cfg.Tick = time.Second
+	}
+	if cfg.First <= 0 {
+		cfg.First = 100
+	}
+	if cfg.Thereafter <= 0 {
+		cfg.Thereafter = 100
+	}
+	keys := make(map[string]bool, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys[key] = true
+	}
+	return &KeyedSamplingHandler{
+		handler: handler,
+		state:   &keyedSamplingState{cfg: cfg, keys: keys, counters: make(map[string]*sampleCounter)},
+	}
+}
+
+func (h *KeyedSamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *KeyedSamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelWarn || IsDebugTargeted(ctx) {
+		return h.handler.Handle(ctx, r)
+	}
+	n := h.state.count(h.sampleKey(r), r.Time)
+	cfg := h.state.cfg
+	if n <= cfg.First {
+		return h.handler.Handle(ctx, r)
+	}
+	if (n-cfg.First)%cfg.Thereafter != 0 {
+		return nil
+	}
+	return h.handler.Handle(ctx, MarkSampled(r, float64(cfg.Thereafter)))
+}
+
+// sampleKey 由 Keys 指定的属性值组成采样键，没有任何属性时按消息
+func (h *KeyedSamplingHandler) sampleKey(r slog.Record) string {
+	if len(h.state.keys) == 0 {
+		return r.Message
+	}
+	values := make(map[string]string, len(h.state.keys))
+	for k, v := range h.bound {
+		values[k] = v
+	}
+	WalkRecordAttrs(h.group, r, func(key string, v slog.Value) bool {
+		if h.state.keys[key] {
+			values[key] = v.String()
+		}
+		return true
+	})
+
+	var b strings.Builder
+	found := false
+	for _, key := range h.state.cfg.Keys {
+		if v := values[key]; v != "" {
+			found = true
+			b.WriteString(v)
+		}
+		b.WriteByte(0)
+	}
+	if !found {
+		return r.Message
+	}
+	return b.String()
+}
+
+// count 返回键在当前周期内的序号（从1开始）
+func (s *keyedSamplingState) count(key string, now time.Time) int {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 定期清理过期的键，避免高基数的属性使计数无限增长
+	if now.After(s.sweep) {
+		for k, c := range s.counters {
+			if now.Sub(c.start) >= s.cfg.Tick {
+				delete(s.counters, k)
+			}
+		}
+		s.sweep = now.Add(s.cfg.Tick)
+	}
+
+	c, ok := s.counters[key]
+	if !ok || now.Sub(c.start) >= s.cfg.Tick {
+		c = &sampleCounter{start: now}
+		s.counters[key] = c
+	}
+	c.n++
+	return c.n
+}
+
+func (h *KeyedSamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithAttrs(attrs)
+	copied := false
+	WalkAttrs(h.group, attrs, func(key string, v slog.Value) bool {
+		if h.state.keys[key] {
+			if !copied {
+				clone.bound = make(map[string]string, len(h.bound)+1) // 不修改父处理器的映射
+				maps.Copy(clone.bound, h.bound)
+				copied = true
+			}
+			clone.bound[key] = v.String()
+		}
+		return true
+	})
+	return &clone
+}
+
+func (h *KeyedSamplingHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.handler = h.handler.WithGroup(name)
+	clone.group = joinKey(h.group, name)
+	return &clone
+}
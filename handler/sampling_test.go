@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSamplingHandler 测试按比例丢弃 Info 记录而保留 Warn 及以上
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSamplingHandler(slog.NewTextHandler(&buf, nil), 0.01))
+	for i := 0; i < 1000; i++ {
+		logger.Info("sampled")
+	}
+	logger.Warn("kept")
+
+	if got := strings.Count(buf.String(), "sampled"); got > 100 {
+		t.Errorf("expected roughly 1%% of info records, got %d", got)
+	}
+	if !strings.Contains(buf.String(), "kept") {
+		t.Error("warn records should never be sampled out")
+	}
+}
+
+// TestMarkSampled 测试保留的记录附加采样标记，已有 sample_rate 时相乘
+func TestMarkSampled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSamplingHandler(slog.NewJSONHandler(&buf, nil), 0.5))
+	for buf.Len() == 0 {
+		logger.Info("HTTP Request", slog.Int("status", 200), slog.Int("sample_rate", 10))
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"sampled":true,"sample_rate":20`) || strings.Count(out, "sample_rate") != 1 {
+		t.Errorf("kept record should carry the combined sample rate: %s", out)
+	}
+
+	buf.Reset()
+	logger.Error("failed")
+	if strings.Contains(buf.String(), "sampled") {
+		t.Errorf("records that bypass sampling should not be marked: %s", buf.String())
+	}
+}
+
+// TestKeyedSamplingHandler 测试按属性分别限速：嘈杂的租户被降频，其他租户与 Warn 记录完整保留
+func TestKeyedSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewKeyedSamplingHandler(slog.NewJSONHandler(&buf, nil), KeyedSamplingConfig{
+		Keys: []string{"tenant"}, Tick: time.Hour, First: 20, Thereafter: 10,
+	})
+	logger := slog.New(h)
+	noisy := logger.With("tenant", "noisy") // WithAttrs 绑定的键同样生效
+	for i := 0; i < 120; i++ {
+		noisy.Info("request")
+	}
+	for i := 0; i < 20; i++ {
+		logger.Info("request", "tenant", "quiet")
+	}
+	noisy.Warn("request")
+
+	out := buf.String()
+	// noisy：前20条 + 之后100条中的10条 + Warn
+	if got := strings.Count(out, `"tenant":"noisy"`); got != 31 {
+		t.Errorf("noisy tenant kept %d records, want 31", got)
+	}
+	if got := strings.Count(out, `"tenant":"quiet"`); got != 20 {
+		t.Errorf("quiet tenant should keep full fidelity, kept %d", got)
+	}
+	if got := strings.Count(out, `"sample_rate":10`); got != 10 {
+		t.Errorf("sampled records should be marked, got %d", got)
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/shuakami/logmiao/privacy"
+)
+
+// 内置的扫描规则名称
+const (
+	ScrubCreditCard = "credit_card" // 通过 Luhn 校验的 13~19 位卡号，允许空格或 - 分隔
+	ScrubJWT        = "jwt"         // eyJ 开头的三段式 JWT
+	ScrubAWSKey     = "aws_key"     // AKIA/ASIA 开头的 AWS Access Key ID
+	ScrubEmail      = "email"       // 邮箱地址
+	ScrubCNID       = "cn_id"       // 18 位中国居民身份证号
+)
+
+// scrubPattern 扫描规则，valid 不为nil时只替换通过校验的匹配
+type scrubPattern struct {
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+var builtinScrubPatterns = map[string]scrubPattern{
+	ScrubCreditCard: {re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhnValid},
+	ScrubJWT:        {re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	ScrubAWSKey:     {re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	ScrubEmail:      {re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	ScrubCNID:       {re: regexp.MustCompile(`\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`)},
+}
+
+// Scrubber 按正则扫描文本，将匹配的敏感内容替换为 [REDACTED]
+type Scrubber struct {
+	patterns []scrubPattern
+}
+
+// NewScrubber 创建扫描器，names 为内置规则名称，patterns 为自定义正则；两者都为空时返回nil
+func NewScrubber(names, patterns []string) (*Scrubber, error) {
+	if len(names) == 0 && len(patterns) == 0 {
+		return nil, nil
+	}
+	s := &Scrubber{}
+	for _, name := range names {
+		p, ok := builtinScrubPatterns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown scrub pattern %q, want credit_card, jwt, aws_key, email or cn_id", name)
+		}
+		s.patterns = append(s.patterns, p)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("scrub pattern %q: %w", pattern, err)
+		}
+		s.patterns = append(s.patterns, scrubPattern{re: re})
+	}
+	return s, nil
+}
+
+// Scrub 替换文本中的敏感内容
+func (s *Scrubber) Scrub(text string) string {
+	for _, p := range s.patterns {
+		if !p.re.MatchString(text) {
+			continue
+		}
+		if p.valid == nil {
+			text = p.re.ReplaceAllLiteralString(text, privacy.Redacted)
+			continue
+		}
+		text = p.re.ReplaceAllStringFunc(text, func(m string) string {
+			if p.valid(m) {
+				return privacy.Redacted
+			}
+			return m
+		})
+	}
+	return text
+}
+
+// luhnValid 对去掉分隔符的数字做 Luhn 校验，减少订单号等长数字的误判
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// ScrubHandler 扫描消息与字符串属性值（含分组内与 WithAttrs 绑定的属性），替换匹配的敏感内容。
+// 与按键脱敏互补：用于敏感内容出现在任意文本中的情况，如拼接进消息的邮箱或令牌
+type ScrubHandler struct {
+	handler  slog.Handler
+	scrubber *Scrubber
+}
+
+// NewScrubHandler 创建扫描脱敏处理器，scrubber 为nil时直接返回原处理器
+func NewScrubHandler(handler slog.Handler, scrubber *Scrubber) slog.Handler {
+	if scrubber == nil {
+		return handler
+	}
+	return &ScrubHandler{handler: handler, scrubber: scrubber}
+}
+
+func (h *ScrubHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *ScrubHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, h.scrubber.Scrub(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.scrubAttr(a))
+		return true
+	})
+	return h.handler.Handle(ctx, nr)
+}
+
+// scrubAttr 扫描字符串值，分组递归处理
+func (h *ScrubHandler) scrubAttr(a slog.Attr) slog.Attr {
+	v := a.Value
+	if v.Kind() == slog.KindLogValuer {
+		v = v.Resolve()
+	}
+	switch v.Kind() {
+	case slog.KindString:
+		if s := h.scrubber.Scrub(v.String()); s != v.String() {
+			return slog.String(a.Key, s)
+		}
+	case slog.KindGroup:
+		group := v.Group()
+		scrubbed := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			scrubbed[i] = h.scrubAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(scrubbed...)}
+	}
+	return a
+}
+
+func (h *ScrubHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scrubbed := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		scrubbed[i] = h.scrubAttr(a)
+	}
+	return &ScrubHandler{handler: h.handler.WithAttrs(scrubbed), scrubber: h.scrubber}
+}
+
+func (h *ScrubHandler) WithGroup(name string) slog.Handler {
+	return &ScrubHandler{handler: h.handler.WithGroup(name), scrubber: h.scrubber}
+}
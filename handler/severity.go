@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// LevelFatal 自定义的致命级别，可通过 SeverityMapping 映射为 FATAL/CRITICAL 等
+const LevelFatal = slog.Level(12)
+
+// SeverityMapping JSON输出中级别字段的名称和取值映射，
+// 供按固定 severity 字符串分类的采集器（如 Stackdriver/Cloud Logging）使用
+type SeverityMapping struct {
+	Key    string                // 级别字段名，为空时使用 "level"
+	Levels map[slog.Level]string // 级别到字符串的映射，未配置的级别使用不高于它的最近一个映射
+}
+
+// StackdriverSeverity Google Cloud Logging 的 severity 映射
+func StackdriverSeverity() SeverityMapping {
+	return SeverityMapping{
+		Key: "severity",
+		Levels: map[slog.Level]string{
+			slog.LevelDebug: "DEBUG",
+			slog.LevelInfo:  "INFO",
+			slog.LevelWarn:  "WARNING",
+			slog.LevelError: "ERROR",
+			LevelFatal:      "CRITICAL",
+		},
+	}
+}
+
+// ParseSeverityLevels 解析配置中的映射，键为 debug、warn、error+4 等级别名称
+func ParseSeverityLevels(levels map[string]string) (map[slog.Level]string, error) {
+	result := make(map[slog.Level]string, len(levels))
+	for name, value := range levels {
+		var level slog.Level
+		if strings.EqualFold(name, "fatal") {
+			level = LevelFatal
+		} else if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("severity level %q: %w", name, err)
+		}
+		result[level] = value
+	}
+	return result, nil
+}
+
+// String 返回级别映射后的字符串
+func (m SeverityMapping) String(level slog.Level) string {
+	if s, ok := m.Levels[level]; ok {
+		return s
+	}
+	levels := make([]slog.Level, 0, len(m.Levels))
+	for l := range m.Levels {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] > levels[j] })
+	for _, l := range levels {
+		if l <= level {
+			return m.Levels[l]
+		}
+	}
+	return level.String()
+}
+
+// SeverityOptions 返回应用级别映射的处理器选项副本，mapping 为空时原样返回
+func SeverityOptions(opts *slog.HandlerOptions, mapping SeverityMapping) *slog.HandlerOptions {
+	out := &slog.HandlerOptions{}
+	if opts != nil {
+		*out = *opts
+	}
+	if mapping.Key == "" && len(mapping.Levels) == 0 {
+		return out
+	}
+
+	next := out.ReplaceAttr
+	out.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.LevelKey {
+			if level, ok := a.Value.Any().(slog.Level); ok {
+				if mapping.Key != "" {
+					a.Key = mapping.Key
+				}
+				if len(mapping.Levels) > 0 {
+					a.Value = slog.StringValue(mapping.String(level))
+				}
+			}
+		}
+		if next != nil {
+			return next(groups, a)
+		}
+		return a
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestSeverityOptions 测试 Stackdriver 预设与自定义级别的映射
+func TestSeverityOptions(t *testing.T) {
+	mapping := StackdriverSeverity()
+	custom, err := ParseSeverityLevels(map[string]string{"fatal": "FATAL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for level, value := range custom {
+		mapping.Levels[level] = value
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, SeverityOptions(nil, mapping)))
+
+	cases := []struct {
+		level slog.Level
+		want  string
+	}{
+		{slog.LevelWarn, "WARNING"},
+		{slog.LevelWarn + 2, "WARNING"},
+		{LevelFatal, "FATAL"},
+	}
+	for _, c := range cases {
+		buf.Reset()
+		logger.Log(context.Background(), c.level, "msg")
+		var out map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		if out["severity"] != c.want {
+			t.Errorf("level %v: severity = %v, want %s", c.level, out["severity"], c.want)
+		}
+		if _, ok := out["level"]; ok {
+			t.Errorf("level key should be renamed: %s", buf.String())
+		}
+	}
+
+	if _, err := ParseSeverityLevels(map[string]string{"loud": "X"}); err == nil {
+		t.Error("expected error for unknown level name")
+	}
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// backupTimeFormat lumberjack 备份文件名中的时间格式
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// SharedFile 多个进程（如 prefork 的工作进程）共享同一日志文件时使用的写入器。
+// 每次写入前取得 <path>.lock 的建议锁，发现文件已被其他进程写入或轮转时重新打开，
+// 使 lumberjack 按文件的实际大小判断轮转，轮转只会由持有锁的一个进程执行。
+// 备份的压缩与清理也在锁内进行，而不是交给 lumberjack 在各进程中并发执行
+type SharedFile struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+	lock   *os.File
+
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+
+	last os.FileInfo // 本进程上次写入后的文件状态
+}
+
+// NewSharedFile 创建多进程共享的文件写入器，接管 l 的备份压缩与清理设置
+func NewSharedFile(l *lumberjack.Logger) (*SharedFile, error) {
+	lock, err := os.OpenFile(l.Filename+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	f := &SharedFile{
+		logger:     l,
+		lock:       lock,
+		maxBackups: l.MaxBackups,
+		maxAge:     time.Duration(l.MaxAge) * 24 * time.Hour,
+		compress:   l.Compress,
+	}
+	l.MaxBackups, l.MaxAge, l.Compress = 0, 0, false
+	return f, nil
+}
+
+func (f *SharedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := lockFile(f.lock); err != nil {
+		return 0, err
+	}
+	defer unlockFile(f.lock)
+
+	if f.last != nil {
+		if info, err := os.Stat(f.logger.Filename); err != nil || !os.SameFile(info, f.last) || info.Size() != f.last.Size() {
+			_ = f.logger.Close() // 下次写入时重新打开并读取实际大小
+		}
+	}
+	n, err := f.logger.Write(p)
+	info, statErr := os.Stat(f.logger.Filename)
+	if statErr == nil && f.last != nil && !os.SameFile(info, f.last) {
+		f.cleanup()
+	}
+	f.last = info
+	return n, err
+}
+
+// Rotate 在锁内轮转日志文件
+func (f *SharedFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := lockFile(f.lock); err != nil {
+		return err
+	}
+	defer unlockFile(f.lock)
+	err := f.logger.Rotate()
+	f.last, _ = os.Stat(f.logger.Filename)
+	f.cleanup()
+	return err
+}
+
+// Close 关闭日志文件与锁文件
+func (f *SharedFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := f.logger.Close()
+	if cerr := f.lock.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// sharedBackup 轮转产生的备份文件
+type sharedBackup struct {
+	path string
+	time time.Time
+}
+
+// cleanup 按轮转设置压缩备份，删除超出数量或保存期限的备份，须在持有锁时调用。错误被忽略，下次轮转时重试
+func (f *SharedFile) cleanup() {
+	if f.maxBackups == 0 && f.maxAge == 0 && !f.compress {
+		return
+	}
+	dir := filepath.Dir(f.logger.Filename)
+	base := filepath.Base(f.logger.Filename)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var backups []sharedBackup
+	for _, e := range entries {
+		name := e.Name()
+		ts, ok := strings.CutPrefix(name, prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimSuffix(ts, ".gz"), ext)
+		t, err := time.Parse(backupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, sharedBackup{path: filepath.Join(dir, name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+
+	cutoff := time.Now().Add(-f.maxAge)
+	for i, b := range backups {
+		if (f.maxBackups > 0 && i >= f.maxBackups) || (f.maxAge > 0 && b.time.Before(cutoff)) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		if f.compress && !strings.HasSuffix(b.path, ".gz") {
+			_ = gzipFile(b.path)
+		}
+	}
+}
+
+// gzipFile 将文件压缩为同名 .gz 文件并删除原文件
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
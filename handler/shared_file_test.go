@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// TestSharedFile 两个写入器（模拟两个进程）并发写入同一文件并多次轮转，所有行完整且不丢失，备份被压缩
+func TestSharedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	const writers, lines = 2, 1500
+	line := strings.Repeat("x", 1000)
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		f, err := NewSharedFile(&lumberjack.Logger{Filename: path, MaxSize: 1, MaxBackups: 10, Compress: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer f.Close()
+			for i := 0; i < lines; i++ {
+				if _, err := fmt.Fprintf(f, "%d-%d %s\n", w, i, line); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "app*"))
+	seen := make(map[string]bool)
+	gzipped := 0
+	for _, name := range files {
+		if strings.HasSuffix(name, ".lock") {
+			continue
+		}
+		data, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = data
+		if strings.HasSuffix(name, ".gz") {
+			gzipped++
+			if r, err = gzip.NewReader(data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			id, rest, _ := strings.Cut(scanner.Text(), " ")
+			if rest != line || seen[id] {
+				t.Fatalf("%s: corrupted or duplicated line %q", name, id)
+			}
+			seen[id] = true
+		}
+		data.Close()
+	}
+	if len(seen) != writers*lines {
+		t.Errorf("expected %d lines, got %d", writers*lines, len(seen))
+	}
+	if gzipped < 2 {
+		t.Errorf("expected compressed backups after rotation, got files %v", files)
+	}
+}
@@ -0,0 +1,433 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Sink 日志输出端，控制台、文件、远程推送等均实现该接口，由 Supervisor 统一管理生命周期
+type Sink interface {
+	Name() string
+	Start(ctx context.Context) error
+	Write(ctx context.Context, r slog.Record) error
+	Flush() error
+	Close() error
+	Healthy() error // 返回nil表示健康
+}
+
+// ContextCloser 可由 ctx 限定关闭耗时的输出端：ctx 结束时放弃剩余的写出并尽快返回。
+// 网络输出端应实现该接口，使无响应的接收端不会阻塞应用关闭
+type ContextCloser interface {
+	CloseContext(ctx context.Context) error
+}
+
+// ContextFlusher 可由 ctx 限定刷新耗时的输出端，如等待已组批的记录发送完成的远程推送
+type ContextFlusher interface {
+	FlushContext(ctx context.Context) error
+}
+
+// Prober 可在启动时主动验证的输出端，如打开文件确认可写、向远程端点发送空请求确认可达且认证通过。
+// 与 Healthy 不同，Probe 可能较慢并产生网络请求，只在预热和就绪检查时调用
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// SinkOptions HandlerSink 的可选生命周期钩子
+type SinkOptions struct {
+	Start   func(ctx context.Context) error
+	Flush   func() error
+	Closer  io.Closer
+	Healthy func() error
+	Probe   func(ctx context.Context) error
+}
+
+// HandlerSink 将 slog.Handler 包装为 Sink
+type HandlerSink struct {
+	name    string
+	handler slog.Handler
+	opts    SinkOptions
+}
+
+// NewHandlerSink 创建基于 slog.Handler 的输出端
+func NewHandlerSink(name string, h slog.Handler, opts SinkOptions) *HandlerSink {
+	return &HandlerSink{name: name, handler: h, opts: opts}
+}
+
+// Handler 返回底层处理器，Supervisor 通过它保留 WithAttrs/WithGroup 的语义
+func (s *HandlerSink) Handler() slog.Handler {
+	return s.handler
+}
+
+func (s *HandlerSink) Name() string {
+	return s.name
+}
+
+func (s *HandlerSink) Start(ctx context.Context) error {
+	if s.opts.Start != nil {
+		return s.opts.Start(ctx)
+	}
+	return nil
+}
+
+func (s *HandlerSink) Write(ctx context.Context, r slog.Record) error {
+	// 定向调试的记录已由上层放行，不再受输出端级别限制
+	if !IsDebugTargeted(ctx) && !s.handler.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return s.handler.Handle(ctx, r)
+}
+
+func (s *HandlerSink) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext 执行 Flush 钩子，Closer 实现 ContextFlusher 时一并刷新
+func (s *HandlerSink) FlushContext(ctx context.Context) error {
+	if s.opts.Flush != nil {
+		if err := s.opts.Flush(); err != nil {
+			return err
+		}
+	}
+	if cf, ok := s.opts.Closer.(ContextFlusher); ok {
+		return cf.FlushContext(ctx)
+	}
+	return nil
+}
+
+func (s *HandlerSink) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext 关闭输出端，Closer 实现 ContextCloser 时将 ctx 传给它
+func (s *HandlerSink) CloseContext(ctx context.Context) error {
+	if cc, ok := s.opts.Closer.(ContextCloser); ok {
+		return cc.CloseContext(ctx)
+	}
+	if s.opts.Closer != nil {
+		return s.opts.Closer.Close()
+	}
+	return nil
+}
+
+func (s *HandlerSink) Healthy() error {
+	if s.opts.Healthy != nil {
+		return s.opts.Healthy()
+	}
+	return nil
+}
+
+func (s *HandlerSink) Probe(ctx context.Context) error {
+	if s.opts.Probe != nil {
+		return s.opts.Probe(ctx)
+	}
+	return nil
+}
+
+// SinkHealth 输出端健康状态
+type SinkHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Supervisor 按顺序启动输出端、定期检查健康状态，并在关闭时逆序关闭
+type Supervisor struct {
+	sinks []Sink
+
+	mu      sync.Mutex
+	started int
+	last    map[string]string // 上次检查的错误信息，用于发现状态变化
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	closed  bool
+}
+
+// NewSupervisor 创建输出端管理器，sinks 的顺序即启动顺序
+func NewSupervisor(sinks ...Sink) *Supervisor {
+	return &Supervisor{
+		sinks: sinks,
+		last:  make(map[string]string),
+		stop:  make(chan struct{}),
+	}
+}
+
+// Sinks 返回管理的输出端
+func (s *Supervisor) Sinks() []Sink {
+	return s.sinks
+}
+
+// Start 按顺序启动所有输出端，任一失败时关闭已启动的输出端并返回错误
+func (s *Supervisor) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sink := range s.sinks {
+		if err := sink.Start(ctx); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				_ = s.sinks[j].Close()
+			}
+			return fmt.Errorf("start sink %s: %w", sink.Name(), err)
+		}
+		s.started = i + 1
+	}
+	return nil
+}
+
+// Handler 返回分发到所有输出端的处理器
+func (s *Supervisor) Handler() slog.Handler {
+	handlers := make([]slog.Handler, 0, len(s.sinks))
+	for _, sink := range s.sinks {
+		if hs, ok := sink.(interface{ Handler() slog.Handler }); ok {
+			handlers = append(handlers, hs.Handler())
+		} else {
+			handlers = append(handlers, &sinkWriteHandler{sink: sink})
+		}
+	}
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+// Flush 刷新所有输出端
+func (s *Supervisor) Flush() error {
+	return s.FlushContext(context.Background())
+}
+
+// FlushContext 刷新所有输出端，ctx 结束时不再等待尚未完成的刷新
+func (s *Supervisor) FlushContext(ctx context.Context) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		if err := flushSink(ctx, sink); err != nil {
+			errs = append(errs, fmt.Errorf("flush sink %s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flushSink 刷新输出端，支持时由 ctx 限定耗时
+func flushSink(ctx context.Context, sink Sink) error {
+	if cf, ok := sink.(ContextFlusher); ok {
+		return cf.FlushContext(ctx)
+	}
+	return sink.Flush()
+}
+
+// Health 返回所有输出端当前的健康状态
+func (s *Supervisor) Health() []SinkHealth {
+	result := make([]SinkHealth, 0, len(s.sinks))
+	for _, sink := range s.sinks {
+		h := SinkHealth{Name: sink.Name(), Healthy: true}
+		if err := sink.Healthy(); err != nil {
+			h.Healthy = false
+			h.Error = err.Error()
+		}
+		result = append(result, h)
+	}
+	return result
+}
+
+// Watch 每隔 interval 检查一次健康状态，状态变化时调用 onChange，Close 时停止
+func (s *Supervisor) Watch(interval time.Duration, onChange func(SinkHealth)) {
+	if interval <= 0 || onChange == nil {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+			for _, h := range s.Health() {
+				s.mu.Lock()
+				changed := s.last[h.Name] != h.Error
+				s.last[h.Name] = h.Error
+				s.mu.Unlock()
+				if changed {
+					onChange(h)
+				}
+			}
+		}
+	}()
+}
+
+// Close 停止健康检查，先刷新再逆序关闭已启动的输出端
+func (s *Supervisor) Close() error {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext 与 Close 相同，实现 ContextCloser 的输出端在 ctx 结束时放弃剩余的写出
+func (s *Supervisor) CloseContext(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	started := s.started
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	errs := []error{s.FlushContext(ctx)}
+	for i := started - 1; i >= 0; i-- {
+		if err := closeSink(ctx, s.sinks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("close sink %s: %w", s.sinks[i].Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closeSink 关闭输出端，支持时由 ctx 限定耗时
+func closeSink(ctx context.Context, sink Sink) error {
+	if cc, ok := sink.(ContextCloser); ok {
+		return cc.CloseContext(ctx)
+	}
+	return sink.Close()
+}
+
+// Probe 并发验证所有输出端：先检查 Healthy，通过后对实现 Prober 的输出端调用 Probe，ctx 限定总耗时
+func (s *Supervisor) Probe(ctx context.Context) []SinkHealth {
+	result := make([]SinkHealth, len(s.sinks))
+	var wg sync.WaitGroup
+	for i, sink := range s.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result[i] = SinkHealth{Name: sink.Name(), Healthy: true}
+			if err := probeSink(ctx, sink); err != nil {
+				result[i].Healthy = false
+				result[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+func probeSink(ctx context.Context, sink Sink) error {
+	if err := sink.Healthy(); err != nil {
+		return err
+	}
+	if p, ok := sink.(Prober); ok {
+		return p.Probe(ctx)
+	}
+	return nil
+}
+
+// fanoutHandler 将记录分发给多个输出端处理器。
+// 记录只在分发前 Clone 一次：Clone 会截断属性切片的容量，任一处理器追加属性时都会重新分配，
+// 各处理器因此可以共享同一份记录，保留记录的处理器（如异步队列）也不受其他处理器影响
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	targeted := IsDebugTargeted(ctx) // 定向调试的记录已由上层放行，不再受输出端级别限制，级别路由仍然生效
+	var errs []error
+	for _, handler := range h.handlers {
+		if targeted || handler.Enabled(ctx, r.Level) {
+			if err := handler.Handle(ctx, r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+// sinkWriteHandler 将只实现 Write 的输出端适配为 slog.Handler，
+// WithAttrs/WithGroup 的属性在写出时按分组嵌套附加到记录上
+type sinkWriteHandler struct {
+	sink   Sink
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *sinkWriteHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return true
+}
+
+func (h *sinkWriteHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.attrs) == 0 && len(h.groups) == 0 {
+		return h.sink.Write(ctx, r)
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(h.attrs...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	nr.AddAttrs(nestAttrs(h.groups, attrs)...)
+	return h.sink.Write(ctx, nr)
+}
+
+func (h *sinkWriteHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sinkWriteHandler{
+		sink:   h.sink,
+		attrs:  append(append([]slog.Attr{}, h.attrs...), nestAttrs(h.groups, attrs)...),
+		groups: h.groups,
+	}
+}
+
+func (h *sinkWriteHandler) WithGroup(name string) slog.Handler {
+	return &sinkWriteHandler{
+		sink:   h.sink,
+		attrs:  h.attrs,
+		groups: append(append([]string{}, h.groups...), name),
+	}
+}
+
+// nestAttrs 将属性嵌套到分组中
+func nestAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		args := make([]any, len(attrs))
+		for j, a := range attrs {
+			args[j] = a
+		}
+		attrs = []slog.Attr{slog.Group(groups[i], args...)}
+	}
+	return attrs
+}
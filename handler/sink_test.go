@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// recordingSink 只实现 Write 的测试输出端
+type recordingSink struct {
+	name     string
+	events   *[]string
+	startErr error
+	records  []slog.Record
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Start(ctx context.Context) error {
+	*s.events = append(*s.events, "start "+s.name)
+	return s.startErr
+}
+
+func (s *recordingSink) Write(ctx context.Context, r slog.Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *recordingSink) Flush() error { return nil }
+
+func (s *recordingSink) Close() error {
+	*s.events = append(*s.events, "close "+s.name)
+	return nil
+}
+
+func (s *recordingSink) Healthy() error { return nil }
+
+// TestSupervisorLifecycle 测试按顺序启动、逆序关闭以及启动失败时回滚
+func TestSupervisorLifecycle(t *testing.T) {
+	var events []string
+	var buf bytes.Buffer
+	custom := &recordingSink{name: "custom", events: &events}
+	sup := NewSupervisor(
+		NewHandlerSink("text", slog.NewTextHandler(&buf, nil), SinkOptions{Healthy: func() error { return errors.New("disk full") }}),
+		custom,
+	)
+	if err := sup.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	slog.New(sup.Handler()).With("app", "demo").WithGroup("req").Info("hello", "id", 1)
+	if !strings.Contains(buf.String(), "app=demo req.id=1") {
+		t.Errorf("handler sink output: %s", buf.String())
+	}
+	if len(custom.records) != 1 || custom.records[0].NumAttrs() != 2 {
+		t.Errorf("write sink should receive app and grouped attrs, got %v", custom.records)
+	}
+
+	health := sup.Health()
+	if health[0].Healthy || health[0].Error != "disk full" || !health[1].Healthy {
+		t.Errorf("unexpected health %+v", health)
+	}
+	if err := sup.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	events = nil
+	failing := NewSupervisor(&recordingSink{name: "a", events: &events},
+		&recordingSink{name: "b", events: &events, startErr: errors.New("unreachable")})
+	if err := failing.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "sink b") {
+		t.Errorf("expected start error for sink b, got %v", err)
+	}
+	if got := strings.Join(events, ","); got != "start a,start b,close a" {
+		t.Errorf("events = %s", got)
+	}
+}
+
+// appendingHandler 追加属性后保留记录的测试处理器
+type appendingHandler struct {
+	key      string
+	retained []slog.Record
+}
+
+func (h *appendingHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *appendingHandler) Handle(_ context.Context, r slog.Record) error {
+	r.AddAttrs(slog.Bool(h.key, true))
+	h.retained = append(h.retained, r)
+	return nil
+}
+func (h *appendingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *appendingHandler) WithGroup(string) slog.Handler      { return h }
+
+// TestFanoutSharedRecord 测试各处理器共享同一份记录时追加的属性互不可见
+func TestFanoutSharedRecord(t *testing.T) {
+	first, second := &appendingHandler{key: "first"}, &appendingHandler{key: "second"}
+	h := &fanoutHandler{handlers: []slog.Handler{first, second}}
+	args := []any{"a", 1, "b", 2, "c", 3, "d", 4, "e", 5, "f", 6} // 超出记录的内联容量
+	slog.New(h).Info("shared", args...)
+
+	keys := func(r slog.Record) string {
+		var ks []string
+		r.Attrs(func(a slog.Attr) bool { ks = append(ks, a.Key); return true })
+		return strings.Join(ks, ",")
+	}
+	if got := keys(first.retained[0]); got != "a,b,c,d,e,f,first" {
+		t.Errorf("first handler attrs = %s", got)
+	}
+	if got := keys(second.retained[0]); got != "a,b,c,d,e,f,second" {
+		t.Errorf("second handler attrs = %s", got)
+	}
+}
+
+// BenchmarkFanoutHandler 多输出端分发性能测试，记录带有超过内联容量的属性
+func BenchmarkFanoutHandler(b *testing.B) {
+	h := NewSupervisor(
+		NewHandlerSink("json", slog.NewJSONHandler(io.Discard, nil), SinkOptions{}),
+		NewHandlerSink("text", slog.NewTextHandler(io.Discard, nil), SinkOptions{}),
+		NewHandlerSink("audit", slog.NewJSONHandler(io.Discard, nil), SinkOptions{}),
+	).Handler()
+	logger := slog.New(h)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("Benchmark test message",
+				slog.String("method", "GET"),
+				slog.String("path", "/api/v1/users"),
+				slog.Int("status", 200),
+				slog.Duration("latency", 1500),
+				slog.String("client_ip", "10.0.0.1"),
+				slog.String("request_id", "req_1"),
+				slog.Int64("response_size", 512),
+			)
+		}
+	})
+}
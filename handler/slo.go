@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sloBuckets 滚动窗口划分的桶数
+const sloBuckets = 12
+
+// sloMinBucketWidth 每个桶的最小时长，窗口小于 sloBuckets 个该时长时被调大，避免桶宽为0
+const sloMinBucketWidth = time.Second
+
+// sloMaxRoutes 单独统计的路由上限，超出的路由归入 other，避免高基数路径占用内存
+const sloMaxRoutes = 1000
+
+// SLOConfig 基于访问日志的 SLO 统计配置
+type SLOConfig struct {
+	Window    time.Duration // 滚动窗口
+	Objective float64       // 可用性目标，如 0.999
+}
+
+// RouteSLO 单个路由在滚动窗口内的可用性统计，5xx 计为失败
+type RouteSLO struct {
+	Route        string  `json:"route"`
+	Total        int64   `json:"total"`
+	Errors       int64   `json:"errors"`
+	Availability float64 `json:"availability"`
+	ErrorRatio   float64 `json:"error_ratio"`
+	BudgetLeft   float64 `json:"budget_left"` // 剩余错误预算比例，小于0表示已超支
+}
+
+// sloBucket 一个时间桶内的请求计数
+type sloBucket struct {
+	start  time.Time
+	total  int64
+	errors int64
+}
+
+// SLOTracker 按路由统计滚动窗口内的请求数和 5xx 数
+type SLOTracker struct {
+	mu     sync.Mutex
+	cfg    SLOConfig
+	routes map[string]*[sloBuckets]sloBucket
+}
+
+// NewSLOTracker 创建 SLO 统计器
+func NewSLOTracker(cfg SLOConfig) *SLOTracker {
+	t := &SLOTracker{routes: make(map[string]*[sloBuckets]sloBucket)}
+	t.Configure(cfg)
+	return t
+}
+
+// Configure 更新窗口和目标，窗口变化时清空已有统计；窗口至少为 12 秒（每个桶 1 秒）
+func (t *SLOTracker) Configure(cfg SLOConfig) {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Hour
+	}
+	cfg.Window = max(cfg.Window, sloBuckets*sloMinBucketWidth)
+	if cfg.Objective <= 0 || cfg.Objective >= 1 {
+		cfg.Objective = 0.999
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cfg.Window != t.cfg.Window {
+		t.routes = make(map[string]*[sloBuckets]sloBucket)
+	}
+	t.cfg = cfg
+}
+
+// Observe 记录一次请求
+func (t *SLOTracker) Observe(route string, status int, now time.Time) {
+	t.ObserveN(route, status, now, 1)
+}
+
+// ObserveN 记录 n 次相同结果的请求，用于按 sample_rate 还原被采样的访问日志
+func (t *SLOTracker) ObserveN(route string, status int, now time.Time, n int) {
+	width := t.bucketWidth()
+	start := now.Truncate(width)
+	idx := int(start.UnixNano()/int64(width)) % sloBuckets
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	buckets, ok := t.routes[route]
+	if !ok {
+		if len(t.routes) >= sloMaxRoutes {
+			route = "other"
+			buckets = t.routes[route]
+		}
+		if buckets == nil {
+			buckets = new([sloBuckets]sloBucket)
+			t.routes[route] = buckets
+		}
+	}
+	b := &buckets[idx]
+	if !b.start.Equal(start) {
+		*b = sloBucket{start: start}
+	}
+	b.total += int64(n)
+	if status >= 500 {
+		b.errors += int64(n)
+	}
+}
+
+// Snapshot 返回各路由在窗口内的统计，按请求数降序排列
+func (t *SLOTracker) Snapshot() []RouteSLO {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := make([]RouteSLO, 0, len(t.routes))
+	for route, buckets := range t.routes {
+		s := RouteSLO{Route: route}
+		for _, b := range buckets {
+			if now.Sub(b.start) < t.cfg.Window {
+				s.Total += b.total
+				s.Errors += b.errors
+			}
+		}
+		if s.Total == 0 {
+			continue
+		}
+		s.ErrorRatio = float64(s.Errors) / float64(s.Total)
+		s.Availability = 1 - s.ErrorRatio
+		s.BudgetLeft = 1 - s.ErrorRatio/(1-t.cfg.Objective)
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Route < out[j].Route
+	})
+	return out
+}
+
+// Objective 返回可用性目标
+func (t *SLOTracker) Objective() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cfg.Objective
+}
+
+// bucketWidth 每个桶的时长
+func (t *SLOTracker) bucketWidth() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cfg.Window / sloBuckets
+}
+
+// SLOHandler 从访问日志记录（type=http_request）中读取路由和状态码交给 SLOTracker 统计
+type SLOHandler struct {
+	handler slog.Handler
+	tracker *SLOTracker
+}
+
+// NewSLOHandler 创建 SLO 统计处理器
+func NewSLOHandler(handler slog.Handler, tracker *SLOTracker) *SLOHandler {
+	return &SLOHandler{handler: handler, tracker: tracker}
+}
+
+func (h *SLOHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SLOHandler) Handle(ctx context.Context, r slog.Record) error {
+	var isAccess bool
+	var method, route, path string
+	status, weight := 0, 1
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "type":
+			isAccess = a.Value.String() == "http_request"
+		case "method":
+			method = a.Value.String()
+		case "route":
+			route = a.Value.String()
+		case "path":
+			path = a.Value.String()
+		case "status":
+			if a.Value.Kind() == slog.KindInt64 {
+				status = int(a.Value.Int64())
+			}
+		case "sample_rate":
+			if rate := sampleRateOf(a.Value); rate > 1 {
+				weight = int(math.Round(rate))
+			}
+		}
+		return true
+	})
+	if isAccess && status > 0 {
+		if route == "" {
+			route = path
+		}
+		now := r.Time
+		if now.IsZero() {
+			now = time.Now()
+		}
+		h.tracker.ObserveN(method+" "+route, status, now, weight)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *SLOHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SLOHandler{handler: h.handler.WithAttrs(attrs), tracker: h.tracker}
+}
+
+func (h *SLOHandler) WithGroup(name string) slog.Handler {
+	return &SLOHandler{handler: h.handler.WithGroup(name), tracker: h.tracker}
+}
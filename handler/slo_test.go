@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestSLOHandler 测试从访问日志记录按路由统计可用性和错误预算
+func TestSLOHandler(t *testing.T) {
+	tracker := NewSLOTracker(SLOConfig{Window: time.Hour, Objective: 0.9})
+	logger := slog.New(NewSLOHandler(slog.NewTextHandler(io.Discard, nil), tracker))
+
+	for i := 0; i < 8; i++ {
+		logger.Info("HTTP Request", "type", "http_request", "method", "GET", "route", "/users/:id", "status", 200)
+	}
+	logger.Error("HTTP Request", "type", "http_request", "method", "GET", "route", "/users/:id", "status", 503)
+	logger.Warn("HTTP Request", "type", "http_request", "method", "GET", "route", "/users/:id", "status", 404)
+	logger.Info("not an access log", "status", 500)
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot) != 1 {
+		t.Fatalf("expected one route, got %+v", snapshot)
+	}
+	s := snapshot[0]
+	if s.Route != "GET /users/:id" || s.Total != 10 || s.Errors != 1 {
+		t.Errorf("unexpected route stats: %+v", s)
+	}
+	if s.Availability != 0.9 || s.BudgetLeft > 0.0001 || s.BudgetLeft < -0.0001 {
+		t.Errorf("availability %v budget %v, expected 0.9 and an exhausted budget", s.Availability, s.BudgetLeft)
+	}
+}
+
+// TestSLOTrackerMinWindow 测试过小的窗口被调大，桶宽不为0
+func TestSLOTrackerMinWindow(t *testing.T) {
+	tracker := NewSLOTracker(SLOConfig{Window: time.Nanosecond})
+	if w := tracker.bucketWidth(); w != time.Second {
+		t.Fatalf("bucket width = %s, want 1s", w)
+	}
+	tracker.Observe("GET /", 500, time.Now())
+	if s := tracker.Snapshot(); len(s) != 1 || s[0].Errors != 1 {
+		t.Errorf("unexpected snapshot: %+v", s)
+	}
+}
@@ -2,97 +2,159 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
-// SmartFilterHandler 智能过滤处理器，过滤不需要的日志消息
-type SmartFilterHandler struct {
-	handler           slog.Handler
-	ignoreGinDebug    bool
-	ignoreHealthCheck bool
-	minLevel          slog.Level
+// FilterFunc 具名过滤器，返回 true 时丢弃记录
+type FilterFunc func(r slog.Record) bool
+
+// 内置过滤器的名称
+const (
+	FilterGinDebug    = "gin_debug"    // Gin 的调试输出
+	FilterHealthCheck = "health_check" // 消息或 path/url 属性包含 /health、/ping、/status、/metrics 的记录
+	FilterChromedp    = "chromedp"     // chromedp 的内部错误与 CookiePartitionKey 解析错误
+)
+
+// DefaultFilters 未指定时启用的过滤器
+var DefaultFilters = []string{FilterGinDebug, FilterHealthCheck}
+
+var (
+	ginDebugRegex         = regexp.MustCompile(`^\[GIN-debug\]|\[GIN\]`)
+	healthCheckRegex      = regexp.MustCompile(`/health|/ping|/status|/metrics`)
+	cookiePartitionRegex  = regexp.MustCompile(`could not unmarshal event.*CookiePartitionKey`)
+	chromedpInternalRegex = regexp.MustCompile(`chromedp: could not retrieve|context deadline exceeded.*chromedp`)
+
+	filtersMu sync.RWMutex
+	filters   = map[string]FilterFunc{
+		FilterGinDebug: func(r slog.Record) bool {
+			return ginDebugRegex.MatchString(r.Message)
+		},
+		FilterHealthCheck: isHealthCheck,
+		FilterChromedp: func(r slog.Record) bool {
+			return cookiePartitionRegex.MatchString(r.Message) || chromedpInternalRegex.MatchString(r.Message)
+		},
+	}
+)
+
+// RegisterFilter 注册可在 features.smart_filters 中按名称启用的过滤器，同名时覆盖。
+// 过滤器在创建处理器时解析，须在 Init 之前注册
+func RegisterFilter(name string, fn FilterFunc) {
+	filtersMu.Lock()
+	defer filtersMu.Unlock()
+	filters[name] = fn
+}
+
+// LookupFilter 按名称查找已注册的过滤器
+func LookupFilter(name string) (FilterFunc, bool) {
+	filtersMu.RLock()
+	defer filtersMu.RUnlock()
+	fn, ok := filters[name]
+	return fn, ok
+}
 
-	// 预编译的正则表达式，提高性能
-	ginDebugRegex         *regexp.Regexp
-	cookiePartitionRegex  *regexp.Regexp
-	healthCheckRegex      *regexp.Regexp
-	chromedpInternalRegex *regexp.Regexp
+// isHealthCheck 检查消息或属性中的路径（包括分组内的 http.path、request.url 等）是否为健康检查
+func isHealthCheck(r slog.Record) bool {
+	if healthCheckRegex.MatchString(r.Message) {
+		return true
+	}
+	found := false
+	WalkRecordAttrs("", r, func(key string, v slog.Value) bool {
+		if (KeyMatches(key, "path") || KeyMatches(key, "url")) && healthCheckRegex.MatchString(v.String()) {
+			found = true
+			return false // 停止迭代
+		}
+		return true
+	})
+	return found
+}
+
+// SmartFilterHandler 智能过滤处理器：按级别过滤，执行启用的具名过滤器，并对重复的上下文取消、连接断开错误去重，
+// 窗口结束时输出一条 type=suppression_summary 的汇总记录注明被去重的次数
+type SmartFilterHandler struct {
+	handler  slog.Handler
+	minLevel slog.Leveler
+	filters  []FilterFunc
 
-	// 重复错误检测
-	errorTracker map[string]time.Time
-	errorMutex   sync.RWMutex
-	errorWindow  time.Duration // 错误去重时间窗口
+	dedup *dedupState // 重复错误检测
 }
 
 // FilterConfig 过滤器配置
 type FilterConfig struct {
-	IgnoreGinDebug    bool       // 过滤Gin调试信息
-	IgnoreHealthCheck bool       // 过滤健康检查请求
-	MinLevel          slog.Level // 最低日志级别
+	Filters           []string      // 启用的过滤器名称，未注册的名称被忽略；为nil时使用 DefaultFilters
+	IgnoreGinDebug    bool          // 同时启用 gin_debug
+	IgnoreHealthCheck bool          // 同时启用 health_check
+	MinLevel          slog.Leveler  // 最低日志级别，传入 *slog.LevelVar 时随其动态变化；为nil时为Info
+	DedupWindow       time.Duration // 相同的上下文错误在该时间内只输出一次，窗口结束时汇总重复次数；默认5分钟
 }
 
 // NewSmartFilterHandler 创建智能过滤处理器
 func NewSmartFilterHandler(handler slog.Handler, config FilterConfig) *SmartFilterHandler {
-	return &SmartFilterHandler{
-		handler:           handler,
-		ignoreGinDebug:    config.IgnoreGinDebug,
-		ignoreHealthCheck: config.IgnoreHealthCheck,
-		minLevel:          config.MinLevel,
-
-		// 预编译正则表达式
-		ginDebugRegex:         regexp.MustCompile(`^\[GIN-debug\]|\[GIN\]`),
-		cookiePartitionRegex:  regexp.MustCompile(`could not unmarshal event.*CookiePartitionKey`),
-		healthCheckRegex:      regexp.MustCompile(`/health|/ping|/status|/metrics`),
-		chromedpInternalRegex: regexp.MustCompile(`chromedp: could not retrieve|context deadline exceeded.*chromedp`),
+	if config.MinLevel == nil {
+		config.MinLevel = slog.LevelInfo
+	}
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = 5 * time.Minute
+	}
+	names := config.Filters
+	if names == nil {
+		names = DefaultFilters
+	}
+	if config.IgnoreGinDebug {
+		names = append(names[:len(names):len(names)], FilterGinDebug)
+	}
+	if config.IgnoreHealthCheck {
+		names = append(names[:len(names):len(names)], FilterHealthCheck)
+	}
 
-		// 重复错误检测配置
-		errorTracker: make(map[string]time.Time),
-		errorWindow:  5 * time.Minute, // 5分钟内的相同错误只记录一次
+	h := &SmartFilterHandler{
+		handler:  handler,
+		minLevel: config.MinLevel,
+		dedup:    &dedupState{window: config.DedupWindow, emit: handler, entries: make(map[string]*dedupEntry)},
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if fn, ok := LookupFilter(name); ok && !seen[name] {
+			seen[name] = true
+			h.filters = append(h.filters, fn)
+		}
 	}
+	return h
 }
 
 func (h *SmartFilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return level >= h.minLevel && h.handler.Enabled(ctx, level)
+	if IsDebugTargeted(ctx) {
+		return true
+	}
+	return level >= h.minLevel.Level() && h.handler.Enabled(ctx, level)
 }
 
 func (h *SmartFilterHandler) Handle(ctx context.Context, r slog.Record) error {
-	// 1. 级别过滤
-	if r.Level < h.minLevel {
+	// 1. 级别过滤（定向调试的记录不受级别限制）
+	if r.Level < h.minLevel.Level() && !IsDebugTargeted(ctx) {
 		return nil
 	}
 
-	msg := r.Message
-
-	// 2. 过滤Gin调试信息
-	if h.ignoreGinDebug && h.ginDebugRegex.MatchString(msg) {
-		return nil
-	}
-
-	// 3. 过滤CookiePartitionKey错误（chromedp内部错误）
-	if h.cookiePartitionRegex.MatchString(msg) {
-		return nil
-	}
-
-	// 4. 过滤chromedp内部错误
-	if h.chromedpInternalRegex.MatchString(msg) {
-		return nil
+	// 2. 启用的具名过滤器
+	for _, fn := range h.filters {
+		if fn(r) {
+			return nil
+		}
 	}
 
-	// 5. 过滤健康检查请求
-	if h.ignoreHealthCheck && h.shouldIgnoreHealthCheck(r) {
-		return nil
-	}
+	msg := r.Message
 
-	// 6. 过滤重复的上下文取消错误
-	if h.isDuplicateContextError(msg) {
+	// 3. 过滤重复的上下文取消错误
+	if h.isDuplicateContextError(r) {
 		return nil
 	}
 
-	// 7. 过滤空消息或只包含空白字符的消息
+	// 4. 过滤空消息或只包含空白字符的消息
 	if strings.TrimSpace(msg) == "" {
 		return nil
 	}
@@ -100,104 +162,150 @@ func (h *SmartFilterHandler) Handle(ctx context.Context, r slog.Record) error {
 	return h.handler.Handle(ctx, r)
 }
 
-// shouldIgnoreHealthCheck 检查是否应该忽略健康检查相关的日志
-func (h *SmartFilterHandler) shouldIgnoreHealthCheck(r slog.Record) bool {
-	// 检查消息内容
-	if h.healthCheckRegex.MatchString(r.Message) {
-		return true
-	}
-
-	// 检查属性中的路径
-	shouldIgnore := false
-	r.Attrs(func(a slog.Attr) bool {
-		if a.Key == "path" || a.Key == "url" {
-			if h.healthCheckRegex.MatchString(a.Value.String()) {
-				shouldIgnore = true
-				return false // 停止迭代
-			}
-		}
-		return true
-	})
-
-	return shouldIgnore
+// contextErrors 去重的上下文取消、连接断开错误
+var contextErrors = []string{
+	"context canceled",
+	"context deadline exceeded",
+	"connection reset by peer",
+	"broken pipe",
 }
 
-// isDuplicateContextError 检查是否是重复的上下文错误
-func (h *SmartFilterHandler) isDuplicateContextError(msg string) bool {
-	contextErrors := []string{
-		"context canceled",
-		"context deadline exceeded",
-		"connection reset by peer",
-		"broken pipe",
-	}
-
-	msgLower := strings.ToLower(msg)
+// isDuplicateContextError 检查是否是窗口内重复的上下文错误
+func (h *SmartFilterHandler) isDuplicateContextError(r slog.Record) bool {
+	msgLower := strings.ToLower(r.Message)
 	for _, errMsg := range contextErrors {
 		if strings.Contains(msgLower, errMsg) {
 			// 基于时间窗口的重复检测
-			return h.shouldFilterDuplicateError(msg)
+			return h.dedup.suppress(r)
 		}
 	}
-
 	return false
 }
 
-// shouldFilterDuplicateError 判断是否应该过滤重复错误
-func (h *SmartFilterHandler) shouldFilterDuplicateError(msg string) bool {
-	now := time.Now()
+// dedupState 派生处理器共享的重复错误计数
+type dedupState struct {
+	mu      sync.Mutex
+	window  time.Duration
+	emit    slog.Handler // 汇总记录的输出处理器
+	entries map[string]*dedupEntry
+	closed  bool
+}
 
-	h.errorMutex.Lock()
-	defer h.errorMutex.Unlock()
+// dedupEntry 一条错误消息在当前窗口内的计数
+type dedupEntry struct {
+	level      slog.Level
+	start      time.Time
+	suppressed int
+	timer      *time.Timer // 窗口结束时输出汇总，出现重复后才启动
+}
 
-	// 清理过期的错误记录
-	for key, timestamp := range h.errorTracker {
-		if now.Sub(timestamp) > h.errorWindow {
-			delete(h.errorTracker, key)
+// suppress 窗口内第一次出现的消息照常输出，之后的重复计数并丢弃；
+// 出现重复时在窗口结束时输出一条汇总，之后再出现的同一消息开始新的窗口
+func (s *dedupState) suppress(r slog.Record) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+
+	// 清理过期且没有重复的记录，有重复的记录由汇总定时器移除
+	for key, e := range s.entries {
+		if e.suppressed == 0 && now.Sub(e.start) >= s.window {
+			delete(s.entries, key)
 		}
 	}
 
-	// 检查当前错误是否在时间窗口内已记录过
-	if lastTime, exists := h.errorTracker[msg]; exists {
-		if now.Sub(lastTime) < h.errorWindow {
-			return true // 过滤重复错误
+	e, ok := s.entries[r.Message]
+	if !ok {
+		s.entries[r.Message] = &dedupEntry{level: r.Level, start: now}
+		return false
+	}
+	e.suppressed++
+	if e.suppressed == 1 {
+		msg := r.Message
+		e.timer = time.AfterFunc(s.window-now.Sub(e.start), func() { s.flush(msg) })
+	}
+	return true
+}
+
+// flush 输出一条消息在窗口内被去重的次数
+func (s *dedupState) flush(msg string) {
+	s.mu.Lock()
+	e := s.entries[msg]
+	delete(s.entries, msg)
+	s.mu.Unlock()
+	s.emitSummary(msg, e)
+}
+
+// flushAll 停止所有汇总定时器并立即输出已有重复的消息的汇总
+func (s *dedupState) flushAll() {
+	s.mu.Lock()
+	pending := make(map[string]*dedupEntry)
+	for msg, e := range s.entries {
+		if e.timer != nil {
+			e.timer.Stop()
+			pending[msg] = e
+			delete(s.entries, msg)
 		}
 	}
+	s.mu.Unlock()
 
-	// 记录新的错误
-	h.errorTracker[msg] = now
-	return false
+	msgs := make([]string, 0, len(pending))
+	for msg := range pending {
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(msgs)
+	for _, msg := range msgs {
+		s.emitSummary(msg, pending[msg])
+	}
 }
 
-func (h *SmartFilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &SmartFilterHandler{
-		handler:               h.handler.WithAttrs(attrs),
-		ignoreGinDebug:        h.ignoreGinDebug,
-		ignoreHealthCheck:     h.ignoreHealthCheck,
-		minLevel:              h.minLevel,
-		ginDebugRegex:         h.ginDebugRegex,
-		cookiePartitionRegex:  h.cookiePartitionRegex,
-		healthCheckRegex:      h.healthCheckRegex,
-		chromedpInternalRegex: h.chromedpInternalRegex,
-		errorTracker:          h.errorTracker, // 共享错误追踪器
-		errorMutex:            h.errorMutex,   // 共享互斥锁
-		errorWindow:           h.errorWindow,  // 共享时间窗口
+// emitSummary 输出一条去重汇总，没有重复时不输出
+func (s *dedupState) emitSummary(msg string, e *dedupEntry) {
+	if e == nil || e.suppressed == 0 {
+		return
 	}
+
+	r := slog.NewRecord(time.Now(), e.level, fmt.Sprintf("suppressed %d occurrences of %q in last %s", e.suppressed, msg, s.window), 0)
+	r.AddAttrs(
+		slog.String("type", "suppression_summary"),
+		slog.Int("suppressed", e.suppressed),
+		slog.Time("since", e.start),
+		slog.Duration("window", s.window),
+		slog.String("sample", msg),
+	)
+	ctx := context.Background()
+	if s.emit.Enabled(ctx, e.level) {
+		_ = s.emit.Handle(ctx, r)
+	}
+}
+
+// Flush 立即输出去重窗口尚未结束的汇总
+func (h *SmartFilterHandler) Flush() error {
+	h.dedup.flushAll()
+	return nil
+}
+
+// Close 停止去重汇总的定时器并输出尚未输出的汇总，之后不再去重
+func (h *SmartFilterHandler) Close() error {
+	h.dedup.mu.Lock()
+	h.dedup.closed = true
+	h.dedup.mu.Unlock()
+	h.dedup.flushAll()
+	return nil
+}
+
+func (h *SmartFilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.handler = h.handler.WithAttrs(attrs) // 重复错误计数为指针，副本之间共享
+	return &c
 }
 
 func (h *SmartFilterHandler) WithGroup(name string) slog.Handler {
-	return &SmartFilterHandler{
-		handler:               h.handler.WithGroup(name),
-		ignoreGinDebug:        h.ignoreGinDebug,
-		ignoreHealthCheck:     h.ignoreHealthCheck,
-		minLevel:              h.minLevel,
-		ginDebugRegex:         h.ginDebugRegex,
-		cookiePartitionRegex:  h.cookiePartitionRegex,
-		healthCheckRegex:      h.healthCheckRegex,
-		chromedpInternalRegex: h.chromedpInternalRegex,
-		errorTracker:          h.errorTracker, // 共享错误追踪器
-		errorMutex:            h.errorMutex,   // 共享互斥锁
-		errorWindow:           h.errorWindow,  // 共享时间窗口
-	}
+	c := *h
+	c.handler = h.handler.WithGroup(name)
+	return &c
 }
 
 // GinLogWriter 实现 io.Writer 接口，用于重定向 Gin 的日志输出
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSmartFilterRegistry 测试只执行启用的具名过滤器，注册的过滤器可按名称启用
+func TestSmartFilterRegistry(t *testing.T) {
+	RegisterFilter("test_sql_noise", func(r slog.Record) bool {
+		return strings.HasPrefix(r.Message, "sql: ")
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(NewSmartFilterHandler(slog.NewTextHandler(&buf, nil), FilterConfig{
+		Filters: []string{"test_sql_noise", FilterChromedp, "not_registered"},
+	}))
+	logger.Info("sql: SELECT 1")
+	logger.Warn("chromedp: could not retrieve document")
+	logger.Info("[GIN-debug] GET /ping")
+	logger.Info("GET /health")
+
+	out := buf.String()
+	if strings.Contains(out, "sql:") || strings.Contains(out, "chromedp") {
+		t.Errorf("enabled filters should drop their records: %s", out)
+	}
+	if !strings.Contains(out, "GIN-debug") || !strings.Contains(out, "/health") {
+		t.Errorf("filters not enabled should not run: %s", out)
+	}
+
+	buf.Reset()
+	logger = slog.New(NewSmartFilterHandler(slog.NewTextHandler(&buf, nil), FilterConfig{}))
+	logger.Info("[GIN-debug] GET /ping")
+	logger.Warn("chromedp: could not retrieve document")
+	if out := buf.String(); strings.Contains(out, "GIN-debug") || !strings.Contains(out, "chromedp") {
+		t.Errorf("nil Filters should enable DefaultFilters only: %s", out)
+	}
+}
+
+// TestSmartFilterDedupSummary 测试重复的上下文错误被去重，窗口结束时输出汇总，之后开始新的窗口
+func TestSmartFilterDedupSummary(t *testing.T) {
+	capture := &capturingHandler{}
+	logger := slog.New(NewSmartFilterHandler(capture, FilterConfig{Filters: []string{}, DedupWindow: 50 * time.Millisecond}))
+	for i := 0; i < 4; i++ {
+		logger.Error("upstream: context canceled")
+	}
+	if msgs := capture.messages(); len(msgs) != 1 {
+		t.Fatalf("repeated errors should be logged once, got %v", msgs)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	msgs := capture.messages()
+	want := `suppressed 3 occurrences of "upstream: context canceled" in last 50ms`
+	if len(msgs) != 2 || msgs[1] != want {
+		t.Fatalf("expected a suppression summary, got %v", msgs)
+	}
+	capture.mu.Lock()
+	summary := capture.records[1]
+	capture.mu.Unlock()
+	summary.Attrs(func(a slog.Attr) bool {
+		if a.Key == "suppressed" && a.Value.Int64() != 3 {
+			t.Errorf("suppressed = %v", a.Value)
+		}
+		return true
+	})
+
+	logger.Error("upstream: context canceled")
+	if msgs := capture.messages(); len(msgs) != 3 {
+		t.Errorf("a new window should log the error again, got %v", msgs)
+	}
+}
+
+// TestSmartFilterClose 测试关闭时停止去重定时器并立即输出尚未输出的汇总
+func TestSmartFilterClose(t *testing.T) {
+	capture := &capturingHandler{}
+	h := NewSmartFilterHandler(capture, FilterConfig{Filters: []string{}, DedupWindow: time.Hour})
+	logger := slog.New(h)
+	for i := 0; i < 3; i++ {
+		logger.Error("read: connection reset by peer")
+	}
+	logger.Error("write: broken pipe")
+
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	msgs := capture.messages()
+	want := `suppressed 2 occurrences of "read: connection reset by peer" in last 1h0m0s`
+	if len(msgs) != 3 || msgs[2] != want {
+		t.Fatalf("expected close to emit the pending summary only for repeated errors, got %v", msgs)
+	}
+
+	logger.Error("write: broken pipe")
+	if n := len(capture.messages()); n != 4 {
+		t.Errorf("errors after close should not be deduplicated, got %d records", n)
+	}
+}
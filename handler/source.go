@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// SourceFormat 调用位置的输出格式
+type SourceFormat string
+
+const (
+	SourceOff   SourceFormat = "off"   // 不记录调用位置，省去 PC 解析开销
+	SourceShort SourceFormat = "short" // 目录/文件:行号，如 api/user.go:42
+	SourceFull  SourceFormat = "full"  // slog 默认的 function/file/line 结构
+)
+
+// SourceOptions 返回按格式设置 AddSource 和 ReplaceAttr 的处理器选项副本，未知格式按 full 处理
+func SourceOptions(opts *slog.HandlerOptions, format SourceFormat) *slog.HandlerOptions {
+	out := &slog.HandlerOptions{}
+	if opts != nil {
+		*out = *opts
+	}
+	switch format {
+	case SourceOff:
+		out.AddSource = false
+	case SourceShort:
+		out.AddSource = true
+		next := out.ReplaceAttr
+		out.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.SourceKey {
+				if src, ok := a.Value.Any().(*slog.Source); ok {
+					a.Value = slog.StringValue(ShortSource(src.File, src.Line))
+				}
+			}
+			if next != nil {
+				return next(groups, a)
+			}
+			return a
+		}
+	default:
+		out.AddSource = true
+	}
+	return out
+}
+
+// ShortSource 返回 目录/文件:行号 形式的调用位置
+func ShortSource(file string, line int) string {
+	dir, name := filepath.Split(file)
+	return filepath.Join(filepath.Base(dir), name) + ":" + strconv.Itoa(line)
+}
+
+// recordSource 解析记录的调用位置
+func recordSource(r slog.Record) *slog.Source {
+	if r.PC == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames([]uintptr{r.PC})
+	f, _ := frames.Next()
+	return &slog.Source{Function: f.Function, File: f.File, Line: f.Line}
+}
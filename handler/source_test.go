@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestSourceOptions 测试 short/full/off 三种调用位置格式
+func TestSourceOptions(t *testing.T) {
+	tests := []struct {
+		format SourceFormat
+		want   string
+	}{
+		{SourceShort, `"source":"handler/source_test.go:`},
+		{SourceFull, `"source":{"function":`},
+		{SourceOff, ""},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buf, SourceOptions(nil, tt.format))).Info("hello")
+		got := buf.String()
+		if tt.want == "" {
+			if strings.Contains(got, `"source"`) {
+				t.Errorf("%s: source should be omitted: %s", tt.format, got)
+			}
+		} else if !strings.Contains(got, tt.want) {
+			t.Errorf("%s: expected %q in %s", tt.format, tt.want, got)
+		}
+	}
+}
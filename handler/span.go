@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SpanTimings 请求内各计时段的累计耗时，由访问日志中间件创建并在请求结束时输出
+type SpanTimings struct {
+	mu     sync.Mutex
+	totals map[string]time.Duration
+	counts map[string]int
+}
+
+// ctxSpansKey 上下文中存放请求级计时的键
+type ctxSpansKey struct{}
+
+// ContextWithSpanTimings 在上下文中绑定新的请求级计时累加器
+func ContextWithSpanTimings(ctx context.Context) (context.Context, *SpanTimings) {
+	timings := &SpanTimings{totals: make(map[string]time.Duration), counts: make(map[string]int)}
+	return context.WithValue(ctx, ctxSpansKey{}, timings), timings
+}
+
+// SpanTimingsFromContext 获取上下文中的请求级计时累加器，不存在时返回nil
+func SpanTimingsFromContext(ctx context.Context) *SpanTimings {
+	if ctx == nil {
+		return nil
+	}
+	timings, _ := ctx.Value(ctxSpansKey{}).(*SpanTimings)
+	return timings
+}
+
+// Add 累加一次计时
+func (t *SpanTimings) Add(name string, d time.Duration) {
+	t.mu.Lock()
+	t.totals[name] += d
+	t.counts[name]++
+	t.mu.Unlock()
+}
+
+// Attrs 返回 spans 分组属性，每个计时段包含 count 和 total_ms，无计时时返回nil
+func (t *SpanTimings) Attrs() []slog.Attr {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.totals) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(t.totals))
+	for name := range t.totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	spans := make([]any, 0, len(names))
+	for _, name := range names {
+		spans = append(spans, slog.Group(name,
+			slog.Int("count", t.counts[name]),
+			slog.Float64("total_ms", millis(int64(t.totals[name]))),
+		))
+	}
+	return []slog.Attr{slog.Group("spans", spans...)}
+}
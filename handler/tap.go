@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultTapBuffer 每个订阅者默认可缓冲的记录数
+const DefaultTapBuffer = 1024
+
+// RecordFunc 订阅者回调，在独立的goroutine中按记录顺序调用
+type RecordFunc func(ctx context.Context, r slog.Record)
+
+// tapEntry 排队等待分发的记录
+type tapEntry struct {
+	ctx context.Context
+	r   slog.Record
+}
+
+// tapSubscriber 单个订阅者：有界队列和分发goroutine，回调较慢时只丢弃该订阅者的记录
+type tapSubscriber struct {
+	fn    RecordFunc
+	queue chan tapEntry
+	done  chan struct{}
+	once  sync.Once
+}
+
+func (s *tapSubscriber) run() {
+	for {
+		select {
+		case e := <-s.queue:
+			s.fn(e.ctx, e.r)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *tapSubscriber) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// RecordTap 将记录复制给订阅的回调，供指标、界面提示、测试等使用而无需实现完整的 slog.Handler。
+// 投递不会阻塞日志调用：订阅者队列已满时丢弃记录并计数
+type RecordTap struct {
+	mu          sync.Mutex
+	subscribers atomic.Pointer[[]*tapSubscriber] // 写时复制，Handle 无锁读取
+	buffer      int
+	dropped     atomic.Int64
+}
+
+// NewRecordTap 创建记录分发器，buffer 为每个订阅者的队列长度，<=0 时使用 DefaultTapBuffer
+func NewRecordTap(buffer int) *RecordTap {
+	if buffer <= 0 {
+		buffer = DefaultTapBuffer
+	}
+	return &RecordTap{buffer: buffer}
+}
+
+// Subscribe 注册回调，返回的函数取消订阅，已排队但尚未分发的记录会被丢弃
+func (t *RecordTap) Subscribe(fn RecordFunc) (cancel func()) {
+	sub := &tapSubscriber{fn: fn, queue: make(chan tapEntry, t.buffer), done: make(chan struct{})}
+	go sub.run()
+
+	t.mu.Lock()
+	var subs []*tapSubscriber
+	if cur := t.subscribers.Load(); cur != nil {
+		subs = append(subs, *cur...)
+	}
+	subs = append(subs, sub)
+	t.subscribers.Store(&subs)
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		if cur := t.subscribers.Load(); cur != nil {
+			subs := make([]*tapSubscriber, 0, len(*cur))
+			for _, s := range *cur {
+				if s != sub {
+					subs = append(subs, s)
+				}
+			}
+			t.subscribers.Store(&subs)
+		}
+		t.mu.Unlock()
+		sub.stop()
+	}
+}
+
+// Dropped 返回因订阅者队列已满而丢弃的记录数
+func (t *RecordTap) Dropped() int64 {
+	return t.dropped.Load()
+}
+
+// active 是否有订阅者
+func (t *RecordTap) active() bool {
+	subs := t.subscribers.Load()
+	return subs != nil && len(*subs) > 0
+}
+
+// publish 将记录投递给所有订阅者，不阻塞
+func (t *RecordTap) publish(ctx context.Context, r slog.Record) {
+	subs := t.subscribers.Load()
+	if subs == nil {
+		return
+	}
+	// 回调在其他goroutine中执行，不应因调用方返回而被取消
+	ctx = context.WithoutCancel(ctx)
+	for _, s := range *subs {
+		select {
+		case s.queue <- tapEntry{ctx: ctx, r: r.Clone()}:
+		default:
+			t.dropped.Add(1)
+		}
+	}
+}
+
+// TapHandler 将经过的记录复制给 RecordTap 的订阅者，WithAttrs/WithGroup 的属性按分组嵌套附加到复制的记录上
+type TapHandler struct {
+	handler slog.Handler
+	tap     *RecordTap
+	attrs   []slog.Attr
+	groups  []string
+}
+
+// NewTapHandler 创建记录分发处理器
+func NewTapHandler(handler slog.Handler, tap *RecordTap) slog.Handler {
+	return &TapHandler{handler: handler, tap: tap}
+}
+
+func (h *TapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *TapHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.tap.active() {
+		if len(h.attrs) == 0 && len(h.groups) == 0 {
+			h.tap.publish(ctx, r)
+		} else {
+			nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+			nr.AddAttrs(h.attrs...)
+			attrs := make([]slog.Attr, 0, r.NumAttrs())
+			r.Attrs(func(a slog.Attr) bool {
+				attrs = append(attrs, a)
+				return true
+			})
+			nr.AddAttrs(nestAttrs(h.groups, attrs)...)
+			h.tap.publish(ctx, nr)
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *TapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TapHandler{
+		handler: h.handler.WithAttrs(attrs),
+		tap:     h.tap,
+		attrs:   append(append([]slog.Attr{}, h.attrs...), nestAttrs(h.groups, attrs)...),
+		groups:  h.groups,
+	}
+}
+
+func (h *TapHandler) WithGroup(name string) slog.Handler {
+	return &TapHandler{
+		handler: h.handler.WithGroup(name),
+		tap:     h.tap,
+		attrs:   h.attrs,
+		groups:  append(append([]string{}, h.groups...), name),
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestRecordTap 测试订阅者收到含绑定属性的完整记录，取消订阅后不再收到，队列已满时丢弃而不阻塞
+func TestRecordTap(t *testing.T) {
+	tap := NewRecordTap(4)
+	got := make(chan slog.Record, 8)
+	cancel := tap.Subscribe(func(ctx context.Context, r slog.Record) { got <- r })
+
+	logger := slog.New(NewTapHandler(slog.NewJSONHandler(io.Discard, nil), tap))
+	logger.With("component", "db").WithGroup("query").Info("slow", "table", "users")
+
+	select {
+	case r := <-got:
+		var keys []string
+		WalkRecordAttrs("", r, func(key string, _ slog.Value) bool {
+			keys = append(keys, key)
+			return true
+		})
+		if r.Message != "slow" || len(keys) != 2 || keys[0] != "component" || keys[1] != "query.table" {
+			t.Errorf("unexpected record %q with attrs %v", r.Message, keys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber did not receive the record")
+	}
+
+	cancel()
+	logger.Info("after cancel")
+	select {
+	case r := <-got:
+		t.Errorf("cancelled subscriber received %q", r.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	tap.Subscribe(func(ctx context.Context, r slog.Record) { <-block })
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			logger.Info("burst")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("slow subscriber should not block logging")
+	}
+	if tap.Dropped() < 15 {
+		t.Errorf("Dropped() = %d, want at least 15", tap.Dropped())
+	}
+}
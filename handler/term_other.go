@@ -0,0 +1,14 @@
+//go:build !unix
+
+package handler
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalWidth 非 Unix 平台读取 COLUMNS 环境变量，未设置时返回0
+func terminalWidth(f *os.File) int {
+	n, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return n
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TransformRule 记录转换规则，按声明顺序依次执行
+type TransformRule struct {
+	Type    string // rename：重命名属性；extract：用正则命名捕获组提取属性；derive：由已有属性计算新属性
+	From    string // 源属性的完整路径（如 http.status）；extract 为空或 msg 时从消息中提取
+	To      string // rename 的新键名，derive 的目标属性名
+	Pattern string // extract 的正则，每个非空的命名捕获组写入同名属性
+	Func    string // derive 的函数：status_class、lower、upper，或 RegisterTransformFunc 注册的名称
+}
+
+// TransformFunc derive 使用的计算函数，返回 false 时不添加属性
+type TransformFunc func(v slog.Value) (slog.Value, bool)
+
+var (
+	transformFuncsMu sync.RWMutex
+	transformFuncs   = map[string]TransformFunc{
+		"status_class": statusClass,
+		"lower": func(v slog.Value) (slog.Value, bool) {
+			return slog.StringValue(strings.ToLower(v.String())), true
+		},
+		"upper": func(v slog.Value) (slog.Value, bool) {
+			return slog.StringValue(strings.ToUpper(v.String())), true
+		},
+	}
+)
+
+// RegisterTransformFunc 注册 derive 可用的计算函数，同名时覆盖
+func RegisterTransformFunc(name string, fn TransformFunc) {
+	transformFuncsMu.Lock()
+	defer transformFuncsMu.Unlock()
+	transformFuncs[name] = fn
+}
+
+// statusClass 将 HTTP 状态码转换为 2xx、4xx 等分类
+func statusClass(v slog.Value) (slog.Value, bool) {
+	var code int64
+	switch v.Kind() {
+	case slog.KindInt64:
+		code = v.Int64()
+	case slog.KindUint64:
+		code = int64(v.Uint64())
+	case slog.KindFloat64:
+		code = int64(v.Float64())
+	default:
+		n, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return slog.Value{}, false
+		}
+		code = n
+	}
+	if code < 100 || code > 599 {
+		return slog.Value{}, false
+	}
+	return slog.StringValue(strconv.FormatInt(code/100, 10) + "xx"), true
+}
+
+// transform 编译后的转换规则。msg 为 nil 表示处理 WithAttrs 绑定的属性，此时没有消息
+type transform func(prefix string, msg *string, attrs []slog.Attr) []slog.Attr
+
+// compileTransform 校验并编译单条规则
+func compileTransform(rule TransformRule) (transform, error) {
+	switch rule.Type {
+	case "rename":
+		if rule.From == "" || rule.To == "" {
+			return nil, fmt.Errorf("rename: from and to are required")
+		}
+		return func(prefix string, _ *string, attrs []slog.Attr) []slog.Attr {
+			return renameAttr(prefix, attrs, rule.From, rule.To)
+		}, nil
+
+	case "extract":
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("extract: %w", err)
+		}
+		if !hasNamedGroup(re) {
+			return nil, fmt.Errorf("extract: pattern %q has no named capture groups", rule.Pattern)
+		}
+		fromMsg := rule.From == "" || rule.From == "msg"
+		return func(prefix string, msg *string, attrs []slog.Attr) []slog.Attr {
+			var src string
+			if fromMsg {
+				if msg == nil {
+					return attrs
+				}
+				src = *msg
+			} else if v, ok := lookupAttr(prefix, attrs, rule.From); ok {
+				src = v.String()
+			} else {
+				return attrs
+			}
+			m := re.FindStringSubmatch(src)
+			for i, name := range re.SubexpNames() {
+				if name != "" && i < len(m) && m[i] != "" {
+					attrs = append(attrs, slog.String(name, m[i]))
+				}
+			}
+			return attrs
+		}, nil
+
+	case "derive":
+		if rule.From == "" || rule.To == "" {
+			return nil, fmt.Errorf("derive: from and to are required")
+		}
+		transformFuncsMu.RLock()
+		fn, ok := transformFuncs[rule.Func]
+		transformFuncsMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("derive: unknown func %q", rule.Func)
+		}
+		return func(prefix string, _ *string, attrs []slog.Attr) []slog.Attr {
+			if v, ok := lookupAttr(prefix, attrs, rule.From); ok {
+				if derived, ok := fn(v); ok {
+					attrs = append(attrs, slog.Attr{Key: rule.To, Value: derived})
+				}
+			}
+			return attrs
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown transform type %q", rule.Type)
+}
+
+// hasNamedGroup 检查正则是否包含命名捕获组
+func hasNamedGroup(re *regexp.Regexp) bool {
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupAttr 按完整路径查找叶子属性的值
+func lookupAttr(prefix string, attrs []slog.Attr, path string) (slog.Value, bool) {
+	var found slog.Value
+	ok := false
+	WalkAttrs(prefix, attrs, func(key string, v slog.Value) bool {
+		if key == path {
+			found, ok = v, true
+		}
+		return !ok
+	})
+	return found, ok
+}
+
+// renameAttr 将完整路径为 from 的属性改名为 to，分组内的属性保留在原分组中
+func renameAttr(prefix string, attrs []slog.Attr, from, to string) []slog.Attr {
+	for i, a := range attrs {
+		key := joinKey(prefix, a.Key)
+		if key == from {
+			attrs[i].Key = to
+			continue
+		}
+		if v := a.Value.Resolve(); v.Kind() == slog.KindGroup && strings.HasPrefix(from, key+".") {
+			group := append([]slog.Attr(nil), v.Group()...)
+			attrs[i].Value = slog.GroupValue(renameAttr(key, group, from, to)...)
+		}
+	}
+	return attrs
+}
+
+// TransformHandler 在记录到达输出端之前按规则重命名、提取和派生属性。
+// 规则同样作用于 WithAttrs 绑定的属性；新增的属性位于记录当前所在的分组
+type TransformHandler struct {
+	handler    slog.Handler
+	transforms []transform
+	group      string // WithGroup 累积的组名
+}
+
+// NewTransformHandler 创建记录转换处理器，规则无效时返回错误；没有规则时直接返回原处理器
+func NewTransformHandler(handler slog.Handler, rules []TransformRule) (slog.Handler, error) {
+	if len(rules) == 0 {
+		return handler, nil
+	}
+	transforms := make([]transform, 0, len(rules))
+	for i, rule := range rules {
+		t, err := compileTransform(rule)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d: %w", i, err)
+		}
+		transforms = append(transforms, t)
+	}
+	return &TransformHandler{handler: handler, transforms: transforms}, nil
+}
+
+func (h *TransformHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *TransformHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for _, t := range h.transforms {
+		attrs = t(h.group, &r.Message, attrs)
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(attrs...)
+	return h.handler.Handle(ctx, nr)
+}
+
+func (h *TransformHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = append([]slog.Attr(nil), attrs...)
+	for _, t := range h.transforms {
+		attrs = t(h.group, nil, attrs)
+	}
+	return &TransformHandler{handler: h.handler.WithAttrs(attrs), transforms: h.transforms, group: h.group}
+}
+
+func (h *TransformHandler) WithGroup(name string) slog.Handler {
+	return &TransformHandler{handler: h.handler.WithGroup(name), transforms: h.transforms, group: joinKey(h.group, name)}
+}
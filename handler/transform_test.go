@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+// TestTransformHandler 测试重命名（含分组与 WithAttrs 绑定的属性）、从消息提取和派生属性
+func TestTransformHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewTransformHandler(slog.NewJSONHandler(&buf, nil), []TransformRule{
+		{Type: "rename", From: "uid", To: "user_id"},
+		{Type: "rename", From: "http.code", To: "status"},
+		{Type: "extract", Pattern: `order (?P<order_id>\d+) for (?P<customer>\w+)`},
+		{Type: "derive", From: "http.status", To: "status_class", Func: "status_class"},
+		{Type: "derive", From: "method", To: "method_lower", Func: "lower"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(h).With("uid", "u-1")
+	logger.Info("order 42 for alice failed",
+		"method", "POST",
+		slog.Group("http", slog.Int("code", 502)),
+	)
+	slog.New(h).WithGroup("http").Info("grouped", "status", 404)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	var first, second map[string]any
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(lines[1], &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first["user_id"] != "u-1" || first["uid"] != nil {
+		t.Errorf("bound attr should be renamed: %s", lines[0])
+	}
+	if http, _ := first["http"].(map[string]any); http["status"] != float64(502) || http["code"] != nil {
+		t.Errorf("grouped attr should be renamed in place: %s", lines[0])
+	}
+	if first["order_id"] != "42" || first["customer"] != "alice" || first["method_lower"] != "post" {
+		t.Errorf("extracted/derived attrs missing: %s", lines[0])
+	}
+	if first["status_class"] != "5xx" {
+		t.Errorf("rules run in order, derive should see the renamed http.status: %s", lines[0])
+	}
+	if http, _ := second["http"].(map[string]any); http["status_class"] != "4xx" {
+		t.Errorf("derive should resolve paths under WithGroup: %s", lines[1])
+	}
+
+	for _, rule := range []TransformRule{
+		{Type: "rename", From: "a"},
+		{Type: "extract", Pattern: `order (\d+)`},
+		{Type: "derive", From: "a", To: "b", Func: "nope"},
+		{Type: "drop"},
+	} {
+		if _, err := NewTransformHandler(slog.NewJSONHandler(&buf, nil), []TransformRule{rule}); err == nil {
+			t.Errorf("rule %+v should be rejected", rule)
+		}
+	}
+}
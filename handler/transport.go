@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultTransportTimeout 未配置时的请求超时
+const DefaultTransportTimeout = 5 * time.Second
+
+// TransportConfig 远程日志推送和Webhook共用的HTTP传输配置
+type TransportConfig struct {
+	Timeout            time.Duration // 单次请求超时，<=0 时为 DefaultTransportTimeout
+	CAFile             string        // 自定义CA证书（PEM），追加到系统信任链
+	CertFile           string        // 客户端证书（PEM），与 KeyFile 一起启用 mTLS
+	KeyFile            string        // 客户端私钥（PEM）
+	ServerName         string        // 覆盖TLS校验使用的服务器名称
+	InsecureSkipVerify bool          // 跳过证书校验，仅用于测试
+	Proxy              string        // http://、https:// 或 socks5:// 代理，为空时读取环境变量
+}
+
+// NewHTTPClient 按传输配置创建HTTP客户端，证书或代理配置无效时返回错误
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %s: no certificates found", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("cert_file and key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse proxy: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTransportTimeout
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestNewHTTPClientCustomCA 测试自定义CA与客户端证书（mTLS）
+func TestNewHTTPClientCustomCA(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+		}
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	// 测试服务器证书同时用作CA和客户端证书
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := http.Get(srv.URL); err == nil {
+		t.Fatal("default client should reject the self-signed certificate")
+	}
+
+	client, err := NewHTTPClient(TransportConfig{CAFile: certFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without client certificate, got %d", resp.StatusCode)
+	}
+
+	if _, err := NewHTTPClient(TransportConfig{CertFile: certFile}); err == nil {
+		t.Error("cert_file without key_file should fail")
+	}
+	if _, err := NewHTTPClient(TransportConfig{Proxy: "ftp://proxy:21"}); err == nil {
+		t.Error("unsupported proxy scheme should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ValueColors 按属性取值着色的规则：属性键 → 取值（不区分大小写）→ 颜色，取值 "*" 匹配其他取值
+type ValueColors map[string]map[string]*color.Color
+
+// DefaultValueColors 默认规则：cache 与 cache_status 的 HIT 为绿色、MISS 为黄色，其他取值为洋红色
+func DefaultValueColors() ValueColors {
+	cache := map[string]*color.Color{
+		"hit":  color.New(color.FgGreen),
+		"miss": color.New(color.FgYellow),
+		"*":    color.New(color.FgMagenta),
+	}
+	return ValueColors{"cache": cache, "cache_status": cache}
+}
+
+// match 返回属性取值对应的颜色
+func (v ValueColors) match(key, value string) (*color.Color, bool) {
+	rules, ok := v[key]
+	if !ok {
+		return nil, false
+	}
+	if c, ok := rules[strings.ToLower(value)]; ok {
+		return c, true
+	}
+	c, ok := rules["*"]
+	return c, ok
+}
+
+// ParseValueColors 解析配置中的着色规则并与默认规则合并，配置了的属性键整体替换其默认规则。
+// 颜色名称见 ParseColor
+func ParseValueColors(rules map[string]map[string]string) (ValueColors, error) {
+	result := DefaultValueColors()
+	for key, values := range rules {
+		parsed := make(map[string]*color.Color, len(values))
+		for value, spec := range values {
+			c, err := ParseColor(spec)
+			if err != nil {
+				return nil, fmt.Errorf("value_colors.%s.%s: %w", key, value, err)
+			}
+			parsed[strings.ToLower(value)] = c
+		}
+		result[key] = parsed
+	}
+	return result, nil
+}
+
+// colorAttributes 颜色与样式名称
+var colorAttributes = map[string]color.Attribute{
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi_black":   color.FgHiBlack,
+	"hi_red":     color.FgHiRed,
+	"hi_green":   color.FgHiGreen,
+	"hi_yellow":  color.FgHiYellow,
+	"hi_blue":    color.FgHiBlue,
+	"hi_magenta": color.FgHiMagenta,
+	"hi_cyan":    color.FgHiCyan,
+	"hi_white":   color.FgHiWhite,
+	"bold":       color.Bold,
+	"underline":  color.Underline,
+}
+
+// ParseColor 解析颜色名称：red、green、hi_red 等前景色，可用 + 连接 bold、underline 样式，如 red+bold
+func ParseColor(spec string) (*color.Color, error) {
+	var attrs []color.Attribute
+	for _, name := range strings.Split(spec, "+") {
+		attr, ok := colorAttributes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q", name)
+		}
+		attrs = append(attrs, attr)
+	}
+	return color.New(attrs...), nil
+}
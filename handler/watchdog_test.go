@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestWatchdogAlert 测试错误数达到阈值时触发告警且遵守冷却时间
+func TestWatchdogAlert(t *testing.T) {
+	wd := NewWatchdog(WatchdogConfig{Window: time.Minute, Threshold: 3, Cooldown: time.Hour})
+	alerts := make(chan WatchdogAlert, 4)
+	wd.OnAlert(func(a WatchdogAlert) { alerts <- a })
+
+	wd.Observe(slog.NewRecord(time.Now(), slog.LevelWarn, "warn only", 0))
+	for i := 0; i < 2; i++ {
+		wd.Observe(slog.NewRecord(time.Now(), slog.LevelError, "db down", 0))
+	}
+	select {
+	case <-alerts:
+		t.Fatal("alert fired before threshold was reached")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	wd.Observe(slog.NewRecord(time.Now(), slog.LevelError, "db down", 0))
+	select {
+	case a := <-alerts:
+		if a.Count != 3 || a.LastMessage != "db down" {
+			t.Errorf("unexpected alert: %+v", a)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected alert after threshold")
+	}
+
+	// 冷却期内不再重复告警
+	for i := 0; i < 5; i++ {
+		wd.Observe(slog.NewRecord(time.Now(), slog.LevelError, "db down", 0))
+	}
+	select {
+	case <-alerts:
+		t.Error("alert should respect cooldown")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+// TestWatchdogConfigureNotify 测试 Configure 替换配置产生的回调，OnAlert 注册的回调保留
+func TestWatchdogConfigureNotify(t *testing.T) {
+	alerts := make(chan string, 4)
+	wd := NewWatchdog(WatchdogConfig{Threshold: 1, Notify: []func(WatchdogAlert){
+		func(WatchdogAlert) { alerts <- "old" },
+	}})
+	wd.OnAlert(func(WatchdogAlert) { alerts <- "user" })
+	wd.Configure(WatchdogConfig{Threshold: 1, Notify: []func(WatchdogAlert){
+		func(WatchdogAlert) { alerts <- "new" },
+	}})
+
+	wd.Observe(slog.NewRecord(time.Now(), slog.LevelError, "db down", 0))
+	got := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case name := <-alerts:
+			got[name] = true
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 callbacks, got %v", got)
+		}
+	}
+	if !got["new"] || !got["user"] {
+		t.Errorf("expected the new and user callbacks, got %v", got)
+	}
+	select {
+	case name := <-alerts:
+		t.Errorf("replaced callback %q should not fire", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
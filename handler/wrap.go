@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// DetectWidth 检测输出所连接终端的宽度，不是终端时返回0（不折行）
+func DetectWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok {
+		return terminalWidth(f)
+	}
+	return 0
+}
+
+// runeWidth 字符在终端中占用的列数，中日韩全角字符占两列
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x1100:
+		return 1
+	case unicode.Is(unicode.Han, r), unicode.Is(unicode.Hangul, r),
+		unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r),
+		r >= 0x3000 && r <= 0x303F,   // 中日韩标点
+		r >= 0xFF00 && r <= 0xFF60,   // 全角字符
+		r >= 0x1F300 && r <= 0x1FAFF: // emoji
+		return 2
+	default:
+		return 1
+	}
+}
+
+// textWidth 字符串在终端中占用的列数
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// wrapText 将文本按终端宽度折行，首行已占用 used 列，续行缩进 indent 列，
+// 优先在空白处断开；width<=0 或空间不足时原样返回
+func wrapText(s string, used, indent, width int) []string {
+	if width <= 0 || used+textWidth(s) <= width || width-indent < 10 {
+		return []string{s}
+	}
+
+	var lines []string
+	var line strings.Builder
+	avail := width - used
+	col := 0
+	lastSpace := -1 // line 中最后一个空白之后的字节位置
+
+	for _, r := range s {
+		rw := runeWidth(r)
+		if col+rw > avail && line.Len() > 0 {
+			current := line.String()
+			rest := ""
+			// 在最近的空白处断开，避免截断单词
+			if lastSpace > 0 && lastSpace < len(current) {
+				rest = current[lastSpace:]
+				current = current[:lastSpace]
+			}
+			lines = append(lines, strings.TrimRight(current, " "))
+			line.Reset()
+			line.WriteString(rest)
+			col = textWidth(rest)
+			avail = width - indent
+			lastSpace = -1
+		}
+		line.WriteRune(r)
+		col += rw
+		if r == ' ' {
+			lastSpace = line.Len()
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
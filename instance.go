@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+)
+
+// Logmiao 日志系统句柄，便于在依赖注入的代码中传递；
+// Init、GetLogger、Stats、Close 等包级函数是默认句柄的薄封装。
+// 输出端、查看器等为进程级资源，同一时间只应有一个活动句柄
+type Logmiao struct {
+	mu         sync.RWMutex
+	cfg        *config.Config
+	logger     *slog.Logger
+	setDefault bool
+}
+
+// std 包级函数使用的默认句柄
+var std = &Logmiao{setDefault: true}
+
+// Option New 的可选项
+type Option func(*options)
+
+type options struct {
+	configPath     string
+	cfg            *config.Config
+	setDefault     bool
+	strict         bool
+	consoleOutput  io.Writer
+	consoleWriters []config.ConsoleWriter
+}
+
+// WithConfigFile 从指定文件或 http(s) 地址加载配置，默认为 configs/logger.yaml
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configPath = path
+	}
+}
+
+// WithConfig 直接使用已构造的配置
+func WithConfig(cfg *config.Config) Option {
+	return func(o *options) {
+		o.cfg = cfg
+	}
+}
+
+// WithStrictConfig 配置文件缺失、语法错误或包含未知配置项时 New 返回错误，而不是回退到默认配置
+func WithStrictConfig() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithoutSetDefault 不替换 slog 默认日志器，也不重定向Gin日志，日志只通过 Logger() 获取
+func WithoutSetDefault() Option {
+	return func(o *options) {
+		o.setDefault = false
+	}
+}
+
+// WithConsoleOutput 将控制台主输出写入 w 而不是 os.Stderr
+func WithConsoleOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.consoleOutput = w
+	}
+}
+
+// WithConsoleWriter 控制台输出同时写入 w，使用独立的格式（color、json、text），
+// 如测试中捕获 JSON 输出的缓冲区
+func WithConsoleWriter(name string, w io.Writer, format string) Option {
+	return func(o *options) {
+		o.consoleWriters = append(o.consoleWriters, config.ConsoleWriter{Name: name, Writer: w, Format: format})
+	}
+}
+
+// New 创建并启动日志系统，返回可注入的句柄：
+//
+//	lm, err := logger.New(ctx, logger.WithConfigFile("configs/logger.yaml"))
+//	defer lm.Shutdown(ctx)
+//	svc := NewService(lm.Logger())
+func New(ctx context.Context, opts ...Option) (*Logmiao, error) {
+	o := options{configPath: "configs/logger.yaml", setDefault: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cfg := o.cfg
+	if cfg == nil {
+		var err error
+		if config.IsRemotePath(o.configPath) {
+			cfg, err = config.LoadRemoteConfig(ctx, &config.HTTPSource{URL: o.configPath})
+			if err != nil {
+				return nil, err
+			}
+		} else if cfg, err = loadFileConfig(o.configPath, o.strict || strictConfig.Load()); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.consoleOutput != nil || len(o.consoleWriters) > 0 {
+		copied := *cfg
+		if o.consoleOutput != nil {
+			copied.Logger.Output.Console.Writer = o.consoleOutput
+		}
+		copied.Logger.Output.Console.Writers = append(append([]config.ConsoleWriter(nil), cfg.Logger.Output.Console.Writers...), o.consoleWriters...)
+		cfg = &copied
+	}
+
+	l := &Logmiao{setDefault: o.setDefault}
+	if err := l.apply(cfg); err != nil {
+		return nil, err
+	}
+	l.startFileWatch(cfg)
+	return l, nil
+}
+
+// apply 按配置重建日志器
+func (l *Logmiao) apply(cfg *config.Config) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	configureMu.Lock()
+	defer configureMu.Unlock()
+
+	oldSinks := sinkNames()
+	logger, err := configure(cfg, l.setDefault)
+	if err != nil {
+		return err
+	}
+	if l.cfg != nil {
+		logReload(logger, config.Diff(l.cfg, cfg), oldSinks, sinkNames())
+	} else {
+		logStartup(logger, cfg, sinkNames())
+	}
+	l.cfg = cfg
+	l.logger = logger
+	return nil
+}
+
+// lastReload 最近一次重新加载配置的时间
+var lastReload atomic.Pointer[time.Time]
+
+// logReload 记录重新加载带来的配置变化和输出端增减，并更新重新加载时间
+func logReload(logger *slog.Logger, changes []config.Change, oldSinks, newSinks []string) {
+	now := time.Now()
+	lastReload.Store(&now)
+
+	added, removed := diffNames(oldSinks, newSinks)
+	if len(changes) == 0 && len(added) == 0 && len(removed) == 0 {
+		logger.Info("Logger config reloaded", slog.Int("changes", 0))
+		return
+	}
+
+	attrs := make([]any, 0, len(changes))
+	for _, c := range changes {
+		attrs = append(attrs, slog.String(c.Path, c.Old+" -> "+c.New))
+	}
+	args := []any{slog.Int("changes", len(changes)), slog.Group("diff", attrs...)}
+	if len(added) > 0 {
+		args = append(args, slog.Any("sinks_added", added))
+	}
+	if len(removed) > 0 {
+		args = append(args, slog.Any("sinks_removed", removed))
+	}
+	logger.Info("Logger config reloaded", args...)
+}
+
+// sinkNames 返回应用日志及各通道当前的输出端名称
+func sinkNames() []string {
+	var names []string
+	for _, h := range Stats().Sinks {
+		names = append(names, h.Name)
+	}
+	return names
+}
+
+// diffNames 返回 b 相对 a 新增和移除的名称
+func diffNames(a, b []string) (added, removed []string) {
+	inA := make(map[string]bool, len(a))
+	for _, name := range a {
+		inA[name] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, name := range b {
+		inB[name] = true
+		if !inA[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range a {
+		if !inB[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+// Logger 返回句柄的日志器
+func (l *Logmiao) Logger() *slog.Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.logger == nil {
+		return slog.Default()
+	}
+	return l.logger
+}
+
+// With 返回附加公共属性的派生日志器
+func (l *Logmiao) With(attrs ...slog.Attr) *slog.Logger {
+	return deriveLogger(l.Logger(), attrs)
+}
+
+// WithGroup 返回将后续属性放入指定分组的派生日志器
+func (l *Logmiao) WithGroup(name string) *slog.Logger {
+	return l.Logger().WithGroup(name)
+}
+
+// Module 返回按模块级别过滤的派生日志器，见包级函数 Module
+func (l *Logmiao) Module(name string) *slog.Logger {
+	return l.With(fields.Module(name))
+}
+
+// Config 返回当前生效的配置
+func (l *Logmiao) Config() *config.Config {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.cfg
+}
+
+// Reconfigure 使用新配置重建输出端，旧的输出端在写完剩余记录后关闭
+func (l *Logmiao) Reconfigure(cfg *config.Config) error {
+	return l.apply(cfg)
+}
+
+// SetLevel 动态设置日志级别，见包级函数 SetLevel
+func (l *Logmiao) SetLevel(level slog.Level) {
+	SetLevel(level)
+}
+
+// Level 返回当前生效的日志级别
+func (l *Logmiao) Level() slog.Level {
+	return GetLevel()
+}
+
+// Stats 返回日志系统内部统计
+func (l *Logmiao) Stats() StatsSnapshot {
+	return Stats()
+}
+
+// Flush 写出已产生的日志，见包级函数 FlushContext
+func (l *Logmiao) Flush(ctx context.Context) error {
+	return FlushContext(ctx)
+}
+
+// Shutdown 关闭日志系统，ctx 结束时取消远程推送等网络输出端进行中的写出，不再等待剩余记录
+func (l *Logmiao) Shutdown(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- closeContext(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
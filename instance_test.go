@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+// TestConsoleWriters 测试控制台主输出替换和按独立格式写入的附加目标
+func TestConsoleWriters(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.Console = config.ConsoleConfig{Enabled: true, Format: "text"}
+
+	var text, captured bytes.Buffer
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault(),
+		WithConsoleOutput(&text), WithConsoleWriter("capture", &captured, "json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lm.Logger().Info("tee", "n", 1)
+	if err := lm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(text.String(), "msg=tee n=1") {
+		t.Errorf("text output = %q", text.String())
+	}
+	if !strings.Contains(captured.String(), `"msg":"tee","n":1`) {
+		t.Errorf("json output = %q", captured.String())
+	}
+	if cfg.Logger.Output.Console.Writer != nil {
+		t.Error("options should not modify the caller's config")
+	}
+}
+
+// TestNew 测试句柄创建、重新配置与关闭，WithoutSetDefault 不替换 slog 默认日志器
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.File = config.FileConfig{Enabled: true, Path: path, Format: "json"}
+
+	before := slog.Default()
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slog.Default() != before {
+		t.Error("WithoutSetDefault should keep slog.Default")
+	}
+
+	lm.Logger().Info("from handle")
+	debugCfg := *cfg
+	debugCfg.Logger.Level = "debug"
+	if err := lm.Reconfigure(&debugCfg); err != nil {
+		t.Fatal(err)
+	}
+	lm.Logger().Debug("after reconfigure")
+	if lm.Config().Logger.Level != "debug" {
+		t.Errorf("Config() = %q", lm.Config().Logger.Level)
+	}
+	if err := lm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "from handle") || !strings.Contains(string(data), "after reconfigure") {
+		t.Errorf("file output = %s", data)
+	}
+	if !strings.Contains(string(data), `"msg":"Logger started"`) || !strings.Contains(string(data), `"type":"startup","env":`) ||
+		!strings.Contains(string(data), `"config":"defaults","config_level":"info"`) || strings.Count(string(data), "Logger started") != 1 {
+		t.Errorf("startup report should be logged once, file output = %s", data)
+	}
+	if !strings.Contains(string(data), `"logger.level":"\"info\" -> \"debug\""`) {
+		t.Errorf("reload should log the level change, file output = %s", data)
+	}
+	if Stats().LastReload == nil {
+		t.Error("Stats().LastReload should be set after Reconfigure")
+	}
+}
+
+// TestReconfigureFailure 测试新配置无效时 Reconfigure 返回错误，全局配置、原日志器和异步队列保持不变
+func TestReconfigureFailure(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.File = config.FileConfig{Enabled: true, Format: "json", Path: logPath}
+	cfg.Logger.Output.Async = config.AsyncConfig{Enabled: true, Queue: config.BackpressureConfig{QueueSize: 64}}
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+
+	oldAsync, oldSinks := asyncHandler, sinkSupervisor
+	for want, mutate := range map[string]func(*config.Config){
+		"logger.features.privacy": func(c *config.Config) { c.Logger.Features.Privacy.ScrubPatterns = []string{"("} },
+		"logger.output.async":     func(c *config.Config) { c.Logger.Output.Async.Queue.Policy = "spill" },
+	} {
+		bad := *cfg
+		mutate(&bad)
+		if err := lm.Reconfigure(&bad); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected a %s error, got %v", want, err)
+		}
+		if GlobalConfig != cfg || config.GlobalConfig != cfg {
+			t.Errorf("a failed %s reconfigure should keep the global config", want)
+		}
+		if asyncHandler != oldAsync || sinkSupervisor != oldSinks {
+			t.Errorf("the old async queue and sinks should stay in place after a failed %s reconfigure", want)
+		}
+	}
+
+	lm.Logger().Info("after failed reconfigure")
+	if err := lm.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "after failed reconfigure") {
+		t.Errorf("old pipeline should keep writing after a failed reconfigure, file output = %s", data)
+	}
+}
+
+// TestHotReload 测试开启 hot_reload 后修改配置文件自动重建日志器并记录差异
+func TestHotReload(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+	cfgPath := filepath.Join(dir, "logger.yaml")
+	writeConfig := func(level string) {
+		content := "logger:\n  level: " + level + "\n  hot_reload: true\n  output:\n    console:\n      enabled: false\n" +
+			"    file:\n      enabled: true\n      format: json\n      path: " + logPath + "\n"
+		if err := os.WriteFile(cfgPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("info")
+
+	lm, err := New(context.Background(), WithConfigFile(cfgPath), WithoutSetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond) // 等待监听建立
+	writeConfig("debug")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for lm.Config().Logger.Level != "debug" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	lm.Logger().Debug("after hot reload")
+	if err := lm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"logger.level":"\"info\" -> \"debug\""`) || !strings.Contains(string(data), "after hot reload") {
+		t.Errorf("file change should be applied and logged, file output = %s", data)
+	}
+}
+
+// TestStrictConfig 测试严格模式下配置文件有问题时 New 返回错误而不是回退到默认配置
+func TestStrictConfig(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "logger.yaml")
+	if err := os.WriteFile(cfgPath, []byte("logger:\n  levle: debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(context.Background(), WithConfigFile(cfgPath), WithoutSetDefault(), WithStrictConfig()); err == nil || !strings.Contains(err.Error(), "levle") {
+		t.Errorf("strict mode should reject unknown keys, got %v", err)
+	}
+	if _, err := New(context.Background(), WithConfigFile(cfgPath+".missing"), WithoutSetDefault(), WithStrictConfig()); err == nil {
+		t.Error("strict mode should reject a missing config file")
+	}
+}
+
+// TestFlush 测试开启异步写出时 Flush 返回后记录已写入文件
+func TestFlush(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.File = config.FileConfig{Enabled: true, Format: "json", Path: logPath}
+	cfg.Logger.Output.Async = config.AsyncConfig{Enabled: true, Queue: config.BackpressureConfig{QueueSize: 64}}
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+
+	for i := 0; i < 20; i++ {
+		lm.Logger().Info("flushed record")
+	}
+	if err := lm.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "flushed record"); got != 20 {
+		t.Errorf("expected 20 records on disk after Flush, got %d", got)
+	}
+}
+
+// TestWarmup 测试输出端预热：fail 模式下初始化失败，warn 模式下输出降级警告且就绪探针返回 503
+func TestWarmup(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	// 日志文件路径是一个目录：所在目录可写，文件本身无法打开。
+	// 文件只接收 Error 记录，避免写入时轮转把目录移走
+	path := t.TempDir()
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.Console = config.ConsoleConfig{Enabled: true, Format: "text"}
+	cfg.Logger.Output.File = config.FileConfig{Enabled: true, Path: path, Format: "json"}
+	cfg.Logger.Output.Routes = []config.RouteConfig{{Level: "error+", Sinks: []string{"file"}}, {Level: "debug..warn", Sinks: []string{"console"}}}
+	cfg.Logger.Warmup = config.WarmupConfig{Mode: "fail", Timeout: time.Second}
+
+	if _, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault()); err == nil || !strings.Contains(err.Error(), "sink file") {
+		t.Fatalf("fail mode should reject the unwritable file, got %v", err)
+	}
+
+	cfg.Logger.Warmup.Mode = "warn"
+	var console bytes.Buffer
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault(), WithConsoleOutput(&console))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+	if !strings.Contains(console.String(), `msg="Log sink not ready, running degraded" sink=file`) {
+		t.Errorf("warn mode should log the degraded sink:\n%s", console.String())
+	}
+	if err := Ready(context.Background()); err == nil {
+		t.Error("Ready should report the unwritable file")
+	}
+
+	w := httptest.NewRecorder()
+	ReadyHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), `"name":"file","healthy":false`) {
+		t.Errorf("readiness = %d %s", w.Code, w.Body.String())
+	}
+}
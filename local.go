@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// localBuffers 尚未关闭的批量缓冲区，Flush 与 Close 时一并写出
+var localBuffers sync.Map // *handler.LocalBuffer → struct{}
+
+// LocalLogger 批量写出的日志器，见 Local
+type LocalLogger struct {
+	*slog.Logger
+	buf *handler.LocalBuffer
+}
+
+// Local 返回供单个goroutine在热循环中使用的日志器：记录先进入该goroutine独占的缓冲区，
+// 每256条、第一条缓冲后100ms或 Error 级别的记录到达时按顺序一次写出，大幅减少与其他goroutine的争用。
+// 循环结束后调用 Close 写出剩余记录；logger.Flush 与 logger.Close 也会写出所有未关闭的缓冲区
+//
+//	local := logger.Local()
+//	defer local.Close()
+//	for _, item := range items {
+//		local.Info("processed", "id", item.ID)
+//	}
+func Local() *LocalLogger {
+	return LocalWithOptions(handler.LocalConfig{})
+}
+
+// LocalWithOptions 与 Local 相同，使用指定的刷新条件
+func LocalWithOptions(cfg handler.LocalConfig) *LocalLogger {
+	buf := handler.NewLocalBuffer(cfg)
+	localBuffers.Store(buf, struct{}{})
+	return &LocalLogger{
+		Logger: slog.New(handler.NewLocalHandler(GetLogger().Handler(), buf)),
+		buf:    buf,
+	}
+}
+
+// Flush 写出缓冲的记录
+func (l *LocalLogger) Flush() error {
+	return l.buf.Flush()
+}
+
+// Close 写出剩余的记录，之后通过该日志器的记录直接写出
+func (l *LocalLogger) Close() error {
+	localBuffers.Delete(l.buf)
+	return l.buf.Close()
+}
+
+// flushLocal 写出所有未关闭的批量缓冲区
+func flushLocal() error {
+	var errs []error
+	localBuffers.Range(func(buf, _ any) bool {
+		errs = append(errs, buf.(*handler.LocalBuffer).Flush())
+		return true
+	})
+	return errors.Join(errs...)
+}
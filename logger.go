@@ -2,14 +2,22 @@ package logger
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gopkg.in/natefinch/lumberjack.v2"
 
 	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
 	"github.com/shuakami/logmiao/formatter"
 	"github.com/shuakami/logmiao/handler"
 	"github.com/shuakami/logmiao/middleware"
@@ -22,7 +30,8 @@ var (
 	GlobalConfig *config.Config
 )
 
-// Init 使用默认配置文件初始化日志系统
+// Init 使用默认配置文件初始化日志系统；可重复调用，也可在多个协程中并发调用，
+// 每次调用都会整体替换全局日志器与配置
 func Init(configPath ...string) error {
 	path := "configs/logger.yaml"
 	if len(configPath) > 0 && configPath[0] != "" {
@@ -31,134 +40,825 @@ func Init(configPath ...string) error {
 	return InitWithConfig(path)
 }
 
-// InitWithConfig 使用指定配置文件初始化日志系统
+// InitWithConfig 使用指定配置文件初始化日志系统，路径为 http(s) 地址时从远程获取并定期轮询；
+// 开启 hot_reload 时监听文件变化并自动重新加载
 func InitWithConfig(configPath string) error {
-	// 加载配置
-	cfg, err := config.LoadConfig(configPath)
+	if config.IsRemotePath(configPath) {
+		return InitWithRemote(&config.HTTPSource{URL: configPath}, config.DefaultRemoteInterval)
+	}
+
+	cfg, err := loadFileConfig(configPath, strictConfig.Load())
 	if err != nil {
-		// 使用默认配置
-		cfg = config.LoadConfigWithDefaults(configPath)
+		return err
 	}
-	GlobalConfig = cfg
 
-	// 初始化日志系统
+	stopRemoteWatch()
+	if err := applyConfig(cfg); err != nil {
+		return err
+	}
+	std.startFileWatch(cfg)
+	return nil
+}
+
+// strictConfig 严格模式，见 SetStrictConfig
+var strictConfig atomic.Bool
+
+// SetStrictConfig 开启严格模式后，Init 与 InitWithConfig 在配置文件缺失、语法错误或包含未知配置项时返回错误，
+// 而不是回退到默认配置。New 可使用 WithStrictConfig
+func SetStrictConfig(strict bool) {
+	strictConfig.Store(strict)
+}
+
+// loadFileConfig 加载配置文件。严格模式下先经 config.Check 校验，发现问题时返回错误；
+// 否则加载失败时回退到默认配置
+func loadFileConfig(path string, strict bool) (*config.Config, error) {
+	if strict {
+		if err := config.Check(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return config.LoadConfig(path)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		cfg = config.LoadConfigWithDefaults(path)
+	}
+	return cfg, nil
+}
+
+// applyConfig 根据配置创建日志器并设置为全局默认
+func applyConfig(cfg *config.Config) error {
+	return std.apply(cfg)
+}
+
+// configureMu 串行化日志系统的初始化与重新配置：Init、Reconfigure、远程热加载和多个句柄共用，
+// 并发调用时依次生效，最后完成的配置为最终状态
+var configureMu sync.Mutex
+
+// currentLogger 最近一次配置生效的日志器，供 GetLogger 并发读取
+var currentLogger atomic.Pointer[slog.Logger]
+
+// configure 根据配置创建日志器，setDefault 为 true 时同时替换 slog 默认日志器并重定向Gin日志；
+// 调用方需持有 configureMu
+func configure(cfg *config.Config, setDefault bool) (*slog.Logger, error) {
+	// 初始化日志系统，失败时全局配置保持不变
 	logger, err := createLogger(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	GlobalConfig = cfg
+	config.SetGlobalConfig(cfg)
 
 	// 设置为全局默认日志器
-	slog.SetDefault(logger)
+	if setDefault {
+		slog.SetDefault(logger)
+	}
 	GlobalLogger = logger
+	currentLogger.Store(logger)
 
-	// 重定向Gin日志
-	if cfg.Logger.Features.SmartFilter {
-		gin.DefaultWriter = handler.NewGinLogWriter(true)
-		gin.DefaultErrorWriter = handler.NewGinLogWriter(true)
+	// 独立日志通道
+	if err := setupChannels(cfg); err != nil {
+		return nil, err
 	}
 
-	return nil
+	// SLO 定期汇总
+	setupSLO(cfg)
+
+	// 重定向Gin日志（需显式开启）
+	if setDefault && cfg.Logger.Middleware.CaptureGinOutput {
+		CaptureGinOutput()
+	}
+
+	return logger, nil
 }
 
 // InitWithDefaults 使用默认配置初始化日志系统
 func InitWithDefaults() error {
-	cfg := config.LoadConfigWithDefaults("")
-	GlobalConfig = cfg
+	return applyConfig(config.LoadConfigWithDefaults(""))
+}
 
-	logger, err := createLogger(cfg)
+// createLogger 根据配置创建日志器
+func createLogger(cfg *config.Config) (*slog.Logger, error) {
+	// 所有输出端共用 logLevel，SetLevel 修改后立即生效；创建成功后才切换为新配置的级别。
+	// 配置了模块级别时，输出端以其中最低的级别为下限，具体模块的级别由 ModuleLevelHandler 判断
+	var level slog.Leveler = logLevel
+	coercers.Clear() // 输出端按新配置重建后重新登记
+	moduleLevels, err := parseModuleLevels(cfg.Logger.Levels)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if moduleLevels != nil {
+		level = moduleLevels
+	}
+	opts := &slog.HandlerOptions{
+		Level: level,
+	}
+	// 脱敏规则与异步队列参数在创建输出端之前检查，无效时正在使用的日志管线保持不变
+	scrubber, err := newScrubber(cfg.Logger.Features.Privacy)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Logger.Output.Async.Enabled {
+		if err := queueConfig(cfg.Logger.Output.Async.Queue).Validate(); err != nil {
+			return nil, fmt.Errorf("logger.output.async: %w", err)
+		}
 	}
 
-	slog.SetDefault(logger)
-	GlobalLogger = logger
-	return nil
+	// 1~2. 控制台与文件输出
+	sinks, err := outputSinks("", cfg.Logger.Output, opts, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 远程推送与Webhook共用的HTTP客户端
+	client, err := handler.NewHTTPClient(transportConfig(cfg.Logger.Transport))
+	if err != nil {
+		return nil, fmt.Errorf("logger.transport: %w", err)
+	}
+
+	// 3. Web查看器与远程推送
+	viewerSinks, err := setupViewer(cfg, level, client)
+	if err != nil {
+		return nil, err
+	}
+	sinks = append(sinks, viewerSinks...)
+
+	// 4. 由输出端管理器按顺序启动并按级别路由分发到各输出端
+	if len(sinks) == 0 {
+		// 如果没有配置任何输出，使用默认控制台处理器
+		sinks = append(sinks, handler.NewHandlerSink("console",
+			handler.NewColorHandler(os.Stderr, handler.SourceOptions(opts, handler.SourceShort)), handler.SinkOptions{}))
+	}
+	if sinks, err = routeSinks("", sinks, cfg.Logger.Output.Routes); err != nil {
+		return nil, err
+	}
+	sinks = handler.MonitorLatency(sinks, latencyConfig(cfg.Logger.Output.Latency))
+	supervisor := handler.NewSupervisor(sinks...)
+	if err := supervisor.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	// 预热：验证文件可写、远程端点可达，fail 模式下快速失败
+	notReady, err := warmupSinks(supervisor, cfg.Logger.Warmup)
+	if err != nil {
+		_ = supervisor.Close()
+		return nil, err
+	}
+	supervisor.Watch(sinkHealthInterval, logSinkHealth)
+	// 记录计数：位于输出端之外，统计实际写出的记录与写入失败
+	finalHandler := handler.NewMetricsHandler(supervisor.Handler(), recordMetrics)
+
+	// 记录转换：重命名、提取和派生属性，所有输出端看到相同的结果
+	if finalHandler, err = transformHandler(finalHandler, cfg.Logger.Transforms); err != nil {
+		_ = supervisor.Close()
+		return nil, err
+	}
+
+	// 记录大小上限：位于转换之外，截断前的属性仍可参与转换
+	recordBudget = handler.NewRecordBudget(cfg.Logger.MaxRecordSize)
+	finalHandler = handler.NewRecordBudgetHandler(finalHandler, recordBudget)
+
+	// 过滤规则：位于异步队列之外，被丢弃的记录不占用队列
+	if finalHandler, err = filterHandler(finalHandler, cfg.Logger.Filters); err != nil {
+		_ = supervisor.Close()
+		return nil, err
+	}
+
+	// 异步写出：输出处理器在后台协程中执行，队列满时按溢出策略处理
+	var async *handler.AsyncHandler
+	if cfg.Logger.Output.Async.Enabled {
+		if async, err = handler.NewAsyncHandler(finalHandler, queueConfig(cfg.Logger.Output.Async.Queue)); err != nil {
+			_ = supervisor.Close()
+			return nil, fmt.Errorf("logger.output.async: %w", err)
+		}
+		finalHandler = async
+	}
+
+	// 日志风暴：合并相似记录，位于看门狗等统计之内，使其仍能看到全部记录
+	var burst *handler.BurstHandler
+	if cfg.Logger.Features.Burst.Enabled {
+		finalHandler = handler.NewBurstHandler(finalHandler, handler.BurstConfig{
+			Threshold: cfg.Logger.Features.Burst.Threshold,
+			Window:    cfg.Logger.Features.Burst.Window,
+		})
+		burst, _ = finalHandler.(*handler.BurstHandler)
+	}
+
+	// 自动采样：按 route、tenant 等属性分别限速，一个嘈杂的键被降频时其他键保持完整
+	if cfg.Logger.Features.AutoSampling {
+		finalHandler = handler.NewKeyedSamplingHandler(finalHandler, handler.KeyedSamplingConfig{
+			Keys:       cfg.Logger.Features.Sampling.Keys,
+			Tick:       cfg.Logger.Features.Sampling.Tick,
+			First:      cfg.Logger.Features.Sampling.First,
+			Thereafter: cfg.Logger.Features.Sampling.Thereafter,
+		})
+	}
+
+	// 记录订阅：OnRecord 的回调只收到通过采样的记录
+	finalHandler = handler.NewTapHandler(finalHandler, recordTap)
+
+	// 发布版本：附加 SetBuildInfo 设置或自动检测的 release 属性
+	finalHandler = handler.NewAttrsFuncHandler(finalHandler, currentRelease)
+
+	// 消息模板：附加归一化的 msg_template，便于查看器和下游系统按模板聚合
+	if cfg.Logger.Features.MsgTemplate {
+		finalHandler = handler.NewMsgTemplateHandler(finalHandler)
+	}
+
+	// 错误堆栈：需在调用方goroutine中采集，因此位于异步处理器之外
+	if cfg.Logger.Features.ErrorStacks {
+		finalHandler = handler.NewErrorStackHandler(finalHandler, handler.DefaultStackDepth)
+	}
+
+	// 5. Baggage 传播：将请求上下文中的 Baggage 条目复制为属性
+	if cfg.Logger.Features.Baggage.Enabled {
+		finalHandler = handler.NewBaggageHandler(finalHandler,
+			cfg.Logger.Features.Baggage.Keys, cfg.Logger.Features.Baggage.Prefix, baggageExtractor)
+	}
+
+	// 6. 错误率看门狗与新错误检测
+	if cfg.Logger.Features.ErrorWatchdog.Enabled {
+		finalHandler = handler.NewWatchdogHandler(finalHandler, setupWatchdog(cfg, client))
+	}
+	if cfg.Logger.Features.NovelErrors.Enabled {
+		finalHandler = handler.NewNovelErrorHandler(finalHandler, setupNovelErrors(cfg, client))
+	}
+	if cfg.Logger.Features.SLO.Enabled {
+		finalHandler = handler.NewSLOHandler(finalHandler, sloTracker)
+	}
+
+	// 模块级别：位于定向调试之内，定向调试的记录不受模块级别限制
+	finalHandler = handler.NewModuleLevelHandler(finalHandler, moduleLevels)
+
+	// 7. 定向调试：命中规则的请求或记录以Debug级别输出
+	if cfg.Logger.Features.DebugTargeting.Enabled {
+		rules := make([]handler.DebugRule, 0, len(cfg.Logger.Features.DebugTargeting.Rules))
+		for _, rule := range cfg.Logger.Features.DebugTargeting.Rules {
+			rules = append(rules, handler.DebugRule{Key: rule.Key, Value: rule.Value})
+		}
+		finalHandler = handler.NewDebugTargetHandler(finalHandler, level, rules)
+	}
+
+	// 运行时级别覆盖：OverrideLevel 或 /api/levels 添加的规则在到期前放行命中的低级别记录
+	finalHandler = handler.NewLevelOverrideHandler(finalHandler, level, levelOverrides)
+
+	// 8. 现场转储：请求上下文绑定记录环时，所有级别的记录都写入记录环
+	if cfg.Logger.Middleware.Incident.Enabled {
+		finalHandler = handler.NewRingHandler(finalHandler)
+	}
+
+	// 9. 按键与按内容脱敏：位于最外层，记录环、订阅者和所有输出端都只看到脱敏后的值
+	finalHandler = handler.NewRedactHandler(finalHandler, cfg.Logger.Features.Privacy.RedactKeys)
+	finalHandler = handler.NewScrubHandler(finalHandler, scrubber)
+
+	// 新的处理链全部创建成功后才替换旧的管线：
+	// 旧的风暴汇总先写入旧的异步队列，队列写完剩余记录后再关闭旧的输出端
+	closeBurst()
+	closeAsync()
+	closeSinks(context.Background())
+	sinkSupervisor = supervisor
+	asyncHandler, burstHandler = async, burst
+	if async != nil {
+		startDropReport(async, cfg.Logger.Output.Async.DropReportInterval)
+	}
+
+	logLevel.Set(parseLogLevel(cfg.Logger.Level))
+	logger := slog.New(finalHandler)
+	logNotReady(logger, notReady)
+	return logger, nil
 }
 
-// createLogger 根据配置创建日志器
-func createLogger(cfg *config.Config) (*slog.Logger, error) {
-	var handlers []slog.Handler
+// outputSinks 根据输出配置创建控制台与文件输出端，应用日志和各通道共用，
+// prefix 用于区分通道输出端的名称，如 "access."
+func outputSinks(prefix string, out config.OutputConfig, opts *slog.HandlerOptions, cfg *config.Config) ([]handler.Sink, error) {
+	var sinks []handler.Sink
 
-	// 解析日志级别
-	level := parseLogLevel(cfg.Logger.Level)
-	opts := &slog.HandlerOptions{
-		Level:     level,
-		AddSource: true,
-	}
-
-	// 1. 创建控制台处理器
-	if cfg.Logger.Output.Console.Enabled {
-		var consoleHandler slog.Handler
-		switch cfg.Logger.Output.Console.Format {
-		case "color":
-			consoleHandler = handler.NewColorHandlerWithOptions(
-				os.Stderr,
-				opts,
-				cfg.Logger.Features.KeywordHighlight,
-				false, // 不使用紧凑模式
-			)
-		case "json":
-			consoleHandler = slog.NewJSONHandler(os.Stderr, opts)
-		default: // text
-			consoleHandler = slog.NewTextHandler(os.Stderr, opts)
+	// Resource 属性附加到所有机器可读的输出
+	resource := resourceAttrs(cfg)
+
+	// JSON输出的级别字段映射
+	severity, err := severityMapping(out.Severity)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 创建控制台处理器：主输出及代码中配置的附加目标，各自使用独立格式
+	if out.Console.Enabled {
+		levelTheme, err := consoleLevelTheme(out.Console)
+		if err != nil {
+			return nil, err
+		}
+		valueColors, err := handler.ParseValueColors(out.Console.ValueColors)
+		if err != nil {
+			return nil, fmt.Errorf("%soutput.console.%w", prefix, err)
 		}
+		consoleOpts := handler.SourceOptions(opts, handler.SourceFormat(out.Console.Source))
+		var primary io.Writer = os.Stderr
+		if out.Console.Writer != nil {
+			primary = out.Console.Writer
+		}
+		targets := append([]config.ConsoleWriter{{Writer: primary, Format: out.Console.Format}}, out.Console.Writers...)
 
-		// 如果启用了智能过滤，包装处理器
-		if cfg.Logger.Features.SmartFilter {
-			filterConfig := handler.FilterConfig{
-				IgnoreGinDebug:    true,
-				IgnoreHealthCheck: true,
-				MinLevel:          level,
+		for _, target := range targets {
+			if target.Writer == nil {
+				continue
+			}
+			format := target.Format
+			if format == "" {
+				format = out.Console.Format
+			}
+			var consoleHandler slog.Handler
+			switch format {
+			case "color":
+				colorHandler := handler.NewColorHandlerWithOptions(
+					target.Writer,
+					consoleOpts,
+					cfg.Logger.Features.KeywordHighlight,
+					false, // 不使用紧凑模式
+				)
+				colorHandler.SetPrettyJSON(out.Console.PrettyJSON)
+				colorHandler.SetWidth(out.Console.Width)
+				colorHandler.SetLevelTheme(levelTheme)
+				colorHandler.SetValueColors(valueColors)
+				consoleHandler = colorHandler
+			case "json":
+				consoleHandler = slog.NewJSONHandler(jsonWriter(target.Writer, cfg), handler.SeverityOptions(consoleOpts, severity)).WithAttrs(resource)
+			default: // text
+				consoleHandler = slog.NewTextHandler(target.Writer, consoleOpts).WithAttrs(resource)
 			}
-			consoleHandler = handler.NewSmartFilterHandler(consoleHandler, filterConfig)
-		}
 
-		handlers = append(handlers, consoleHandler)
+			// 如果启用了智能过滤，包装处理器，刷新和关闭输出端时输出尚未输出的去重汇总
+			var sinkOpts handler.SinkOptions
+			if cfg.Logger.Features.SmartFilter {
+				for _, name := range cfg.Logger.Features.SmartFilters {
+					if _, ok := handler.LookupFilter(name); !ok {
+						return nil, fmt.Errorf("logger.features.smart_filters: unknown filter %q", name)
+					}
+				}
+				filterConfig := handler.FilterConfig{
+					Filters:  cfg.Logger.Features.SmartFilters,
+					MinLevel: opts.Level,
+				}
+				filter := handler.NewSmartFilterHandler(consoleHandler, filterConfig)
+				consoleHandler = filter
+				sinkOpts = handler.SinkOptions{Flush: filter.Flush, Closer: filter}
+			}
+
+			name := prefix + "console"
+			if target.Name != "" {
+				name += "." + target.Name
+			}
+			sinks = append(sinks, handler.NewHandlerSink(name, consoleHandler, sinkOpts))
+		}
 	}
 
 	// 2. 创建文件处理器
-	if cfg.Logger.Output.File.Enabled {
+	if out.File.Enabled {
+		logPath, err := logFilePath(out.File.Path)
+		if err != nil {
+			return nil, err
+		}
+
 		// 确保日志目录存在
-		logDir := filepath.Dir(cfg.Logger.Output.File.Path)
+		logDir := filepath.Dir(logPath)
 		if err := os.MkdirAll(logDir, 0755); err != nil {
 			return nil, err
 		}
 
 		// 创建文件写入器（带轮转）
-		fileWriter := &lumberjack.Logger{
-			Filename:   cfg.Logger.Output.File.Path,
-			MaxSize:    cfg.Logger.Output.File.Rotation.MaxSize, // MB
-			MaxBackups: cfg.Logger.Output.File.Rotation.MaxBackups,
-			MaxAge:     cfg.Logger.Output.File.Rotation.MaxAge, // days
-			Compress:   cfg.Logger.Output.File.Rotation.Compress,
+		rotator := &lumberjack.Logger{
+			Filename:   logPath,
+			MaxSize:    out.File.Rotation.MaxSize, // MB
+			MaxBackups: out.File.Rotation.MaxBackups,
+			MaxAge:     out.File.Rotation.MaxAge, // days
+			Compress:   out.File.Rotation.Compress,
+		}
+
+		var fileWriter io.WriteCloser = rotator
+		if out.File.Shared {
+			if fileWriter, err = handler.NewSharedFile(rotator); err != nil {
+				return nil, fmt.Errorf("%soutput.file.shared: %w", prefix, err)
+			}
+		}
+		var position *handler.PositionWriter
+		if out.File.PositionFile != "" {
+			sidecar, err := logFilePath(out.File.PositionFile)
+			if err != nil {
+				return nil, fmt.Errorf("%soutput.file.position_file: %w", prefix, err)
+			}
+			position = handler.NewPositionWriter(fileWriter, logPath, sidecar, handler.DefaultPositionInterval)
+			fileWriter = position
+		}
+		if prefix == "" {
+			filePosition.Store(position)
 		}
 
+		fileOpts := handler.SourceOptions(opts, handler.SourceFormat(out.File.Source))
 		var fileHandler slog.Handler
-		switch cfg.Logger.Output.File.Format {
+		switch out.File.Format {
 		case "json":
-			fileHandler = slog.NewJSONHandler(fileWriter, opts)
+			w := io.Writer(fileWriter)
+			if out.File.TamperEvident {
+				w = handler.NewHashChainWriter(fileWriter, handler.LastChainHash(logPath))
+			}
+			fileHandler = slog.NewJSONHandler(jsonWriter(w, cfg), handler.SeverityOptions(fileOpts, severity))
 		default: // text
-			fileHandler = slog.NewTextHandler(fileWriter, opts)
+			fileHandler = slog.NewTextHandler(fileWriter, fileOpts)
+		}
+
+		fileHandler, err = coerceHandler(prefix+"file", fileHandler, out.File.Coerce)
+		if err != nil {
+			return nil, fmt.Errorf("%soutput.file: %w", prefix, err)
 		}
 
 		// 文件日志通常不需要智能过滤，保留所有信息用于调试
-		handlers = append(handlers, fileHandler)
+		sinks = append(sinks, handler.NewHandlerSink(prefix+"file", fileHandler.WithAttrs(resource), handler.SinkOptions{
+			Flush: func() error {
+				if err := syncFile(logPath); err != nil || position == nil {
+					return err
+				}
+				return position.Update()
+			},
+			Closer:  fileWriter,
+			Healthy: func() error { return dirWritable(logDir) },
+			Probe:   func(context.Context) error { return fileWritable(logPath) },
+		}))
+	}
+
+	return sinks, nil
+}
+
+// routeSinks 按 output.routes 为输出端附加级别路由，prefix 与 outputSinks 相同
+func routeSinks(prefix string, sinks []handler.Sink, routes []config.RouteConfig) ([]handler.Sink, error) {
+	parsed := make([]handler.Route, 0, len(routes))
+	for i, route := range routes {
+		levels, err := handler.ParseLevelRange(route.Level)
+		if err != nil {
+			return nil, fmt.Errorf("logger.output.routes[%d]: %w", i, err)
+		}
+		names := make([]string, len(route.Sinks))
+		for j, name := range route.Sinks {
+			names[j] = prefix + name
+		}
+		parsed = append(parsed, handler.Route{Levels: levels, Sinks: names})
+	}
+	routed, err := handler.RouteSinks(sinks, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("logger.output.routes: %w", err)
+	}
+	return routed, nil
+}
+
+// sinkHealthInterval 输出端健康检查间隔
+const sinkHealthInterval = 30 * time.Second
+
+// sinkSupervisor 当前应用日志使用的输出端管理器
+var sinkSupervisor *handler.Supervisor
+
+// closeSinks 刷新并关闭当前的输出端
+func closeSinks(ctx context.Context) {
+	if sinkSupervisor != nil {
+		_ = sinkSupervisor.CloseContext(ctx)
+		sinkSupervisor = nil
+	}
+}
+
+// latencyConfig 将配置转换为输出端耗时监控参数，连续超时时记录警告
+func latencyConfig(cfg config.LatencyConfig) handler.LatencyConfig {
+	return handler.LatencyConfig{
+		Budget:       cfg.Budget,
+		Strikes:      cfg.Strikes,
+		Action:       cfg.Action,
+		QueueSize:    cfg.QueueSize,
+		OpenDuration: cfg.OpenDuration,
+		OnSlow:       logSlowSink,
+	}
+}
+
+// logSlowSink 输出端连续超时时记录日志
+func logSlowSink(e handler.LatencyEvent) {
+	GetLogger().Warn("Log sink slow",
+		slog.String("sink", e.Sink),
+		slog.Duration("elapsed", e.Elapsed),
+		slog.Duration("budget", e.Budget),
+		slog.String("action", e.Action),
+	)
+}
+
+// logSinkHealth 输出端健康状态变化时记录日志
+func logSinkHealth(h handler.SinkHealth) {
+	if h.Healthy {
+		GetLogger().Info("Log sink recovered", slog.String("sink", h.Name))
+		return
+	}
+	GetLogger().Warn("Log sink unhealthy", slog.String("sink", h.Name), slog.String("error", h.Error))
+}
+
+// filePosition 应用日志文件的位置跟踪，未配置 output.file.position_file 时为nil
+var filePosition atomic.Pointer[handler.PositionWriter]
+
+// FilePosition 返回应用日志活动文件的路径、inode 与已写出的偏移量，未配置 output.file.position_file 时返回 false
+func FilePosition() (handler.FilePosition, bool) {
+	p := filePosition.Load()
+	if p == nil {
+		return handler.FilePosition{}, false
+	}
+	_ = p.Update() // 读取失败时返回上一次的位置
+	return p.Position(), true
+}
+
+// syncFile 将日志文件已写入的内容同步到磁盘。lumberjack 不缓冲写入，也不暴露文件句柄，
+// 因此另外打开同一文件调用 Sync，效果作用于整个文件
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // 尚未写入任何记录
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// dirWritable 检查日志目录是否存在且可写
+func dirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".logmiao-health-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// fileWritable 以追加方式打开日志文件，确认文件本身可写（如未被其他用户占有、不是只读文件）
+func fileWritable(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// burstHandler 当前使用的风暴汇总处理器，未启用时为nil
+var burstHandler *handler.BurstHandler
+
+// closeBurst 停止风暴汇总并输出尚未输出的汇总
+func closeBurst() {
+	if burstHandler != nil {
+		_ = burstHandler.Close()
+		burstHandler = nil
+	}
+}
+
+// asyncHandler 当前使用的异步处理器
+var asyncHandler *handler.AsyncHandler
+
+// stopDropReport 停止丢弃汇总并输出最后一次汇总，未启动时为nil
+var stopDropReport func()
+
+// closeAsync 关闭异步处理器并写出剩余记录
+func closeAsync() {
+	if asyncHandler != nil {
+		_ = asyncHandler.Close()
+		if stopDropReport != nil {
+			stopDropReport()
+			stopDropReport = nil
+		}
+		asyncHandler = nil
+	}
+}
+
+// startDropReport 按 interval 汇总异步队列丢弃的记录数，期间没有丢弃时不输出
+func startDropReport(h *handler.AsyncHandler, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	stopDropReport = func() {
+		cancel()
+		<-done
+	}
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var reported int64
+		for {
+			select {
+			case <-ctx.Done():
+				reportDrops(h, &reported)
+				return
+			case <-ticker.C:
+				reportDrops(h, &reported)
+			}
+		}
+	}()
+}
+
+// reportDrops 输出自上次汇总以来丢弃的记录数。汇总记录绕过队列同步写出，队列已满时也不会被丢弃
+func reportDrops(h *handler.AsyncHandler, reported *int64) {
+	stats := h.Stats()
+	dropped := stats.Dropped - *reported
+	if dropped <= 0 {
+		return
 	}
+	*reported = stats.Dropped
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "Dropped log records", 0)
+	r.AddAttrs(
+		slog.String("type", "async_drop"),
+		slog.Int64("dropped", dropped),
+		slog.Int64("dropped_total", stats.Dropped),
+		slog.String("policy", stats.Policy),
+		slog.Int("queue_size", stats.Cap),
+	)
+	_ = h.HandleSync(context.Background(), r)
+}
 
-	// 3. 创建多路分发处理器
-	if len(handlers) == 0 {
-		// 如果没有配置任何处理器，使用默认控制台处理器
-		handlers = append(handlers, handler.NewColorHandler(os.Stderr, opts))
+// queueConfig 将配置转换为队列参数
+func queueConfig(cfg config.BackpressureConfig) handler.QueueConfig {
+	return handler.QueueConfig{
+		Size:       cfg.QueueSize,
+		Policy:     handler.OverflowPolicy(cfg.Policy),
+		SampleRate: cfg.SampleRate,
 	}
+}
 
-	var finalHandler slog.Handler
-	if len(handlers) == 1 {
-		finalHandler = handlers[0]
-	} else {
-		finalHandler = NewMultiHandler(handlers...)
+// batchConfig 将配置文件中的组批配置转换为处理器选项
+func batchConfig(cfg config.BatchConfig) handler.BatchConfig {
+	return handler.BatchConfig{
+		MaxRecords:  cfg.MaxRecords,
+		MaxBytes:    cfg.MaxBytes,
+		MaxInterval: cfg.MaxInterval,
+		Concurrency: cfg.Concurrency,
 	}
+}
 
-	return slog.New(finalHandler), nil
+// retryConfig 将配置文件中的重试策略转换为处理器选项
+func retryConfig(cfg config.RetryConfig) handler.RetryConfig {
+	return handler.RetryConfig{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+		Multiplier:     cfg.Multiplier,
+		Jitter:         cfg.Jitter,
+	}
+}
+
+// severityMapping 根据配置生成JSON输出的级别映射
+func severityMapping(cfg config.SeverityConfig) (handler.SeverityMapping, error) {
+	var mapping handler.SeverityMapping
+	switch strings.ToLower(cfg.Profile) {
+	case "":
+	case "stackdriver", "gcp":
+		mapping = handler.StackdriverSeverity()
+	default:
+		return mapping, fmt.Errorf("unknown severity profile %q", cfg.Profile)
+	}
+
+	if cfg.Key != "" {
+		mapping.Key = cfg.Key
+	}
+	levels, err := handler.ParseSeverityLevels(cfg.Levels)
+	if err != nil {
+		return mapping, err
+	}
+	if len(levels) > 0 && mapping.Levels == nil {
+		mapping.Levels = make(map[slog.Level]string, len(levels))
+	}
+	for level, value := range levels {
+		mapping.Levels[level] = value
+	}
+	return mapping, nil
+}
+
+// consoleLevelTheme 根据配置返回控制台级别标签主题
+func consoleLevelTheme(cfg config.ConsoleConfig) (handler.LevelTheme, error) {
+	theme, ok := handler.LevelThemeByName(cfg.LevelTheme)
+	if !ok {
+		return theme, fmt.Errorf("unknown console level theme %q", cfg.LevelTheme)
+	}
+	labels, err := handler.ParseSeverityLevels(cfg.LevelLabels)
+	if err != nil {
+		return theme, err
+	}
+	return theme.WithLabels(labels), nil
+}
+
+// transportConfig 将配置文件中的传输配置转换为处理器选项
+func transportConfig(cfg config.TransportConfig) handler.TransportConfig {
+	return handler.TransportConfig{
+		Timeout:            cfg.Timeout,
+		CAFile:             cfg.CAFile,
+		CertFile:           cfg.CertFile,
+		KeyFile:            cfg.KeyFile,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		Proxy:              cfg.Proxy,
+	}
+}
+
+// jsonWriter 启用信封时包装JSON输出
+func jsonWriter(w io.Writer, cfg *config.Config) io.Writer {
+	if cfg.Logger.Output.Envelope.Enabled {
+		app := cfg.Logger.Output.Envelope.App
+		if app == "" {
+			app = cfg.Logger.Resource.Resolve()[config.ResourceServiceName]
+		}
+		return handler.NewEnvelopeWriter(w, app)
+	}
+	return w
+}
+
+// recordBudget 当前生效的记录大小上限，供 Stats 读取截断次数
+var recordBudget *handler.RecordBudget
+
+// coercers 各输出端的类型转换器，按输出端名称记录，供 Stats 汇总转换失败次数
+var coercers sync.Map
+
+// coerceHandler 按 coerce 配置包装输出端处理器，没有配置时返回原处理器
+func coerceHandler(name string, h slog.Handler, types map[string]string) (slog.Handler, error) {
+	if len(types) == 0 {
+		return h, nil
+	}
+	coercer, err := handler.NewCoercer(types)
+	if err != nil {
+		return nil, err
+	}
+	coercers.Store(name, coercer)
+	return handler.NewCoerceHandler(h, coercer), nil
+}
+
+// resourceAttrs 将 Resource 配置转换为日志属性
+func resourceAttrs(cfg *config.Config) []slog.Attr {
+	resource := cfg.Logger.Resource.Resolve()
+	attrs := make([]slog.Attr, 0, len(resource))
+	for _, key := range config.SortedKeys(resource) {
+		attrs = append(attrs, slog.String(key, resource[key]))
+	}
+	return attrs
+}
+
+// transformHandler 按 logger.transforms 包装记录转换处理器
+func transformHandler(h slog.Handler, transforms []config.TransformConfig) (slog.Handler, error) {
+	rules := make([]handler.TransformRule, 0, len(transforms))
+	for _, t := range transforms {
+		rules = append(rules, handler.TransformRule{Type: t.Type, From: t.From, To: t.To, Pattern: t.Pattern, Func: t.Func})
+	}
+	h, err := handler.NewTransformHandler(h, rules)
+	if err != nil {
+		return nil, fmt.Errorf("logger.transforms: %w", err)
+	}
+	return h, nil
+}
+
+// filterHandler 按 logger.filters 包装规则过滤处理器
+func filterHandler(h slog.Handler, filters []config.FilterConfig) (slog.Handler, error) {
+	rules := make([]handler.FilterRule, 0, len(filters))
+	for _, f := range filters {
+		rules = append(rules, handler.FilterRule{Message: f.Message, Attr: f.Attr, Value: f.Value, Level: f.Level, Action: f.Action, To: f.To})
+	}
+	h, err := handler.NewRuleFilterHandler(h, rules)
+	if err != nil {
+		return nil, fmt.Errorf("logger.filters: %w", err)
+	}
+	return h, nil
+}
+
+// scrubHandler 按 features.privacy.scrub 与 scrub_patterns 包装内容扫描脱敏处理器
+func scrubHandler(h slog.Handler, cfg config.PrivacyConfig) (slog.Handler, error) {
+	scrubber, err := newScrubber(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return handler.NewScrubHandler(h, scrubber), nil
+}
+
+// newScrubber 编译内置与自定义的按内容脱敏规则，未配置时返回nil
+func newScrubber(cfg config.PrivacyConfig) (*handler.Scrubber, error) {
+	scrubber, err := handler.NewScrubber(cfg.Scrub, cfg.ScrubPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("logger.features.privacy: %w", err)
+	}
+	return scrubber, nil
+}
+
+// parseModuleLevels 解析 logger.levels，未配置时返回nil；模块级别以 logLevel 为基准
+func parseModuleLevels(levels map[string]string) (*handler.ModuleLevels, error) {
+	if len(levels) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]slog.Level, len(levels))
+	for module, name := range levels {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("logger.levels.%s: %w", module, err)
+		}
+		parsed[module] = level
+	}
+	return &handler.ModuleLevels{Base: logLevel, Levels: parsed}, nil
 }
 
 // parseLogLevel 解析日志级别字符串
@@ -180,7 +880,20 @@ func parseLogLevel(levelStr string) slog.Level {
 // PrintBanner 打印应用启动横幅
 func PrintBanner(appName, version string) {
 	if GlobalConfig != nil {
-		formatter.PrintBanner(appName, version, GlobalConfig)
+		releaseVersion, commit := BuildInfo()
+		formatter.PrintBannerWithRelease(appName, version, GlobalConfig,
+			formatter.Release{Version: releaseVersion, Commit: commit})
+		if GlobalConfig.Logger.StructuredBanner {
+			logBanner(GetLogger(), appName, version, GlobalConfig, releaseVersion, commit)
+		}
+	}
+}
+
+// PrintHealthCheck 打印健康检查信息，开启 structured_banner 时同时输出 type=health 的结构化记录
+func PrintHealthCheck(status string, details map[string]interface{}) {
+	formatter.PrintHealthCheck(status, details)
+	if GlobalConfig != nil && GlobalConfig.Logger.StructuredBanner {
+		logHealth(GetLogger(), status, details)
 	}
 }
 
@@ -189,6 +902,17 @@ func PrintStartupSuccess(port string) {
 	formatter.PrintStartupSuccess(port)
 }
 
+// CaptureGinOutput 将 gin.DefaultWriter 和 gin.DefaultErrorWriter 重定向到日志系统（过滤调试输出），
+// 返回的函数恢复之前的写入器
+func CaptureGinOutput() (restore func()) {
+	prevOut, prevErr := gin.DefaultWriter, gin.DefaultErrorWriter
+	gin.DefaultWriter = handler.NewGinLogWriter(true)
+	gin.DefaultErrorWriter = handler.NewGinLogWriter(true)
+	return func() {
+		gin.DefaultWriter, gin.DefaultErrorWriter = prevOut, prevErr
+	}
+}
+
 // GinMiddleware 返回Gin框架的日志中间件
 func GinMiddleware() gin.HandlerFunc {
 	return middleware.GinMiddleware()
@@ -204,11 +928,100 @@ func RequestID() gin.HandlerFunc {
 	return middleware.RequestID()
 }
 
+// RequestIDWithConfig 返回带配置的请求ID中间件
+func RequestIDWithConfig(cfg middleware.RequestIDConfig) gin.HandlerFunc {
+	return middleware.RequestIDWithConfig(cfg)
+}
+
 // Recovery 返回带日志记录的恢复中间件
 func Recovery() gin.HandlerFunc {
 	return middleware.Recovery()
 }
 
+// MarkHandlerStart 返回标记处理函数开始时间的中间件，注册在所有中间件之后，
+// 使 phase_timings 能区分中间件链与处理函数的耗时
+func MarkHandlerStart() gin.HandlerFunc {
+	return middleware.MarkHandlerStart()
+}
+
+// Tenant 返回租户提取中间件，租户标识绑定到访问日志和请求上下文日志器
+func Tenant() gin.HandlerFunc {
+	return middleware.Tenant()
+}
+
+// TenantWithConfig 返回带配置的租户提取中间件
+func TenantWithConfig(cfg middleware.TenantConfig) gin.HandlerFunc {
+	return middleware.TenantWithConfig(cfg)
+}
+
+// Session 返回会话生命周期日志中间件，记录会话开始/过期并附加脱敏的 session_id
+func Session() gin.HandlerFunc {
+	return middleware.Session()
+}
+
+// SessionWithConfig 返回带配置的会话生命周期日志中间件
+func SessionWithConfig(cfg middleware.SessionConfig) gin.HandlerFunc {
+	return middleware.SessionWithConfig(cfg)
+}
+
+// Audit 返回审计中间件，变更类请求的操作者、资源、动作和前后差异写入 audit 通道
+func Audit() gin.HandlerFunc {
+	return middleware.Audit()
+}
+
+// AuditWithConfig 返回带配置的审计中间件
+func AuditWithConfig(cfg middleware.AuditConfig) gin.HandlerFunc {
+	return middleware.AuditWithConfig(cfg)
+}
+
+// Incident 返回 5xx 现场转储中间件，需启用 middleware.incident 才会保留 Debug 记录
+func Incident() gin.HandlerFunc {
+	return middleware.Incident()
+}
+
+// IncidentWithConfig 返回带配置的现场转储中间件
+func IncidentWithConfig(cfg middleware.IncidentConfig) gin.HandlerFunc {
+	return middleware.IncidentWithConfig(cfg)
+}
+
+// HAR 返回失败请求 HAR 导出中间件
+func HAR() gin.HandlerFunc {
+	return middleware.HAR()
+}
+
+// HARWithConfig 返回带配置的失败请求 HAR 导出中间件
+func HARWithConfig(cfg middleware.HARConfig) gin.HandlerFunc {
+	return middleware.HARWithConfig(cfg)
+}
+
+// Baggage 返回解析 W3C baggage 请求头的中间件，配合 features.baggage 使用
+func Baggage() gin.HandlerFunc {
+	return middleware.Baggage()
+}
+
+// baggageExtractor 自定义的 Baggage 提取函数，为nil时使用 Baggage 中间件解析的结果
+var baggageExtractor handler.BaggageExtractor
+
+// SetBaggageExtractor 设置 Baggage 提取函数，例如接入 OpenTelemetry:
+//
+//	logger.SetBaggageExtractor(func(ctx context.Context) map[string]string {
+//		m := map[string]string{}
+//		for _, member := range baggage.FromContext(ctx).Members() {
+//			m[member.Key()] = member.Value()
+//		}
+//		return m
+//	})
+//
+// 需在 Init 之前调用
+func SetBaggageExtractor(extract handler.BaggageExtractor) {
+	baggageExtractor = extract
+}
+
+// DebugTarget 返回定向调试中间件，携带有效调试令牌的请求以Debug级别记录日志
+func DebugTarget() gin.HandlerFunc {
+	return middleware.DebugTarget()
+}
+
 // Error 创建错误属性，用于结构化错误记录
 func Error(err error) slog.Attr {
 	if err == nil {
@@ -228,7 +1041,8 @@ func ErrorWithStack(err error, stack string) slog.Attr {
 	)
 }
 
-// MultiHandler 多路分发处理器
+// MultiHandler 多路分发处理器，记录只在分发前 Clone 一次后由各处理器共享，
+// 分发过程不加锁，写出的串行化由各处理器自行负责
 type MultiHandler struct {
 	handlers []slog.Handler
 }
@@ -250,11 +1064,12 @@ func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
 	// 将记录分发给所有处理器
-	for _, handler := range h.handlers {
-		if handler.Enabled(ctx, r.Level) {
-			// 克隆记录以避免并发问题
-			recordClone := r.Clone()
-			if err := handler.Handle(ctx, recordClone); err != nil {
+	targeted := handler.IsDebugTargeted(ctx)
+	// Clone 截断属性切片的容量，任一处理器追加属性都会重新分配，因此克隆一次即可共享
+	r = r.Clone()
+	for _, hd := range h.handlers {
+		if targeted || hd.Enabled(ctx, r.Level) {
+			if err := hd.Handle(ctx, r); err != nil {
 				// 记录处理错误，但继续处理其他处理器
 				slog.Default().Error("Handler error", "error", err)
 			}
@@ -281,29 +1096,122 @@ func (h *MultiHandler) WithGroup(name string) slog.Handler {
 
 // GetLogger 获取当前的日志器实例
 func GetLogger() *slog.Logger {
+	if l := currentLogger.Load(); l != nil {
+		return l
+	}
 	if GlobalLogger != nil {
 		return GlobalLogger
 	}
 	return slog.Default()
 }
 
-// SetLevel 动态设置日志级别
+// With 返回附加公共属性的派生日志器，适合为组件创建专用日志器：
+//
+//	dbLog := logger.With(slog.String("component", "db"))
+func With(attrs ...slog.Attr) *slog.Logger {
+	return deriveLogger(GetLogger(), attrs)
+}
+
+// Module 返回带 module 属性的派生日志器，其级别由 logger.levels 中对应的模块决定：
+//
+//	dbLog := logger.Module("database")
+func Module(name string) *slog.Logger {
+	return deriveLogger(GetLogger(), []slog.Attr{fields.Module(name)})
+}
+
+// WithGroup 返回将后续属性放入指定分组的派生日志器
+func WithGroup(name string) *slog.Logger {
+	return GetLogger().WithGroup(name)
+}
+
+// deriveLogger 以属性派生日志器
+func deriveLogger(l *slog.Logger, attrs []slog.Attr) *slog.Logger {
+	if len(attrs) == 0 {
+		return l
+	}
+	return slog.New(l.Handler().WithAttrs(attrs))
+}
+
+// logLevel 应用日志及未单独设置级别的通道共用的级别，
+// 控制台、文件、查看器等处理器都引用它，修改后无需重建处理器即可生效
+var logLevel = new(slog.LevelVar)
+
+// SetLevel 动态设置日志级别，立即作用于所有输出端；重新加载配置时恢复为配置中的级别
 func SetLevel(level slog.Level) {
-	// 注意：slog的处理器级别在创建时设定，无法动态修改
-	// 这里提供接口，实际实现需要根据具体需求调整
-	slog.Info("Log level change requested", slog.String("new_level", level.String()))
+	old := logLevel.Level()
+	logLevel.Set(level)
+	if old != level {
+		GetLogger().Info("Log level changed", slog.String("old_level", old.String()), slog.String("new_level", level.String()))
+	}
+}
+
+// GetLevel 返回当前生效的日志级别
+func GetLevel() slog.Level {
+	return logLevel.Level()
 }
 
-// Flush 刷新所有处理器的缓冲区
-func Flush() {
-	// 对于lumberjack等文件写入器，可能需要手动刷新
-	// 这里提供接口，实际实现依赖于具体的处理器类型
-	slog.Debug("Logger flush requested")
+// Flush 写出已产生的日志：等待异步队列和远程推送发送完成，并将日志文件同步到磁盘，
+// 最长等待 logger.shutdown_timeout
+func Flush() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+	return FlushContext(ctx)
 }
 
-// Close 关闭日志系统，释放资源
+// FlushContext 与 Flush 相同，由 ctx 限定等待时间
+func FlushContext(ctx context.Context) error {
+	configureMu.Lock()
+	defer configureMu.Unlock()
+
+	errs := []error{flushLocal()}
+	if burstHandler != nil {
+		burstHandler.Flush()
+	}
+	if asyncHandler != nil {
+		errs = append(errs, asyncHandler.Flush(ctx))
+	}
+	if sinkSupervisor != nil {
+		errs = append(errs, sinkSupervisor.FlushContext(ctx))
+	}
+	channelsMu.RLock()
+	for _, s := range channelSupervisors {
+		errs = append(errs, s.FlushContext(ctx))
+	}
+	channelsMu.RUnlock()
+	return errors.Join(errs...)
+}
+
+// Close 关闭日志系统，写出剩余的记录并释放文件等资源，最长等待 logger.shutdown_timeout
 func Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+	return std.Shutdown(ctx)
+}
+
+// shutdownTimeout 返回 Flush 与 Close 的最长等待时间
+func shutdownTimeout() time.Duration {
+	if cfg := GlobalConfig; cfg != nil && cfg.Logger.ShutdownTimeout > 0 {
+		return cfg.Logger.ShutdownTimeout
+	}
+	return defaultShutdownTimeout
+}
+
+// defaultShutdownTimeout 未配置 shutdown_timeout 时 Flush 与 Close 的最长等待时间
+const defaultShutdownTimeout = 5 * time.Second
+
+// closeContext 关闭日志系统，ctx 结束时网络输出端放弃剩余的写出，不再等待无响应的接收端
+func closeContext(ctx context.Context) error {
 	slog.Info("Logger is shutting down")
-	// 这里可以添加清理逻辑，比如关闭文件句柄等
-	return nil
+	configureMu.Lock()
+	defer configureMu.Unlock()
+	stopRemoteWatch()
+	stopFileWatch()
+	stopSLOSummary()
+	_ = flushLocal()
+	closeBurst()
+	closeAsync()
+	closeSinks(ctx)
+	closeChannelSinks(ctx)
+	closeViewer(ctx)
+	return ctx.Err()
 }
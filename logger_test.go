@@ -1,10 +1,22 @@
 package logger
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
 )
 
 // TestInitWithDefaults 测试默认初始化
@@ -45,6 +57,238 @@ func TestParseLogLevel(t *testing.T) {
 	}
 }
 
+// TestSetLevel 测试 SetLevel 立即作用于已创建的输出端，重新加载配置后恢复配置中的级别
+func TestSetLevel(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	var text, captured bytes.Buffer
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Features.SmartFilter = true
+	cfg.Logger.Output.Console = config.ConsoleConfig{Enabled: true, Format: "text"}
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault(),
+		WithConsoleOutput(&text), WithConsoleWriter("capture", &captured, "json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+
+	lm.Logger().Debug("before SetLevel")
+	SetLevel(slog.LevelDebug)
+	if GetLevel() != slog.LevelDebug || lm.Level() != slog.LevelDebug {
+		t.Errorf("GetLevel() = %v", GetLevel())
+	}
+	lm.Logger().Debug("after SetLevel")
+
+	for name, out := range map[string]string{"text": text.String(), "json": captured.String()} {
+		if strings.Contains(out, "before SetLevel") || !strings.Contains(out, "after SetLevel") {
+			t.Errorf("%s output should only contain the debug record after SetLevel:\n%s", name, out)
+		}
+	}
+
+	if err := lm.Reconfigure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if GetLevel() != slog.LevelInfo {
+		t.Errorf("Reconfigure should restore the configured level, got %v", GetLevel())
+	}
+}
+
+// TestModuleLevels 测试 logger.levels 配置的模块级别作用于全部输出端
+func TestModuleLevels(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	var captured bytes.Buffer
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Levels = map[string]string{"database": "debug", "http": "warn"}
+	cfg.Logger.Features.SmartFilter = true
+	cfg.Logger.Output.Console = config.ConsoleConfig{Enabled: true, Format: "text"}
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault(),
+		WithConsoleOutput(io.Discard), WithConsoleWriter("capture", &captured, "json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+
+	Module("database").Debug("db debug")
+	lm.Module("http").Info("http info")
+	lm.Logger().Debug("base debug")
+	lm.Logger().Info("base info")
+
+	out := captured.String()
+	for msg, want := range map[string]bool{"db debug": true, "http info": false, "base debug": false, "base info": true} {
+		if strings.Contains(out, msg) != want {
+			t.Errorf("%q present = %v, want %v:\n%s", msg, !want, want, out)
+		}
+	}
+	if !strings.Contains(out, `"module":"database"`) {
+		t.Errorf("module attr missing: %s", out)
+	}
+
+	cfg.Logger.Levels = map[string]string{"database": "verbose"}
+	if err := lm.Reconfigure(cfg); err == nil {
+		t.Error("invalid module level should be rejected")
+	}
+}
+
+// blockingWriter 在 release 关闭前阻塞写入
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+// TestReportDrops 测试非阻塞队列满时丢弃记录，并由汇总记录报告丢弃数量
+func TestReportDrops(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	async, _ := handler.NewAsyncHandler(slog.NewJSONHandler(w, nil), handler.QueueConfig{Size: 1, Policy: handler.PolicyDropNewest})
+	defer async.Close()
+
+	logger := slog.New(async)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			logger.Info("burst")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drop_newest should not block the caller")
+	}
+
+	dropped := async.Stats().Dropped
+	if dropped < 3 {
+		t.Fatalf("Dropped = %d, want at least 3", dropped)
+	}
+	close(w.release)
+
+	var reported int64
+	reportDrops(async, &reported)
+	reportDrops(async, &reported) // 没有新的丢弃时不重复输出
+
+	w.mu.Lock()
+	out := w.buf.String()
+	w.mu.Unlock()
+	if strings.Count(out, "Dropped log records") != 1 {
+		t.Fatalf("expected one drop summary:\n%s", out)
+	}
+	if !strings.Contains(out, fmt.Sprintf(`"dropped":%d`, dropped)) {
+		t.Errorf("summary should report %d dropped records:\n%s", dropped, out)
+	}
+}
+
+// TestStructuredBanner 测试横幅与健康检查的结构化记录
+func TestStructuredBanner(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	cfg := config.LoadConfigWithDefaults("")
+
+	logBanner(logger, "My API", "1.0.0", cfg, "", "abc123")
+	logHealth(logger, "degraded", map[string]interface{}{"db": "down", "cache": "ok"})
+
+	out := buf.String()
+	for _, want := range []string{
+		`"type":"banner","app":"My API","version":"1.0.0"`,
+		`"config_level":"info"`,
+		`"commit":"abc123"`,
+		`"type":"health","status":"degraded","details":{"cache":"ok","db":"down"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"release"`) {
+		t.Errorf("empty release should be omitted:\n%s", out)
+	}
+	if strings.Count(out, `"level":`) != 2 {
+		t.Errorf("each record should carry a single level key:\n%s", out)
+	}
+}
+
+// TestWriteMetrics 测试 Prometheus 文本格式的计数导出
+func TestWriteMetrics(t *testing.T) {
+	before := Stats().Records["error"]
+	slog.New(handler.NewMetricsHandler(slog.NewJSONHandler(io.Discard, nil), recordMetrics)).Error("boom")
+
+	var buf bytes.Buffer
+	if err := WriteMetrics(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE logmiao_records_total counter\n",
+		fmt.Sprintf("logmiao_records_total{level=\"error\"} %d\n", before+1),
+		"logmiao_dropped_total{stage=\"async\"} ",
+		"logmiao_handler_errors_total ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel = %s", got)
+	}
+}
+
+// TestLevelOverrideAPI 测试通过 /api/levels 添加的覆盖规则放开指定模块的 Debug 记录，删除后恢复
+func TestLevelOverrideAPI(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	var captured bytes.Buffer
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.Console = config.ConsoleConfig{Enabled: true, Format: "text"}
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault(),
+		WithConsoleOutput(io.Discard), WithConsoleWriter("capture", &captured, "json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lm.Shutdown(context.Background())
+
+	api := LevelOverrideHandler()
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/levels",
+		strings.NewReader(`{"key":"module","value":"payments","level":"debug","ttl":"10m"}`)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"value":"payments"`) {
+		t.Fatalf("POST: %d %s", rec.Code, rec.Body.String())
+	}
+	lm.Logger().With("module", "payments").Debug("payments debug")
+	lm.Logger().With("module", "orders").Debug("orders debug")
+
+	rec = httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/levels?key=module&value=payments", nil))
+	if rec.Code != http.StatusOK || len(LevelOverrides()) != 0 {
+		t.Fatalf("DELETE: %d %s", rec.Code, rec.Body.String())
+	}
+	lm.Logger().With("module", "payments").Debug("after delete")
+
+	out := captured.String()
+	if !strings.Contains(out, "payments debug") || strings.Contains(out, "orders debug") || strings.Contains(out, "after delete") {
+		t.Errorf("override should only apply to module=payments while active:\n%s", out)
+	}
+
+	for _, body := range []string{`{"value":"x"}`, `{"key":"module","level":"loud"}`, `{"key":"module","ttl":"-1m"}`} {
+		rec = httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/levels", strings.NewReader(body)))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
 // TestErrorAttr 测试错误属性创建
 func TestErrorAttr(t *testing.T) {
 	// 测试 nil 错误
@@ -147,3 +391,126 @@ func BenchmarkJSONHandler(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkMultiHandler 多路分发性能测试：彩色控制台与 JSON 文件同时输出
+func BenchmarkMultiHandler(b *testing.B) {
+	logger := slog.New(NewMultiHandler(
+		handler.NewColorHandler(io.Discard, nil),
+		slog.NewJSONHandler(io.Discard, nil),
+	))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			logger.Info("Benchmark test message",
+				slog.String("key1", "value1"),
+				slog.Int("key2", 42),
+				slog.Duration("key3", 100),
+				slog.String("key4", "value4"),
+				slog.String("key5", "value5"),
+				slog.Bool("key6", true),
+			)
+		}
+	})
+}
+
+// TestEventBuilder 测试链式日志事件
+func TestEventBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := WithContext(context.Background(), l)
+
+	Event(ctx, "user.login").
+		Str("user_id", "42").
+		Dur("latency", 15*time.Millisecond).
+		Err(errors.New("boom")).
+		Send()
+
+	// 未启用的级别返回nil，链式调用不会panic
+	DebugEvent(ctx, "skipped").Str("k", "v").Send()
+
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"user.login"`) || !strings.Contains(out, `"user_id":"42"`) || !strings.Contains(out, `"error":"boom"`) {
+		t.Errorf("unexpected event output: %s", out)
+	}
+	if strings.Contains(out, "skipped") {
+		t.Error("disabled debug event should not be written")
+	}
+}
+
+// TestSetBuildInfo 测试 release 属性附加到记录
+func TestSetBuildInfo(t *testing.T) {
+	SetBuildInfo("1.4.2", "9f1c2ab")
+	defer SetBuildInfo("", "")
+
+	if version, commit := BuildInfo(); version != "1.4.2" || commit != "9f1c2ab" {
+		t.Errorf("BuildInfo() = %q, %q", version, commit)
+	}
+
+	var buf bytes.Buffer
+	l := slog.New(handler.NewAttrsFuncHandler(slog.NewJSONHandler(&buf, nil), currentRelease))
+	l.Info("deployed")
+	if !strings.Contains(buf.String(), `"release":{"version":"1.4.2","commit":"9f1c2ab"}`) {
+		t.Errorf("record should carry release attrs: %s", buf.String())
+	}
+}
+
+// TestInitConcurrent 测试并发和重复初始化依次生效，不产生数据竞争
+func TestInitConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := InitWithDefaults(); err != nil {
+				t.Error(err)
+			}
+			GetLogger().Debug("concurrent init")
+		}()
+	}
+	wg.Wait()
+
+	if GetLogger() != GlobalLogger || GlobalConfig == nil || config.GlobalConfig != GlobalConfig {
+		t.Error("globals should reflect the last applied config")
+	}
+}
+
+// TestCaptureGinOutput 测试默认不替换 Gin 输出，显式调用时重定向并可恢复
+func TestCaptureGinOutput(t *testing.T) {
+	original := gin.DefaultWriter
+	if err := InitWithDefaults(); err != nil {
+		t.Fatal(err)
+	}
+	if gin.DefaultWriter != original {
+		t.Fatal("Init should not replace gin.DefaultWriter unless capture_gin_output is set")
+	}
+
+	restore := CaptureGinOutput()
+	if _, ok := gin.DefaultWriter.(*handler.GinLogWriter); !ok {
+		t.Errorf("gin.DefaultWriter = %T, want *handler.GinLogWriter", gin.DefaultWriter)
+	}
+	restore()
+	if gin.DefaultWriter != original {
+		t.Error("restore should reinstate the previous writer")
+	}
+}
+
+// TestResourceAttrs 测试 Resource 属性按键名排序附加，环境变量中的服务名被配置覆盖
+func TestResourceAttrs(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "host.name=web-1")
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+
+	cfg := &config.Config{}
+	cfg.Logger.Resource.ServiceName = "api"
+	cfg.Logger.Resource.ServiceVersion = "1.4.0"
+
+	var got []string
+	for _, a := range resourceAttrs(cfg) {
+		got = append(got, a.Key+"="+a.Value.String())
+	}
+	want := "host.name=web-1,service.name=api,service.version=1.4.0"
+	if strings.Join(got, ",") != want {
+		t.Errorf("resourceAttrs = %v, want %s", got, want)
+	}
+}
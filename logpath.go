@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// logFilePath 规范化日志文件路径：统一分隔符、清理多余的 . 和 ..，并转换为绝对路径。
+// 标准库在 Windows 上只为绝对路径自动添加 \\?\ 前缀，转换后超过 MAX_PATH 的深层目录也能正常创建和轮转，
+// 轮转备份也不会因进程工作目录变化而写到别处
+func logFilePath(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("日志文件路径为空")
+	}
+	p := filepath.Clean(filepath.FromSlash(path))
+	if err := checkLogPath(p); err != nil {
+		return "", err
+	}
+	return filepath.Abs(p)
+}
+
+// windowsReservedNames Windows 保留的设备名，不区分大小写，带扩展名时同样保留
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// windowsPathError 检查路径在 Windows 上是否可用：不能包含 <>:"|?* 和控制字符，不能使用保留设备名，
+// 路径段不能以空格或 . 结尾（Windows 会静默去掉，导致写入与配置不同的文件）。
+// 与平台无关，便于在任意平台上校验面向 Windows 部署的配置
+func windowsPathError(path string) error {
+	p := path
+	if strings.HasPrefix(p, `\\?\`) || strings.HasPrefix(p, `\\.\`) {
+		return fmt.Errorf("日志路径 %q: 不支持设备路径前缀，请使用普通路径", path)
+	}
+	if len(p) >= 2 && p[1] == ':' && isASCIILetter(p[0]) {
+		p = p[2:]
+	}
+
+	for _, elem := range strings.FieldsFunc(p, func(r rune) bool { return r == '\\' || r == '/' }) {
+		if elem == "." || elem == ".." {
+			continue
+		}
+		if i := strings.IndexFunc(elem, func(r rune) bool { return r < 32 || strings.ContainsRune(`<>:"|?*`, r) }); i >= 0 {
+			return fmt.Errorf("日志路径 %q: %q 包含 Windows 不允许的字符 %q", path, elem, elem[i])
+		}
+		if strings.HasSuffix(elem, " ") || strings.HasSuffix(elem, ".") {
+			return fmt.Errorf("日志路径 %q: %q 以空格或 . 结尾", path, elem)
+		}
+		base, _, _ := strings.Cut(elem, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+			return fmt.Errorf("日志路径 %q: %q 是 Windows 保留的设备名", path, elem)
+		}
+	}
+	return nil
+}
+
+// isASCIILetter 检查是否为盘符字母
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package logger
+
+// checkLogPath 非 Windows 平台不限制路径字符
+func checkLogPath(path string) error {
+	return nil
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+// TestWindowsPathError 测试面向 Windows 的路径校验
+func TestWindowsPathError(t *testing.T) {
+	valid := []string{
+		`logs\app.log`,
+		`C:\ProgramData\MyService\logs\app.log`,
+		`c:/logs/app.2024-01-02T15-04-05.000.log`,
+		`\\fileserver\share\logs\app.log`,
+		`..\logs\app.log`,
+		`logs\console.log`,
+	}
+	for _, p := range valid {
+		if err := windowsPathError(p); err != nil {
+			t.Errorf("windowsPathError(%q) = %v", p, err)
+		}
+	}
+
+	invalid := []string{
+		`logs\app?.log`,
+		`C:\logs\a:b.log`,
+		`logs\CON`,
+		`logs\nul.log`,
+		`logs\com1.txt`,
+		`logs.\app.log`,
+		`logs\app.log `,
+		`\\?\C:\logs\app.log`,
+	}
+	for _, p := range invalid {
+		if err := windowsPathError(p); err == nil {
+			t.Errorf("windowsPathError(%q) should fail", p)
+		}
+	}
+}
+
+// TestLogFilePath 测试日志路径转换为清理后的绝对路径
+func TestLogFilePath(t *testing.T) {
+	got, err := logFilePath("logs/./sub/../app.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := filepath.Abs(filepath.Join("logs", "app.log"))
+	if got != want {
+		t.Errorf("logFilePath = %q, want %q", got, want)
+	}
+	if _, err := logFilePath(" "); err == nil {
+		t.Error("empty path should fail")
+	}
+}
+
+// TestFileRotation 测试超过 max_size 后在日志所在目录生成轮转备份，备份名不含 Windows 不允许的字符
+func TestFileRotation(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", strings.Repeat("d", 64), strings.Repeat("e", 64))
+	cfg := &config.Config{}
+	cfg.Logger.Level = "info"
+	cfg.Logger.Output.File = config.FileConfig{
+		Enabled:  true,
+		Path:     filepath.ToSlash(filepath.Join(dir, "app.log")),
+		Format:   "json",
+		Rotation: config.RotationConfig{MaxSize: 1, MaxBackups: 2},
+	}
+
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.SetGlobalConfig(prev) })
+
+	lm, err := New(context.Background(), WithConfig(cfg), WithoutSetDefault())
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := strings.Repeat("x", 4096)
+	for i := 0; i < 300; i++ { // 约 1.2MB，触发一次轮转
+		lm.Logger().Info("rotate", "payload", payload)
+	}
+	if err := lm.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var backups []string
+	for _, e := range entries {
+		if e.Name() != "app.log" && strings.HasPrefix(e.Name(), "app-") {
+			backups = append(backups, e.Name())
+		}
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected one rotated backup, got %v", entries)
+	}
+	if err := windowsPathError(backups[0]); err != nil {
+		t.Errorf("backup name is not valid on Windows: %v", err)
+	}
+}
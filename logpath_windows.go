@@ -0,0 +1,6 @@
+package logger
+
+// checkLogPath Windows 上校验路径中的非法字符和保留设备名
+func checkLogPath(path string) error {
+	return windowsPathError(path)
+}
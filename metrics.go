@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// recordMetrics 全局记录计数，重新初始化时保留，使导出的 counter 保持单调递增
+var recordMetrics = handler.NewRecordMetrics()
+
+// metricLevels 导出时级别标签的顺序
+var metricLevels = []string{"debug", "info", "warn", "error"}
+
+// WriteMetrics 以 Prometheus 文本格式写出日志系统的计数：
+// logmiao_records_total{level}、logmiao_dropped_total{stage}、logmiao_handler_errors_total、
+// logmiao_truncated_total 以及各输出端的 logmiao_sink_healthy{sink}
+func WriteMetrics(w io.Writer) error {
+	stats := Stats()
+	bw := bufio.NewWriter(w)
+
+	writeMetricHeader(bw, "logmiao_records_total", "counter", "Log records written to the sinks, by level.")
+	for _, level := range metricLevels {
+		fmt.Fprintf(bw, "logmiao_records_total{level=%q} %d\n", level, stats.Records[level])
+	}
+
+	var asyncDropped, pushDropped int64
+	if stats.Async != nil {
+		asyncDropped = stats.Async.Dropped
+	}
+	if stats.ViewerPush != nil {
+		pushDropped = stats.ViewerPush.Dropped
+	}
+	writeMetricHeader(bw, "logmiao_dropped_total", "counter", "Log records dropped before reaching a sink, by stage.")
+	fmt.Fprintf(bw, "logmiao_dropped_total{stage=\"async\"} %d\n", asyncDropped)
+	fmt.Fprintf(bw, "logmiao_dropped_total{stage=\"push\"} %d\n", pushDropped)
+	fmt.Fprintf(bw, "logmiao_dropped_total{stage=\"tap\"} %d\n", stats.TapDropped)
+
+	writeMetricHeader(bw, "logmiao_handler_errors_total", "counter", "Records that at least one sink failed to write.")
+	fmt.Fprintf(bw, "logmiao_handler_errors_total %d\n", stats.HandlerErrors)
+
+	writeMetricHeader(bw, "logmiao_truncated_total", "counter", "Records truncated to logger.max_record_size.")
+	fmt.Fprintf(bw, "logmiao_truncated_total %d\n", stats.Truncated)
+
+	if len(stats.Sinks) > 0 {
+		writeMetricHeader(bw, "logmiao_sink_healthy", "gauge", "Whether the sink passed its last health check.")
+		for _, s := range stats.Sinks {
+			healthy := 0
+			if s.Healthy {
+				healthy = 1
+			}
+			fmt.Fprintf(bw, "logmiao_sink_healthy{sink=\"%s\"} %d\n", escapeLabel(s.Name), healthy)
+		}
+	}
+	return bw.Flush()
+}
+
+// MetricsHandler 返回 Prometheus 抓取用的 HTTP 处理器，可挂载到应用的 /metrics；
+// 启用查看器时设置 viewer.metrics 也会在查看器端口提供 /metrics
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteMetrics(w)
+	})
+}
+
+func writeMetricHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// labelEscaper 按 Prometheus 文本格式转义标签值
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
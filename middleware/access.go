@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// AccessInfo 与框架无关的请求信息，供 Gin、Hertz 等框架的访问日志中间件共用
+type AccessInfo struct {
+	Method          string
+	Path            string
+	Route           string // 路由模板，如 /users/:id
+	Query           string
+	Status          int
+	Latency         time.Duration
+	TTFB            time.Duration // 首字节耗时，0 表示未知
+	ClientIP        string
+	UserAgent       string
+	Proto           string               // HTTP 协议版本，如 HTTP/1.1、HTTP/2.0
+	TLS             *tls.ConnectionState // HTTPS 请求的连接信息，明文请求为 nil
+	RequestID       string
+	RequestSize     int64
+	ResponseSize    int64
+	Header          http.Header // 请求头，仅在 LogHeaders 时记录
+	Body            []byte      // 已读取的请求体，仅在错误或调试时记录
+	ContentEncoding string      // 请求体的 Content-Encoding，gzip/deflate 会在记录前解压
+	Errors          []string    // 处理过程中收集的错误
+	Attrs           []slog.Attr // 框架特有的附加属性（如缓存状态）
+}
+
+// connAttrs 协议版本与 TLS 连接信息，未知的项不输出
+func connAttrs(proto string, state *tls.ConnectionState) []slog.Attr {
+	var attrs []slog.Attr
+	if proto != "" {
+		attrs = append(attrs, slog.String("proto", proto))
+	}
+	if state == nil {
+		return attrs
+	}
+	attrs = append(attrs,
+		slog.String("tls_version", tls.VersionName(state.Version)),
+		slog.String("tls_cipher", tls.CipherSuiteName(state.CipherSuite)),
+	)
+	if state.ServerName != "" {
+		attrs = append(attrs, slog.String("tls_sni", state.ServerName))
+	}
+	if state.NegotiatedProtocol != "" {
+		attrs = append(attrs, slog.String("tls_alpn", state.NegotiatedProtocol))
+	}
+	return attrs
+}
+
+// LogAccess 按中间件配置输出一条访问日志
+func LogAccess(ctx context.Context, cfg GinMiddlewareConfig, info AccessInfo) {
+	sampled := sampledOut(cfg, info)
+	if sampled == 0 {
+		return
+	}
+	logger := accessLoggerFor(cfg)
+
+	// 准备日志属性
+	attrs := []slog.Attr{
+		fields.Type("http_request"),
+		fields.Method(info.Method),
+		fields.Path(info.Path),
+		fields.HTTPStatus(info.Status),
+		fields.Latency(info.Latency),
+		fields.ClientIP(info.ClientIP),
+		fields.UserAgent(info.UserAgent),
+		fields.RequestSize(info.RequestSize),
+		fields.ResponseSize(info.ResponseSize),
+	}
+
+	if info.TTFB > 0 {
+		attrs = append(attrs, fields.TTFB(info.TTFB))
+	}
+
+	if info.Route != "" {
+		attrs = append(attrs, fields.Route(info.Route))
+	}
+
+	if info.Query != "" {
+		attrs = append(attrs, slog.String("query", info.Query))
+	}
+
+	if cfg.ConnInfo {
+		attrs = append(attrs, connAttrs(info.Proto, info.TLS)...)
+	}
+
+	attrs = append(attrs, info.Attrs...)
+
+	// 记录请求头（如果配置了）
+	verbose := info.Status >= 400 || logger.Enabled(ctx, slog.LevelDebug)
+	if cfg.LogHeaders && verbose {
+		headers := make(map[string]string)
+		for name, values := range info.Header {
+			// 过滤敏感头信息
+			if isSensitiveHeader(name) {
+				headers[name] = "[FILTERED]"
+			} else {
+				headers[name] = strings.Join(values, ", ")
+			}
+		}
+		if len(headers) > 0 {
+			attrs = append(attrs, slog.Any("headers", headers))
+		}
+	}
+
+	// 记录请求体（仅在错误时或调试模式）
+	if cfg.LogBody && len(info.Body) > 0 && verbose {
+		attrs = append(attrs, slog.String("request_body", prepareBodyForLogging(info.Body, info.ContentEncoding, cfg.MaxBodySize)))
+	}
+
+	// 记录错误信息
+	if len(info.Errors) > 0 {
+		attrs = append(attrs, slog.String("errors", strings.Join(info.Errors, "; ")))
+	}
+
+	// 添加请求标识符（如果有）
+	if info.RequestID != "" {
+		attrs = append(attrs, fields.RequestID(info.RequestID))
+	}
+
+	// 采样保留的记录附加采样标记和采样率，供下游按比例还原请求量
+	if sampled > 1 {
+		attrs = append(attrs, fields.Sampled(), fields.SampleRate(sampled))
+	}
+
+	// 添加请求级属性（如租户标识）
+	attrs = append(attrs, handler.AttrsFromContext(ctx)...)
+
+	// 根据状态码选择日志级别
+	logger.LogAttrs(ctx, getLogLevelForStatus(info.Status), "HTTP Request", attrs...)
+}
+
+// Skipped 检查路径是否属于 SkipPaths（如健康检查）
+func (cfg GinMiddlewareConfig) Skipped(path string) bool {
+	for _, skipPath := range cfg.SkipPaths {
+		if strings.HasPrefix(path, skipPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipSummary 汇总 SkipPaths 中的请求，SkipMode 不是 summary 时 Observe 为空操作
+type SkipSummary struct {
+	summary *healthSummary
+}
+
+// NewSkipSummary 按中间件配置创建跳过请求的汇总器，汇总记录写入访问日志
+func NewSkipSummary(cfg GinMiddlewareConfig) *SkipSummary {
+	if cfg.SkipMode != HealthCheckSummary {
+		return &SkipSummary{}
+	}
+	return &SkipSummary{summary: newHealthSummary(cfg.SummaryInterval, func(ctx context.Context, msg string, attrs ...slog.Attr) {
+		accessLoggerFor(cfg).LogAttrs(ctx, slog.LevelInfo, msg, attrs...)
+	})}
+}
+
+// Observe 记录一次被跳过的请求
+func (s *SkipSummary) Observe(path string, status int) {
+	if s.summary != nil {
+		s.summary.Observe(path, status)
+	}
+}
+
+// ContextAttrMap 将配置中的上下文属性列表转换为 GinMiddlewareConfig.ContextAttrs
+func ContextAttrMap(rules []config.ContextAttr) map[string]string {
+	if len(rules) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		if rule.Key != "" {
+			m[rule.Key] = rule.Attr
+		}
+	}
+	return m
+}
+
+// ContextValues 按 ContextAttrs 从请求上下文（如 gin.Context.Get）读取值，生成按属性名排序的访问日志属性
+func (cfg GinMiddlewareConfig) ContextValues(get func(key string) (any, bool)) []slog.Attr {
+	if len(cfg.ContextAttrs) == 0 {
+		return nil
+	}
+	var attrs []slog.Attr
+	for key, name := range cfg.ContextAttrs {
+		value, ok := get(key)
+		if !ok || value == nil {
+			continue
+		}
+		if name == "" {
+			name = key
+		}
+		attrs = append(attrs, slog.Any(name, value))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+// HeaderAttrMap 将配置中的响应头列表转换为 GinMiddlewareConfig.ResponseHeaders
+func HeaderAttrMap(rules []config.HeaderAttr) map[string]string {
+	if len(rules) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		if rule.Header != "" {
+			m[rule.Header] = rule.Attr
+		}
+	}
+	return m
+}
+
+// HeaderValues 按 ResponseHeaders 读取响应头，生成按属性名排序的访问日志属性，未设置的响应头不输出。
+// 属性名为空时使用小写并以下划线连接的头名称，如 X-Cache → x_cache
+func (cfg GinMiddlewareConfig) HeaderValues(get func(header string) string) []slog.Attr {
+	if len(cfg.ResponseHeaders) == 0 {
+		return nil
+	}
+	var attrs []slog.Attr
+	for header, name := range cfg.ResponseHeaders {
+		value := get(header)
+		if value == "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ReplaceAll(strings.ToLower(header), "-", "_")
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
+
+// sampledOut 按采样配置决定是否记录：返回 0 表示丢弃，1 表示未采样，大于 1 为保留记录代表的请求数
+func sampledOut(cfg GinMiddlewareConfig, info AccessInfo) int {
+	if cfg.SampleRate <= 1 || info.Status < 200 || info.Status >= 300 {
+		return 1
+	}
+	if cfg.SlowThreshold > 0 && info.Latency >= cfg.SlowThreshold {
+		return 1
+	}
+	if rand.Intn(cfg.SampleRate) != 0 {
+		return 0
+	}
+	return cfg.SampleRate
+}
+
+// ShouldLogRequestBody 检查是否应该读取并记录请求体（仅 POST/PUT/PATCH，且不是文件上传）
+func ShouldLogRequestBody(method, contentType string) bool {
+	return shouldLogRequestBody(method, contentType)
+}
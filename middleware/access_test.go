@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+)
+
+// TestAccessSampling 测试只对快速的 2xx 请求采样
+func TestAccessSampling(t *testing.T) {
+	cfg := GinMiddlewareConfig{SampleRate: 50, SlowThreshold: time.Second}
+
+	kept := 0
+	for i := 0; i < 5000; i++ {
+		switch n := sampledOut(cfg, AccessInfo{Status: 200, Latency: time.Millisecond}); n {
+		case 0:
+		case 50:
+			kept++
+		default:
+			t.Fatalf("unexpected sample weight %d", n)
+		}
+	}
+	if kept == 0 || kept > 250 {
+		t.Errorf("expected roughly 1 in 50 successful requests kept, got %d/5000", kept)
+	}
+
+	always := []AccessInfo{
+		{Status: 404, Latency: time.Millisecond},
+		{Status: 503, Latency: time.Millisecond},
+		{Status: 200, Latency: 2 * time.Second},
+	}
+	for _, info := range always {
+		if n := sampledOut(cfg, info); n != 1 {
+			t.Errorf("status %d latency %v should always be logged, got %d", info.Status, info.Latency, n)
+		}
+	}
+}
+
+// TestAccessContextAttrs 测试下游中间件写入 gin.Context 的值按映射出现在访问日志中
+func TestAccessContextAttrs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	cfg.ContextAttrs = map[string]string{"user_id": "", "orgID": "org_id", "role": ""}
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.Use(func(c *gin.Context) { // 模拟鉴权中间件
+		c.Set("user_id", 42)
+		c.Set("orgID", "acme")
+		c.Next()
+	})
+	r.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/me", nil))
+
+	out := buf.String()
+	if !strings.Contains(out, `"org_id":"acme","user_id":42`) {
+		t.Errorf("context values should be logged under their mapped names: %s", out)
+	}
+	if strings.Contains(out, `"role"`) {
+		t.Errorf("unset context keys should be omitted: %s", out)
+	}
+}
+
+// TestAccessCacheAttrs 测试默认的 cache_status 上下文键与配置的响应头出现在访问日志中
+func TestAccessCacheAttrs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	cfg.ResponseHeaders = HeaderAttrMap([]config.HeaderAttr{{Header: "X-Cache"}, {Header: "Age", Attr: "cache_age"}, {Header: "ETag"}})
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.GET("/item", func(c *gin.Context) {
+		c.Set("cache_status", "HIT")
+		c.Header("X-Cache", "HIT from edge")
+		c.Header("Age", "42")
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/item", nil))
+
+	out := buf.String()
+	for _, want := range []string{`"cache":"HIT"`, `"cache_age":"42","x_cache":"HIT from edge"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in access log: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "etag") {
+		t.Errorf("unset response headers should be omitted: %s", out)
+	}
+}
+
+// TestAccessRequestSize 测试未读取请求体时也能记录请求大小
+func TestAccessRequestSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.LogBody = false
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.POST("/upload", func(c *gin.Context) {
+		io.Copy(io.Discard, c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("0123456789"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	chunked := httptest.NewRequest(http.MethodPost, "/upload", io.MultiReader(strings.NewReader("abcdef")))
+	chunked.ContentLength = -1
+	r.ServeHTTP(httptest.NewRecorder(), chunked)
+
+	out := buf.String()
+	if !strings.Contains(out, `"request_size":10`) {
+		t.Errorf("request_size should come from Content-Length: %s", out)
+	}
+	if !strings.Contains(out, `"request_size":6`) {
+		t.Errorf("request_size should count bytes read when the length is unknown: %s", out)
+	}
+}
+
+// TestAccessPhaseTimings 测试首字节耗时与各阶段耗时
+func TestAccessPhaseTimings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.PhaseTimings = true
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.Use(func(c *gin.Context) { time.Sleep(20 * time.Millisecond) }) // 较慢的中间件
+	r.Use(MarkHandlerStart())
+	r.GET("/report", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.String(http.StatusOK, "ok")
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	var record struct {
+		TTFB    time.Duration `json:"ttfb"`
+		Latency time.Duration `json:"latency"`
+		Phases  struct {
+			Middleware float64  `json:"middleware_ms"`
+			Handler    float64  `json:"handler_ms"`
+			Render     *float64 `json:"render_ms"`
+		} `json:"phases"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if record.TTFB < 40*time.Millisecond || record.TTFB > record.Latency {
+		t.Errorf("ttfb = %v, latency = %v", record.TTFB, record.Latency)
+	}
+	if record.Phases.Middleware < 20 || record.Phases.Handler < 20 || record.Phases.Render == nil {
+		t.Errorf("unexpected phases: %s", buf.String())
+	}
+}
+
+// TestAccessConnInfo 测试开启 ConnInfo 后记录协议版本与 TLS 连接信息，明文请求不输出 TLS 属性
+func TestAccessConnInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	cfg.ConnInfo = true
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.GET("/secure", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/secure", nil)
+	req.Proto = "HTTP/2.0"
+	req.TLS = &tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		ServerName:         "api.example.com",
+		NegotiatedProtocol: "h2",
+	}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	out := buf.String()
+	if !strings.Contains(out, `"proto":"HTTP/2.0","tls_version":"TLS 1.3","tls_cipher":"TLS_AES_128_GCM_SHA256","tls_sni":"api.example.com","tls_alpn":"h2"`) {
+		t.Errorf("expected connection info in access log: %s", out)
+	}
+
+	buf.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/secure", nil))
+	out = buf.String()
+	if !strings.Contains(out, `"proto":"HTTP/1.1"`) || strings.Contains(out, "tls_") {
+		t.Errorf("plaintext request should only log proto: %s", out)
+	}
+}
+
+// TestGinMiddlewareConfigFromGlobal 测试中间件配置读取全局配置的 middleware 部分
+func TestGinMiddlewareConfigFromGlobal(t *testing.T) {
+	prev := config.GlobalConfig
+	t.Cleanup(func() { config.GlobalConfig = prev })
+
+	config.GlobalConfig = nil
+	if cfg := GinMiddlewareConfigFromGlobal(); cfg.MaxBodySize != DefaultGinMiddlewareConfig().MaxBodySize {
+		t.Errorf("expected defaults without config, got %+v", cfg)
+	}
+
+	config.GlobalConfig = &config.Config{}
+	config.GlobalConfig.Logger.Middleware.MaxBodySize = 512
+	config.GlobalConfig.Logger.Middleware.Sampling.Rate = 10
+	config.GlobalConfig.Logger.Middleware.ConnInfo = true
+	config.GlobalConfig.Logger.Middleware.ContextAttrs = []config.ContextAttr{{Key: "orgID", Attr: "org_id"}}
+	cfg := GinMiddlewareConfigFromGlobal()
+	if cfg.MaxBodySize != 512 || cfg.SampleRate != 10 || !cfg.ConnInfo || cfg.ContextAttrs["orgID"] != "org_id" {
+		t.Errorf("config not mapped: %+v", cfg)
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/utils"
+)
+
+// 业务处理函数通过 c.Set 向审计中间件提供的键
+const (
+	AuditActorKey    = "audit_actor"    // 操作者，未设置时使用 user_id
+	AuditResourceKey = "audit_resource" // 资源，未设置时使用路由模板
+	AuditActionKey   = "audit_action"   // 动作，未设置时使用请求方法
+	AuditBeforeKey   = "audit_before"   // 变更前的对象
+	AuditAfterKey    = "audit_after"    // 变更后的对象
+)
+
+// AuditConfig 审计中间件配置
+type AuditConfig struct {
+	Methods []string     // 需要审计的请求方法
+	Logger  *slog.Logger // 审计日志写入的日志器，为空时使用审计通道或全局日志器
+}
+
+// DefaultAuditConfig 默认审计配置
+func DefaultAuditConfig() AuditConfig {
+	return AuditConfig{Methods: []string{"POST", "PUT", "PATCH", "DELETE"}}
+}
+
+// Audit 审计中间件，配置来自 logger.middleware.audit，日志写入 audit 通道
+func Audit() gin.HandlerFunc {
+	cfg := DefaultAuditConfig()
+	if config.GlobalConfig != nil && len(config.GlobalConfig.Logger.Middleware.Audit.Methods) > 0 {
+		cfg.Methods = config.GlobalConfig.Logger.Middleware.Audit.Methods
+	}
+	return AuditWithConfig(cfg)
+}
+
+// AuditWithConfig 返回带配置的审计中间件，对变更类请求记录操作者、资源、动作和前后差异
+//
+//	c.Set(middleware.AuditBeforeKey, oldUser)
+//	c.Set(middleware.AuditAfterKey, newUser)
+func AuditWithConfig(cfg AuditConfig) gin.HandlerFunc {
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+
+	return func(c *gin.Context) {
+		if !methods[c.Request.Method] {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		resource := c.GetString(AuditResourceKey)
+		if resource == "" {
+			resource = c.FullPath()
+		}
+		if resource == "" {
+			resource = c.Request.URL.Path
+		}
+		action := c.GetString(AuditActionKey)
+		if action == "" {
+			action = c.Request.Method
+		}
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			fields.Type("audit"),
+			slog.String("actor", auditActor(c)),
+			slog.String("resource", resource),
+			slog.String("action", action),
+			slog.Bool("success", status < 400),
+			fields.HTTPStatus(status),
+			fields.ClientIP(utils.GetClientIP(c)),
+		}
+		if requestID := c.GetString("request_id"); requestID != "" {
+			attrs = append(attrs, fields.RequestID(requestID))
+		}
+
+		before, hasBefore := c.Get(AuditBeforeKey)
+		after, hasAfter := c.Get(AuditAfterKey)
+		if hasBefore || hasAfter {
+			if diff := AuditDiff(before, after); len(diff) > 0 {
+				attrs = append(attrs, slog.Any("diff", diff))
+			}
+		}
+		attrs = append(attrs, handler.AttrsFromContext(c.Request.Context())...)
+
+		l := cfg.Logger
+		if l == nil {
+			l = auditLogger.Load()
+		}
+		if l == nil {
+			l = slog.Default()
+		}
+		l.LogAttrs(c.Request.Context(), slog.LevelInfo, "Audit", attrs...)
+	}
+}
+
+// auditActor 获取操作者
+func auditActor(c *gin.Context) string {
+	for _, key := range []string{AuditActorKey, fields.KeyUserID} {
+		if v, ok := c.Get(key); ok && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+// AuditChange 单个字段的变更
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditDiff 比较变更前后对象的顶层字段，返回发生变化的字段；
+// 对象先按JSON序列化，因此结构体字段名遵循其 json 标签
+func AuditDiff(before, after interface{}) map[string]AuditChange {
+	from := toAuditMap(before)
+	to := toAuditMap(after)
+
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	diff := make(map[string]AuditChange)
+	for _, k := range keys {
+		if !reflect.DeepEqual(from[k], to[k]) {
+			diff[k] = AuditChange{From: from[k], To: to[k]}
+		}
+	}
+	return diff
+}
+
+// toAuditMap 将对象转换为顶层字段映射，非对象值存放在 value 键下
+func toAuditMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return map[string]interface{}{"value": fmt.Sprint(v)}
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		var raw interface{}
+		_ = json.Unmarshal(data, &raw)
+		return map[string]interface{}{"value": raw}
+	}
+	return m
+}
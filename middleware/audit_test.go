@@ -0,0 +1,29 @@
+package middleware
+
+import "testing"
+
+// TestAuditDiff 测试前后对象的字段差异
+func TestAuditDiff(t *testing.T) {
+	type user struct {
+		Name  string `json:"name"`
+		Role  string `json:"role"`
+		Email string `json:"email,omitempty"`
+	}
+	diff := AuditDiff(
+		user{Name: "alice", Role: "viewer"},
+		user{Name: "alice", Role: "admin", Email: "a@example.com"},
+	)
+
+	if len(diff) != 2 {
+		t.Fatalf("expected 2 changed fields, got %+v", diff)
+	}
+	if c := diff["role"]; c.From != "viewer" || c.To != "admin" {
+		t.Errorf("role change = %+v", c)
+	}
+	if c := diff["email"]; c.From != nil || c.To != "a@example.com" {
+		t.Errorf("email change = %+v", c)
+	}
+	if _, ok := diff["name"]; ok {
+		t.Error("unchanged field should not appear in diff")
+	}
+}
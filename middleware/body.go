@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// decodeBody 按 Content-Encoding 解压请求体，最多返回 maxSize 字节，truncated 表示内容超出上限
+func decodeBody(body []byte, encoding string, maxSize int) (decoded []byte, truncated bool, err error) {
+	var r io.Reader
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		r = bytes.NewReader(body)
+	case "gzip", "x-gzip":
+		if r, err = gzip.NewReader(bytes.NewReader(body)); err != nil {
+			return nil, false, err
+		}
+	case "deflate":
+		// HTTP 的 deflate 通常带 zlib 头，也有客户端发送裸 deflate 流
+		if r, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+			r, err = flate.NewReader(bytes.NewReader(body)), nil
+		}
+	default:
+		return nil, false, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	if maxSize <= 0 {
+		decoded, err = io.ReadAll(r)
+		return decoded, false, err
+	}
+	decoded, err = io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	if len(decoded) > maxSize {
+		// 按字符边界截断，避免截出不完整的 UTF-8 序列
+		decoded = decoded[:maxSize]
+		for i := 0; i < utf8.UTFMax-1 && len(decoded) > 0; i++ {
+			if r, size := utf8.DecodeLastRune(decoded); r != utf8.RuneError || size != 1 {
+				break
+			}
+			decoded = decoded[:len(decoded)-1]
+		}
+		truncated = true
+	}
+	return decoded, truncated, nil
+}
+
+// isBinary 检查内容是否为二进制：不是合法的 UTF-8 或包含除空白外的控制字符
+func isBinary(data []byte) bool {
+	if !utf8.Valid(data) {
+		return true
+	}
+	for _, b := range data {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' {
+			return true
+		}
+	}
+	return false
+}
+
+// binarySummary 将二进制请求体描述为大小和 SHA-256 前缀
+func binarySummary(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf("[binary %d bytes sha256:%s]", len(body), hex.EncodeToString(sum[:8]))
+}
+
+// countingBody 统计已读取字节数的请求体，用于长度未知的请求
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
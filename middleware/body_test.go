@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+)
+
+// TestPrepareBodyForLogging 测试压缩请求体解压记录，二进制内容记录为大小和哈希
+func TestPrepareBodyForLogging(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(`{"name": "alice"}`))
+	w.Close()
+
+	if got := prepareBodyForLogging(gz.Bytes(), "gzip", 1024); got != `{"name":"alice"}` {
+		t.Errorf("gzip body = %q", got)
+	}
+
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0x01, 0xff}
+	if got := prepareBodyForLogging(binary, "", 1024); !strings.HasPrefix(got, "[binary 7 bytes sha256:") {
+		t.Errorf("binary body = %q", got)
+	}
+	if got := prepareBodyForLogging([]byte("not gzip"), "gzip", 1024); !strings.HasPrefix(got, "[binary 8 bytes") {
+		t.Errorf("undecodable body = %q", got)
+	}
+
+	// 截断不应切开多字节字符
+	if got := prepareBodyForLogging([]byte("你好世界"), "", 7); got != "你好...(truncated)" {
+		t.Errorf("truncated body = %q", got)
+	}
+}
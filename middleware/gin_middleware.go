@@ -2,23 +2,68 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"log/slog"
+	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
 	"github.com/shuakami/logmiao/utils"
 )
 
 // GinMiddlewareConfig Gin中间件配置
 type GinMiddlewareConfig struct {
-	LogBody     bool     // 是否记录请求体（仅在错误时）
-	LogHeaders  bool     // 是否记录请求头
-	MaxBodySize int      // 最大请求体记录大小
-	SkipPaths   []string // 跳过记录的路径（如健康检查）
+	LogBody     bool         // 是否记录请求体（仅在错误时）
+	LogHeaders  bool         // 是否记录请求头
+	MaxBodySize int          // 最大请求体记录大小
+	SkipPaths   []string     // 跳过记录的路径（如健康检查）
+	Logger      *slog.Logger // 访问日志写入的日志器，为空时使用访问日志通道或全局日志器
+	TrackSpans  bool         // 汇总请求内 StartSpan 的计时，随访问日志输出 spans 属性
+
+	SampleRate    int           // 2xx 访问日志每 SampleRate 条保留 1 条，<=1 表示不采样
+	SlowThreshold time.Duration // 耗时达到该值的请求不参与采样，0 表示不按耗时豁免
+
+	SkipMode        string        // SkipPaths 中请求的处理方式：drop（默认）或 summary
+	SummaryInterval time.Duration // summary 模式下的汇总周期，默认1分钟
+
+	ContextAttrs    map[string]string // 上下文键到访问日志属性名的映射，如 user_id -> user.id
+	ResponseHeaders map[string]string // 响应头到访问日志属性名的映射，如 X-Cache -> cache
+	PhaseTimings    bool              // 输出 phases 属性：中间件链、处理函数、响应写出各阶段耗时
+	ConnInfo        bool              // 输出 proto 及 tls_version、tls_cipher、tls_sni、tls_alpn 属性，用于排查客户端兼容问题
+}
+
+// accessLogger 访问日志通道的日志器
+var accessLogger atomic.Pointer[slog.Logger]
+
+// SetAccessLogger 设置访问日志通道，传入nil时访问日志写入全局日志器
+func SetAccessLogger(l *slog.Logger) {
+	accessLogger.Store(l)
+}
+
+// auditLogger 审计通道的日志器
+var auditLogger atomic.Pointer[slog.Logger]
+
+// SetAuditLogger 设置审计通道，传入nil时审计日志写入全局日志器
+func SetAuditLogger(l *slog.Logger) {
+	auditLogger.Store(l)
+}
+
+// accessLoggerFor 返回访问日志应写入的日志器
+func accessLoggerFor(cfg GinMiddlewareConfig) *slog.Logger {
+	if cfg.Logger != nil {
+		return cfg.Logger
+	}
+	if l := accessLogger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
 }
 
 // DefaultGinMiddlewareConfig 默认配置
@@ -28,37 +73,56 @@ func DefaultGinMiddlewareConfig() GinMiddlewareConfig {
 		LogHeaders:  false,
 		MaxBodySize: 2048,
 		SkipPaths:   []string{"/health", "/ping", "/metrics"},
+		TrackSpans:  true,
+		// 缓存中间件写入 c.Set("cache_status", "HIT") 时记录为 cache 属性
+		ContextAttrs: map[string]string{"cache_status": fields.KeyCache},
 	}
 }
 
 // GinMiddleware 返回Gin框架的日志中间件
 func GinMiddleware() gin.HandlerFunc {
+	return GinMiddlewareWithConfig(GinMiddlewareConfigFromGlobal())
+}
+
+// GinMiddlewareConfigFromGlobal 按全局配置的 middleware 部分生成中间件配置，未加载配置时返回默认配置。
+// 其他框架的访问日志中间件应使用它，与 Gin 集成保持一致
+func GinMiddlewareConfigFromGlobal() GinMiddlewareConfig {
 	cfg := DefaultGinMiddlewareConfig()
-	if config.GlobalConfig != nil {
-		cfg.LogBody = config.GlobalConfig.Logger.Middleware.LogBody
-		cfg.LogHeaders = config.GlobalConfig.Logger.Middleware.LogHeaders
-		cfg.MaxBodySize = config.GlobalConfig.Logger.Middleware.MaxBodySize
+	if config.GlobalConfig == nil {
+		return cfg
 	}
-	return GinMiddlewareWithConfig(cfg)
+	cfg.LogBody = config.GlobalConfig.Logger.Middleware.LogBody
+	cfg.LogHeaders = config.GlobalConfig.Logger.Middleware.LogHeaders
+	cfg.MaxBodySize = config.GlobalConfig.Logger.Middleware.MaxBodySize
+	cfg.TrackSpans = config.GlobalConfig.Logger.Features.PerformanceTracking
+	cfg.SampleRate = config.GlobalConfig.Logger.Middleware.Sampling.Rate
+	cfg.SlowThreshold = config.GlobalConfig.Logger.Middleware.Sampling.SlowThreshold
+	cfg.SkipMode = config.GlobalConfig.Logger.Middleware.HealthChecks.Mode
+	cfg.SummaryInterval = config.GlobalConfig.Logger.Middleware.HealthChecks.Interval
+	cfg.ContextAttrs = ContextAttrMap(config.GlobalConfig.Logger.Middleware.ContextAttrs)
+	cfg.ResponseHeaders = HeaderAttrMap(config.GlobalConfig.Logger.Middleware.ResponseHeaders)
+	cfg.PhaseTimings = config.GlobalConfig.Logger.Middleware.PhaseTimings
+	cfg.ConnInfo = config.GlobalConfig.Logger.Middleware.ConnInfo
+	return cfg
 }
 
 // GinMiddlewareWithConfig 返回带配置的Gin框架日志中间件
 func GinMiddlewareWithConfig(cfg GinMiddlewareConfig) gin.HandlerFunc {
+	summary := NewSkipSummary(cfg)
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		rawQuery := c.Request.URL.RawQuery
 
 		// 检查是否需要跳过记录
-		for _, skipPath := range cfg.SkipPaths {
-			if strings.HasPrefix(path, skipPath) {
-				c.Next()
-				return
-			}
+		if cfg.Skipped(path) {
+			c.Next()
+			summary.Observe(path, c.Writer.Status())
+			return
 		}
 
 		var bodyBytes []byte
-		var requestSize int64
+		requestSize := c.Request.ContentLength
 
 		// 读取请求体（仅对特定方法和非文件上传）
 		if cfg.LogBody && shouldLogRequestBody(c.Request.Method, c.Request.Header.Get("Content-Type")) {
@@ -70,79 +134,72 @@ func GinMiddlewareWithConfig(cfg GinMiddlewareConfig) gin.HandlerFunc {
 			}
 		}
 
-		// 处理请求
-		c.Next()
-
-		// 计算响应时间
-		latency := time.Since(start)
-		status := c.Writer.Status()
-		responseSize := int64(c.Writer.Size())
-
-		// 准备日志属性
-		attrs := []slog.Attr{
-			slog.String("type", "http_request"),
-			slog.String("method", c.Request.Method),
-			slog.String("path", path),
-			slog.Int("status", status),
-			slog.Duration("latency", latency),
-			slog.String("client_ip", utils.GetClientIP(c)),
-			slog.String("user_agent", c.Request.UserAgent()),
-			slog.Int64("request_size", requestSize),
-			slog.Int64("response_size", responseSize),
+		// 长度未知（如分块传输）时统计处理过程中实际读取的字节数
+		var counter *countingBody
+		if requestSize < 0 {
+			requestSize = 0
+			if c.Request.Body != nil && c.Request.Body != http.NoBody {
+				counter = &countingBody{ReadCloser: c.Request.Body}
+				c.Request.Body = counter
+			}
 		}
 
-		if rawQuery != "" {
-			attrs = append(attrs, slog.String("query", rawQuery))
+		// 请求级计时，供 StartSpan 累加
+		var timings *handler.SpanTimings
+		if cfg.TrackSpans {
+			var ctx context.Context
+			ctx, timings = handler.ContextWithSpanTimings(c.Request.Context())
+			c.Request = c.Request.WithContext(ctx)
 		}
 
-		// 添加缓存状态（如果有）
-		if cacheStatus, exists := c.Get("cache_status"); exists {
-			if status, ok := cacheStatus.(string); ok {
-				attrs = append(attrs, slog.String("cache", status))
-			}
-		}
+		// 记录首字节时间
+		writer := &timingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
 
-		// 记录请求头（如果配置了）
-		if cfg.LogHeaders && (status >= 400 || slog.Default().Enabled(nil, slog.LevelDebug)) {
-			headers := make(map[string]string)
-			for name, values := range c.Request.Header {
-				// 过滤敏感头信息
-				if isSensitiveHeader(name) {
-					headers[name] = "[FILTERED]"
-				} else {
-					headers[name] = strings.Join(values, ", ")
-				}
-			}
-			if len(headers) > 0 {
-				attrs = append(attrs, slog.Any("headers", headers))
-			}
-		}
+		// 处理请求
+		c.Next()
+		end := time.Now()
 
-		// 记录请求体（仅在错误时或调试模式）
-		if cfg.LogBody && len(bodyBytes) > 0 && (status >= 400 || slog.Default().Enabled(nil, slog.LevelDebug)) {
-			bodyToLog := prepareBodyForLogging(bodyBytes, cfg.MaxBodySize)
-			attrs = append(attrs, slog.String("request_body", bodyToLog))
+		if counter != nil {
+			requestSize = counter.n
 		}
 
-		// 记录错误信息
-		if len(c.Errors) > 0 {
-			errorMessages := make([]string, len(c.Errors))
-			for i, err := range c.Errors {
-				errorMessages[i] = err.Error()
-			}
-			attrs = append(attrs, slog.String("errors", strings.Join(errorMessages, "; ")))
+		var errs []string
+		for _, err := range c.Errors {
+			errs = append(errs, err.Error())
 		}
 
-		// 根据状态码选择日志级别
-		level := getLogLevelForStatus(status)
-		message := "HTTP Request"
-
-		// 添加请求标识符（如果有）
-		if requestID := c.GetHeader("X-Request-ID"); requestID != "" {
-			attrs = append(attrs, slog.String("request_id", requestID))
+		var extra []slog.Attr
+		if timings != nil {
+			extra = append(extra, timings.Attrs()...)
+		}
+		extra = append(extra, cfg.ContextValues(c.Get)...)
+		extra = append(extra, cfg.HeaderValues(c.Writer.Header().Get)...)
+		if cfg.PhaseTimings {
+			extra = append(extra, writer.phaseAttrs(c, start, end)...)
 		}
 
-		slog.LogAttrs(c.Request.Context(), level, message, attrs...)
+		LogAccess(c.Request.Context(), cfg, AccessInfo{
+			Method:          c.Request.Method,
+			Path:            path,
+			Route:           c.FullPath(),
+			Query:           rawQuery,
+			Status:          c.Writer.Status(),
+			Latency:         end.Sub(start),
+			TTFB:            writer.ttfb(start),
+			ClientIP:        utils.GetClientIP(c),
+			UserAgent:       c.Request.UserAgent(),
+			Proto:           c.Request.Proto,
+			TLS:             c.Request.TLS,
+			RequestID:       requestIDOf(c),
+			RequestSize:     requestSize,
+			ResponseSize:    int64(c.Writer.Size()),
+			Header:          c.Request.Header,
+			Body:            bodyBytes,
+			ContentEncoding: c.Request.Header.Get("Content-Encoding"),
+			Errors:          errs,
+			Attrs:           extra,
+		})
 	}
 }
 
@@ -181,17 +238,22 @@ func isSensitiveHeader(name string) bool {
 	return false
 }
 
-// prepareBodyForLogging 准备用于日志记录的请求体
-func prepareBodyForLogging(bodyBytes []byte, maxSize int) string {
+// prepareBodyForLogging 准备用于日志记录的请求体：按 Content-Encoding 解压，
+// 二进制内容记录为大小和哈希
+func prepareBodyForLogging(bodyBytes []byte, encoding string, maxSize int) string {
 	if len(bodyBytes) == 0 {
 		return ""
 	}
 
-	bodyStr := string(bodyBytes)
+	decoded, truncated, err := decodeBody(bodyBytes, encoding, maxSize)
+	if err != nil || isBinary(decoded) {
+		return binarySummary(bodyBytes)
+	}
 
 	// 限制大小
-	if len(bodyStr) > maxSize {
-		bodyStr = bodyStr[:maxSize] + "...(truncated)"
+	bodyStr := string(decoded)
+	if truncated {
+		bodyStr += "...(truncated)"
 	}
 
 	// 美化JSON格式（如果是JSON的话）
@@ -234,15 +296,36 @@ func getLogLevelForStatus(status int) slog.Level {
 	}
 }
 
-// RequestID 中间件，为每个请求添加唯一标识符
-func RequestID() gin.HandlerFunc {
+// DebugTarget 定向调试中间件，请求头携带有效令牌时该请求的日志不受全局级别限制
+func DebugTarget() gin.HandlerFunc {
+	header := "X-Debug-Log"
+	secret := ""
+	if config.GlobalConfig != nil {
+		targeting := config.GlobalConfig.Logger.Features.DebugTargeting
+		if targeting.Header != "" {
+			header = targeting.Header
+		}
+		secret = targeting.Secret
+	}
+	return DebugTargetWithSecret(header, secret)
+}
+
+// DebugTargetWithSecret 使用指定请求头和签名密钥的定向调试中间件
+func DebugTargetWithSecret(header, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetHeader(header); token != "" && utils.VerifyDebugToken(secret, token) {
+			c.Request = c.Request.WithContext(handler.WithDebugTarget(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// Baggage 解析 W3C baggage 请求头并绑定到请求上下文，供 BaggageHandler 复制为日志属性
+func Baggage() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = utils.GenerateRequestID()
-			c.Header("X-Request-ID", requestID)
+		if members := handler.ParseBaggageHeader(c.GetHeader("baggage")); len(members) > 0 {
+			c.Request = c.Request.WithContext(handler.ContextWithBaggage(c.Request.Context(), members))
 		}
-		c.Set("request_id", requestID)
 		c.Next()
 	}
 }
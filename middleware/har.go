@@ -0,0 +1,366 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// HARConfig 失败请求 HAR 导出配置
+type HARConfig struct {
+	Dir         string            // HAR 文件目录
+	Statuses    []string          // 需要导出的状态码，如 4xx、5xx、429
+	Paths       []string          // 路径前缀过滤，为空时不过滤
+	MaxEntries  int               // 单个 HAR 文件的最大条目数，达到后新建文件
+	MaxBodySize int               // 请求体和响应体最大记录字节数
+	Redactor    *handler.Redactor // 请求体、响应体与查询串的脱敏规则，为nil时使用 handler.DefaultRedactKeys
+}
+
+// DefaultHARConfig 默认 HAR 导出配置
+func DefaultHARConfig() HARConfig {
+	return HARConfig{
+		Dir:         "logs/har",
+		Statuses:    []string{"5xx"},
+		MaxEntries:  100,
+		MaxBodySize: 64 * 1024,
+		Redactor:    handler.NewRedactor(handler.DefaultRedactKeys, nil),
+	}
+}
+
+// HAR 失败请求 HAR 导出中间件，配置来自 logger.middleware.har
+func HAR() gin.HandlerFunc {
+	cfg := DefaultHARConfig()
+	if config.GlobalConfig != nil {
+		har := config.GlobalConfig.Logger.Middleware.HAR
+		if har.Dir != "" {
+			cfg.Dir = har.Dir
+		}
+		if len(har.Statuses) > 0 {
+			cfg.Statuses = har.Statuses
+		}
+		cfg.Paths = har.Paths
+		if har.MaxEntries > 0 {
+			cfg.MaxEntries = har.MaxEntries
+		}
+		if har.MaxBodySize > 0 {
+			cfg.MaxBodySize = har.MaxBodySize
+		}
+		cfg.Redactor = configRedactor(config.GlobalConfig.Logger.Features.Privacy)
+	}
+	return HARWithConfig(cfg)
+}
+
+// HARWithConfig 返回带配置的 HAR 导出中间件，匹配过滤条件的失败请求累积写入 HAR 文件，
+// 可直接导入浏览器开发者工具重放；敏感请求头会被替换为 [FILTERED]，请求体、响应体和查询串按 Redactor 脱敏
+func HARWithConfig(cfg HARConfig) gin.HandlerFunc {
+	defaults := DefaultHARConfig()
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = defaults.MaxEntries
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaults.MaxBodySize
+	}
+	if cfg.Redactor == nil {
+		cfg.Redactor = defaults.Redactor
+	}
+	recorder := &harRecorder{cfg: cfg}
+
+	return func(c *gin.Context) {
+		if !harPathMatches(cfg.Paths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		var reqBody []byte
+		if c.Request.Body != nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(cfg.MaxBodySize)+1))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(reqBody), c.Request.Body))
+		}
+		capture := &captureWriter{ResponseWriter: c.Writer, limit: cfg.MaxBodySize}
+		c.Writer = capture
+
+		c.Next()
+
+		status := capture.Status()
+		if !harStatusMatches(cfg.Statuses, status) {
+			return
+		}
+		entry := newHAREntry(c.Request, reqBody, capture, start, cfg)
+		if path, err := recorder.add(entry); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to write HAR file", fields.Err(err))
+		} else {
+			slog.DebugContext(c.Request.Context(), "Request added to HAR file",
+				fields.Path(c.Request.URL.Path), fields.HTTPStatus(status), slog.String("har", path))
+		}
+	}
+}
+
+// harPathMatches 检查路径是否匹配任一前缀
+func harPathMatches(prefixes []string, path string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// harStatusMatches 检查状态码是否匹配，支持 4xx/5xx 通配和具体状态码
+func harStatusMatches(patterns []string, status int) bool {
+	code := strconv.Itoa(status)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if len(p) == 3 && strings.HasSuffix(p, "xx") && p[0] == code[0] {
+			return true
+		}
+		if p == code {
+			return true
+		}
+	}
+	return false
+}
+
+// harFooter HAR 文件的结尾，每次追加条目时覆盖旧的结尾，使文件始终是完整的 JSON
+const harFooter = "\n    ]\n  }\n}\n"
+
+// harRecorder 将条目追加到当前 HAR 文件，每次只写入新条目与文件结尾，不重写已有内容
+type harRecorder struct {
+	mu    sync.Mutex
+	cfg   HARConfig
+	file  *os.File
+	path  string
+	count int   // 当前文件的条目数
+	end   int64 // 文件结尾的偏移，新条目从这里写入
+}
+
+// add 追加条目并写出文件，返回文件路径
+func (r *harRecorder) add(entry harEntry) (string, error) {
+	data, err := json.MarshalIndent(entry, "      ", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil || r.count >= r.cfg.MaxEntries {
+		if err := r.open(); err != nil {
+			return "", err
+		}
+	}
+	sep := ",\n      "
+	if r.count == 0 {
+		sep = "\n      "
+	}
+	chunk := sep + string(data)
+	if _, err := r.file.WriteAt([]byte(chunk+harFooter), r.end); err != nil {
+		return "", err
+	}
+	r.end += int64(len(chunk))
+	r.count++
+	return r.path, nil
+}
+
+// open 关闭当前文件并新建 HAR 文件，写入文档开头
+func (r *harRecorder) open() error {
+	if r.file != nil {
+		_ = r.file.Close()
+		r.file = nil
+	}
+	if err := os.MkdirAll(r.cfg.Dir, 0755); err != nil {
+		return err
+	}
+	// 同一毫秒内写满一个文件时加序号，不覆盖刚写完的文件
+	base := filepath.Join(r.cfg.Dir, "failed-"+time.Now().Format("20060102-150405.000"))
+	path := base + ".har"
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	for i := 1; os.IsExist(err); i++ {
+		path = base + "-" + strconv.Itoa(i) + ".har"
+		f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	}
+	if err != nil {
+		return err
+	}
+	creator, _ := json.Marshal(harCreator{Name: "logmiao", Version: moduleVersion()})
+	header := `{
+  "log": {
+    "version": "1.2",
+    "creator": ` + string(creator) + `,
+    "entries": [`
+	if _, err := f.WriteString(header + harFooter); err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file, r.path, r.count, r.end = f, path, 0, int64(len(header))
+	return nil
+}
+
+// moduleVersion 返回 logmiao 模块版本，无法获取时返回 devel
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/shuakami/logmiao" {
+				return dep.Version
+			}
+		}
+	}
+	return "devel"
+}
+
+// HAR 1.2 文档结构
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// newHAREntry 根据请求和捕获的响应构建 HAR 条目
+func newHAREntry(req *http.Request, reqBody []byte, resp *captureWriter, start time.Time, cfg HARConfig) harEntry {
+	elapsed := float64(time.Since(start).Microseconds()) / 1000
+
+	url := *req.URL
+	url.RawQuery = cfg.Redactor.Query(url.RawQuery)
+	if url.Host == "" {
+		url.Host = req.Host
+	}
+	if url.Scheme == "" {
+		url.Scheme = "http"
+		if req.TLS != nil {
+			url.Scheme = "https"
+		}
+	}
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            elapsed,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         url.String(),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    req.ContentLength,
+		},
+		Response: harResponse{
+			Status:      resp.Status(),
+			StatusText:  http.StatusText(resp.Status()),
+			HTTPVersion: req.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(resp.Header()),
+			Content: harContent{
+				Size:     resp.Size(),
+				MimeType: resp.Header().Get("Content-Type"),
+				Text:     truncateBody(cfg.Redactor.Body(resp.body.Bytes(), resp.Header().Get("Content-Type")), cfg.MaxBodySize),
+			},
+			HeadersSize: -1,
+			BodySize:    resp.Size(),
+		},
+		Timings: harTimings{Wait: elapsed},
+	}
+	for name, values := range url.Query() {
+		for _, v := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: v})
+		}
+	}
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     truncateBody(cfg.Redactor.Body(reqBody, req.Header.Get("Content-Type")), cfg.MaxBodySize),
+		}
+	}
+	return entry
+}
+
+// harHeaders 转换请求头，敏感头替换为 [FILTERED]
+func harHeaders(h http.Header) []harNameValue {
+	out := make([]harNameValue, 0, len(h))
+	for name, value := range filteredHeaders(h) {
+		out = append(out, harNameValue{Name: name, Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestHARExport 测试只有匹配状态码的请求写入 HAR 文件
+func TestHARExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	r := gin.New()
+	r.Use(HARWithConfig(HARConfig{Dir: dir, Statuses: []string{"4xx"}}))
+	r.GET("/items/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	for _, path := range []string{"/items/1", "/items/missing?verbose=1", "/items/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.har"))
+	if len(files) != 1 {
+		t.Fatalf("expected one HAR file, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	var doc harDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected one failed entry, got %d", len(doc.Log.Entries))
+	}
+	entry := doc.Log.Entries[0]
+	if entry.Response.Status != http.StatusNotFound || entry.Request.QueryString[0].Name != "verbose" {
+		t.Errorf("unexpected HAR entry: %+v", entry)
+	}
+}
+
+// TestHARAppend 测试条目逐个追加后文件仍是完整的 HAR，达到上限时新建文件，请求体与查询串已脱敏
+func TestHARAppend(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	r := gin.New()
+	r.Use(HARWithConfig(HARConfig{Dir: dir, Statuses: []string{"4xx"}, MaxEntries: 3}))
+	r.POST("/login", func(c *gin.Context) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "denied"})
+	})
+
+	for i := 0; i < 4; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/login?token=t0ps3cret", strings.NewReader(`{"user":"bob","password":"hunter2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.har"))
+	if len(files) != 2 {
+		t.Fatalf("expected two HAR files, got %d", len(files))
+	}
+	total := 0
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		var doc harDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s is not valid JSON: %v", f, err)
+		}
+		total += len(doc.Log.Entries)
+		if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "t0ps3cret") {
+			t.Errorf("HAR leaks secrets: %s", data)
+		}
+	}
+	if total != 4 {
+		t.Errorf("expected 4 entries, got %d", total)
+	}
+}
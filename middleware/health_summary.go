@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 健康检查请求的处理方式
+const (
+	HealthCheckDrop    = "drop"    // 不记录
+	HealthCheckSummary = "summary" // 按路径计数，每个周期输出一条汇总记录
+)
+
+// DefaultHealthSummaryInterval 默认的健康检查汇总周期
+const DefaultHealthSummaryInterval = time.Minute
+
+// healthSummary 汇总 SkipPaths 中的请求，周期内第一次请求到达时开始计时，周期结束时输出一条汇总
+type healthSummary struct {
+	mu       sync.Mutex
+	interval time.Duration
+	start    time.Time
+	counts   map[string]int
+	failures map[string]int
+	emit     func(ctx context.Context, msg string, attrs ...slog.Attr)
+}
+
+// newHealthSummary 创建健康检查汇总器，emit 为汇总记录的输出函数
+func newHealthSummary(interval time.Duration, emit func(ctx context.Context, msg string, attrs ...slog.Attr)) *healthSummary {
+	if interval <= 0 {
+		interval = DefaultHealthSummaryInterval
+	}
+	return &healthSummary{interval: interval, emit: emit}
+}
+
+// Observe 记录一次健康检查请求，4xx/5xx 计入 failures
+func (s *healthSummary) Observe(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.start = time.Now()
+		s.counts = make(map[string]int)
+		s.failures = make(map[string]int)
+		time.AfterFunc(s.interval, s.flush)
+	}
+	s.counts[path]++
+	if status >= 400 {
+		s.failures[path]++
+	}
+}
+
+// flush 输出当前周期的汇总并开始新的周期
+func (s *healthSummary) flush() {
+	s.mu.Lock()
+	counts, failures, start := s.counts, s.failures, s.start
+	s.counts, s.failures = nil, nil
+	s.mu.Unlock()
+	if len(counts) == 0 {
+		return
+	}
+
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	attrs := []slog.Attr{
+		slog.String("type", "health_summary"),
+		slog.Time("since", start),
+		slog.Duration("window", s.interval),
+		slog.Int("total", total),
+		slog.Any("paths", countAttrs(counts)),
+	}
+	if len(failures) > 0 {
+		attrs = append(attrs, slog.Any("failures", countAttrs(failures)))
+	}
+	s.emit(context.Background(), "Health check summary", attrs...)
+}
+
+// countAttrs 将计数按键排序转换为分组值
+func countAttrs(m map[string]int) slog.Value {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Int(k, m[k]))
+	}
+	return slog.GroupValue(attrs...)
+}
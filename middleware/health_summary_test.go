@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lockedBuffer 可并发写入和读取的缓冲区，汇总记录由定时器协程写出
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Len() int {
+	return len(b.String())
+}
+
+// TestHealthCheckSummary 测试 summary 模式下健康检查请求汇总为一条记录
+func TestHealthCheckSummary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf lockedBuffer
+	cfg := DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	cfg.SkipMode = HealthCheckSummary
+	cfg.SummaryInterval = 50 * time.Millisecond
+
+	r := gin.New()
+	r.Use(GinMiddlewareWithConfig(cfg))
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) })
+	for _, path := range []string{"/health", "/health", "/health", "/ping"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("health checks should not be logged individually: %s", buf.String())
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	var summary struct {
+		Msg      string         `json:"msg"`
+		Total    int            `json:"total"`
+		Paths    map[string]int `json:"paths"`
+		Failures map[string]int `json:"failures"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &summary); err != nil {
+		t.Fatalf("expected a single summary record: %v: %s", err, buf.String())
+	}
+	if summary.Total != 4 || summary.Paths["/health"] != 3 || summary.Failures["/ping"] != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
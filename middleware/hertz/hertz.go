@@ -0,0 +1,151 @@
+// Package hertz 提供 CloudWeGo Hertz 框架的日志中间件，与 Gin 集成保持一致：
+// 访问日志、请求ID、恢复和请求体记录。
+//
+// 该包是独立模块，避免主模块引入 Hertz 依赖：
+//
+//	go get github.com/shuakami/logmiao/middleware/hertz
+package hertz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/middleware"
+)
+
+// AccessLog 返回Hertz框架的访问日志中间件，配置来源与 logger.GinMiddleware 相同
+func AccessLog() app.HandlerFunc {
+	return AccessLogWithConfig(middleware.GinMiddlewareConfigFromGlobal())
+}
+
+// AccessLogWithConfig 返回带配置的Hertz框架访问日志中间件
+func AccessLogWithConfig(cfg middleware.GinMiddlewareConfig) app.HandlerFunc {
+	summary := middleware.NewSkipSummary(cfg)
+	return func(ctx context.Context, c *app.RequestContext) {
+		start := time.Now()
+		path := string(c.Request.URI().Path())
+
+		// 检查是否需要跳过记录
+		if cfg.Skipped(path) {
+			c.Next(ctx)
+			summary.Observe(path, c.Response.StatusCode())
+			return
+		}
+
+		method := string(c.Request.Method())
+		query := string(c.Request.URI().QueryString())
+
+		// Hertz 已缓冲请求体，直接读取不影响后续处理
+		var body []byte
+		if cfg.LogBody && middleware.ShouldLogRequestBody(method, string(c.Request.Header.ContentType())) {
+			body = append([]byte(nil), c.Request.Body()...)
+		}
+
+		// 请求级计时，供 StartSpan 累加
+		var timings *handler.SpanTimings
+		if cfg.TrackSpans {
+			ctx, timings = handler.ContextWithSpanTimings(ctx)
+		}
+
+		// 处理请求
+		c.Next(ctx)
+
+		var errs []string
+		for _, err := range c.Errors {
+			errs = append(errs, err.Error())
+		}
+
+		var extra []slog.Attr
+		if timings != nil {
+			extra = append(extra, timings.Attrs()...)
+		}
+		extra = append(extra, cfg.ContextValues(c.Get)...)
+		extra = append(extra, cfg.HeaderValues(func(header string) string {
+			return string(c.Response.Header.Peek(header))
+		})...)
+
+		middleware.LogAccess(ctx, cfg, middleware.AccessInfo{
+			Method:          method,
+			Path:            path,
+			Route:           c.FullPath(),
+			Query:           query,
+			Status:          c.Response.StatusCode(),
+			Latency:         time.Since(start),
+			ClientIP:        c.ClientIP(),
+			UserAgent:       string(c.UserAgent()),
+			Proto:           c.Request.Header.GetProtocol(),
+			RequestID:       requestIDOf(c),
+			RequestSize:     int64(len(c.Request.Body())),
+			ResponseSize:    int64(len(c.Response.Body())),
+			Header:          requestHeader(c),
+			Body:            body,
+			ContentEncoding: string(c.Request.Header.Peek("Content-Encoding")),
+			Errors:          errs,
+			Attrs:           extra,
+		})
+	}
+}
+
+// RequestID 请求ID中间件，为每个请求添加唯一标识符
+func RequestID() app.HandlerFunc {
+	return RequestIDWithConfig(middleware.DefaultRequestIDConfig())
+}
+
+// RequestIDWithConfig 返回带配置的请求ID中间件
+func RequestIDWithConfig(cfg middleware.RequestIDConfig) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		requestID, generated := cfg.Resolve(func(name string) string {
+			return string(c.GetHeader(name))
+		})
+		// Trailer 选项目前只作用于 Gin 中间件
+		if header := cfg.ResponseHeaderName(generated); header != "" {
+			c.Header(header, requestID)
+		}
+		c.Set("request_id", requestID)
+		c.Next(ctx)
+	}
+}
+
+// Recovery 带日志记录的恢复中间件
+func Recovery() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				slog.ErrorContext(ctx, "Panic recovered",
+					slog.String("type", "panic"),
+					slog.String("method", string(c.Request.Method())),
+					slog.String("path", string(c.Request.URI().Path())),
+					slog.String("client_ip", c.ClientIP()),
+					slog.String("error", fmt.Sprint(recovered)),
+					slog.String("user_agent", string(c.UserAgent())),
+					slog.String("stack", string(debug.Stack())),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next(ctx)
+	}
+}
+
+// requestIDOf 返回请求ID中间件设置的ID，未使用该中间件时读取 X-Request-ID 请求头
+func requestIDOf(c *app.RequestContext) string {
+	if id := c.GetString("request_id"); id != "" {
+		return id
+	}
+	return string(c.GetHeader(middleware.RequestIDHeader))
+}
+
+// requestHeader 将Hertz请求头转换为 http.Header
+func requestHeader(c *app.RequestContext) http.Header {
+	header := make(http.Header)
+	c.Request.Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+	return header
+}
@@ -0,0 +1,82 @@
+package hertz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/shuakami/logmiao/middleware"
+)
+
+// TestAccessLog 测试访问日志字段、请求ID和错误请求的请求体记录
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := middleware.DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(RequestID(), AccessLogWithConfig(cfg))
+	engine.POST("/orders/:id", func(ctx context.Context, c *app.RequestContext) {
+		c.String(http.StatusBadRequest, "invalid")
+	})
+	engine.GET("/health", func(ctx context.Context, c *app.RequestContext) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	body := `{"item":"book","qty":2}`
+	w := ut.PerformRequest(engine, http.MethodPost, "/orders/7?debug=1", &ut.Body{Body: strings.NewReader(body), Len: len(body)},
+		ut.Header{Key: "Content-Type", Value: "application/json"},
+		ut.Header{Key: middleware.RequestIDHeader, Value: "req-42"})
+	if got := w.Header().Get(middleware.RequestIDHeader); got != "req-42" {
+		t.Errorf("request ID header = %q, want req-42", got)
+	}
+	ut.PerformRequest(engine, http.MethodGet, "/health", nil)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("expected one access log (health checks skipped), got %d:\n%s", len(lines), buf.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(lines[0], &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"method": "POST", "path": "/orders/7", "route": "/orders/:id", "query": "debug=1",
+		"status": float64(400), "request_id": "req-42", "request_body": body,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v in %s", key, got[key], value, lines[0])
+		}
+	}
+}
+
+// TestRecovery 测试 panic 被恢复为 500 并记录错误日志
+func TestRecovery(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.Use(Recovery())
+	engine.GET("/boom", func(ctx context.Context, c *app.RequestContext) {
+		panic("nil map")
+	})
+
+	w := ut.PerformRequest(engine, http.MethodGet, "/boom", nil)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+	if out := buf.String(); !strings.Contains(out, `"msg":"Panic recovered"`) || !strings.Contains(out, `"error":"nil map"`) {
+		t.Errorf("expected a panic log, got %s", out)
+	}
+}
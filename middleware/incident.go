@@ -0,0 +1,295 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/utils"
+)
+
+// IncidentConfig 5xx 请求现场转储配置
+type IncidentConfig struct {
+	Dir         string            // 转储目录
+	RingSize    int               // 每个请求保留的最近记录数（含 Debug 级别）
+	MaxBodySize int               // 请求体和响应体最大记录字节数
+	Redactor    *handler.Redactor // 请求体、响应体与查询串的脱敏规则，为nil时使用 handler.DefaultRedactKeys
+}
+
+// DefaultIncidentConfig 默认转储配置
+func DefaultIncidentConfig() IncidentConfig {
+	return IncidentConfig{
+		Dir:         "logs/incidents",
+		RingSize:    100,
+		MaxBodySize: 64 * 1024,
+		Redactor:    handler.NewRedactor(handler.DefaultRedactKeys, nil),
+	}
+}
+
+// Incident 5xx 现场转储中间件，配置来自 logger.middleware.incident
+func Incident() gin.HandlerFunc {
+	cfg := DefaultIncidentConfig()
+	if config.GlobalConfig != nil {
+		incident := config.GlobalConfig.Logger.Middleware.Incident
+		if incident.Dir != "" {
+			cfg.Dir = incident.Dir
+		}
+		if incident.RingSize > 0 {
+			cfg.RingSize = incident.RingSize
+		}
+		if incident.MaxBodySize > 0 {
+			cfg.MaxBodySize = incident.MaxBodySize
+		}
+		cfg.Redactor = configRedactor(config.GlobalConfig.Logger.Features.Privacy)
+	}
+	return IncidentWithConfig(cfg)
+}
+
+// IncidentWithConfig 返回带配置的现场转储中间件。
+// 请求以 5xx 结束或发生 panic 时，将请求头、请求体、响应体、堆栈和该请求最近的日志记录
+// 写入 Dir 下的独立文件并记录文件路径；panic 会在转储后重新抛出，交给外层 Recovery 处理。
+// 请求体、响应体和查询串按 Redactor 脱敏后写入
+func IncidentWithConfig(cfg IncidentConfig) gin.HandlerFunc {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultIncidentConfig().MaxBodySize
+	}
+	if cfg.Redactor == nil {
+		cfg.Redactor = DefaultIncidentConfig().Redactor
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		ring := handler.NewRecordRing(cfg.RingSize)
+		c.Request = c.Request.WithContext(handler.WithRecordRing(c.Request.Context(), ring))
+
+		var reqBody []byte
+		if c.Request.Body != nil {
+			reqBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(cfg.MaxBodySize)+1))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(reqBody), c.Request.Body))
+		}
+		capture := &captureWriter{ResponseWriter: c.Writer, limit: cfg.MaxBodySize}
+		c.Writer = capture
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				stack := string(debug.Stack())
+				writeIncident(c, cfg, start, ring, reqBody, capture, fmt.Sprint(recovered), stack)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			writeIncident(c, cfg, start, ring, reqBody, capture, "", "")
+		}
+	}
+}
+
+// captureWriter 在写出响应的同时保留响应体副本
+type captureWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if room := w.limit - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(room, len(b))])
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// incidentBundle 现场转储文件内容
+type incidentBundle struct {
+	Time      time.Time         `json:"time"`
+	RequestID string            `json:"request_id,omitempty"`
+	Latency   string            `json:"latency"`
+	Request   incidentRequest   `json:"request"`
+	Response  incidentResponse  `json:"response"`
+	Panic     string            `json:"panic,omitempty"`
+	Stack     string            `json:"stack,omitempty"`
+	Errors    []string          `json:"errors,omitempty"`
+	Records   []incidentRecord  `json:"records"`
+	Context   map[string]string `json:"context,omitempty"`
+}
+
+type incidentRequest struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Proto    string            `json:"proto"`
+	ClientIP string            `json:"client_ip"`
+	Headers  map[string]string `json:"headers"`
+	Body     string            `json:"body,omitempty"`
+}
+
+type incidentResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body,omitempty"`
+}
+
+type incidentRecord struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"msg"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// writeIncident 写出转储文件并记录其路径
+func writeIncident(c *gin.Context, cfg IncidentConfig, start time.Time, ring *handler.RecordRing,
+	reqBody []byte, capture *captureWriter, panicValue, stack string) {
+	status := c.Writer.Status()
+	if panicValue != "" {
+		status = http.StatusInternalServerError
+	}
+	requestID := c.GetString("request_id")
+	reqURL := *c.Request.URL
+	reqURL.RawQuery = cfg.Redactor.Query(reqURL.RawQuery)
+
+	bundle := incidentBundle{
+		Time:      start,
+		RequestID: requestID,
+		Latency:   time.Since(start).String(),
+		Request: incidentRequest{
+			Method:   c.Request.Method,
+			URL:      reqURL.String(),
+			Proto:    c.Request.Proto,
+			ClientIP: utils.GetClientIP(c),
+			Headers:  filteredHeaders(c.Request.Header),
+			Body:     truncateBody(cfg.Redactor.Body(reqBody, c.Request.Header.Get("Content-Type")), cfg.MaxBodySize),
+		},
+		Response: incidentResponse{
+			Status:  status,
+			Headers: filteredHeaders(capture.Header()),
+			Body:    truncateBody(cfg.Redactor.Body(capture.body.Bytes(), capture.Header().Get("Content-Type")), cfg.MaxBodySize),
+		},
+		Panic: panicValue,
+		Stack: stack,
+	}
+	for _, err := range c.Errors {
+		bundle.Errors = append(bundle.Errors, err.Error())
+	}
+	for _, a := range handler.AttrsFromContext(c.Request.Context()) {
+		if bundle.Context == nil {
+			bundle.Context = make(map[string]string)
+		}
+		bundle.Context[a.Key] = a.Value.String()
+	}
+	for _, r := range ring.Records() {
+		rec := incidentRecord{Time: r.Time, Level: r.Level.String(), Message: r.Message}
+		r.Attrs(func(a slog.Attr) bool {
+			if rec.Attrs == nil {
+				rec.Attrs = make(map[string]interface{})
+			}
+			rec.Attrs[a.Key] = attrValue(a.Value)
+			return true
+		})
+		bundle.Records = append(bundle.Records, rec)
+	}
+
+	path, err := saveIncident(cfg.Dir, start, requestID, bundle)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to write incident bundle", fields.Err(err))
+		return
+	}
+	slog.LogAttrs(c.Request.Context(), slog.LevelError, "Incident bundle written",
+		fields.Type("incident"),
+		fields.Path(c.Request.URL.Path),
+		fields.HTTPStatus(status),
+		fields.RequestID(requestID),
+		slog.String("bundle", path),
+	)
+}
+
+// saveIncident 将转储内容写入独立文件
+func saveIncident(dir string, start time.Time, requestID string, bundle incidentBundle) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := start.Format("20060102-150405.000000")
+	if requestID != "" {
+		name += "-" + sanitizeFileName(requestID)
+	}
+	path := filepath.Join(dir, name+".json")
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// filteredHeaders 复制请求头，敏感头替换为 [FILTERED]
+func filteredHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name, values := range h {
+		if isSensitiveHeader(name) {
+			out[name] = "[FILTERED]"
+		} else {
+			out[name] = strings.Join(values, ", ")
+		}
+	}
+	return out
+}
+
+// attrValue 属性值的 JSON 形式，分组转换为嵌套的 map
+func attrValue(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	m := make(map[string]any, len(v.Group()))
+	for _, ga := range v.Group() {
+		m[ga.Key] = attrValue(ga.Value)
+	}
+	return m
+}
+
+// configRedactor 按 features.privacy 的 redact_keys 与 scrub 规则创建请求体脱敏器，
+// 未配置 redact_keys 时与未加载配置时相同，使用 handler.DefaultRedactKeys；
+// 扫描规则无效时（createLogger 已报告）只按键脱敏
+func configRedactor(cfg config.PrivacyConfig) *handler.Redactor {
+	keys := cfg.RedactKeys
+	if len(keys) == 0 {
+		keys = handler.DefaultRedactKeys
+	}
+	scrubber, _ := handler.NewScrubber(cfg.Scrub, cfg.ScrubPatterns)
+	return handler.NewRedactor(keys, scrubber)
+}
+
+// truncateBody 截断过长的请求体或响应体
+func truncateBody(body string, max int) string {
+	if len(body) > max {
+		return body[:max] + "...(truncated)"
+	}
+	return body
+}
+
+// sanitizeFileName 去除文件名中的路径分隔符等字符
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// TestIncidentBundle 测试 5xx 请求写出包含请求、响应和 Debug 记录的转储文件
+func TestIncidentBundle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+	slog.SetDefault(slog.New(handler.NewRingHandler(slog.NewTextHandler(io.Discard, nil))))
+
+	dir := t.TempDir()
+	r := gin.New()
+	r.Use(IncidentWithConfig(IncidentConfig{Dir: dir, RingSize: 10}))
+	r.POST("/orders", func(c *gin.Context) {
+		slog.DebugContext(c.Request.Context(), "loading cart", "cart_id", 42, slog.Group("user", "id", 7))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "boom", "token": "tok-123"})
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?api_key=k1", strings.NewReader(`{"sku":"A1","card":{"cvv":123},"password":"hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one incident bundle, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	var bundle incidentBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"hunter2", "123", "tok-123", "k1"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("bundle leaks %q: %s", leaked, data)
+		}
+	}
+	if !strings.Contains(bundle.Request.Body, `"sku":"A1"`) || !strings.Contains(bundle.Response.Body, `"error":"boom"`) {
+		t.Errorf("bundle bodies = %q / %q", bundle.Request.Body, bundle.Response.Body)
+	}
+	if bundle.Request.Headers["Authorization"] != "[FILTERED]" {
+		t.Error("sensitive headers should be filtered")
+	}
+	if len(bundle.Records) != 1 || bundle.Records[0].Message != "loading cart" {
+		t.Errorf("debug records should be captured, got %+v", bundle.Records)
+	} else if user, ok := bundle.Records[0].Attrs["user"].(map[string]any); !ok || user["id"] != float64(7) {
+		t.Errorf("group attrs should be nested objects, got %+v", bundle.Records[0].Attrs)
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/utils"
+)
+
+// RequestIDHeader 默认的请求ID请求头
+const RequestIDHeader = "X-Request-ID"
+
+// 请求ID写回响应的时机
+const (
+	ResponseAlways    = "always"    // 总是写回
+	ResponseGenerated = "generated" // 只在新生成ID时写回，入站请求已带ID时不写回
+	ResponseNever     = "never"     // 不写回
+)
+
+// RequestIDConfig 请求ID中间件配置
+type RequestIDConfig struct {
+	Generator func() string // ID生成函数，为空时使用 utils.GenerateRequestID
+	Headers   []string      // 按顺序读取的入站请求头，为空时只读取 X-Request-ID
+
+	ResponseHeader string // 写回请求ID的响应头，为空时使用 X-Request-ID
+	Response       string // 写回时机：always（默认）、generated、never
+	Trailer        bool   // 处理完成后再以 HTTP trailer 写回，供流式响应的客户端在响应结束时读取
+}
+
+// DefaultRequestIDConfig 按全局配置创建请求ID中间件配置，格式无效时回退到默认格式
+func DefaultRequestIDConfig() RequestIDConfig {
+	cfg := RequestIDConfig{Generator: utils.GenerateRequestID, Headers: []string{RequestIDHeader}}
+	if config.GlobalConfig == nil {
+		return cfg
+	}
+	idCfg := config.GlobalConfig.Logger.Middleware.RequestID
+	if gen, err := utils.NewIDGenerator(idCfg.Format, idCfg.Prefix, idCfg.NodeID); err != nil {
+		slog.Warn("Invalid request id format, using default", slog.String("format", idCfg.Format))
+	} else {
+		cfg.Generator = gen
+	}
+	if len(idCfg.Headers) > 0 {
+		cfg.Headers = idCfg.Headers
+	}
+	cfg.ResponseHeader = idCfg.ResponseHeader
+	cfg.Response = idCfg.Response
+	cfg.Trailer = idCfg.Trailer
+	return cfg
+}
+
+// ResponseHeaderName 返回写回请求ID的响应头，generated 表示ID为新生成，不需要写回时返回空
+func (cfg RequestIDConfig) ResponseHeaderName(generated bool) string {
+	switch cfg.Response {
+	case ResponseNever:
+		return ""
+	case ResponseGenerated:
+		if !generated {
+			return ""
+		}
+	}
+	if cfg.ResponseHeader == "" {
+		return RequestIDHeader
+	}
+	return cfg.ResponseHeader
+}
+
+// Resolve 从入站请求头读取请求ID，都没有时生成新ID，generated 表示ID为新生成
+func (cfg RequestIDConfig) Resolve(header func(string) string) (id string, generated bool) {
+	headers := cfg.Headers
+	if len(headers) == 0 {
+		headers = []string{RequestIDHeader}
+	}
+	for _, name := range headers {
+		if value := strings.TrimSpace(header(name)); value != "" {
+			if http.CanonicalHeaderKey(name) == "X-Amzn-Trace-Id" {
+				value = amznTraceRoot(value)
+			}
+			if value != "" {
+				return value, false
+			}
+		}
+	}
+	if cfg.Generator != nil {
+		return cfg.Generator(), true
+	}
+	return utils.GenerateRequestID(), true
+}
+
+// amznTraceRoot 提取 X-Amzn-Trace-Id 中的 Root 字段，如 Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1
+func amznTraceRoot(value string) string {
+	for _, part := range strings.Split(value, ";") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok && k == "Root" {
+			return v
+		}
+	}
+	return ""
+}
+
+// RequestID 中间件，为每个请求添加唯一标识符
+func RequestID() gin.HandlerFunc {
+	return RequestIDWithConfig(DefaultRequestIDConfig())
+}
+
+// RequestIDWithConfig 返回带配置的请求ID中间件
+func RequestIDWithConfig(cfg RequestIDConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID, generated := cfg.Resolve(c.GetHeader)
+		header := cfg.ResponseHeaderName(generated)
+		if header != "" {
+			c.Header(header, requestID)
+		}
+		c.Set("request_id", requestID)
+		c.Next()
+
+		// 带 TrailerPrefix 的键在响应体之后作为 trailer 发送，无需事先声明
+		if header != "" && cfg.Trailer {
+			c.Writer.Header().Set(http.TrailerPrefix+header, requestID)
+		}
+	}
+}
+
+// requestIDOf 返回请求ID中间件设置的ID，未使用该中间件时读取 X-Request-ID 请求头
+func requestIDOf(c *gin.Context) string {
+	if id := c.GetString("request_id"); id != "" {
+		return id
+	}
+	return c.GetHeader(RequestIDHeader)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRequestIDResolve 测试按顺序读取入站请求头，X-Amzn-Trace-Id 只取 Root
+func TestRequestIDResolve(t *testing.T) {
+	cfg := RequestIDConfig{
+		Generator: func() string { return "generated" },
+		Headers:   []string{"X-Request-ID", "X-Correlation-ID", "X-Amzn-Trace-Id"},
+	}
+
+	cases := []struct {
+		header    http.Header
+		want      string
+		generated bool
+	}{
+		{http.Header{"X-Correlation-Id": {"corr-1"}}, "corr-1", false},
+		{http.Header{"X-Request-Id": {"req-1"}, "X-Correlation-Id": {"corr-1"}}, "req-1", false},
+		{http.Header{"X-Amzn-Trace-Id": {"Root=1-5759e988-bd862e3fe1be46a994272793;Sampled=1"}}, "1-5759e988-bd862e3fe1be46a994272793", false},
+		{http.Header{}, "generated", true},
+	}
+	for _, c := range cases {
+		id, generated := cfg.Resolve(c.header.Get)
+		if id != c.want || generated != c.generated {
+			t.Errorf("Resolve(%v) = %q, %v", c.header, id, generated)
+		}
+	}
+}
+
+// TestRequestIDResponse 测试写回的响应头名称、只在生成时写回以及 trailer
+func TestRequestIDResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	generator := func() string { return "generated" }
+	cases := []struct {
+		cfg        RequestIDConfig
+		inbound    string
+		wantHeader string
+		wantValue  string
+	}{
+		{RequestIDConfig{}, "in-1", "X-Request-Id", "in-1"},
+		{RequestIDConfig{ResponseHeader: "X-Trace-Ref"}, "", "X-Trace-Ref", "generated"},
+		{RequestIDConfig{Response: ResponseGenerated}, "in-1", "X-Request-Id", ""},
+		{RequestIDConfig{Response: ResponseGenerated}, "", "X-Request-Id", "generated"},
+		{RequestIDConfig{Response: ResponseNever}, "", "X-Request-Id", ""},
+	}
+	for _, c := range cases {
+		c.cfg.Generator = generator
+		r := gin.New()
+		r.Use(RequestIDWithConfig(c.cfg))
+		r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if c.inbound != "" {
+			req.Header.Set("X-Request-ID", c.inbound)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Header().Get(c.wantHeader); got != c.wantValue {
+			t.Errorf("%+v: %s = %q, want %q", c.cfg, c.wantHeader, got, c.wantValue)
+		}
+	}
+
+	r := gin.New()
+	r.Use(RequestIDWithConfig(RequestIDConfig{Generator: generator, Trailer: true}))
+	r.GET("/stream", func(c *gin.Context) {
+		c.Writer.WriteString("chunk")
+		c.Writer.Flush()
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	if got := w.Result().Trailer.Get("X-Request-ID"); got != "generated" {
+		t.Errorf("trailer X-Request-ID = %q", got)
+	}
+}
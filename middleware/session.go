@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/utils"
+)
+
+// SessionConfig 会话生命周期日志配置
+type SessionConfig struct {
+	Cookie      string        // 会话Cookie名
+	Header      string        // 会话请求头，Cookie不存在时使用
+	IdleTimeout time.Duration // 超过该时长无请求视为会话过期
+}
+
+// DefaultSessionConfig 默认会话配置
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		Cookie:      "session_id",
+		Header:      "X-Session-ID",
+		IdleTimeout: 30 * time.Minute,
+	}
+}
+
+// Session 会话生命周期日志中间件，配置来自 logger.middleware.session
+func Session() gin.HandlerFunc {
+	cfg := DefaultSessionConfig()
+	if config.GlobalConfig != nil {
+		session := config.GlobalConfig.Logger.Middleware.Session
+		cfg.Cookie = session.Cookie
+		cfg.Header = session.Header
+		if session.IdleTimeout > 0 {
+			cfg.IdleTimeout = session.IdleTimeout
+		}
+	}
+	return SessionWithConfig(cfg)
+}
+
+// SessionWithConfig 返回带配置的会话中间件，首次出现的会话记录 Session started，
+// 空闲超时的会话记录 Session expired，脱敏后的 session_id 绑定到请求内所有日志
+func SessionWithConfig(cfg SessionConfig) gin.HandlerFunc {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = DefaultSessionConfig().IdleTimeout
+	}
+	tracker := newSessionTracker(cfg.IdleTimeout)
+	return func(c *gin.Context) {
+		id := ""
+		if cfg.Cookie != "" {
+			id, _ = c.Cookie(cfg.Cookie)
+		}
+		if id == "" && cfg.Header != "" {
+			id = c.GetHeader(cfg.Header)
+		}
+		if id == "" {
+			c.Next()
+			return
+		}
+
+		masked := utils.MaskSessionID(id)
+		ctx := handler.ContextWithAttrs(c.Request.Context(), fields.SessionID(masked))
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(fields.KeySessionID, masked)
+
+		if tracker.touch(ctx, masked, time.Now()) {
+			slog.LogAttrs(ctx, slog.LevelInfo, "Session started",
+				fields.Type("session"),
+				fields.SessionID(masked),
+				fields.ClientIP(utils.GetClientIP(c)),
+				fields.UserAgent(c.Request.UserAgent()),
+			)
+		}
+		c.Next()
+	}
+}
+
+// sessionState 单个会话的活动状态
+type sessionState struct {
+	start    time.Time
+	last     time.Time
+	requests int64
+}
+
+// sessionTracker 记录活跃会话，有活跃会话时每半个空闲超时清理一次，没有新请求也能及时记录过期
+type sessionTracker struct {
+	mu       sync.Mutex
+	timeout  time.Duration
+	sessions map[string]*sessionState
+	sweeping bool // 清理定时器是否已启动
+}
+
+// newSessionTracker 创建会话跟踪器
+func newSessionTracker(timeout time.Duration) *sessionTracker {
+	return &sessionTracker{
+		timeout:  timeout,
+		sessions: make(map[string]*sessionState),
+	}
+}
+
+// touch 记录一次会话活动，返回是否为新会话；清理前再次出现的过期会话在此时记录过期日志
+func (t *sessionTracker) touch(ctx context.Context, id string, now time.Time) bool {
+	var expired []slog.Attr
+
+	t.mu.Lock()
+	s, ok := t.sessions[id]
+	if ok && now.Sub(s.last) > t.timeout {
+		expired = sessionExpiredAttrs(id, s)
+		ok = false
+	}
+	if !ok {
+		s = &sessionState{start: now}
+		t.sessions[id] = s
+	}
+	s.last = now
+	s.requests++
+	if !t.sweeping {
+		t.sweeping = true
+		time.AfterFunc(t.timeout/2, t.sweep)
+	}
+	t.mu.Unlock()
+
+	if expired != nil {
+		slog.LogAttrs(context.WithoutCancel(ctx), slog.LevelInfo, "Session expired", expired...)
+	}
+	return !ok
+}
+
+// sweep 记录空闲超时的会话，仍有活跃会话时继续定期清理
+func (t *sessionTracker) sweep() {
+	expired := t.expire(time.Now())
+
+	t.mu.Lock()
+	if len(t.sessions) > 0 {
+		time.AfterFunc(t.timeout/2, t.sweep)
+	} else {
+		t.sweeping = false
+	}
+	t.mu.Unlock()
+
+	for _, attrs := range expired {
+		slog.LogAttrs(context.Background(), slog.LevelInfo, "Session expired", attrs...)
+	}
+}
+
+// expire 移除 now 时已空闲超时的会话，返回它们的过期日志属性
+func (t *sessionTracker) expire(now time.Time) [][]slog.Attr {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var expired [][]slog.Attr
+	for id, s := range t.sessions {
+		if now.Sub(s.last) > t.timeout {
+			expired = append(expired, sessionExpiredAttrs(id, s))
+			delete(t.sessions, id)
+		}
+	}
+	return expired
+}
+
+// sessionExpiredAttrs 过期会话的汇总属性
+func sessionExpiredAttrs(id string, s *sessionState) []slog.Attr {
+	return []slog.Attr{
+		fields.Type("session"),
+		fields.SessionID(id),
+		fields.Duration(s.last.Sub(s.start)),
+		slog.Int64("requests", s.requests),
+	}
+}
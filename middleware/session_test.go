@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSessionTracker 测试会话开始与空闲过期判定
+func TestSessionTracker(t *testing.T) {
+	tracker := newSessionTracker(time.Minute)
+	now := time.Now()
+	ctx := context.Background()
+
+	if !tracker.touch(ctx, "a", now) {
+		t.Error("first request should start a session")
+	}
+	if tracker.touch(ctx, "a", now.Add(30*time.Second)) {
+		t.Error("request within idle timeout should continue the session")
+	}
+	if !tracker.touch(ctx, "a", now.Add(5*time.Minute)) {
+		t.Error("request after idle timeout should start a new session")
+	}
+	if s := tracker.sessions["a"]; s.requests != 1 {
+		t.Errorf("restarted session should reset request count, got %d", s.requests)
+	}
+}
+
+// syncBuffer 并发安全的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestSessionTrackerSweep 测试没有新请求时空闲会话也会被定期清理并记录过期
+func TestSessionTrackerSweep(t *testing.T) {
+	var out syncBuffer
+	prev := slog.Default()
+	defer slog.SetDefault(prev)
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, nil)))
+
+	tracker := newSessionTracker(20 * time.Millisecond)
+	tracker.touch(context.Background(), "sess_a", time.Now())
+	tracker.touch(context.Background(), "sess_a", time.Now())
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(out.String(), "Session expired") {
+		if time.Now().After(deadline) {
+			t.Fatal("idle session was not expired without further requests")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "requests=2") {
+		t.Errorf("expired log should carry the request count: %s", out.String())
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if len(tracker.sessions) != 0 {
+		t.Errorf("expired session should be removed, %d left", len(tracker.sessions))
+	}
+}
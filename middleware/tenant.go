@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/fields"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// TenantConfig 租户提取配置，按 Header、JWT声明、子域名的顺序查找
+type TenantConfig struct {
+	Header    string                    // 携带租户标识的请求头，如 X-Tenant-ID
+	JWTClaim  string                    // Authorization Bearer 令牌中的声明名，如 org_id
+	Subdomain bool                      // 是否从子域名提取，如 acme.example.com -> acme
+	Extractor func(*gin.Context) string // 自定义提取函数，优先于以上方式
+}
+
+// DefaultTenantConfig 默认租户提取配置
+func DefaultTenantConfig() TenantConfig {
+	return TenantConfig{Header: "X-Tenant-ID"}
+}
+
+// Tenant 租户提取中间件，配置来自 logger.middleware.tenant
+func Tenant() gin.HandlerFunc {
+	cfg := DefaultTenantConfig()
+	if config.GlobalConfig != nil {
+		tenant := config.GlobalConfig.Logger.Middleware.Tenant
+		cfg.Header = tenant.Header
+		cfg.JWTClaim = tenant.JWTClaim
+		cfg.Subdomain = tenant.Subdomain
+	}
+	return TenantWithConfig(cfg)
+}
+
+// TenantWithConfig 返回带配置的租户提取中间件，
+// 租户标识写入 gin 上下文的 tenant_id，并绑定到访问日志和 FromContext 返回的日志器
+func TenantWithConfig(cfg TenantConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tenant := extractTenant(c, cfg); tenant != "" {
+			c.Set(fields.KeyTenantID, tenant)
+			c.Request = c.Request.WithContext(handler.ContextWithAttrs(c.Request.Context(), fields.TenantID(tenant)))
+		}
+		c.Next()
+	}
+}
+
+// extractTenant 按配置顺序提取租户标识
+func extractTenant(c *gin.Context, cfg TenantConfig) string {
+	if cfg.Extractor != nil {
+		if tenant := cfg.Extractor(c); tenant != "" {
+			return tenant
+		}
+	}
+	if cfg.Header != "" {
+		if tenant := strings.TrimSpace(c.GetHeader(cfg.Header)); tenant != "" {
+			return tenant
+		}
+	}
+	if cfg.JWTClaim != "" {
+		if tenant := jwtClaim(c.GetHeader("Authorization"), cfg.JWTClaim); tenant != "" {
+			return tenant
+		}
+	}
+	if cfg.Subdomain {
+		return subdomain(c.Request.Host)
+	}
+	return ""
+}
+
+// jwtClaim 读取 Bearer 令牌载荷中的声明，不校验签名，仅用于日志标注
+func jwtClaim(authorization, claim string) string {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ""
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	switch v := claims[claim].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+// subdomain 提取主机名最左侧的子域名，IP地址、二级域名和 www 返回空
+func subdomain(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) < 3 || labels[0] == "www" {
+		return ""
+	}
+	return labels[0]
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// TestExtractTenantSources 测试从 JWT 声明和子域名提取租户
+func TestExtractTenantSources(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"org_id":"acme","seq":42}`))
+	auth := "Bearer header." + payload + ".sig"
+	if got := jwtClaim(auth, "org_id"); got != "acme" {
+		t.Errorf("jwtClaim(org_id) = %q", got)
+	}
+	if got := jwtClaim(auth, "seq"); got != "42" {
+		t.Errorf("jwtClaim(seq) = %q", got)
+	}
+	if got := jwtClaim("Basic abc", "org_id"); got != "" {
+		t.Errorf("non-bearer authorization should be ignored, got %q", got)
+	}
+
+	cases := map[string]string{
+		"acme.example.com:8080": "acme",
+		"www.example.com":       "",
+		"example.com":           "",
+		"10.0.0.1:80":           "",
+	}
+	for host, want := range cases {
+		if got := subdomain(host); got != want {
+			t.Errorf("subdomain(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// handlerStartKey MarkHandlerStart 记录处理函数开始时间的上下文键
+const handlerStartKey = "logmiao_handler_start"
+
+// MarkHandlerStart 返回标记处理函数开始时间的中间件，需作为最后一个中间件注册，
+// 开启 PhaseTimings 时据此区分中间件链与处理函数的耗时
+func MarkHandlerStart() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(handlerStartKey, time.Now())
+		c.Next()
+	}
+}
+
+// timingWriter 记录首字节写出时间的 ResponseWriter
+type timingWriter struct {
+	gin.ResponseWriter
+	firstByte time.Time
+}
+
+func (w *timingWriter) mark() {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+	}
+}
+
+func (w *timingWriter) WriteHeaderNow() {
+	w.mark()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingWriter) Write(p []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.mark()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timingWriter) Flush() {
+	w.mark()
+	w.ResponseWriter.Flush()
+}
+
+// ttfb 返回首字节耗时，未写出响应时为 0
+func (w *timingWriter) ttfb(start time.Time) time.Duration {
+	if w.firstByte.IsZero() {
+		return 0
+	}
+	return w.firstByte.Sub(start)
+}
+
+// phaseAttrs 按请求生命周期拆分耗时：middleware（开始至处理函数，需 MarkHandlerStart）、
+// handler（处理函数至首字节）、render（首字节至完成）
+func (w *timingWriter) phaseAttrs(c *gin.Context, start, end time.Time) []slog.Attr {
+	var phases []any
+	handlerStart := start
+	if v, ok := c.Get(handlerStartKey); ok {
+		if t, ok := v.(time.Time); ok {
+			handlerStart = t
+			phases = append(phases, slog.Float64("middleware_ms", millis(t.Sub(start))))
+		}
+	}
+	firstByte := w.firstByte
+	if firstByte.IsZero() {
+		firstByte = end
+	}
+	phases = append(phases,
+		slog.Float64("handler_ms", millis(firstByte.Sub(handlerStart))),
+		slog.Float64("render_ms", millis(end.Sub(firstByte))),
+	)
+	return []slog.Attr{slog.Group("phases", phases...)}
+}
+
+// millis 将耗时转换为毫秒
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+const (
+	// defaultOverrideTTL 未指定有效期时级别覆盖的持续时间
+	defaultOverrideTTL = 10 * time.Minute
+	// maxOverrideTTL 级别覆盖的最长有效期，避免遗忘的调试规则长期生效
+	maxOverrideTTL = 24 * time.Hour
+)
+
+// levelOverrides 运行时级别覆盖规则，重新加载配置后保留直到到期
+var levelOverrides = handler.NewLevelOverrides()
+
+// OverrideLevel 在 ttl 内将属性 key 的值等于 value 的记录（如 module=payments）按 level 输出，
+// 不修改配置也无需重启；ttl<=0 时为10分钟，最长24小时。相同 key 与 value 的规则被替换
+func OverrideLevel(key, value string, level slog.Level, ttl time.Duration) handler.LevelOverride {
+	if ttl <= 0 {
+		ttl = defaultOverrideTTL
+	}
+	ttl = min(ttl, maxOverrideTTL)
+	rule := handler.LevelOverride{Key: key, Value: value, Level: level, Expires: time.Now().Add(ttl)}
+	levelOverrides.Set(rule)
+	GetLogger().Info("Level override set",
+		slog.String("key", key), slog.String("value", value),
+		slog.String("level", level.String()), slog.Time("expires", rule.Expires))
+	return rule
+}
+
+// RemoveLevelOverride 提前删除级别覆盖规则，返回规则是否存在
+func RemoveLevelOverride(key, value string) bool {
+	if !levelOverrides.Remove(key, value) {
+		return false
+	}
+	GetLogger().Info("Level override removed", slog.String("key", key), slog.String("value", value))
+	return true
+}
+
+// LevelOverrides 返回尚未到期的级别覆盖规则
+func LevelOverrides() []handler.LevelOverride {
+	return levelOverrides.List()
+}
+
+// LevelOverrideHandler 返回管理级别覆盖规则的HTTP处理器：GET 列出规则，
+// POST {"key":"module","value":"payments","level":"debug","ttl":"10m"} 添加，DELETE ?key=&value= 删除。
+// 处理器本身不做认证，启用 viewer.admin 时挂载在查看器的 /api/levels 上并使用查看器的认证
+func LevelOverrideHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req struct {
+				Key   string `json:"key"`
+				Value string `json:"value"`
+				Level string `json:"level"`
+				TTL   string `json:"ttl"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if req.Key == "" {
+				http.Error(w, "key is required", http.StatusBadRequest)
+				return
+			}
+			level := slog.LevelDebug
+			if req.Level != "" {
+				if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+					http.Error(w, fmt.Sprintf("invalid level %q", req.Level), http.StatusBadRequest)
+					return
+				}
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				var err error
+				if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+					http.Error(w, fmt.Sprintf("invalid ttl %q", req.TTL), http.StatusBadRequest)
+					return
+				}
+			}
+			OverrideLevel(req.Key, req.Value, level, ttl)
+		case http.MethodDelete:
+			if !RemoveLevelOverride(r.URL.Query().Get("key"), r.URL.Query().Get("value")) {
+				http.Error(w, "override not found", http.StatusNotFound)
+				return
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(LevelOverrides())
+	})
+}
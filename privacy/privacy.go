@@ -0,0 +1,235 @@
+// Package privacy 提供在类型层面保证脱敏的值：Secret 无论通过日志、fmt 还是 JSON 输出都不会泄露原文，
+// Struct 按字段标签脱敏结构体，不依赖按键名猜测哪些字段敏感
+package privacy
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Redacted 敏感值的输出形式
+const Redacted = "[REDACTED]"
+
+// Secret 敏感字符串，如密码、令牌、密钥。实现 slog.LogValuer、fmt.Formatter 和 encoding.TextMarshaler，
+// 任何输出方式都只显示 [REDACTED]；需要原文时显式调用 Reveal
+type Secret string
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+func (s Secret) String() string {
+	return Redacted
+}
+
+// Format 使 %v、%s、%q、%#v 等所有格式化动词都输出 [REDACTED]
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = io.WriteString(f, `"`+Redacted+`"`)
+		return
+	}
+	_, _ = io.WriteString(f, Redacted)
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// Reveal 返回原文
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// Sensitive 任意类型的敏感值，如凭据结构体、令牌列表，输出方式与 Secret 相同；
+// 值在日志处理器看到之前就已替换，即使处理器没有经过按键脱敏也不会泄露
+type Sensitive[T any] struct {
+	value T
+}
+
+// Hide 将值包装为 Sensitive
+func Hide[T any](v T) Sensitive[T] {
+	return Sensitive[T]{value: v}
+}
+
+func (s Sensitive[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+func (s Sensitive[T]) String() string {
+	return Redacted
+}
+
+func (s Sensitive[T]) Format(f fmt.State, verb rune) {
+	Secret("").Format(f, verb)
+}
+
+func (s Sensitive[T]) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// Reveal 返回原值
+func (s Sensitive[T]) Reveal() T {
+	return s.value
+}
+
+// Masked 部分脱敏的字符串，保留首尾少量字符便于排查（如 ab****yz），其余同 Secret
+type Masked string
+
+func (s Masked) LogValue() slog.Value {
+	return slog.StringValue(Mask(string(s)))
+}
+
+func (s Masked) String() string {
+	return Mask(string(s))
+}
+
+func (s Masked) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprintf(f, "%q", Mask(string(s)))
+		return
+	}
+	_, _ = io.WriteString(f, Mask(string(s)))
+}
+
+func (s Masked) MarshalText() ([]byte, error) {
+	return []byte(Mask(string(s))), nil
+}
+
+// Reveal 返回原文
+func (s Masked) Reveal() string {
+	return string(s)
+}
+
+// Mask 部分脱敏：不超过4个字符时全部替换为*，否则保留首尾各两个字符
+func Mask(s string) string {
+	n := utf8.RuneCountInString(s)
+	if n <= 4 {
+		return strings.Repeat("*", n)
+	}
+	runes := []rune(s)
+	return string(runes[:2]) + strings.Repeat("*", n-4) + string(runes[n-2:])
+}
+
+// Struct 返回按字段标签脱敏的 slog.LogValuer，适合在结构体的 LogValue 方法中使用：
+//
+//	type User struct {
+//		Name     string
+//		Email    string `log:"email,mask"`
+//		Password string `log:",secret"`
+//		Internal string `log:"-"`
+//	}
+//
+//	func (u User) LogValue() slog.Value { return privacy.StructValue(u) }
+//
+// 标签的第一部分为输出的键名（为空时使用字段名），secret 输出 [REDACTED]，mask 部分脱敏，- 不输出。
+// 只输出导出的字段，嵌入的结构体字段展开到同一层
+func Struct(v any) slog.LogValuer {
+	return structValuer{v: v}
+}
+
+type structValuer struct {
+	v any
+}
+
+func (s structValuer) LogValue() slog.Value {
+	return StructValue(s.v)
+}
+
+// StructValue 按字段标签把结构体转换为分组值，见 Struct。v 不是结构体（或其指针）时原样返回
+func StructValue(v any) slog.Value {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return slog.AnyValue(v)
+	}
+	return slog.GroupValue(structAttrs(rv)...)
+}
+
+var (
+	logValuerType = reflect.TypeFor[slog.LogValuer]()
+	timeType      = reflect.TypeFor[time.Time]()
+)
+
+func structAttrs(rv reflect.Value) []slog.Attr {
+	rt := rv.Type()
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		// 未导出类型的嵌入字段仍会提升其导出字段
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("log")
+		if tag == "-" {
+			continue
+		}
+		name, opt, _ := strings.Cut(tag, ",")
+		fv := rv.Field(i)
+
+		if field.Anonymous && name == "" && opt == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && !fv.Type().Implements(logValuerType) {
+				attrs = append(attrs, structAttrs(fv)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		switch opt {
+		case "secret":
+			attrs = append(attrs, slog.String(name, Redacted))
+		case "mask":
+			attrs = append(attrs, slog.String(name, Mask(fmt.Sprint(fieldInterface(fv)))))
+		default:
+			attrs = append(attrs, slog.Attr{Key: name, Value: fieldValue(fv)})
+		}
+	}
+	return attrs
+}
+
+// fieldValue 字段的日志值：自身实现 LogValuer 的保持不变，嵌套结构体继续按标签脱敏
+func fieldValue(fv reflect.Value) slog.Value {
+	if fv.Type().Implements(logValuerType) || fv.Type() == timeType {
+		return slog.AnyValue(fieldInterface(fv))
+	}
+	inner := fv
+	if inner.Kind() == reflect.Pointer && !inner.IsNil() {
+		inner = inner.Elem()
+	}
+	if inner.Kind() == reflect.Struct && inner.Type() != timeType && !inner.Type().Implements(logValuerType) {
+		return slog.GroupValue(structAttrs(inner)...)
+	}
+	return slog.AnyValue(fieldInterface(fv))
+}
+
+// fieldInterface 返回字段的值。经由未导出的嵌入字段访问的值不能取出也不能调用其方法，
+// 改用文本形式；其中实现了 LogValuer 的值无法确认是否敏感，按 Secret 处理
+func fieldInterface(fv reflect.Value) any {
+	if fv.CanInterface() {
+		return fv.Interface()
+	}
+	if fv.Type().Implements(logValuerType) {
+		return Redacted
+	}
+	return fmt.Sprint(fv)
+}
@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// defaultWarmupTimeout 未配置 logger.warmup.timeout 时预热与就绪检查的最长耗时
+const defaultWarmupTimeout = 5 * time.Second
+
+// warmupSinks 按 logger.warmup 验证刚启动的输出端，返回未通过验证的输出端；
+// fail 模式下存在未通过的输出端时返回错误
+func warmupSinks(supervisor *handler.Supervisor, cfg config.WarmupConfig) ([]handler.SinkHealth, error) {
+	if cfg.Mode == "" || cfg.Mode == "off" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), warmupTimeout(cfg))
+	defer cancel()
+
+	var failed []handler.SinkHealth
+	var errs []error
+	for _, h := range supervisor.Probe(ctx) {
+		if !h.Healthy {
+			failed = append(failed, h)
+			errs = append(errs, fmt.Errorf("sink %s: %s", h.Name, h.Error))
+		}
+	}
+	if cfg.Mode == "fail" && len(errs) > 0 {
+		return nil, fmt.Errorf("logger.warmup: %w", errors.Join(errs...))
+	}
+	return failed, nil
+}
+
+// logNotReady 输出预热未通过的输出端，日志系统以降级模式继续运行
+func logNotReady(logger *slog.Logger, failed []handler.SinkHealth) {
+	for _, h := range failed {
+		logger.Warn("Log sink not ready, running degraded", slog.String("sink", h.Name), slog.String("error", h.Error))
+	}
+}
+
+func warmupTimeout(cfg config.WarmupConfig) time.Duration {
+	if cfg.Timeout > 0 {
+		return cfg.Timeout
+	}
+	return defaultWarmupTimeout
+}
+
+// Ready 验证应用日志及各通道的全部输出端（文件可写、远程端点可达），全部可用时返回nil
+func Ready(ctx context.Context) error {
+	sinks, err := probeSinks(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, h := range sinks {
+		if !h.Healthy {
+			errs = append(errs, fmt.Errorf("sink %s: %s", h.Name, h.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// probeSinks 并发验证应用日志及各通道的输出端
+func probeSinks(ctx context.Context) ([]handler.SinkHealth, error) {
+	supervisor := sinkSupervisor
+	if supervisor == nil {
+		return nil, errors.New("logger not initialized")
+	}
+	sinks := supervisor.Probe(ctx)
+	channelsMu.RLock()
+	supervisors := channelSupervisors
+	channelsMu.RUnlock()
+	for _, s := range supervisors {
+		sinks = append(sinks, s.Probe(ctx)...)
+	}
+	return sinks, nil
+}
+
+// ReadyHandler 返回供编排系统使用的就绪探针，如 Kubernetes 的 readinessProbe：
+// 全部输出端可用时返回 200，否则返回 503，响应体列出各输出端的状态。单次检查的耗时受 logger.warmup.timeout 限制
+func ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := defaultWarmupTimeout
+		if cfg := GlobalConfig; cfg != nil {
+			timeout = warmupTimeout(cfg.Logger.Warmup)
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		resp := struct {
+			Ready bool                 `json:"ready"`
+			Error string               `json:"error,omitempty"`
+			Sinks []handler.SinkHealth `json:"sinks,omitempty"`
+		}{Ready: true}
+		sinks, err := probeSinks(ctx)
+		if err != nil {
+			resp.Ready = false
+			resp.Error = err.Error()
+		}
+		resp.Sinks = sinks
+		for _, h := range sinks {
+			if !h.Healthy {
+				resp.Ready = false
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !resp.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
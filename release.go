@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	releaseOnce  sync.Once
+	releaseAttrs atomic.Pointer[[]slog.Attr]
+)
+
+// SetBuildInfo 设置发布版本和提交号，之后的所有记录都会附加 release.version 和 release.commit，
+// 横幅中也会显示；未调用时从编译信息中自动检测
+//
+//	go build -ldflags "-X main.version=1.4.2 -X main.commit=$(git rev-parse --short HEAD)"
+//	logger.SetBuildInfo(version, commit)
+func SetBuildInfo(version, commit string) {
+	releaseOnce.Do(func() {})
+	storeRelease(version, commit)
+}
+
+// BuildInfo 返回当前的发布版本和提交号
+func BuildInfo() (version, commit string) {
+	for _, a := range currentRelease() {
+		for _, field := range a.Value.Group() {
+			switch field.Key {
+			case "version":
+				version = field.Value.String()
+			case "commit":
+				commit = field.Value.String()
+			}
+		}
+	}
+	return version, commit
+}
+
+// currentRelease 返回附加到记录的 release 属性，首次调用时自动检测
+func currentRelease() []slog.Attr {
+	releaseOnce.Do(func() {
+		storeRelease(detectBuildInfo())
+	})
+	if attrs := releaseAttrs.Load(); attrs != nil {
+		return *attrs
+	}
+	return nil
+}
+
+// storeRelease 保存 release 属性，版本和提交号都为空时不附加
+func storeRelease(version, commit string) {
+	var fields []any
+	if version != "" {
+		fields = append(fields, slog.String("version", version))
+	}
+	if commit != "" {
+		fields = append(fields, slog.String("commit", commit))
+	}
+	var attrs []slog.Attr
+	if len(fields) > 0 {
+		attrs = []slog.Attr{slog.Group("release", fields...)}
+	}
+	releaseAttrs.Store(&attrs)
+}
+
+// detectBuildInfo 从编译信息读取主模块版本和 VCS 提交号
+func detectBuildInfo() (version, commit string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		version = v
+	}
+	dirty := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			commit = s.Value
+			if len(commit) > 12 {
+				commit = commit[:12]
+			}
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if commit != "" && dirty {
+		commit += "-dirty"
+	}
+	return version, commit
+}
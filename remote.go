@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+var (
+	remoteMu     sync.Mutex
+	remoteCancel context.CancelFunc
+)
+
+// InitWithRemote 从远程来源（HTTP、Consul、etcd）加载配置并初始化日志系统，
+// interval 大于0时定期轮询，配置变化后自动重建日志器
+func InitWithRemote(src config.RemoteSource, interval time.Duration) error {
+	stopRemoteWatch()
+	stopFileWatch()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	cfg, err := config.LoadRemoteConfig(ctx, src)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if err := applyConfig(cfg); err != nil {
+		return err
+	}
+
+	if interval > 0 {
+		watchCtx, watchCancel := context.WithCancel(context.Background())
+		remoteMu.Lock()
+		if remoteCancel != nil {
+			remoteCancel() // 并发初始化时只保留最后一个轮询
+		}
+		remoteCancel = watchCancel
+		remoteMu.Unlock()
+
+		go config.WatchRemote(watchCtx, src, interval, func(newCfg *config.Config) {
+			// 生效后由 logReload 输出配置差异
+			if err := applyConfig(newCfg); err != nil {
+				slog.Error("Failed to apply remote config", Error(err))
+			}
+		})
+	}
+
+	return nil
+}
+
+// stopRemoteWatch 停止远程配置轮询
+func stopRemoteWatch() {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+
+	if remoteCancel != nil {
+		remoteCancel()
+		remoteCancel = nil
+	}
+}
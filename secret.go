@@ -0,0 +1,11 @@
+package logger
+
+import "github.com/shuakami/logmiao/privacy"
+
+// Secret 将值标记为敏感：所有输出端、fmt 与 JSON 都只显示 [REDACTED]，不依赖按键名识别，
+// 自定义处理器或通道绕过脱敏时同样安全。需要原值时调用 Reveal
+//
+//	logger.Info("connect", "dsn", logger.Secret(dsn), "creds", logger.Secret(creds))
+func Secret[T any](v T) privacy.Sensitive[T] {
+	return privacy.Hide(v)
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// sloTracker 全局 SLO 统计器，应用日志和访问日志通道共用
+var sloTracker = handler.NewSLOTracker(handler.SLOConfig{})
+
+var (
+	sloMu     sync.Mutex
+	sloCancel context.CancelFunc
+)
+
+// setupSLO 按配置更新统计器并启动定期汇总
+func setupSLO(cfg *config.Config) {
+	stopSLOSummary()
+
+	sloCfg := cfg.Logger.Features.SLO
+	if !sloCfg.Enabled {
+		return
+	}
+	sloTracker.Configure(handler.SLOConfig{
+		Window:    sloCfg.Window,
+		Objective: sloCfg.Objective,
+	})
+	if sloCfg.SummaryInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sloMu.Lock()
+	sloCancel = cancel
+	sloMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(sloCfg.SummaryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				logSLOSummary()
+			}
+		}
+	}()
+}
+
+// stopSLOSummary 停止定期汇总
+func stopSLOSummary() {
+	sloMu.Lock()
+	defer sloMu.Unlock()
+	if sloCancel != nil {
+		sloCancel()
+		sloCancel = nil
+	}
+}
+
+// logSLOSummary 为每个路由输出一条汇总记录，错误预算耗尽的路由以 Warn 级别输出
+func logSLOSummary() {
+	objective := sloTracker.Objective()
+	for _, s := range sloTracker.Snapshot() {
+		level := slog.LevelInfo
+		if s.BudgetLeft < 0 {
+			level = slog.LevelWarn
+		}
+		GetLogger().LogAttrs(context.Background(), level, "SLO summary",
+			slog.String("type", "slo"),
+			slog.String("route", s.Route),
+			slog.Int64("total", s.Total),
+			slog.Int64("errors", s.Errors),
+			slog.Float64("availability", s.Availability),
+			slog.Float64("objective", objective),
+			slog.Float64("budget_left", s.BudgetLeft),
+		)
+	}
+}
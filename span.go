@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// DefaultSlowSpanThreshold 默认的慢计时段阈值
+const DefaultSlowSpanThreshold = 500 * time.Millisecond
+
+// StartSpan 开始一个计时段，返回的函数在结束时调用：
+// 耗时以 DEBUG 输出，超过 features.slow_span_threshold 时以 WARN 输出，
+// 并累加到请求级计时中，随访问日志的 spans 属性输出。
+// features.performance_tracking 关闭时返回空操作。
+//
+//	done := logger.StartSpan(ctx, "db.query")
+//	defer done()
+func StartSpan(ctx context.Context, name string, attrs ...slog.Attr) func() {
+	cfg := config.GlobalConfig
+	if cfg != nil && !cfg.Logger.Features.PerformanceTracking {
+		return func() {}
+	}
+	threshold := DefaultSlowSpanThreshold
+	if cfg != nil && cfg.Logger.Features.SlowSpanThreshold > 0 {
+		threshold = cfg.Logger.Features.SlowSpanThreshold
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		if timings := handler.SpanTimingsFromContext(ctx); timings != nil {
+			timings.Add(name, elapsed)
+		}
+
+		level, msg := slog.LevelDebug, "Span finished"
+		if elapsed >= threshold {
+			level, msg = slog.LevelWarn, "Slow span"
+		}
+		logger := FromContext(ctx)
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+		logger.LogAttrs(ctx, level, msg, append([]slog.Attr{
+			slog.String("span", name),
+			slog.Duration("duration", elapsed),
+		}, attrs...)...)
+	}
+}
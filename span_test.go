@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/middleware"
+)
+
+// TestStartSpanAccessLog 测试请求内的计时汇总到访问日志的 spans 属性
+func TestStartSpanAccessLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	cfg := middleware.DefaultGinMiddlewareConfig()
+	cfg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := gin.New()
+	r.Use(middleware.GinMiddlewareWithConfig(cfg))
+	r.GET("/items", func(c *gin.Context) {
+		for i := 0; i < 2; i++ {
+			StartSpan(c.Request.Context(), "db.query")()
+		}
+		c.Status(http.StatusOK)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	var entry struct {
+		Spans map[string]struct {
+			Count   int     `json:"count"`
+			TotalMs float64 `json:"total_ms"`
+		} `json:"spans"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := entry.Spans["db.query"].Count; got != 2 {
+		t.Errorf("expected 2 db.query spans on the access log, got %d: %s", got, buf.String())
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+// logStartup 输出一条结构化的启动报告，记录部署环境、实际加载的配置来源、输出端和已开启的功能，
+// 便于在聚合日志中确认实例以哪份配置启动
+func logStartup(logger *slog.Logger, cfg *config.Config, sinks []string) {
+	resource := cfg.Logger.Resource.Resolve()
+	attrs := []any{
+		slog.String("type", "startup"),
+		slog.String("env", resource[config.ResourceEnvironment]),
+		slog.String("config", cfg.SourceName()),
+		slog.String("config_level", cfg.Logger.Level),
+		slog.Any("sinks", sinks),
+		slog.Any("features", cfg.Logger.Features.EnabledNames()),
+		slog.String("logmiao_version", Version),
+		slog.String("go_version", runtime.Version()),
+		slog.Int("pid", os.Getpid()),
+	}
+	if len(cfg.Includes) > 0 {
+		attrs = append(attrs, slog.Any("includes", cfg.Includes))
+	}
+	if service := resource[config.ResourceServiceName]; service != "" {
+		attrs = append(attrs, slog.String("service", service))
+	}
+	logger.Info("Logger started", attrs...)
+}
+
+// logBanner 输出与启动横幅内容相同的结构化记录，使横幅信息同样进入文件等输出端
+func logBanner(logger *slog.Logger, appName, version string, cfg *config.Config, releaseVersion, commit string) {
+	attrs := []any{
+		slog.String("type", "banner"),
+		slog.String("app", appName),
+		slog.String("version", version),
+		slog.String("go_version", runtime.Version()),
+		slog.String("platform", runtime.GOOS+"/"+runtime.GOARCH),
+		slog.Int("cpus", runtime.NumCPU()),
+		slog.String("env", cfg.Logger.Resource.Resolve()[config.ResourceEnvironment]),
+		slog.String("config", cfg.SourceName()),
+		slog.String("config_level", cfg.Logger.Level),
+		slog.Any("features", cfg.Logger.Features.EnabledNames()),
+	}
+	if releaseVersion != "" {
+		attrs = append(attrs, slog.String("release", releaseVersion))
+	}
+	if commit != "" {
+		attrs = append(attrs, slog.String("commit", commit))
+	}
+	if cfg.Logger.Viewer.Enabled {
+		attrs = append(attrs, slog.Int("viewer_port", cfg.Logger.Viewer.Port))
+	}
+	logger.Info("Application starting", attrs...)
+}
+
+// logHealth 输出健康检查的结构化记录，details 按键名排序放在 details 分组中
+func logHealth(logger *slog.Logger, status string, details map[string]interface{}) {
+	keys := make([]string, 0, len(details))
+	for key := range details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	group := make([]any, 0, len(keys))
+	for _, key := range keys {
+		group = append(group, slog.Any(key, details[key]))
+	}
+	attrs := []any{slog.String("type", "health"), slog.String("status", status)}
+	if len(group) > 0 {
+		attrs = append(attrs, slog.Group("details", group...))
+	}
+	logger.Info("Health check", attrs...)
+}
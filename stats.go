@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/viewer"
+)
+
+// StatsSnapshot 日志系统内部统计
+type StatsSnapshot struct {
+	Async      *handler.QueueStats        `json:"async,omitempty"`       // 异步写出队列统计
+	ViewerPush *viewer.PushStats          `json:"viewer_push,omitempty"` // 远程推送统计，含熔断器状态
+	SLO        []handler.RouteSLO         `json:"slo,omitempty"`         // 各路由滚动窗口内的可用性
+	Sinks      []handler.SinkHealth       `json:"sinks,omitempty"`       // 应用日志及各通道输出端的健康状态
+	Latency    []handler.SinkLatencyStats `json:"latency,omitempty"`     // 启用 output.latency 时各输出端的写入耗时统计
+	LastReload *time.Time                 `json:"last_reload,omitempty"` // 最近一次 Reconfigure 或热加载的时间
+
+	CoerceFailures map[string]int64 `json:"coerce_failures,omitempty"` // 配置了 coerce 的输出端各自的类型转换失败次数
+	TapDropped     int64            `json:"tap_dropped,omitempty"`     // OnRecord 回调跟不上而丢弃的记录数
+	Truncated      int64            `json:"truncated,omitempty"`       // 超出 max_record_size 而被截断的记录数
+	Records        map[string]int64 `json:"records"`                   // 各级别写出的记录数
+	HandlerErrors  int64            `json:"handler_errors,omitempty"`  // 输出端写入失败的记录数
+}
+
+// Stats 返回日志系统当前的内部统计
+func Stats() StatsSnapshot {
+	var snapshot StatsSnapshot
+	if asyncHandler != nil {
+		asyncStats := asyncHandler.Stats()
+		snapshot.Async = &asyncStats
+	}
+	if viewerPusher != nil {
+		pushStats := viewerPusher.Stats()
+		snapshot.ViewerPush = &pushStats
+	}
+	if GlobalConfig != nil && GlobalConfig.Logger.Features.SLO.Enabled {
+		snapshot.SLO = sloTracker.Snapshot()
+	}
+	if sinkSupervisor != nil {
+		snapshot.Sinks = append(snapshot.Sinks, sinkSupervisor.Health()...)
+		snapshot.Latency = append(snapshot.Latency, sinkSupervisor.Latency()...)
+	}
+	channelsMu.RLock()
+	for _, s := range channelSupervisors {
+		snapshot.Sinks = append(snapshot.Sinks, s.Health()...)
+		snapshot.Latency = append(snapshot.Latency, s.Latency()...)
+	}
+	channelsMu.RUnlock()
+	snapshot.LastReload = lastReload.Load()
+	snapshot.TapDropped = recordTap.Dropped()
+	snapshot.Records = recordMetrics.Records()
+	snapshot.HandlerErrors = recordMetrics.Errors()
+	if recordBudget != nil {
+		snapshot.Truncated = recordBudget.Truncated()
+	}
+	coercers.Range(func(name, c any) bool {
+		if snapshot.CoerceFailures == nil {
+			snapshot.CoerceFailures = make(map[string]int64)
+		}
+		snapshot.CoerceFailures[name.(string)] = c.(*handler.Coercer).Failures()
+		return true
+	})
+	return snapshot
+}
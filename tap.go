@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// recordTap 全局记录分发器，重新初始化时保留已注册的订阅
+var recordTap = handler.NewRecordTap(handler.DefaultTapBuffer)
+
+// OnRecord 订阅经过全局日志器的记录（已通过级别、采样等过滤，含 WithAttrs 绑定的属性），
+// 用于驱动指标、界面提示或测试断言，而无需实现完整的 slog.Handler。
+// 回调在独立的goroutine中按顺序执行，不会阻塞日志调用；回调跟不上时丢弃记录，丢弃数见 Stats().TapDropped。
+// 返回的函数取消订阅
+func OnRecord(fn func(ctx context.Context, r slog.Record)) (cancel func()) {
+	return recordTap.Subscribe(fn)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 请求ID格式
+const (
+	IDFormatDefault   = "default"   // req_<32位十六进制>
+	IDFormatUUIDv7    = "uuidv7"    // 按时间有序的 UUID（RFC 9562）
+	IDFormatULID      = "ulid"      // 26位 Crockford Base32，按时间有序
+	IDFormatSnowflake = "snowflake" // 64位整数：41位毫秒时间戳、10位节点、12位序号
+)
+
+// NewIDGenerator 返回指定格式的ID生成函数，prefix 非空时加在生成的ID前，nodeID 用于 snowflake
+func NewIDGenerator(format, prefix string, nodeID int64) (func() string, error) {
+	var gen func() string
+	switch strings.ToLower(format) {
+	case "", IDFormatDefault:
+		gen = GenerateRequestID
+	case IDFormatUUIDv7:
+		gen = NewUUIDv7
+	case IDFormatULID:
+		gen = NewULID
+	case IDFormatSnowflake:
+		gen = NewSnowflake(nodeID).Next
+	default:
+		return nil, fmt.Errorf("unknown request id format %q", format)
+	}
+	if prefix == "" {
+		return gen, nil
+	}
+	return func() string { return prefix + gen() }, nil
+}
+
+// NewUUIDv7 生成 UUIDv7：48位毫秒时间戳加随机数
+func NewUUIDv7() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	ms := uint64(time.Now().UnixMilli())
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	b[6] = 0x70 | b[6]&0x0f // 版本 7
+	b[8] = 0x80 | b[8]&0x3f // RFC 9562 变体
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+// crockford ULID 使用的 Base32 字母表
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID 生成 ULID：48位毫秒时间戳加80位随机数
+func NewULID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[6:])
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+
+	// 128位按5位一组编码为26个字符，首字符只使用高3位
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// snowflakeEpoch snowflake 时间戳的起点（2024-01-01 UTC）
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+// Snowflake 单进程内按节点号生成递增的 snowflake ID
+type Snowflake struct {
+	mu     sync.Mutex
+	node   int64
+	lastMs int64
+	seq    int64
+}
+
+// NewSnowflake 创建 snowflake 生成器，节点号取低10位
+func NewSnowflake(node int64) *Snowflake {
+	return &Snowflake{node: node & 0x3ff}
+}
+
+// Next 生成下一个ID，同一毫秒内序号用尽时等待下一毫秒
+func (s *Snowflake) Next() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := time.Now().UnixMilli() - snowflakeEpoch
+	if ms < s.lastMs {
+		ms = s.lastMs // 时钟回拨时沿用上次的时间戳
+	}
+	if ms == s.lastMs {
+		s.seq = (s.seq + 1) & 0xfff
+		if s.seq == 0 {
+			for ms <= s.lastMs {
+				time.Sleep(100 * time.Microsecond)
+				ms = time.Now().UnixMilli() - snowflakeEpoch
+			}
+		}
+	} else {
+		s.seq = 0
+	}
+	s.lastMs = ms
+	return fmt.Sprint(ms<<22 | s.node<<12 | s.seq)
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignDebugToken 生成定向调试令牌，格式为 "<过期时间戳>.<签名>"
+func SignDebugToken(secret string, ttl time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return expires + "." + signToken(secret, expires)
+}
+
+// VerifyDebugToken 校验定向调试令牌的签名和有效期
+func VerifyDebugToken(secret, token string) bool {
+	if secret == "" {
+		return false
+	}
+
+	expires, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	ts, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > ts {
+		return false
+	}
+
+	return hmac.Equal([]byte(sig), []byte(signToken(secret, expires)))
+}
+
+// signToken 计算HMAC-SHA256签名
+func signToken(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
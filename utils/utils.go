@@ -2,9 +2,13 @@ package utils
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -105,6 +109,13 @@ func MaskPhone(phone string) string {
 		return phone // 不脱敏，直接返回原值
 	}
 
+	// 优先使用配置中的地区规则
+	if config.GlobalConfig != nil {
+		if masked, ok := applyPhoneRules(phone, config.GlobalConfig.Logger.Features.Privacy.PhoneRules); ok {
+			return masked
+		}
+	}
+
 	if len(phone) < 7 {
 		if len(phone) <= 2 {
 			return strings.Repeat("*", len(phone))
@@ -134,6 +145,68 @@ func MaskPhone(phone string) string {
 	return phone[:2] + strings.Repeat("*", len(phone)-4) + phone[len(phone)-2:]
 }
 
+// compiledPhoneRule 预编译的手机号脱敏规则
+type compiledPhoneRule struct {
+	re   *regexp.Regexp
+	mask string
+}
+
+var (
+	phoneRulesMu     sync.Mutex
+	phoneRulesSource []config.PhoneMaskRule
+	phoneRulesCache  []compiledPhoneRule
+)
+
+// applyPhoneRules 按顺序尝试配置的脱敏规则，返回第一条匹配规则的结果
+func applyPhoneRules(phone string, rules []config.PhoneMaskRule) (string, bool) {
+	if len(rules) == 0 {
+		return "", false
+	}
+
+	for _, rule := range compilePhoneRules(rules) {
+		if rule.re.MatchString(phone) {
+			return rule.re.ReplaceAllString(phone, rule.mask), true
+		}
+	}
+	return "", false
+}
+
+// compilePhoneRules 编译脱敏规则并缓存，配置未变化时直接复用
+func compilePhoneRules(rules []config.PhoneMaskRule) []compiledPhoneRule {
+	phoneRulesMu.Lock()
+	defer phoneRulesMu.Unlock()
+
+	if len(phoneRulesSource) == len(rules) && len(rules) > 0 && &phoneRulesSource[0] == &rules[0] {
+		return phoneRulesCache
+	}
+
+	compiled := make([]compiledPhoneRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			// 无效的正则由 config.Check 报告，此处跳过，避免影响日志输出
+			continue
+		}
+		compiled = append(compiled, compiledPhoneRule{re: re, mask: rule.Mask})
+	}
+
+	phoneRulesSource = rules
+	phoneRulesCache = compiled
+	return compiled
+}
+
+// MaskSessionID 会话ID脱敏 - 启用时替换为稳定的哈希前缀，同一会话在各请求间保持一致
+func MaskSessionID(id string) string {
+	if id == "" {
+		return ""
+	}
+	if config.GlobalConfig != nil && !config.GlobalConfig.Logger.Features.Privacy.EnableSessionMask {
+		return id
+	}
+	sum := sha256.Sum256([]byte(id))
+	return "sess_" + hex.EncodeToString(sum[:6])
+}
+
 // maskString 通用字符串脱敏
 func maskString(s string) string {
 	if len(s) <= 2 {
@@ -2,10 +2,12 @@ package utils
 
 import (
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shuakami/logmiao/config"
 )
 
 // TestGetClientIP 测试客户端IP获取功能
@@ -99,6 +101,28 @@ func TestGenerateRequestID(t *testing.T) {
 	}
 }
 
+// TestIDGenerators 测试各请求ID格式及前缀
+func TestIDGenerators(t *testing.T) {
+	cases := map[string]*regexp.Regexp{
+		IDFormatUUIDv7:    regexp.MustCompile(`^api-[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		IDFormatULID:      regexp.MustCompile(`^api-[0-9A-HJKMNP-TV-Z]{26}$`),
+		IDFormatSnowflake: regexp.MustCompile(`^api-[0-9]+$`),
+	}
+	for format, pattern := range cases {
+		gen, err := NewIDGenerator(format, "api-", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, second := gen(), gen()
+		if !pattern.MatchString(first) || first == second {
+			t.Errorf("%s ids %q, %q", format, first, second)
+		}
+	}
+	if _, err := NewIDGenerator("guid", "", 0); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
 // TestMaskEmail 测试邮箱脱敏
 func TestMaskEmail(t *testing.T) {
 	tests := []struct {
@@ -140,6 +164,43 @@ func TestMaskPhone(t *testing.T) {
 	}
 }
 
+// TestMaskPhoneWithRules 测试按地区配置的手机号脱敏规则
+func TestMaskPhoneWithRules(t *testing.T) {
+	original := config.GlobalConfig
+	defer func() { config.GlobalConfig = original }()
+
+	config.GlobalConfig = &config.Config{
+		Logger: config.LoggerConfig{
+			Features: config.FeaturesConfig{
+				Privacy: config.PrivacyConfig{
+					EnablePhoneMask: true,
+					PhoneRules: []config.PhoneMaskRule{
+						{Region: "US", Pattern: `^\+1(\d{3})\d{3}(\d{4})$`, Mask: "+1$1***$2"},
+						{Region: "UK", Pattern: `^\+44(\d{4})\d{6}$`, Mask: "+44$1******"},
+						{Region: "BAD", Pattern: `(`, Mask: "ignored"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"+14155552671", "+1415***2671"},
+		{"+447911123456", "+447911******"},
+		{"13812345678", "138****5678"}, // 未匹配规则时回退到默认逻辑
+	}
+
+	for _, test := range tests {
+		result := MaskPhone(test.input)
+		if result != test.expected {
+			t.Errorf("MaskPhone(%s) = %s, expected %s", test.input, result, test.expected)
+		}
+	}
+}
+
 // TestSanitizeUserInput 测试用户输入清理
 func TestSanitizeUserInput(t *testing.T) {
 	tests := []struct {
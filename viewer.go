@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+	"github.com/shuakami/logmiao/viewer"
+)
+
+var (
+	// viewerServer 当前运行的Web查看器
+	viewerServer *viewer.Server
+	// viewerPusher 当前使用的远程推送器
+	viewerPusher *viewer.Pusher
+	// recentStore 保存最近日志的内存存储，查看器或 viewer.recent 启用时存在
+	recentStore atomic.Pointer[viewer.Store]
+)
+
+// setupViewer 根据配置启动Web查看器和远程推送，返回需要加入分发链的输出端
+func setupViewer(cfg *config.Config, level slog.Leveler, client *http.Client) ([]handler.Sink, error) {
+	closeViewer(context.Background())
+
+	viewerCfg := cfg.Logger.Viewer
+	source := viewerCfg.Source
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+
+	var sinks []handler.Sink
+	resource := resourceAttrs(cfg)
+
+	if viewerCfg.Enabled || viewerCfg.Recent {
+		store := viewer.NewStore(viewerCfg.BufferSize)
+		name := "recent"
+		if viewerCfg.Enabled {
+			viewerCfg.Source = source // 检索历史文件时作为其中记录的来源名称
+			server := viewer.NewServer(viewerCfg, store)
+			if viewerCfg.Metrics {
+				server.Mount("/metrics", MetricsHandler())
+			}
+			if viewerCfg.Admin {
+				server.Mount("/api/levels", LevelOverrideHandler())
+			}
+			if err := server.Start(); err != nil {
+				return nil, err
+			}
+			viewerServer = server
+			name = "viewer"
+		}
+		recentStore.Store(store)
+		sinks = append(sinks, handler.NewHandlerSink(name,
+			viewer.NewHandler(store, source, level).WithAttrs(resource), handler.SinkOptions{}))
+	}
+
+	if viewerCfg.Push.Enabled && viewerCfg.Push.URL != "" {
+		breaker := handler.NewCircuitBreaker(handler.BreakerConfig{
+			FailureThreshold: viewerCfg.Push.CircuitBreaker.FailureThreshold,
+			OpenDuration:     viewerCfg.Push.CircuitBreaker.OpenDuration,
+		})
+		pusher, err := viewer.NewPusherWithOptions(viewerCfg.Push.URL, viewer.PushOptions{
+			Username:  viewerCfg.Push.Username,
+			Password:  viewerCfg.Push.Password,
+			Token:     viewerCfg.Push.Token,
+			Breaker:   breaker,
+			SpillPath: viewerCfg.Push.SpillPath,
+			Queue:     queueConfig(viewerCfg.Push.Backpressure),
+			Client:    client,
+			Batch:     batchConfig(viewerCfg.Push.Batch),
+			Retry:     retryConfig(viewerCfg.Push.Retry),
+
+			WriteTimeout: viewerCfg.Push.WriteTimeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("viewer.push.backpressure: %w", err)
+		}
+		viewerPusher = pusher
+		pushHandler, err := coerceHandler("push", viewer.NewHandler(pusher, source, level), viewerCfg.Push.Coerce)
+		if err != nil {
+			_ = pusher.Close()
+			viewerPusher = nil
+			return nil, fmt.Errorf("viewer.push: %w", err)
+		}
+		sinks = append(sinks, handler.NewHandlerSink("push",
+			pushHandler.WithAttrs(resource), handler.SinkOptions{
+				Closer: pusher,
+				Probe:  pusher.Probe,
+				Healthy: func() error {
+					if stats := breaker.Stats(); stats.State == handler.BreakerOpen {
+						return fmt.Errorf("circuit open: %s", stats.LastError)
+					}
+					return nil
+				},
+			}))
+	}
+
+	return sinks, nil
+}
+
+// Recent 返回最近 n 条满足 filter 的日志，按时间升序排列，filter 为 nil 时不过滤；
+// 需启用 viewer 或 viewer.recent，否则返回nil。可用于在应用自己的管理界面中展示最近日志
+func Recent(n int, filter func(viewer.Entry) bool) []viewer.Entry {
+	store := recentStore.Load()
+	if store == nil {
+		return nil
+	}
+	return store.Recent(n, filter)
+}
+
+// closeViewer 停止Web查看器和远程推送
+func closeViewer(ctx context.Context) {
+	recentStore.Store(nil)
+	if viewerServer != nil {
+		_ = viewerServer.Close()
+		viewerServer = nil
+	}
+	if viewerPusher != nil {
+		_ = viewerPusher.CloseContext(ctx)
+		viewerPusher = nil
+	}
+}
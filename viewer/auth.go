@@ -0,0 +1,280 @@
+package viewer
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SessionCookie 登录后保存会话的 Cookie 名称
+const SessionCookie = "logmiao_session"
+
+const (
+	defaultSessionTTL  = 12 * time.Hour
+	defaultMaxFailures = 5
+	defaultLockout     = 15 * time.Minute
+)
+
+// errNoCredentials 启用查看器但未配置任何认证方式
+var errNoCredentials = errors.New("viewer.auth: no credentials configured; set password_hash (logmiao hash-password) or tokens, or auth.disabled: true for local debugging")
+
+// authenticator 查看器认证：登录页面签发的会话 Cookie、API 使用的 Bearer 令牌，以及兼容推送端和 TUI 的 Basic 认证。
+// 同一客户端连续失败 MaxFailures 次后锁定 Lockout 时长，锁定期间不再校验密码
+type authenticator struct {
+	cfg    config.AuthConfig
+	tokens [][32]byte // 令牌的 SHA-256，比较时长度固定
+
+	mu       sync.Mutex
+	sessions map[string]time.Time // 会话 → 过期时间
+	failures map[string]*authFailures
+	now      func() time.Time
+}
+
+// authFailures 单个客户端的连续失败次数与锁定截止时间
+type authFailures struct {
+	count  int
+	locked time.Time
+}
+
+func newAuthenticator(cfg config.AuthConfig) *authenticator {
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = defaultSessionTTL
+	}
+	if cfg.MaxFailures <= 0 {
+		cfg.MaxFailures = defaultMaxFailures
+	}
+	if cfg.Lockout <= 0 {
+		cfg.Lockout = defaultLockout
+	}
+	a := &authenticator{
+		cfg:      cfg,
+		sessions: make(map[string]time.Time),
+		failures: make(map[string]*authFailures),
+		now:      time.Now,
+	}
+	for _, token := range cfg.Tokens {
+		if token != "" {
+			a.tokens = append(a.tokens, sha256.Sum256([]byte(token)))
+		}
+	}
+	return a
+}
+
+// configured 是否配置了任何认证方式
+func (a *authenticator) configured() bool {
+	return len(a.tokens) > 0 || (a.cfg.Username != "" && (a.cfg.PasswordHash != "" || a.cfg.Password != ""))
+}
+
+// checkPassword 校验用户名和密码，优先使用 bcrypt 哈希，兼容旧的明文 password
+func (a *authenticator) checkPassword(user, pass string) bool {
+	if a.cfg.Username == "" {
+		return false
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(user), []byte(a.cfg.Username)) == 1
+	var passOK bool
+	switch {
+	case a.cfg.PasswordHash != "":
+		passOK = bcrypt.CompareHashAndPassword([]byte(a.cfg.PasswordHash), []byte(pass)) == nil
+	case a.cfg.Password != "":
+		passOK = subtle.ConstantTimeCompare([]byte(pass), []byte(a.cfg.Password)) == 1
+	}
+	return userOK && passOK
+}
+
+// checkToken 校验 Bearer 令牌
+func (a *authenticator) checkToken(token string) bool {
+	sum := sha256.Sum256([]byte(token))
+	ok := false
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(sum[:], t[:]) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// locked 返回客户端的剩余锁定时长，未锁定时返回0
+func (a *authenticator) locked(client string) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if f, ok := a.failures[client]; ok {
+		if d := f.locked.Sub(a.now()); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// recordResult 记录一次认证结果，成功时清零失败次数，连续失败达到上限时锁定
+func (a *authenticator) recordResult(client string, ok bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if ok {
+		delete(a.failures, client)
+		return
+	}
+	f := a.failures[client]
+	if f == nil {
+		f = &authFailures{}
+		a.failures[client] = f
+	}
+	f.count++
+	if f.count >= a.cfg.MaxFailures {
+		f.count = 0
+		f.locked = a.now().Add(a.cfg.Lockout)
+	}
+}
+
+// newSession 创建会话并返回其标识，同时清理已过期的会话
+func (a *authenticator) newSession() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	id := hex.EncodeToString(buf)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	for s, expires := range a.sessions {
+		if now.After(expires) {
+			delete(a.sessions, s)
+		}
+	}
+	a.sessions[id] = now.Add(a.cfg.SessionTTL)
+	return id
+}
+
+// validSession 检查请求携带的会话是否有效
+func (a *authenticator) validSession(r *http.Request) bool {
+	c, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	expires, ok := a.sessions[c.Value]
+	return ok && a.now().Before(expires)
+}
+
+func (a *authenticator) endSession(r *http.Request) {
+	if c, err := r.Cookie(SessionCookie); err == nil {
+		a.mu.Lock()
+		delete(a.sessions, c.Value)
+		a.mu.Unlock()
+	}
+}
+
+// clientKey 按客户端IP统计失败次数
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// middleware 认证中间件：会话 Cookie、Bearer 令牌或 Basic 认证任一有效即可访问；
+// 未认证的页面请求跳转到登录页，API 请求返回 401
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			a.handleLogin(w, r)
+			return
+		}
+		if r.URL.Path == "/logout" {
+			a.handleLogout(w, r)
+			return
+		}
+		if a.validSession(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		auth := r.Header.Get("Authorization")
+		if auth == "" {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				unauthorized(w)
+			} else {
+				http.Redirect(w, r, "login", http.StatusSeeOther)
+			}
+			return
+		}
+
+		client := clientKey(r)
+		if d := a.locked(client); d > 0 {
+			tooManyAttempts(w, d)
+			return
+		}
+		var ok bool
+		if token, isBearer := strings.CutPrefix(auth, "Bearer "); isBearer {
+			ok = a.checkToken(token)
+		} else if user, pass, isBasic := r.BasicAuth(); isBasic {
+			ok = a.checkPassword(user, pass)
+		}
+		a.recordResult(client, ok)
+		if !ok {
+			unauthorized(w)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleLogin 显示登录页面；提交后校验密码并签发会话 Cookie
+func (a *authenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		servePage(w, "static/login.html")
+		return
+	}
+	client := clientKey(r)
+	if d := a.locked(client); d > 0 {
+		tooManyAttempts(w, d)
+		return
+	}
+	ok := a.checkPassword(r.PostFormValue("username"), r.PostFormValue("password"))
+	a.recordResult(client, ok)
+	if !ok {
+		http.Redirect(w, r, "login?failed=1", http.StatusSeeOther)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    a.newSession(),
+		Path:     "/",
+		MaxAge:   int(a.cfg.SessionTTL / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "./", http.StatusSeeOther)
+}
+
+func (a *authenticator) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	a.endSession(r)
+	http.SetCookie(w, &http.Cookie{Name: SessionCookie, Value: "", Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "login", http.StatusSeeOther)
+}
+
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="logmiao"`)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+func tooManyAttempts(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+	http.Error(w, "too many failed attempts", http.StatusTooManyRequests)
+}
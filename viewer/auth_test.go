@@ -0,0 +1,90 @@
+package viewer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/shuakami/logmiao/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// TestAuth 测试登录会话、Bearer 令牌、Basic 认证与连续失败后的锁定
+func TestAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(config.ViewerConfig{Auth: config.AuthConfig{
+		Username:     "admin",
+		PasswordHash: string(hash),
+		Tokens:       []string{"api-token"},
+		MaxFailures:  2,
+	}}, NewStore(10))
+	h := server.Handler()
+
+	do := func(req *http.Request, client string) *httptest.ResponseRecorder {
+		req.RemoteAddr = client + ":40000"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	login := func(password, client string) *httptest.ResponseRecorder {
+		form := url.Values{"username": {"admin"}, "password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return do(req, client)
+	}
+
+	if w := do(httptest.NewRequest(http.MethodGet, "/", nil), "10.0.0.1"); w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/login" {
+		t.Errorf("page without session should redirect to login, got %d %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := do(httptest.NewRequest(http.MethodGet, "/api/logs", nil), "10.0.0.1"); w.Code != http.StatusUnauthorized {
+		t.Errorf("api without credentials returned %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs", nil)
+	req.Header.Set("Authorization", "Bearer api-token")
+	if w := do(req, "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("bearer token returned %d", w.Code)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/logs", nil)
+	req.SetBasicAuth("admin", "s3cret")
+	if w := do(req, "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("basic auth returned %d", w.Code)
+	}
+
+	// 连续两次失败后锁定，正确的密码也被拒绝；其他客户端不受影响
+	login("wrong", "10.0.0.2")
+	login("wrong", "10.0.0.2")
+	if w := login("s3cret", "10.0.0.2"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("locked client should get 429, got %d", w.Code)
+	}
+
+	w := login("s3cret", "10.0.0.3")
+	cookies := w.Result().Cookies()
+	if w.Code != http.StatusSeeOther || len(cookies) != 1 || cookies[0].Name != SessionCookie || !cookies[0].HttpOnly {
+		t.Fatalf("login should set an HttpOnly session cookie, got %d %v", w.Code, cookies)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/api/logs", nil)
+	req.AddCookie(cookies[0])
+	if w := do(req, "10.0.0.3"); w.Code != http.StatusOK {
+		t.Errorf("session cookie returned %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(cookies[0])
+	do(req, "10.0.0.3")
+	req = httptest.NewRequest(http.MethodGet, "/api/logs", nil)
+	req.AddCookie(cookies[0])
+	if w := do(req, "10.0.0.3"); w.Code != http.StatusUnauthorized {
+		t.Errorf("session should be invalid after logout, got %d", w.Code)
+	}
+
+	if err := NewServer(config.ViewerConfig{}, NewStore(10)).Start(); !errors.Is(err, errNoCredentials) {
+		t.Errorf("Start without credentials should fail, got %v", err)
+	}
+}
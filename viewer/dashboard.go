@@ -0,0 +1,153 @@
+package viewer
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// HistogramBucket 每分钟的记录数，按级别统计；Suppressed 为采样、风暴汇总等未写出的估算记录数
+type HistogramBucket struct {
+	Time       time.Time      `json:"time"`
+	Counts     map[string]int `json:"counts"`
+	Suppressed int            `json:"suppressed"`
+}
+
+// Count 排行榜条目
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Dashboard 查看器仪表盘数据
+type Dashboard struct {
+	Since       time.Time         `json:"since"`
+	Histogram   []HistogramBucket `json:"histogram"`
+	TopErrors   []Count           `json:"top_errors"`
+	TopMessages []Count           `json:"top_messages"`
+	TopRoutes5x []Count           `json:"top_routes_5xx"`
+}
+
+// maxDashboardWindow 仪表盘统计窗口的上限，限制直方图的桶数
+const maxDashboardWindow = 24 * time.Hour
+
+// Dashboard 统计最近 window 时间内的每分钟级别分布（含被抑制的记录数）、高频错误消息、高频消息模板和5xx最多的路由
+func (s *Store) Dashboard(window time.Duration, top int) Dashboard {
+	if window <= 0 {
+		window = time.Hour
+	}
+	window = min(window, maxDashboardWindow)
+	if top <= 0 {
+		top = 10
+	}
+
+	since := time.Now().Add(-window).Truncate(time.Minute)
+	buckets := make(map[time.Time]map[string]int)
+	suppressed := make(map[time.Time]int)
+	errors := make(map[string]int)
+	messages := make(map[string]int)
+	routes := make(map[string]int)
+
+	s.mu.RLock()
+	// 直方图从缓冲区中最早的记录开始，不为缓冲区之前的时间补齐空桶
+	if s.count > 0 && s.at(0).Time.After(since) {
+		since = s.at(0).Time.Truncate(time.Minute)
+	}
+	for i := 0; i < s.count; i++ {
+		e := *s.at(i)
+		if e.Time.Before(since) {
+			continue
+		}
+
+		minute := e.Time.Truncate(time.Minute)
+		counts, ok := buckets[minute]
+		if !ok {
+			counts = make(map[string]int)
+			buckets[minute] = counts
+		}
+		counts[e.Level]++
+		suppressed[minute] += suppressedCount(e)
+
+		if e.Level == "ERROR" {
+			errors[e.Message]++
+		}
+		if template, ok := e.Attrs["msg_template"].(string); ok {
+			messages[template]++
+		}
+
+		if status, ok := toInt(e.Attrs["status"]); ok && status >= 500 {
+			if path, ok := e.Attrs["path"].(string); ok {
+				method, _ := e.Attrs["method"].(string)
+				routes[method+" "+path]++
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	// 补齐没有记录的分钟，保证图表横轴连续
+	histogram := make([]HistogramBucket, 0, int(time.Since(since)/time.Minute)+1)
+	for t := since; !t.After(time.Now()); t = t.Add(time.Minute) {
+		counts := buckets[t]
+		if counts == nil {
+			counts = map[string]int{}
+		}
+		histogram = append(histogram, HistogramBucket{Time: t, Counts: counts, Suppressed: suppressed[t]})
+	}
+
+	return Dashboard{
+		Since:       since,
+		Histogram:   histogram,
+		TopErrors:   topCounts(errors, top),
+		TopMessages: topCounts(messages, top),
+		TopRoutes5x: topCounts(routes, top),
+	}
+}
+
+// suppressedCount 记录代表的未写出记录数：采样保留的记录按 sample_rate 代表 rate 条（自身之外 rate-1 条），
+// 风暴汇总等汇总记录的 suppressed 属性为合并掉的条数。来自远程实例的记录同样适用
+func suppressedCount(e Entry) int {
+	if n, ok := toInt(e.Attrs["suppressed"]); ok && n > 0 {
+		return n
+	}
+	if rate, ok := e.Attrs["sample_rate"].(float64); ok && rate > 1 {
+		return int(math.Round(rate - 1))
+	}
+	if rate, ok := toInt(e.Attrs["sample_rate"]); ok && rate > 1 {
+		return rate - 1
+	}
+	return 0
+}
+
+// topCounts 返回计数最高的 n 项
+func topCounts(m map[string]int, n int) []Count {
+	result := make([]Count, 0, len(m))
+	for key, count := range m {
+		result = append(result, Count{Key: key, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// toInt 将JSON解码或slog转换后的数值统一转换为int
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,125 @@
+package viewer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DefaultExportColumns 导出时默认包含的列
+var DefaultExportColumns = []string{"time", "level", "source", "msg", "attrs"}
+
+// ExportCSV 将记录按指定列导出为CSV，attr.<key> 列取对应属性（支持 a.b 嵌套路径）
+func ExportCSV(w io.Writer, entries []Entry, columns []string) error {
+	if len(columns) == 0 {
+		columns = DefaultExportColumns
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	row := make([]string, len(columns))
+	for _, e := range entries {
+		for i, col := range columns {
+			row[i] = formatColumn(columnValue(e, col))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportNDJSON 将记录按指定列导出为每行一个JSON对象
+func ExportNDJSON(w io.Writer, entries []Entry, columns []string) error {
+	if len(columns) == 0 {
+		columns = DefaultExportColumns
+	}
+
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		obj := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			if v := columnValue(e, col); v != nil {
+				obj[col] = v
+			}
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnValue 取出一条记录中指定列的值
+func columnValue(e Entry, col string) interface{} {
+	switch col {
+	case "id":
+		return e.ID
+	case "time":
+		return e.Time.Format(time.RFC3339Nano)
+	case "level":
+		return e.Level
+	case "msg", "message":
+		return e.Message
+	case "source":
+		return e.Source
+	case "attrs":
+		if len(e.Attrs) == 0 {
+			return nil
+		}
+		return e.Attrs
+	}
+
+	if key, ok := strings.CutPrefix(col, "attr."); ok {
+		return lookupAttr(e.Attrs, key)
+	}
+	return nil
+}
+
+// lookupAttr 按点分路径查找嵌套属性
+func lookupAttr(attrs map[string]interface{}, path string) interface{} {
+	if v, ok := attrs[path]; ok {
+		return v
+	}
+
+	current := attrs
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		v, ok := current[part]
+		if !ok {
+			return nil
+		}
+		if i == len(parts)-1 {
+			return v
+		}
+		next, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = next
+	}
+	return nil
+}
+
+// formatColumn 将列值格式化为CSV单元格
+func formatColumn(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(val)
+		return string(data)
+	default:
+		return fmt.Sprint(val)
+	}
+}
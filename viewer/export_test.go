@@ -0,0 +1,39 @@
+package viewer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExportCSV 测试按列导出CSV
+func TestExportCSV(t *testing.T) {
+	entries := []Entry{{
+		Time:    time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Level:   "ERROR",
+		Message: "payment failed",
+		Attrs: map[string]interface{}{
+			"user_id": "u1",
+			"http":    map[string]interface{}{"status": int64(502)},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, entries, []string{"level", "msg", "attr.user_id", "attr.http.status"}); err != nil {
+		t.Fatalf("ExportCSV failed: %v", err)
+	}
+
+	expected := "level,msg,attr.user_id,attr.http.status\nERROR,payment failed,u1,502\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected CSV output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := ExportNDJSON(&buf, entries, []string{"msg", "attr.missing"}); err != nil {
+		t.Fatalf("ExportNDJSON failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != `{"msg":"payment failed"}` {
+		t.Errorf("unexpected NDJSON output: %s", buf.String())
+	}
+}
@@ -0,0 +1,153 @@
+package viewer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Appender 接收日志条目的目标，本地存储和远程推送都实现该接口
+type Appender interface {
+	Add(e Entry) Entry
+}
+
+// Handler 将本进程的日志写入查看器存储
+type Handler struct {
+	store  Appender
+	source string
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewHandler 创建查看器处理器，source 为本进程在查看器中显示的来源名称
+func NewHandler(store Appender, source string, level slog.Leveler) *Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Handler{
+		store:  store,
+		source: source,
+		level:  level,
+	}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make(map[string]interface{}, r.NumAttrs()+len(h.attrs))
+	for _, a := range h.attrs {
+		addAttr(attrs, a)
+	}
+
+	// 记录自带的属性需要放在 WithGroup 指定的分组下
+	target := attrs
+	if r.NumAttrs() > 0 {
+		for _, g := range h.groups {
+			sub, ok := target[g].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				target[g] = sub
+			}
+			target = sub
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(target, a)
+		return true
+	})
+
+	h.store.Add(Entry{
+		Time:    r.Time,
+		Level:   r.Level.String(),
+		Message: r.Message,
+		Source:  h.source,
+		Attrs:   attrs,
+	})
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	if len(h.groups) > 0 {
+		// 将属性包装到当前分组下，保持与其他处理器一致的结构
+		grouped := make([]any, len(attrs))
+		for i, a := range attrs {
+			grouped[i] = a
+		}
+		wrapped := slog.Group(h.groups[len(h.groups)-1], grouped...)
+		for i := len(h.groups) - 2; i >= 0; i-- {
+			wrapped = slog.Group(h.groups[i], wrapped)
+		}
+		clone.attrs = append(append([]slog.Attr{}, h.attrs...), wrapped)
+		return &clone
+	}
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string{}, h.groups...), name)
+	return &clone
+}
+
+// addAttr 将属性写入map，分组展开为嵌套map
+func addAttr(m map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		if len(group) == 0 {
+			return
+		}
+		if a.Key == "" {
+			for _, ga := range group {
+				addAttr(m, ga)
+			}
+			return
+		}
+		sub, ok := m[a.Key].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{}, len(group))
+			m[a.Key] = sub
+		}
+		for _, ga := range group {
+			addAttr(sub, ga)
+		}
+		return
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		m[a.Key] = a.Value.String()
+	case slog.KindInt64:
+		m[a.Key] = a.Value.Int64()
+	case slog.KindUint64:
+		m[a.Key] = a.Value.Uint64()
+	case slog.KindFloat64:
+		m[a.Key] = a.Value.Float64()
+	case slog.KindBool:
+		m[a.Key] = a.Value.Bool()
+	case slog.KindDuration:
+		m[a.Key] = a.Value.Duration().String()
+	case slog.KindTime:
+		m[a.Key] = a.Value.Time()
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			m[a.Key] = err.Error()
+		} else {
+			m[a.Key] = a.Value.Any()
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package viewer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/shuakami/logmiao/handler"
+)
+
+// PushOptions 远程推送选项
+type PushOptions struct {
+	Username  string
+	Password  string
+	Token     string                  // Bearer 令牌，设置后代替 Basic 认证
+	Breaker   *handler.CircuitBreaker // 熔断器，为nil时不熔断
+	SpillPath string                  // 熔断或发送失败时将条目以JSON行写入该文件，为空时丢弃
+	Queue     handler.QueueConfig     // 发送队列及溢出策略，默认容量1024、丢弃新记录
+	Client    *http.Client            // 自定义HTTP客户端（mTLS、代理），为nil时使用5秒超时的默认客户端
+	Batch     handler.BatchConfig     // 组批阈值，默认每批100条或每秒发送一次
+	Retry     handler.RetryConfig     // 发送失败时的重试策略，默认不重试
+	// WriteTimeout 单次发送的超时，超时按可重试错误处理；为0时只受 Client 自身超时限制
+	WriteTimeout time.Duration
+}
+
+// PushStats 远程推送统计
+type PushStats struct {
+	Sent    int64                `json:"sent"`
+	Dropped int64                `json:"dropped"` // 队列溢出或发送失败后丢弃的条目
+	Spilled int64                `json:"spilled"` // 写入降级文件的条目
+	Retries int64                `json:"retries"` // 批次重试次数
+	Queue   handler.QueueStats   `json:"queue"`
+	Batch   handler.BatchStats   `json:"batch"`
+	Breaker handler.BreakerStats `json:"breaker"`
+}
+
+// Pusher 将日志批量推送到远程 logmiao 查看器的 /api/ingest 接口
+type Pusher struct {
+	url    string
+	opts   PushOptions
+	client *http.Client
+
+	queue   *handler.Queue[Entry]
+	batcher *handler.Batcher[Entry]
+
+	// ctx 所有发送请求的父上下文，CloseContext 的 ctx 结束时取消，中断无响应的发送
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sent    atomic.Int64
+	dropped atomic.Int64
+	spilled atomic.Int64
+	retries atomic.Int64
+}
+
+// NewPusher 创建远程推送器，baseURL 为中心查看器地址，如 http://logs.internal:8081
+func NewPusher(baseURL, username, password string) *Pusher {
+	// 默认的 drop_newest 策略总是有效
+	p, _ := NewPusherWithOptions(baseURL, PushOptions{Username: username, Password: password})
+	return p
+}
+
+// NewPusherWithOptions 创建带熔断和降级选项的远程推送器，队列的溢出策略无效时返回错误
+func NewPusherWithOptions(baseURL string, opts PushOptions) (*Pusher, error) {
+	if opts.Queue.Policy == "" {
+		opts.Queue.Policy = handler.PolicyDropNewest
+	}
+	queue, err := handler.NewQueue[Entry](opts.Queue)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 5 * time.Second}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pusher{
+		url:    strings.TrimRight(baseURL, "/") + "/api/ingest",
+		opts:   opts,
+		client: opts.Client,
+		queue:  queue,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.batcher = handler.NewBatcher(p.queue.C(), opts.Batch, entrySize, p.deliver)
+	return p, nil
+}
+
+// Add 将条目放入发送队列，队列已满时按配置的溢出策略处理
+func (p *Pusher) Add(e Entry) Entry {
+	p.queue.Push(e)
+	return e
+}
+
+// Stats 返回推送统计
+func (p *Pusher) Stats() PushStats {
+	stats := PushStats{
+		Sent:    p.sent.Load(),
+		Dropped: p.dropped.Load() + p.queue.Dropped(),
+		Spilled: p.spilled.Load(),
+		Retries: p.retries.Load(),
+		Queue:   p.queue.Stats(),
+		Batch:   p.batcher.Stats(),
+	}
+	if p.opts.Breaker != nil {
+		stats.Breaker = p.opts.Breaker.Stats()
+	}
+	return stats
+}
+
+// FlushContext 立即发送已入队的条目并等待发送完成，实现 handler.ContextFlusher
+func (p *Pusher) FlushContext(ctx context.Context) error {
+	return p.batcher.Flush(ctx)
+}
+
+// Close 发送剩余的条目并停止推送
+func (p *Pusher) Close() error {
+	return p.CloseContext(context.Background())
+}
+
+// CloseContext 发送剩余的条目并停止推送；ctx 结束时取消进行中的发送，
+// 剩余条目写入降级文件或丢弃，避免无响应的接收端阻塞应用关闭
+func (p *Pusher) CloseContext(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.batcher.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// entrySize 估算条目序列化后的大小，用于按字节数组批
+func entrySize(e Entry) int {
+	data, _ := json.Marshal(e)
+	return len(data) + 1
+}
+
+// deliver 经过熔断器发送一批条目，熔断或失败时走降级路径
+func (p *Pusher) deliver(batch []Entry) {
+	breaker := p.opts.Breaker
+	if breaker != nil && !breaker.Allow() {
+		p.spill(batch)
+		return
+	}
+
+	attempts := 0
+	err := handler.Retry(p.ctx, p.opts.Retry, func() error {
+		if attempts++; attempts > 1 {
+			p.retries.Add(1)
+		}
+		return p.send(batch)
+	})
+	if err != nil {
+		if breaker != nil {
+			breaker.Failure(err)
+		}
+		p.spill(batch)
+		return
+	}
+
+	if breaker != nil {
+		breaker.Success()
+	}
+	p.sent.Add(int64(len(batch)))
+}
+
+// send 发送一批条目
+func (p *Pusher) send(batch []Entry) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	ctx := p.ctx
+	if p.opts.WriteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.opts.WriteTimeout)
+		defer cancel()
+	}
+	return p.post(ctx, body)
+}
+
+// Probe 发送空批次，确认远程查看器可达且认证通过，用于启动预热和就绪检查
+func (p *Pusher) Probe(ctx context.Context) error {
+	return p.post(ctx, []byte("[]"))
+}
+
+// post 将请求体发送到远程查看器
+func (p *Pusher) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.opts.Token)
+	} else if p.opts.Username != "" {
+		req.SetBasicAuth(p.opts.Username, p.opts.Password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("push failed: %w", &handler.StatusError{StatusCode: resp.StatusCode})
+	}
+	return nil
+}
+
+// spill 将未能发送的条目写入降级文件，未配置时丢弃
+func (p *Pusher) spill(batch []Entry) {
+	if p.opts.SpillPath == "" {
+		p.dropped.Add(int64(len(batch)))
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.opts.SpillPath), 0755); err != nil {
+		p.dropped.Add(int64(len(batch)))
+		return
+	}
+	f, err := os.OpenFile(p.opts.SpillPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		p.dropped.Add(int64(len(batch)))
+		return
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range batch {
+		_ = enc.Encode(e)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		p.dropped.Add(int64(len(batch)))
+		return
+	}
+	p.spilled.Add(int64(len(batch)))
+}
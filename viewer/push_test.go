@@ -0,0 +1,88 @@
+package viewer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// TestPusherHungCollector 测试接收端无响应时单次发送超时与 CloseContext 的截止时间都能生效
+func TestPusherHungCollector(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	t.Run("write_timeout", func(t *testing.T) {
+		p, _ := NewPusherWithOptions(server.URL, PushOptions{
+			Client:       &http.Client{},
+			WriteTimeout: 50 * time.Millisecond,
+			Batch:        handler.BatchConfig{MaxRecords: 1},
+		})
+		p.Add(Entry{Message: "stuck"})
+
+		start := time.Now()
+		if err := p.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Close took %s with a 50ms write timeout", elapsed)
+		}
+		if s := p.Stats(); s.Sent != 0 || s.Dropped != 1 {
+			t.Errorf("stats = %+v, want the timed out record dropped", s)
+		}
+	})
+
+	t.Run("close_deadline", func(t *testing.T) {
+		p, _ := NewPusherWithOptions(server.URL, PushOptions{
+			Client: &http.Client{},
+			Batch:  handler.BatchConfig{MaxRecords: 1},
+			Retry:  handler.RetryConfig{MaxAttempts: 5},
+		})
+		p.Add(Entry{Message: "stuck"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if err := p.CloseContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("CloseContext = %v, want deadline exceeded", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("CloseContext took %s past a 100ms deadline", elapsed)
+		}
+		if s := p.Stats(); s.Retries != 0 || s.Dropped != 1 {
+			t.Errorf("stats = %+v, want no retries after the deadline", s)
+		}
+	})
+}
+
+// TestPusherProbe 测试预热探测：令牌正确时通过，认证失败时返回错误，且不会写入任何条目
+func TestPusherProbe(t *testing.T) {
+	store := NewStore(10)
+	srv := NewServer(config.ViewerConfig{Auth: config.AuthConfig{Tokens: []string{"push-token"}}}, store)
+	server := httptest.NewServer(srv.Handler())
+	defer server.Close()
+
+	for token, wantOK := range map[string]bool{"push-token": true, "wrong": false} {
+		p, _ := NewPusherWithOptions(server.URL, PushOptions{Client: &http.Client{}, Token: token})
+		err := p.Probe(context.Background())
+		if (err == nil) != wantOK {
+			t.Errorf("token %q: Probe = %v", token, err)
+		}
+		_ = p.Close()
+	}
+	if n := len(store.List(Query{})); n != 0 {
+		t.Errorf("probe should not ingest entries, store has %d", n)
+	}
+}
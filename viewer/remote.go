@@ -0,0 +1,84 @@
+package viewer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RemoteSource 轮询另一个查看器的 /api/logs，将新记录导入本地存储
+type RemoteSource struct {
+	URL      string // 远程查看器地址，例如 http://logs.internal:8081
+	Username string
+	Password string
+	Token    string        // Bearer 令牌，设置后代替 Basic 认证
+	Interval time.Duration // 轮询间隔
+
+	client *http.Client
+	lastID uint64
+}
+
+// NewRemoteSource 创建远程查看器来源
+func NewRemoteSource(url, username, password string) *RemoteSource {
+	return &RemoteSource{
+		URL:      strings.TrimRight(url, "/"),
+		Username: username,
+		Password: password,
+		Interval: time.Second,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Run 持续拉取远程新增的记录，直到 ctx 结束
+func (s *RemoteSource) Run(ctx context.Context, store *Store) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.poll(ctx, store)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll 拉取一次，远程记录ID单调递增，只导入上次之后的记录
+func (s *RemoteSource) poll(ctx context.Context, store *Store) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/api/logs?limit=1000", nil)
+	if err != nil {
+		return
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	} else if s.Username != "" {
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var entries []Entry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return
+	}
+	// 远程查看器重启后ID从头计数
+	if len(entries) > 0 && entries[len(entries)-1].ID < s.lastID {
+		s.lastID = 0
+	}
+	for _, e := range entries {
+		if e.ID <= s.lastID {
+			continue
+		}
+		s.lastID = e.ID
+		store.Add(e)
+	}
+}
@@ -0,0 +1,139 @@
+package viewer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyFile 检索历史记录时读取的日志文件及其来源名称
+type historyFile struct {
+	source string
+	path   string
+}
+
+// historyFiles 返回 viewer.history 匹配的文件（来源为本进程）和 viewer.sources 采集的文件
+func (s *Server) historyFiles() []historyFile {
+	var files []historyFile
+	for _, pattern := range s.cfg.History {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, path := range matches {
+			files = append(files, historyFile{source: s.cfg.Source, path: path})
+		}
+	}
+	for _, src := range s.cfg.Sources {
+		name := src.Name
+		if name == "" {
+			name = src.Path
+		}
+		files = append(files, historyFile{source: name, path: src.Path})
+	}
+	return files
+}
+
+// search 按查询条件检索记录：先查内存缓冲区，指定的起始时间早于缓冲区最早记录且结果不足 Limit 条时，
+// 再从日志文件中检索更早的记录补足。文件中的记录没有编号（ID 为0）
+func (s *Server) search(q Query) []Entry {
+	entries := s.store.List(q)
+	if q.Since.IsZero() || q.Before != 0 || (q.Limit > 0 && len(entries) >= q.Limit) {
+		return entries
+	}
+	files := s.historyFiles()
+	if len(files) == 0 {
+		return entries
+	}
+
+	cutoff, ok := s.store.Oldest()
+	if ok && !q.Since.Before(cutoff) {
+		return entries
+	}
+	fileQuery := q
+	if ok && (fileQuery.Until.IsZero() || cutoff.Before(fileQuery.Until)) {
+		fileQuery.Until = cutoff // 缓冲区中已有的时间段不重复检索
+	}
+	limit := 0
+	if q.Limit > 0 {
+		limit = q.Limit - len(entries)
+	}
+	return append(searchFiles(files, fileQuery, limit), entries...)
+}
+
+// searchFiles 在 JSON 日志文件中检索满足条件的记录，按时间升序返回最近的 limit 条，limit<=0 表示全部。
+// 支持 gzip 压缩的轮转文件，无法读取的文件被跳过
+func searchFiles(files []historyFile, q Query, limit int) []Entry {
+	var result []Entry
+	for _, f := range files {
+		_ = scanFile(f.path, func(e Entry) {
+			e.Source = f.source
+			if q.matches(e) {
+				result = append(result, e)
+			}
+		})
+		// 各文件内按时间有序，累积过多时先保留最近的部分以限制内存
+		if limit > 0 && len(result) > 4*limit {
+			sortByTime(result)
+			result = append(result[:0], result[len(result)-limit:]...)
+		}
+	}
+	sortByTime(result)
+	if limit > 0 && len(result) > limit {
+		result = result[len(result)-limit:]
+	}
+	return result
+}
+
+// scanFile 逐行解析 JSON 日志文件
+func scanFile(path string, fn func(Entry)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxIngestSize)
+	for scanner.Scan() {
+		if e, ok := ParseJSONLine(scanner.Bytes()); ok {
+			fn(e)
+		}
+	}
+	return scanner.Err()
+}
+
+// sortByTime 按时间稳定排序
+func sortByTime(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+}
+
+// parseTimeParam 解析时间参数：相对时长（如 10m、24h，表示 now 之前）或 RFC3339 时间
+func parseTimeParam(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: use a duration like 10m or an RFC3339 timestamp", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,392 @@
+package viewer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// maxIngestSize 单次推送请求体的最大大小
+const maxIngestSize = 10 << 20
+
+const (
+	// streamBuffer 实时推送每个连接可缓冲的记录数，超出时断开由浏览器重连补发
+	streamBuffer = 256
+	// streamHeartbeat 实时推送的心跳间隔，避免代理因空闲断开连接
+	streamHeartbeat = 15 * time.Second
+)
+
+// Server Web日志查看器
+type Server struct {
+	cfg    config.ViewerConfig
+	store  *Store
+	auth   *authenticator
+	srv    *http.Server
+	cancel context.CancelFunc
+	done   chan struct{} // Close 时关闭，结束实时推送的长连接
+	once   sync.Once
+	extra  map[string]http.Handler // Mount 挂载的额外路由
+}
+
+// NewServer 创建查看器服务
+func NewServer(cfg config.ViewerConfig, store *Store) *Server {
+	return &Server{
+		cfg:   cfg,
+		store: store,
+		auth:  newAuthenticator(cfg.Auth),
+		done:  make(chan struct{}),
+	}
+}
+
+// Store 返回查看器使用的存储
+func (s *Server) Store() *Store {
+	return s.store
+}
+
+// Mount 在查看器上挂载额外的路由（如 /metrics），与其他页面一样需要认证，须在 Start 或 Handler 之前调用
+func (s *Server) Mount(pattern string, h http.Handler) {
+	if s.extra == nil {
+		s.extra = make(map[string]http.Handler)
+	}
+	s.extra[pattern] = h
+}
+
+// Handler 返回查看器的HTTP处理器，可挂载到已有的服务中；未配置认证时不做认证，由挂载方负责访问控制
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/dashboard", s.handleDashboardPage)
+	mux.HandleFunc("/api/dashboard", s.handleDashboard)
+	mux.HandleFunc("/api/logs", s.handleLogs)
+	mux.HandleFunc("/api/logs/{id}", s.handleEntry)
+	mux.HandleFunc("/api/stream", s.handleStream)
+	mux.HandleFunc("/api/sources", s.handleSources)
+	mux.HandleFunc("/api/ingest", s.handleIngest)
+	mux.HandleFunc("/api/export", s.handleExport)
+	for pattern, h := range s.extra {
+		mux.Handle(pattern, h)
+	}
+	if !s.auth.configured() {
+		return mux
+	}
+	return s.auth.middleware(mux)
+}
+
+// Start 启动文件来源采集和HTTP监听。未配置任何认证方式时拒绝启动，除非设置了 auth.disabled
+func (s *Server) Start() error {
+	if !s.auth.configured() && !s.cfg.Auth.Disabled {
+		return errNoCredentials
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, src := range s.cfg.Sources {
+		go NewFileSource(src.Name, src.Path).Run(ctx, s.store)
+	}
+
+	s.srv = &http.Server{
+		Addr:              fmt.Sprintf(":%d", s.cfg.Port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	// 等待片刻以捕获端口占用等启动错误
+	select {
+	case err := <-errCh:
+		cancel()
+		return err
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+}
+
+// Close 停止查看器
+func (s *Server) Close() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.once.Do(func() { close(s.done) })
+	if s.srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	servePage(w, "static/index.html")
+}
+
+func (s *Server) handleDashboardPage(w http.ResponseWriter, r *http.Request) {
+	servePage(w, "static/dashboard.html")
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	window, err := time.ParseDuration(r.URL.Query().Get("window"))
+	if err != nil {
+		window = time.Hour
+	}
+	top, _ := strconv.Atoi(r.URL.Query().Get("top"))
+	writeJSON(w, s.store.Dashboard(window, top))
+}
+
+// handleLogs 查询记录，如 /api/logs?level=error&since=10m&q=timeout&attr.user_id=123
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if q.Limit <= 0 {
+		q.Limit = 500
+	}
+	writeJSON(w, s.search(q))
+}
+
+// handleEntry 按编号返回单条记录，供书签链接定位
+func (s *Server) handleEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+	entry, ok := s.store.Get(id)
+	if !ok {
+		http.Error(w, "entry no longer in buffer", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, entry)
+}
+
+// handleStream 以 Server-Sent Events 实时推送新记录，支持与 /api/logs 相同的 source、level 过滤。
+// 重连时浏览器携带的 Last-Event-ID（或 after 参数）之后到达的记录会先补发
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	q, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q.Before, q.Limit = 0, 0
+	if id, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		q.After = id
+	}
+	backlog, entries, cancel := s.store.Subscribe(q, streamBuffer)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // 关闭 nginx 的响应缓冲
+	w.WriteHeader(http.StatusOK)
+	for _, e := range backlog {
+		if writeEvent(w, e) != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case e, ok := <-entries:
+			if !ok {
+				return // 跟不上被断开，浏览器会凭 Last-Event-ID 重连补发
+			}
+			if writeEvent(w, e) != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent 以记录编号为事件 ID 写出一条 SSE 事件
+func writeEvent(w io.Writer, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.ID, data)
+	return err
+}
+
+// handleExport 导出过滤后的记录，format=csv|ndjson，columns=time,level,msg,attr.user_id
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	q, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries := s.search(q)
+
+	var columns []string
+	if cols := r.URL.Query().Get("columns"); cols != "" {
+		for _, col := range strings.Split(cols, ",") {
+			if col = strings.TrimSpace(col); col != "" {
+				columns = append(columns, col)
+			}
+		}
+	}
+
+	filename := "logmiao-" + time.Now().Format("20060102-150405")
+	switch r.URL.Query().Get("format") {
+	case "ndjson", "json":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.ndjson"`)
+		err = ExportNDJSON(w, entries, columns)
+	default:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`.csv"`)
+		err = ExportCSV(w, entries, columns)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseQuery 从请求参数解析查询条件：source、level、limit、before、after、
+// since/until（相对时长如 10m，或 RFC3339 时间）、q（消息文本）、attr.<路径>（属性取值）
+func parseQuery(r *http.Request) (Query, error) {
+	params := r.URL.Query()
+	q := Query{Source: params.Get("source"), Text: params.Get("q")}
+	q.Limit, _ = strconv.Atoi(params.Get("limit"))
+	q.Before, _ = strconv.ParseUint(params.Get("before"), 10, 64)
+	q.After, _ = strconv.ParseUint(params.Get("after"), 10, 64)
+	if level := params.Get("level"); level != "" {
+		q.MinLevel = parseLevel(strings.ToUpper(level))
+		q.HasLevel = true
+	}
+
+	now := time.Now()
+	for name, dst := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if value := params.Get(name); value != "" {
+			t, err := parseTimeParam(value, now)
+			if err != nil {
+				return Query{}, fmt.Errorf("%s: %w", name, err)
+			}
+			*dst = t
+		}
+	}
+
+	for name, values := range params {
+		if path, ok := strings.CutPrefix(name, "attr."); ok && path != "" {
+			if q.Attrs == nil {
+				q.Attrs = make(map[string]string)
+			}
+			q.Attrs[path] = values[0]
+		}
+	}
+	return q, nil
+}
+
+func (s *Server) handleSources(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.store.Sources())
+}
+
+// handleIngest 接收远程实例推送的日志，支持 JSON 数组或 slog JSON 行格式
+func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxIngestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	source := r.Header.Get("X-Logmiao-Source")
+	if source == "" {
+		source = r.URL.Query().Get("source")
+	}
+	if source == "" {
+		source = "remote"
+	}
+
+	count := 0
+	trimmed := bytes.TrimSpace(body)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		var entries []Entry
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, e := range entries {
+			if e.Source == "" {
+				e.Source = source
+			}
+			s.store.Add(e)
+			count++
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxIngestSize)
+		for scanner.Scan() {
+			if e, ok := ParseJSONLine(scanner.Bytes()); ok {
+				e.Source = source
+				s.store.Add(e)
+				count++
+			}
+		}
+	}
+
+	writeJSON(w, map[string]int{"accepted": count})
+}
+
+// servePage 输出内嵌的静态页面
+func servePage(w http.ResponseWriter, name string) {
+	page, err := staticFiles.ReadFile(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
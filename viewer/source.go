@@ -0,0 +1,131 @@
+package viewer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// FileSource 跟踪一个 JSON 格式的日志文件，将新增内容导入存储
+type FileSource struct {
+	Name     string        // 在查看器中显示的来源名称
+	Path     string        // 日志文件路径（logmiao 的 JSON 文件输出）
+	Interval time.Duration // 轮询间隔
+
+	offset int64
+	file   os.FileInfo // 上次读取的文件，用于识别轮转
+}
+
+// NewFileSource 创建文件日志来源
+func NewFileSource(name, path string) *FileSource {
+	if name == "" {
+		name = path
+	}
+	return &FileSource{
+		Name:     name,
+		Path:     path,
+		Interval: time.Second,
+	}
+}
+
+// Run 持续读取文件新增内容，直到 ctx 结束
+func (s *FileSource) Run(ctx context.Context, store *Store) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.poll(store)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll 读取一次文件的新增内容
+func (s *FileSource) poll(store *Store) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return // 文件可能尚未创建或正在轮转
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	// 文件被截断，或轮转为新文件（即使新文件已超过原偏移量）后从头读取
+	if info.Size() < s.offset || (s.file != nil && !os.SameFile(s.file, info)) {
+		s.offset = 0
+	}
+	s.file = info
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// 不完整的行留到下次读取
+			return
+		}
+		s.offset += int64(len(line))
+		if entry, ok := ParseJSONLine(line); ok {
+			entry.Source = s.Name
+			store.Add(entry)
+		}
+	}
+}
+
+// ParseJSONLine 解析一行 slog JSON 日志
+func ParseJSONLine(line []byte) (Entry, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return Entry{}, false
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, false
+	}
+
+	// 兼容信封格式 {"schema_version":1,"app":"...","payload":{...}}
+	if payload, ok := raw["payload"].(map[string]interface{}); ok {
+		if _, hasVersion := raw["schema_version"]; hasVersion {
+			raw = payload
+		}
+	}
+
+	entry := Entry{Attrs: make(map[string]interface{}, len(raw))}
+	for key, value := range raw {
+		switch key {
+		case "time":
+			if ts, ok := value.(string); ok {
+				entry.Time, _ = time.Parse(time.RFC3339Nano, ts)
+			}
+		case "level", "severity":
+			entry.Level = strings.ToUpper(toString(value))
+		case "msg":
+			entry.Message = toString(value)
+		default:
+			entry.Attrs[key] = value
+		}
+	}
+	return entry, true
+}
+
+// toString 将任意值转换为字符串
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,298 @@
+package viewer
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize 默认保留的日志条数
+const DefaultBufferSize = 5000
+
+// Entry 查看器中的一条日志记录
+type Entry struct {
+	ID      uint64                 `json:"id"` // 存储分配的编号，不随插入、淘汰变化，可用于书签链接
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"msg"`
+	Source  string                 `json:"source"` // 日志来源（进程/文件/远程实例名称）
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Query 日志查询条件
+type Query struct {
+	Source   string     // 只返回指定来源，空表示全部
+	MinLevel slog.Level // 最低级别
+	HasLevel bool       // 是否按级别过滤
+	Limit    int        // 返回最近的条数，<=0 表示全部
+	Before   uint64     // 只返回排在该编号记录之前的记录，用于向前翻阅；该记录已淘汰时返回空
+	After    uint64     // 只返回编号大于该值（即在其之后到达）的记录，用于实时推送断线后补发
+
+	Since time.Time         // 只返回不早于该时间的记录，零值表示不限
+	Until time.Time         // 只返回早于该时间的记录，零值表示不限
+	Text  string            // 消息中包含该文本（不区分大小写）
+	Attrs map[string]string // 属性完整路径（如 http.status）到取值的精确匹配
+}
+
+// matches 检查记录是否满足查询条件（Before 与 Limit 除外）
+func (q Query) matches(e Entry) bool {
+	if q.Source != "" && e.Source != q.Source {
+		return false
+	}
+	if q.HasLevel && parseLevel(e.Level) < q.MinLevel {
+		return false
+	}
+	if q.After != 0 && e.ID <= q.After {
+		return false
+	}
+	if !q.Since.IsZero() && e.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Time.Before(q.Until) {
+		return false
+	}
+	if q.Text != "" && !strings.Contains(strings.ToLower(e.Message), strings.ToLower(q.Text)) {
+		return false
+	}
+	for path, value := range q.Attrs {
+		if formatColumn(lookupAttr(e.Attrs, path)) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Store 内存环形日志存储，按时间戳有序保存来自多个来源的记录
+type Store struct {
+	mu       sync.RWMutex
+	entries  []Entry // 环形缓冲区，head 处为最早的记录
+	head     int
+	count    int
+	capacity int
+	nextID   uint64
+	sources  map[string]int
+	// subscribers 实时订阅者及其过滤条件，见 Subscribe
+	subscribers map[chan Entry]Query
+}
+
+// NewStore 创建内存日志存储
+func NewStore(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = DefaultBufferSize
+	}
+	return &Store{
+		entries:  make([]Entry, capacity),
+		capacity: capacity,
+		sources:  make(map[string]int),
+	}
+}
+
+// Add 按时间顺序插入一条记录，超出容量时丢弃最旧的记录
+func (s *Store) Add(e Entry) Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	e.ID = s.nextID
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	// 多来源的记录可能乱序到达，晚于已有记录时直接追加，否则按时间戳找到插入位置
+	idx := s.count
+	if s.count > 0 && s.at(s.count-1).Time.After(e.Time) {
+		idx = sort.Search(s.count, func(i int) bool {
+			return s.at(i).Time.After(e.Time)
+		})
+	}
+	if s.count == s.capacity && idx > 0 {
+		// 已满时淘汰最旧的记录
+		s.sources[s.at(0).Source]--
+		*s.at(0) = Entry{}
+		s.head = (s.head + 1) % s.capacity
+		s.count--
+		idx--
+	}
+	// 已满且早于所有记录时，新记录本身即为应淘汰的最旧记录，不再保存
+	if s.count < s.capacity {
+		for i := s.count; i > idx; i-- {
+			*s.at(i) = *s.at(i - 1)
+		}
+		*s.at(idx) = e
+		s.count++
+		s.sources[e.Source]++
+	}
+
+	for ch, q := range s.subscribers {
+		if !q.matches(e) {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+			// 订阅者跟不上时断开，由其凭最后收到的编号重新订阅补发，而不是静默丢失记录
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return e
+}
+
+// Subscribe 订阅之后到达且满足 q 的记录。q.After 非0时同时返回缓冲区中在其之后到达的记录，用于补发断线期间的记录，
+// 补发与订阅之间不会遗漏或重复。订阅者跟不上导致通道缓冲已满时通道被关闭；cancel 取消订阅
+func (s *Store) Subscribe(q Query, buffer int) (backlog []Entry, ch <-chan Entry, cancel func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q.After != 0 {
+		backlog = s.listLocked(q)
+	}
+	c := make(chan Entry, buffer)
+	if s.subscribers == nil {
+		s.subscribers = make(map[chan Entry]Query)
+	}
+	s.subscribers[c] = q
+	return backlog, c, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[c]; ok {
+			delete(s.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+// List 返回满足条件的记录，按时间升序排列
+func (s *Store) List(q Query) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.listLocked(q)
+}
+
+// at 返回按时间排序的第 i 条记录，调用方需持有锁
+func (s *Store) at(i int) *Entry {
+	return &s.entries[(s.head+i)%s.capacity]
+}
+
+// listLocked 实现 List，调用方需持有锁
+func (s *Store) listLocked(q Query) []Entry {
+	lo, hi := 0, s.count
+	if q.Before != 0 {
+		i, ok := s.indexOf(q.Before)
+		if !ok {
+			return []Entry{}
+		}
+		hi = i
+	}
+	// 记录按时间有序，时间范围用二分查找缩小扫描区间
+	if !q.Since.IsZero() {
+		lo += sort.Search(hi-lo, func(i int) bool {
+			return !s.at(lo + i).Time.Before(q.Since)
+		})
+	}
+	if !q.Until.IsZero() {
+		hi = lo + sort.Search(hi-lo, func(i int) bool {
+			return !s.at(lo + i).Time.Before(q.Until)
+		})
+	}
+
+	result := make([]Entry, 0, hi-lo)
+	for i := lo; i < hi; i++ {
+		if e := *s.at(i); q.matches(e) {
+			result = append(result, e)
+		}
+	}
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
+
+// Get 按编号返回记录，记录已被淘汰时返回 false
+func (s *Store) Get(id uint64) (Entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i, ok := s.indexOf(id); ok {
+		return *s.at(i), true
+	}
+	return Entry{}, false
+}
+
+// indexOf 返回编号为 id 的记录位置。记录按时间排序而编号按到达顺序分配，两者不一定一致，因此需要逐条查找
+func (s *Store) indexOf(id uint64) (int, bool) {
+	for i := s.count - 1; i >= 0; i-- {
+		if s.at(i).ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Recent 返回最近 n 条满足 filter 的记录，按时间升序排列，n<=0 表示全部，filter 为 nil 时不过滤
+func (s *Store) Recent(n int, filter func(Entry) bool) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Entry
+	for i := s.count - 1; i >= 0 && (n <= 0 || len(result) < n); i-- {
+		if e := *s.at(i); filter == nil || filter(e) {
+			result = append(result, e)
+		}
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+// Oldest 返回缓冲区中最早记录的时间，存储为空时返回 false
+func (s *Store) Oldest() (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.count == 0 {
+		return time.Time{}, false
+	}
+	return s.at(0).Time, true
+}
+
+// Sources 返回当前存储中各来源的记录数
+func (s *Store) Sources() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]int, len(s.sources))
+	for name, count := range s.sources {
+		if count > 0 {
+			result[name] = count
+		}
+	}
+	return result
+}
+
+// Len 返回当前保存的记录数
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.count
+}
+
+// parseLevel 解析级别字符串，无法识别时按 INFO 处理
+func parseLevel(s string) slog.Level {
+	// 兼容 Stackdriver 风格的 severity 取值
+	switch strings.ToUpper(s) {
+	case "WARNING":
+		return slog.LevelWarn
+	case "CRITICAL", "FATAL", "ALERT", "EMERGENCY":
+		return slog.LevelError + 4
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
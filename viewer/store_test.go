@@ -0,0 +1,328 @@
+package viewer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+// TestStoreMergesByTime 测试多来源记录按时间戳合并
+func TestStoreMergesByTime(t *testing.T) {
+	store := NewStore(3)
+	base := time.Now()
+
+	store.Add(Entry{Time: base.Add(2 * time.Second), Message: "b", Source: "api"})
+	store.Add(Entry{Time: base, Message: "a", Source: "worker"})
+	store.Add(Entry{Time: base.Add(3 * time.Second), Message: "c", Source: "api"})
+	store.Add(Entry{Time: base.Add(4 * time.Second), Message: "d", Source: "worker"})
+
+	entries := store.List(Query{})
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Message)
+	}
+	if strings.Join(got, "") != "bcd" {
+		t.Errorf("expected entries ordered as bcd, got %s", strings.Join(got, ""))
+	}
+
+	if sources := store.Sources(); sources["api"] != 2 || sources["worker"] != 1 {
+		t.Errorf("unexpected source counts: %v", sources)
+	}
+
+	if worker := store.List(Query{Source: "worker"}); len(worker) != 1 || worker[0].Message != "d" {
+		t.Errorf("source filter returned %v", worker)
+	}
+
+	// 环形缓冲区回绕后：乱序记录插入到正确位置，早于全部记录的记录直接淘汰
+	store.Add(Entry{Time: base.Add(3500 * time.Millisecond), Message: "e", Source: "worker"})
+	store.Add(Entry{Time: base, Message: "z", Source: "api"})
+	got = got[:0]
+	for _, e := range store.List(Query{}) {
+		got = append(got, e.Message)
+	}
+	if strings.Join(got, "") != "ced" {
+		t.Errorf("expected entries ordered as ced after wrapping, got %s", strings.Join(got, ""))
+	}
+	if since := store.List(Query{Since: base.Add(3500 * time.Millisecond)}); len(since) != 2 || since[0].Message != "e" {
+		t.Errorf("since filter returned %v", since)
+	}
+	if sources := store.Sources(); sources["api"] != 1 || sources["worker"] != 2 {
+		t.Errorf("unexpected source counts after wrapping: %v", sources)
+	}
+}
+
+// TestFileSourceRotation 测试文件轮转后即使新文件已超过原偏移量也从头读取
+func TestFileSourceRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := NewStore(10)
+	source := NewFileSource("app", path)
+
+	write(`{"level":"INFO","msg":"before rotation"}` + "\n")
+	source.poll(store)
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	write(`{"level":"INFO","msg":"first after rotation"}` + "\n" + `{"level":"INFO","msg":"second after rotation"}` + "\n")
+	source.poll(store)
+
+	var got []string
+	for _, e := range store.List(Query{}) {
+		got = append(got, e.Message)
+	}
+	if len(got) != 3 || got[1] != "first after rotation" {
+		t.Errorf("rotated file should be read from the start, got %q", got)
+	}
+}
+
+// TestIngest 测试远程推送接口
+func TestIngest(t *testing.T) {
+	store := NewStore(10)
+	server := NewServer(config.ViewerConfig{}, store)
+
+	body := `{"time":"2024-01-01T10:00:00Z","level":"ERROR","msg":"db down","host":"db1"}` + "\n" +
+		`{"time":"2024-01-01T09:00:00Z","level":"INFO","msg":"started"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/ingest", strings.NewReader(body))
+	req.Header.Set("X-Logmiao-Source", "node-2")
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ingest returned %d: %s", w.Code, w.Body.String())
+	}
+
+	entries := store.List(Query{})
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Message != "started" || entries[1].Source != "node-2" || entries[1].Attrs["host"] != "db1" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+}
+
+// TestStoreRecent 测试按过滤条件返回最近的记录
+func TestStoreRecent(t *testing.T) {
+	store := NewStore(10)
+	base := time.Now()
+	for i, level := range []string{"INFO", "ERROR", "INFO", "ERROR", "ERROR"} {
+		store.Add(Entry{Time: base.Add(time.Duration(i) * time.Second), Level: level, Message: fmt.Sprint(i)})
+	}
+
+	errors := store.Recent(2, func(e Entry) bool { return e.Level == "ERROR" })
+	if len(errors) != 2 || errors[0].Message != "3" || errors[1].Message != "4" {
+		t.Errorf("unexpected recent errors: %+v", errors)
+	}
+	if all := store.Recent(0, nil); len(all) != 5 {
+		t.Errorf("expected all 5 entries, got %d", len(all))
+	}
+}
+
+// TestStoreScrollback 测试按编号向前翻阅和书签链接，编号不随乱序插入与淘汰变化
+func TestStoreScrollback(t *testing.T) {
+	store := NewStore(4)
+	base := time.Now()
+	a := store.Add(Entry{Time: base, Message: "a"})
+	c := store.Add(Entry{Time: base.Add(2 * time.Second), Message: "c"})
+	b := store.Add(Entry{Time: base.Add(time.Second), Message: "b"}) // 乱序到达
+	d := store.Add(Entry{Time: base.Add(3 * time.Second), Message: "d"})
+
+	older := store.List(Query{Before: c.ID, Limit: 1})
+	if len(older) != 1 || older[0].ID != b.ID {
+		t.Errorf("expected b before c, got %+v", older)
+	}
+
+	server := NewServer(config.ViewerConfig{}, store)
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/logs/%d", d.ID), nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"msg":"d"`) {
+		t.Errorf("bookmark lookup returned %d: %s", w.Code, w.Body.String())
+	}
+
+	store.Add(Entry{Time: base.Add(4 * time.Second), Message: "e"}) // 淘汰 a
+	if _, ok := store.Get(a.ID); ok {
+		t.Error("evicted entry should not be found")
+	}
+	if got := store.List(Query{Before: a.ID}); len(got) != 0 {
+		t.Errorf("scrollback past an evicted entry should be empty, got %+v", got)
+	}
+	w = httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/logs/%d", a.ID), nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("evicted bookmark returned %d", w.Code)
+	}
+	if e, ok := store.Get(c.ID); !ok || e.Message != "c" {
+		t.Errorf("id of c should be stable, got %+v", e)
+	}
+}
+
+// TestStream 测试实时推送：按级别过滤，凭 Last-Event-ID 补发断线期间的记录，随后推送新记录
+func TestStream(t *testing.T) {
+	store := NewStore(10)
+	a := store.Add(Entry{Level: "INFO", Message: "a"})
+	b := store.Add(Entry{Level: "WARN", Message: "b"})
+	store.Add(Entry{Level: "INFO", Message: "c"})
+
+	server := NewServer(config.ViewerConfig{}, store)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/stream?level=warn", nil)
+	req.Header.Set("Last-Event-ID", fmt.Sprint(a.ID))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	events := make(chan string, 4)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+				events <- id
+			}
+		}
+		close(events)
+	}()
+	next := func() string {
+		select {
+		case id := <-events:
+			return id
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for event")
+			return ""
+		}
+	}
+
+	if id := next(); id != fmt.Sprint(b.ID) {
+		t.Errorf("backlog should replay b (id %d), got %s", b.ID, id)
+	}
+	store.Add(Entry{Level: "DEBUG", Message: "filtered"})
+	d := store.Add(Entry{Level: "ERROR", Message: "d"})
+	if id := next(); id != fmt.Sprint(d.ID) {
+		t.Errorf("expected live event d (id %d), got %s", d.ID, id)
+	}
+
+	server.Close()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected event after Close")
+		}
+	case <-time.After(time.Second):
+		t.Error("Close should end the stream")
+	}
+}
+
+// TestSearch 测试按时间、级别、消息文本与属性查询，起始时间早于缓冲区时从历史文件补足
+func TestSearch(t *testing.T) {
+	now := time.Now()
+	dir := t.TempDir()
+	history := fmt.Sprintf(`{"time":%q,"level":"ERROR","msg":"upstream timeout","user_id":123}
+{"time":%q,"level":"ERROR","msg":"upstream timeout","user_id":456}
+{"time":%q,"level":"INFO","msg":"request timeout retried","user_id":123}
+`, now.Add(-2*time.Hour).Format(time.RFC3339Nano), now.Add(-90*time.Minute).Format(time.RFC3339Nano),
+		now.Add(-80*time.Minute).Format(time.RFC3339Nano))
+	if err := os.WriteFile(filepath.Join(dir, "app.log"), []byte(history), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(10)
+	store.Add(Entry{Time: now.Add(-30 * time.Minute), Level: "ERROR", Message: "DB Timeout", Attrs: map[string]interface{}{"user_id": 123}})
+	store.Add(Entry{Time: now.Add(-20 * time.Minute), Level: "ERROR", Message: "db timeout", Attrs: map[string]interface{}{"user_id": 7}})
+	store.Add(Entry{Time: now.Add(-5 * time.Minute), Level: "WARN", Message: "slow timeout", Attrs: map[string]interface{}{"user_id": 123}})
+	server := NewServer(config.ViewerConfig{Source: "api", History: []string{filepath.Join(dir, "*.log")}}, store)
+
+	get := func(query string) (int, []Entry) {
+		w := httptest.NewRecorder()
+		server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs?"+query, nil))
+		var entries []Entry
+		_ = json.Unmarshal(w.Body.Bytes(), &entries)
+		return w.Code, entries
+	}
+
+	_, recent := get("level=error&since=1h&q=timeout&attr.user_id=123")
+	if len(recent) != 1 || recent[0].Message != "DB Timeout" {
+		t.Errorf("expected only the buffered match, got %+v", recent)
+	}
+
+	_, older := get("level=error&since=3h&q=timeout&attr.user_id=123")
+	if len(older) != 2 || older[0].Message != "upstream timeout" || older[0].Source != "api" || older[1].Message != "DB Timeout" {
+		t.Errorf("expected history match followed by buffered match, got %+v", older)
+	}
+
+	_, until := get(fmt.Sprintf("until=%s&since=3h", url.QueryEscape(now.Add(-25*time.Minute).Format(time.RFC3339Nano))))
+	if len(until) != 4 || until[3].Message != "DB Timeout" {
+		t.Errorf("until should exclude later records, got %+v", until)
+	}
+
+	if code, _ := get("since=yesterday"); code != http.StatusBadRequest {
+		t.Errorf("invalid since returned %d", code)
+	}
+}
+
+// TestDashboardSuppressed 测试采样保留的记录与风暴汇总记录计入直方图的 suppressed 序列
+func TestDashboardSuppressed(t *testing.T) {
+	store := NewStore(10)
+	now := time.Now()
+	store.Add(Entry{Time: now.Add(-10 * time.Minute), Level: "INFO", Message: "earlier"})
+	store.Add(Entry{Time: now, Level: "INFO", Message: "sampled", Attrs: map[string]interface{}{"sampled": true, "sample_rate": float64(10)}})
+	store.Add(Entry{Time: now, Level: "WARN", Message: "12 similar warn records in last 30s", Attrs: map[string]interface{}{"type": "burst_summary", "suppressed": int64(12)}})
+	store.Add(Entry{Time: now, Level: "INFO", Message: "plain"})
+
+	histogram := store.Dashboard(time.Hour, 10).Histogram
+	var bucket HistogramBucket
+	for _, b := range histogram {
+		if b.Time.Equal(now.Truncate(time.Minute)) {
+			bucket = b
+		}
+	}
+	if bucket.Suppressed != 21 || bucket.Counts["INFO"] != 2 || bucket.Counts["WARN"] != 1 {
+		t.Errorf("unexpected bucket %+v", bucket)
+	}
+	if histogram[1].Suppressed != 0 {
+		t.Errorf("empty minutes should have no suppressed records: %+v", histogram[1])
+	}
+}
+
+// TestDashboardWindow 测试直方图从缓冲区中最早的记录开始，过大的窗口被限制
+func TestDashboardWindow(t *testing.T) {
+	store := NewStore(10)
+	now := time.Now()
+	store.Add(Entry{Time: now.Add(-5 * time.Minute), Level: "INFO", Message: "first"})
+
+	d := store.Dashboard(1000*time.Hour, 10)
+	if want := now.Add(-5 * time.Minute).Truncate(time.Minute); !d.Since.Equal(want) {
+		t.Errorf("since = %s, want the oldest buffered minute %s", d.Since, want)
+	}
+	if n := len(d.Histogram); n < 5 || n > 7 {
+		t.Errorf("expected about 6 buckets, got %d", n)
+	}
+
+	empty := NewStore(10).Dashboard(1000*time.Hour, 10)
+	if n := len(empty.Histogram); n > int(maxDashboardWindow/time.Minute)+1 {
+		t.Errorf("window should be capped at %s, got %d buckets", maxDashboardWindow, n)
+	}
+}
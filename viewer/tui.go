@@ -0,0 +1,330 @@
+package viewer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TUI 终端日志查看器，与Web查看器共用 Store 查询层
+type TUI struct {
+	store   *Store
+	query   Query
+	follow  bool // 跟随最新日志
+	detail  bool // 显示详情面板
+	cursor  int  // 当前选中记录在过滤结果中的位置
+	top     int  // 列表首行对应的记录位置
+	width   int
+	height  int
+	entries []Entry
+
+	// Size 返回终端宽高，为空时使用 80x24
+	Size func() (width, height int)
+	// Refresh 无按键时的刷新间隔，默认 500ms
+	Refresh time.Duration
+}
+
+// NewTUI 创建终端查看器，默认跟随最新日志
+func NewTUI(store *Store) *TUI {
+	return &TUI{
+		store:   store,
+		follow:  true,
+		width:   80,
+		height:  24,
+		Refresh: 500 * time.Millisecond,
+	}
+}
+
+// SetMinLevel 设置最低显示级别
+func (t *TUI) SetMinLevel(level slog.Level) {
+	t.query.MinLevel = level
+	t.query.HasLevel = true
+}
+
+// Run 进入全屏界面，从 in 读取按键并向 out 绘制，按 q 或 ctx 结束时退出
+// 调用方负责将终端切换为原始模式
+func (t *TUI) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	keys := make(chan string)
+	go readKeys(in, keys)
+
+	// 切换到备用屏幕并隐藏光标
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	ticker := time.NewTicker(t.Refresh)
+	defer ticker.Stop()
+
+	for {
+		if t.Size != nil {
+			t.width, t.height = t.Size()
+		}
+		if _, err := io.WriteString(out, t.Render()); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok || !t.HandleKey(key) {
+				return nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// HandleKey 处理一个按键，返回 false 表示退出
+func (t *TUI) HandleKey(key string) bool {
+	page := t.listHeight()
+	switch key {
+	case "q", "ctrl+c":
+		return false
+	case "up", "k":
+		t.move(-1)
+	case "down", "j":
+		t.move(1)
+	case "pgup", "b":
+		t.move(-page)
+	case "pgdn", " ":
+		t.move(page)
+	case "home", "g":
+		t.follow = false
+		t.cursor = 0
+	case "end", "G":
+		t.follow = true
+	case "f":
+		t.follow = !t.follow
+	case "enter":
+		t.detail = !t.detail
+	case "esc":
+		t.detail = false
+	case "0":
+		t.query.HasLevel = false
+	case "1", "2", "3", "4":
+		levels := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+		t.SetMinLevel(levels[key[0]-'1'])
+	}
+	return true
+}
+
+// move 移动选中行，手动滚动时停止跟随
+func (t *TUI) move(delta int) {
+	t.cursor += delta
+	t.follow = delta > 0 && t.cursor >= len(t.entries)-1
+}
+
+// listHeight 日志列表可用的行数
+func (t *TUI) listHeight() int {
+	h := t.height - 2 // 标题栏和状态栏
+	if t.detail {
+		h /= 2
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// Render 查询存储并返回完整的一帧画面
+func (t *TUI) Render() string {
+	t.entries = t.store.List(t.query)
+	if t.follow || t.cursor >= len(t.entries) {
+		t.cursor = len(t.entries) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+
+	listHeight := t.listHeight()
+	if t.cursor < t.top {
+		t.top = t.cursor
+	}
+	if t.cursor >= t.top+listHeight {
+		t.top = t.cursor - listHeight + 1
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H\x1b[2J")
+
+	// 标题栏
+	level := "ALL"
+	if t.query.HasLevel {
+		level = t.query.MinLevel.String() + "+"
+	}
+	follow := "off"
+	if t.follow {
+		follow = "on"
+	}
+	header := fmt.Sprintf(" logmiao  %d records  level: %s  follow: %s", len(t.entries), level, follow)
+	sb.WriteString("\x1b[7m" + pad(header, t.width) + "\x1b[0m\r\n")
+
+	// 日志列表
+	for row := 0; row < listHeight; row++ {
+		i := t.top + row
+		if i < len(t.entries) {
+			line := pad(formatTUILine(t.entries[i]), t.width)
+			if i == t.cursor {
+				sb.WriteString("\x1b[7m" + line + "\x1b[0m")
+			} else {
+				sb.WriteString(levelANSI(t.entries[i].Level) + line + "\x1b[0m")
+			}
+		}
+		sb.WriteString("\r\n")
+	}
+
+	// 详情面板
+	if t.detail {
+		detailHeight := t.height - 2 - listHeight
+		lines := []string{strings.Repeat("─", t.width)}
+		if t.cursor < len(t.entries) {
+			lines = append(lines, detailLines(t.entries[t.cursor])...)
+		}
+		for row := 0; row < detailHeight; row++ {
+			if row < len(lines) {
+				sb.WriteString(truncate(lines[row], t.width))
+			}
+			sb.WriteString("\r\n")
+		}
+	}
+
+	// 状态栏
+	help := " ↑↓/jk move  PgUp/PgDn page  g/G top/bottom  f follow  Enter detail  1-4 level  0 all  q quit"
+	sb.WriteString("\x1b[2m" + truncate(help, t.width) + "\x1b[0m")
+	return sb.String()
+}
+
+// formatTUILine 列表中一条记录的单行摘要
+func formatTUILine(e Entry) string {
+	line := fmt.Sprintf("%s %-5s %s", e.Time.Format("15:04:05.000"), e.Level, e.Message)
+	if e.Source != "" {
+		line += "  [" + e.Source + "]"
+	}
+	return line
+}
+
+// detailLines 详情面板内容：属性按键排序，多行值（如堆栈）逐行展开
+func detailLines(e Entry) []string {
+	lines := []string{
+		fmt.Sprintf("time:   %s", e.Time.Format(time.RFC3339Nano)),
+		fmt.Sprintf("level:  %s", e.Level),
+		fmt.Sprintf("msg:    %s", e.Message),
+	}
+	if e.Source != "" {
+		lines = append(lines, fmt.Sprintf("source: %s", e.Source))
+	}
+
+	keys := make([]string, 0, len(e.Attrs))
+	for key := range e.Attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch v := e.Attrs[key].(type) {
+		case string:
+			if strings.Contains(v, "\n") {
+				lines = append(lines, key+":")
+				for _, l := range strings.Split(strings.TrimRight(v, "\n"), "\n") {
+					lines = append(lines, "    "+l)
+				}
+				continue
+			}
+			lines = append(lines, key+": "+v)
+		case map[string]interface{}, []interface{}:
+			data, _ := json.MarshalIndent(v, "    ", "  ")
+			lines = append(lines, key+":")
+			lines = append(lines, strings.Split("    "+string(data), "\n")...)
+		default:
+			lines = append(lines, key+": "+toString(v))
+		}
+	}
+	return lines
+}
+
+// levelANSI 级别对应的终端颜色
+func levelANSI(level string) string {
+	switch parseLevel(level) {
+	case slog.LevelError:
+		return "\x1b[31m"
+	case slog.LevelWarn:
+		return "\x1b[33m"
+	case slog.LevelDebug:
+		return "\x1b[2m"
+	default:
+		return ""
+	}
+}
+
+// truncate 按字符数截断到终端宽度
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width])
+}
+
+// pad 截断并用空格补齐到终端宽度
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	if n := utf8.RuneCountInString(s); n < width {
+		s += strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// readKeys 将原始模式下的输入字节解析为按键名称
+func readKeys(in io.Reader, keys chan<- string) {
+	defer close(keys)
+	r := bufio.NewReader(in)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+		switch b {
+		case 3:
+			keys <- "ctrl+c"
+		case '\r', '\n':
+			keys <- "enter"
+		case 0x1b:
+			// 方向键等转义序列：ESC [ A、ESC [ 5 ~
+			if r.Buffered() == 0 {
+				keys <- "esc"
+				continue
+			}
+			if next, _ := r.ReadByte(); next != '[' {
+				keys <- "esc"
+				continue
+			}
+			seq, _ := r.ReadByte()
+			switch seq {
+			case 'A':
+				keys <- "up"
+			case 'B':
+				keys <- "down"
+			case 'H':
+				keys <- "home"
+			case 'F':
+				keys <- "end"
+			case '5', '6':
+				_, _ = r.ReadByte() // '~'
+				if seq == '5' {
+					keys <- "pgup"
+				} else {
+					keys <- "pgdn"
+				}
+			}
+		default:
+			keys <- string(b)
+		}
+	}
+}
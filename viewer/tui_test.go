@@ -0,0 +1,47 @@
+package viewer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTUIFilterAndDetail 测试级别过滤、选中移动与详情面板
+func TestTUIFilterAndDetail(t *testing.T) {
+	store := NewStore(10)
+	base := time.Now()
+	store.Add(Entry{Time: base, Level: "INFO", Message: "started"})
+	store.Add(Entry{Time: base.Add(time.Second), Level: "ERROR", Message: "db down",
+		Attrs: map[string]interface{}{"stack": "main.run\n\tmain.go:10", "user_id": "u1"}})
+	store.Add(Entry{Time: base.Add(2 * time.Second), Level: "INFO", Message: "retrying"})
+
+	tui := NewTUI(store)
+	frame := tui.Render()
+	if !strings.Contains(frame, "3 records") || !strings.Contains(frame, "retrying") {
+		t.Fatalf("unexpected frame: %q", frame)
+	}
+
+	tui.HandleKey("4")
+	frame = tui.Render()
+	if !strings.Contains(frame, "1 records") || strings.Contains(frame, "retrying") {
+		t.Errorf("level filter not applied: %q", frame)
+	}
+
+	tui.HandleKey("enter")
+	frame = tui.Render()
+	for _, want := range []string{"stack:", "    \tmain.go:10", "user_id: u1"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("detail pane missing %q: %q", want, frame)
+		}
+	}
+
+	tui.HandleKey("0")
+	tui.HandleKey("g")
+	tui.Render()
+	if tui.follow || tui.cursor != 0 {
+		t.Errorf("g should jump to the first record, cursor=%d follow=%v", tui.cursor, tui.follow)
+	}
+	if tui.HandleKey("q") {
+		t.Error("q should quit")
+	}
+}
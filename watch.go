@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/shuakami/logmiao/config"
+)
+
+var (
+	fileWatchMu     sync.Mutex
+	fileWatchCancel context.CancelFunc
+)
+
+// startFileWatch 在 hot_reload 开启时监听 cfg 的配置文件，修改后经句柄 l 整体重建日志器，
+// 配置差异由 logReload 输出；同一时间只保留一个监听
+func (l *Logmiao) startFileWatch(cfg *config.Config) {
+	stopFileWatch()
+	if !cfg.Logger.HotReload || cfg.Source == "" {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fileWatchMu.Lock()
+	if fileWatchCancel != nil {
+		fileWatchCancel() // 并发初始化时只保留最后一个监听
+	}
+	fileWatchCancel = cancel
+	fileWatchMu.Unlock()
+
+	go func() {
+		err := config.WatchFile(ctx, cfg, func(newCfg *config.Config) {
+			if err := l.reloadFile(newCfg); err != nil {
+				slog.Error("Failed to apply reloaded config", Error(err), slog.String("config", newCfg.Source))
+			}
+		}, func(err error) {
+			slog.Error("Failed to reload config, keeping current config", Error(err), slog.String("config", cfg.Source))
+		})
+		if err != nil {
+			slog.Error("Failed to watch config file", Error(err), slog.String("config", cfg.Source))
+		}
+	}()
+}
+
+// reloadFile 应用从文件重新加载的配置，保留只能在代码中设置的控制台输出目标
+func (l *Logmiao) reloadFile(cfg *config.Config) error {
+	if current := l.Config(); current != nil {
+		cfg.Logger.Output.Console.Writer = current.Logger.Output.Console.Writer
+		cfg.Logger.Output.Console.Writers = current.Logger.Output.Console.Writers
+	}
+	if err := l.apply(cfg); err != nil {
+		return err
+	}
+	if !cfg.Logger.HotReload {
+		stopFileWatch()
+	}
+	return nil
+}
+
+// stopFileWatch 停止配置文件监听
+func stopFileWatch() {
+	fileWatchMu.Lock()
+	defer fileWatchMu.Unlock()
+
+	if fileWatchCancel != nil {
+		fileWatchCancel()
+		fileWatchCancel = nil
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"net/http"
+
+	"github.com/shuakami/logmiao/config"
+	"github.com/shuakami/logmiao/handler"
+)
+
+// errorWatchdog 全局错误率看门狗，重新初始化时保留 OnErrorRate 注册的回调
+var errorWatchdog = handler.NewWatchdog(handler.WatchdogConfig{})
+
+// OnErrorRate 注册错误率告警回调，需开启 features.error_watchdog
+func OnErrorRate(fn func(handler.WatchdogAlert)) {
+	errorWatchdog.OnAlert(fn)
+}
+
+// setupWatchdog 按配置更新看门狗，配置中的Webhook随配置替换，修改或删除后不再向旧地址告警
+func setupWatchdog(cfg *config.Config, client *http.Client) *handler.Watchdog {
+	wdCfg := cfg.Logger.Features.ErrorWatchdog
+	var notify []func(handler.WatchdogAlert)
+	if wdCfg.Webhook != "" {
+		notify = append(notify, handler.WebhookAlertWithClient(wdCfg.Webhook, client))
+	}
+	errorWatchdog.Configure(handler.WatchdogConfig{
+		Window:    wdCfg.Window,
+		Threshold: wdCfg.Threshold,
+		Cooldown:  wdCfg.Cooldown,
+		Notify:    notify,
+	})
+	return errorWatchdog
+}
+
+// novelErrors 全局新错误检测器，重新初始化时保留已学习的模板和 OnNovelError 注册的回调
+var novelErrors = handler.NewNovelErrorDetector(handler.NovelErrorConfig{})
+
+// OnNovelError 注册新错误回调，需开启 features.novel_errors
+func OnNovelError(fn func(handler.NovelError)) {
+	novelErrors.OnNovelError(fn)
+}
+
+// setupNovelErrors 按配置更新新错误检测器，配置中的Webhook随配置替换
+func setupNovelErrors(cfg *config.Config, client *http.Client) *handler.NovelErrorDetector {
+	neCfg := cfg.Logger.Features.NovelErrors
+	var notify []func(handler.NovelError)
+	if neCfg.Webhook != "" {
+		notify = append(notify, handler.WebhookNovelErrorWithClient(neCfg.Webhook, client))
+	}
+	novelErrors.Configure(handler.NovelErrorConfig{
+		Capacity: neCfg.Capacity,
+		Warmup:   neCfg.Warmup,
+		Notify:   notify,
+	})
+	return novelErrors
+}